@@ -0,0 +1,47 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// AddressApplication handles the business logic for the saved addresses of the users
+type AddressApplication struct {
+	addressRepo repository.AddressRepository
+}
+
+var _ AddressApplicationInterface = &AddressApplication{}
+
+// AddressApplicationInterface defines the methods available for AddressApplication
+type AddressApplicationInterface interface {
+	CreateAddress(address *entity.Address) (*entity.Address, error)
+	UpdateAddressByID(id uint64, address *entity.Address) (*entity.Address, error)
+	DeleteAddressByID(id uint64) error
+	GetAddressByIDAndUserID(id uint64, userID uint64) (*entity.Address, error)
+	GetAllAddressesByUserID(userID uint64) ([]entity.Address, error)
+}
+
+// CreateAddress creates a new address in the database
+func (a *AddressApplication) CreateAddress(address *entity.Address) (*entity.Address, error) {
+	return a.addressRepo.CreateAddress(address)
+}
+
+// UpdateAddressByID updates the address
+func (a *AddressApplication) UpdateAddressByID(id uint64, address *entity.Address) (*entity.Address, error) {
+	return a.addressRepo.UpdateAddressByID(id, address)
+}
+
+// DeleteAddressByID deletes the address
+func (a *AddressApplication) DeleteAddressByID(id uint64) error {
+	return a.addressRepo.DeleteAddressByID(id)
+}
+
+// GetAddressByIDAndUserID retrieves an address of a user by its ID
+func (a *AddressApplication) GetAddressByIDAndUserID(id uint64, userID uint64) (*entity.Address, error) {
+	return a.addressRepo.GetAddressByIDAndUserID(id, userID)
+}
+
+// GetAllAddressesByUserID retrieves the addresses of a user, the default one first
+func (a *AddressApplication) GetAllAddressesByUserID(userID uint64) ([]entity.Address, error) {
+	return a.addressRepo.GetAllAddressesByUserID(userID)
+}
@@ -0,0 +1,61 @@
+package application
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// APIKeyApplication handles the business logic for the API keys of the organizations
+type APIKeyApplication struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+var _ APIKeyApplicationInterface = &APIKeyApplication{}
+
+// APIKeyApplicationInterface defines the methods available for APIKeyApplication
+type APIKeyApplicationInterface interface {
+	CreateAPIKey(apiKey *entity.APIKey) (*entity.APIKey, error)
+	GetActiveAPIKeyByKeyHash(keyHash string) (*entity.APIKey, error)
+	GetAPIKeyByIDAndOrganizationID(id uint64, organizationID uint64) (*entity.APIKey, error)
+	GetAllAPIKeysByOrganizationID(organizationID uint64) ([]entity.APIKey, error)
+	UpdateAPIKeyLastUsedAtByID(id uint64, lastUsedAt time.Time) error
+	RevokeAPIKeyByID(id uint64, revokedAt time.Time) error
+	RevokeAPIKeysByOrganizationIDAndUserID(organizationID uint64, userID uint64, revokedAt time.Time) error
+}
+
+// CreateAPIKey creates a new API key in the database
+func (a *APIKeyApplication) CreateAPIKey(apiKey *entity.APIKey) (*entity.APIKey, error) {
+	return a.apiKeyRepo.CreateAPIKey(apiKey)
+}
+
+// GetActiveAPIKeyByKeyHash retrieves the unrevoked API key with the given hash
+func (a *APIKeyApplication) GetActiveAPIKeyByKeyHash(keyHash string) (*entity.APIKey, error) {
+	return a.apiKeyRepo.GetActiveAPIKeyByKeyHash(keyHash)
+}
+
+// GetAPIKeyByIDAndOrganizationID retrieves an API key of an organization by its ID
+func (a *APIKeyApplication) GetAPIKeyByIDAndOrganizationID(id uint64, organizationID uint64) (*entity.APIKey, error) {
+	return a.apiKeyRepo.GetAPIKeyByIDAndOrganizationID(id, organizationID)
+}
+
+// GetAllAPIKeysByOrganizationID retrieves the API keys of an organization
+func (a *APIKeyApplication) GetAllAPIKeysByOrganizationID(organizationID uint64) ([]entity.APIKey, error) {
+	return a.apiKeyRepo.GetAllAPIKeysByOrganizationID(organizationID)
+}
+
+// UpdateAPIKeyLastUsedAtByID records when an API key was last used
+func (a *APIKeyApplication) UpdateAPIKeyLastUsedAtByID(id uint64, lastUsedAt time.Time) error {
+	return a.apiKeyRepo.UpdateAPIKeyLastUsedAtByID(id, lastUsedAt)
+}
+
+// RevokeAPIKeyByID revokes an API key
+func (a *APIKeyApplication) RevokeAPIKeyByID(id uint64, revokedAt time.Time) error {
+	return a.apiKeyRepo.RevokeAPIKeyByID(id, revokedAt)
+}
+
+// RevokeAPIKeysByOrganizationIDAndUserID revokes the API keys a member created for an organization
+func (a *APIKeyApplication) RevokeAPIKeysByOrganizationIDAndUserID(organizationID uint64, userID uint64, revokedAt time.Time) error {
+	return a.apiKeyRepo.RevokeAPIKeysByOrganizationIDAndUserID(organizationID, userID, revokedAt)
+}
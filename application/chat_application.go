@@ -0,0 +1,71 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// ChatApplication handles the business logic for the conversations of the local chat provider
+type ChatApplication struct {
+	chatRepo repository.ChatRepository
+}
+
+var _ ChatApplicationInterface = &ChatApplication{}
+
+// ChatApplicationInterface defines the methods available for ChatApplication
+type ChatApplicationInterface interface {
+	CreateChannel(channel *entity.ChatChannel) error
+	GetAllChannelsByUserID(userID string) ([]entity.ChatChannel, error)
+	AddMember(member *entity.ChatMember) error
+	RemoveMember(channelID string, userID string) error
+	GetMember(channelID string, userID string) (*entity.ChatMember, error)
+	GetAllMembersByChannelID(channelID string) ([]entity.ChatMember, error)
+	UpdateMemberLastReadMessageID(channelID string, userID string, messageID uint64) error
+	CreateMessage(message *entity.ChatMessage) (*entity.ChatMessage, error)
+	GetAllMessagesByChannelID(channelID string, beforeID uint64, limit int) ([]entity.ChatMessage, error)
+}
+
+// CreateChannel creates a chat channel
+func (a *ChatApplication) CreateChannel(channel *entity.ChatChannel) error {
+	return a.chatRepo.CreateChannel(channel)
+}
+
+// GetAllChannelsByUserID retrieves the chat channels of a user
+func (a *ChatApplication) GetAllChannelsByUserID(userID string) ([]entity.ChatChannel, error) {
+	return a.chatRepo.GetAllChannelsByUserID(userID)
+}
+
+// AddMember adds a member to a chat channel
+func (a *ChatApplication) AddMember(member *entity.ChatMember) error {
+	return a.chatRepo.AddMember(member)
+}
+
+// RemoveMember removes a member from a chat channel
+func (a *ChatApplication) RemoveMember(channelID string, userID string) error {
+	return a.chatRepo.RemoveMember(channelID, userID)
+}
+
+// GetMember retrieves the membership of a user in a chat channel
+func (a *ChatApplication) GetMember(channelID string, userID string) (*entity.ChatMember, error) {
+	return a.chatRepo.GetMember(channelID, userID)
+}
+
+// GetAllMembersByChannelID retrieves the members of a chat channel
+func (a *ChatApplication) GetAllMembersByChannelID(channelID string) ([]entity.ChatMember, error) {
+	return a.chatRepo.GetAllMembersByChannelID(channelID)
+}
+
+// UpdateMemberLastReadMessageID records the last message a member has read
+func (a *ChatApplication) UpdateMemberLastReadMessageID(channelID string, userID string, messageID uint64) error {
+	return a.chatRepo.UpdateMemberLastReadMessageID(channelID, userID, messageID)
+}
+
+// CreateMessage creates a new chat message
+func (a *ChatApplication) CreateMessage(message *entity.ChatMessage) (*entity.ChatMessage, error) {
+	return a.chatRepo.CreateMessage(message)
+}
+
+// GetAllMessagesByChannelID retrieves the messages of a chat channel
+func (a *ChatApplication) GetAllMessagesByChannelID(channelID string, beforeID uint64, limit int) ([]entity.ChatMessage, error) {
+	return a.chatRepo.GetAllMessagesByChannelID(channelID, beforeID, limit)
+}
@@ -0,0 +1,37 @@
+package application
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// ChatOutboxApplication handles the business logic for the pending chat membership changes
+type ChatOutboxApplication struct {
+	chatOutboxRepo repository.ChatOutboxRepository
+}
+
+var _ ChatOutboxApplicationInterface = &ChatOutboxApplication{}
+
+// ChatOutboxApplicationInterface defines the methods available for ChatOutboxApplication
+type ChatOutboxApplicationInterface interface {
+	CreateChatOutboxes(chatOutboxes []entity.ChatOutbox) error
+	UpdateChatOutboxByID(id uint64, chatOutbox *entity.ChatOutbox) (*entity.ChatOutbox, error)
+	GetAllDueChatOutboxes(now time.Time, limit int) ([]entity.ChatOutbox, error)
+}
+
+// CreateChatOutboxes stores chat membership changes to be applied to the chat provider
+func (a *ChatOutboxApplication) CreateChatOutboxes(chatOutboxes []entity.ChatOutbox) error {
+	return a.chatOutboxRepo.CreateChatOutboxes(chatOutboxes)
+}
+
+// UpdateChatOutboxByID updates the chat membership change
+func (a *ChatOutboxApplication) UpdateChatOutboxByID(id uint64, chatOutbox *entity.ChatOutbox) (*entity.ChatOutbox, error) {
+	return a.chatOutboxRepo.UpdateChatOutboxByID(id, chatOutbox)
+}
+
+// GetAllDueChatOutboxes retrieves the chat membership changes due for an attempt
+func (a *ChatOutboxApplication) GetAllDueChatOutboxes(now time.Time, limit int) ([]entity.ChatOutbox, error) {
+	return a.chatOutboxRepo.GetAllDueChatOutboxes(now, limit)
+}
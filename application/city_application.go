@@ -14,17 +14,17 @@ var _ CityApplicationInterface = &CityApplication{}
 
 // CityApplicationInterface defines the methods available for CityApplication.
 type CityApplicationInterface interface {
-	CountCities() (int64, error)
-	GetAllCities(page int, perPage int) ([]entity.City, error)
+	CountCities(regionID uint64) (int64, error)
+	GetAllCities(regionID uint64, page int, perPage int) ([]entity.City, error)
 	GetCityByID(uint64) (*entity.City, error)
 }
 
-func (a *CityApplication) CountCities() (int64, error) {
-	return a.cityRepo.CountCities()
+func (a *CityApplication) CountCities(regionID uint64) (int64, error) {
+	return a.cityRepo.CountCities(regionID)
 }
 
-func (a *CityApplication) GetAllCities(page int, perPage int) ([]entity.City, error) {
-	return a.cityRepo.GetAllCities(page, perPage)
+func (a *CityApplication) GetAllCities(regionID uint64, page int, perPage int) ([]entity.City, error) {
+	return a.cityRepo.GetAllCities(regionID, page, perPage)
 }
 
 func (a *CityApplication) GetCityByID(cityID uint64) (*entity.City, error) {
@@ -0,0 +1,38 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// CreditApplication handles the business logic for credits
+type CreditApplication struct {
+	creditRepo repository.CreditRepository
+}
+
+var _ CreditApplicationInterface = &CreditApplication{}
+
+// CreditApplicationInterface defines the methods available for CreditApplication
+type CreditApplicationInterface interface {
+	CreateCredit(credit *entity.Credit) (*entity.Credit, error)
+	CountCreditsByUserIDAndReason(userID uint64, reason entity.CreditReason) (int64, error)
+	SumCreditsByUserID(userID uint64) (float64, error)
+	DeleteCreditsByOrderIDAndReason(orderID uint64, reason entity.CreditReason) error
+}
+
+// CreateCredit creates a new credit in the database
+func (a *CreditApplication) CreateCredit(credit *entity.Credit) (*entity.Credit, error) {
+	return a.creditRepo.CreateCredit(credit)
+}
+
+func (a *CreditApplication) CountCreditsByUserIDAndReason(userID uint64, reason entity.CreditReason) (int64, error) {
+	return a.creditRepo.CountCreditsByUserIDAndReason(userID, reason)
+}
+
+func (a *CreditApplication) SumCreditsByUserID(userID uint64) (float64, error) {
+	return a.creditRepo.SumCreditsByUserID(userID)
+}
+
+func (a *CreditApplication) DeleteCreditsByOrderIDAndReason(orderID uint64, reason entity.CreditReason) error {
+	return a.creditRepo.DeleteCreditsByOrderIDAndReason(orderID, reason)
+}
@@ -0,0 +1,35 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// DistrictApplication handles the business logic for districts
+type DistrictApplication struct {
+	districtRepo repository.DistrictRepository
+}
+
+var _ DistrictApplicationInterface = &DistrictApplication{}
+
+// DistrictApplicationInterface defines the methods available for DistrictApplication
+type DistrictApplicationInterface interface {
+	CountDistricts(regionID uint64, cityID uint64) (int64, error)
+	GetAllDistricts(regionID uint64, cityID uint64, page int, perPage int) ([]entity.District, error)
+	GetDistrictByID(uint64) (*entity.District, error)
+}
+
+// CountDistricts counts the districts, of a region or a city when their IDs aren't 0
+func (a *DistrictApplication) CountDistricts(regionID uint64, cityID uint64) (int64, error) {
+	return a.districtRepo.CountDistricts(regionID, cityID)
+}
+
+// GetAllDistricts retrieves a page of the districts, of a region or a city when their IDs aren't 0
+func (a *DistrictApplication) GetAllDistricts(regionID uint64, cityID uint64, page int, perPage int) ([]entity.District, error) {
+	return a.districtRepo.GetAllDistricts(regionID, cityID, page, perPage)
+}
+
+// GetDistrictByID retrieves a district by its ID
+func (a *DistrictApplication) GetDistrictByID(districtID uint64) (*entity.District, error) {
+	return a.districtRepo.GetDistrictByID(districtID)
+}
@@ -0,0 +1,29 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// DriverCoverageApplication handles the business logic for the cities and regions drivers serve
+type DriverCoverageApplication struct {
+	driverCoverageRepo repository.DriverCoverageRepository
+}
+
+var _ DriverCoverageApplicationInterface = &DriverCoverageApplication{}
+
+// DriverCoverageApplicationInterface defines the methods available for DriverCoverageApplication
+type DriverCoverageApplicationInterface interface {
+	GetAllDriverCoveragesByDriverID(driverID uint64) ([]entity.DriverCoverage, error)
+	ReplaceDriverCoveragesByDriverID(driverID uint64, coverages []entity.DriverCoverage) ([]entity.DriverCoverage, error)
+}
+
+// GetAllDriverCoveragesByDriverID retrieves the cities and regions a driver serves
+func (a *DriverCoverageApplication) GetAllDriverCoveragesByDriverID(driverID uint64) ([]entity.DriverCoverage, error) {
+	return a.driverCoverageRepo.GetAllDriverCoveragesByDriverID(driverID)
+}
+
+// ReplaceDriverCoveragesByDriverID replaces the cities and regions a driver serves
+func (a *DriverCoverageApplication) ReplaceDriverCoveragesByDriverID(driverID uint64, coverages []entity.DriverCoverage) ([]entity.DriverCoverage, error) {
+	return a.driverCoverageRepo.ReplaceDriverCoveragesByDriverID(driverID, coverages)
+}
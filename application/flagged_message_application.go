@@ -0,0 +1,47 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// FlaggedMessageApplication handles the business logic for flagged chat messages
+type FlaggedMessageApplication struct {
+	flaggedMessageRepo repository.FlaggedMessageRepository
+}
+
+var _ FlaggedMessageApplicationInterface = &FlaggedMessageApplication{}
+
+// FlaggedMessageApplicationInterface defines the methods available for FlaggedMessageApplication
+type FlaggedMessageApplicationInterface interface {
+	CreateFlaggedMessage(flaggedMessage *entity.FlaggedMessage) (*entity.FlaggedMessage, error)
+	UpdateFlaggedMessageByID(id uint64, flaggedMessage *entity.FlaggedMessage) (*entity.FlaggedMessage, error)
+	GetFlaggedMessageByID(id uint64) (*entity.FlaggedMessage, error)
+	GetAllFlaggedMessages(status string, page int, perPage int) ([]entity.FlaggedMessage, error)
+	CountFlaggedMessages(status string) (int64, error)
+}
+
+// CreateFlaggedMessage creates a new flagged message in the database
+func (a *FlaggedMessageApplication) CreateFlaggedMessage(flaggedMessage *entity.FlaggedMessage) (*entity.FlaggedMessage, error) {
+	return a.flaggedMessageRepo.CreateFlaggedMessage(flaggedMessage)
+}
+
+// UpdateFlaggedMessageByID updates the flagged message
+func (a *FlaggedMessageApplication) UpdateFlaggedMessageByID(id uint64, flaggedMessage *entity.FlaggedMessage) (*entity.FlaggedMessage, error) {
+	return a.flaggedMessageRepo.UpdateFlaggedMessageByID(id, flaggedMessage)
+}
+
+// GetFlaggedMessageByID retrieves a flagged message by its ID
+func (a *FlaggedMessageApplication) GetFlaggedMessageByID(id uint64) (*entity.FlaggedMessage, error) {
+	return a.flaggedMessageRepo.GetFlaggedMessageByID(id)
+}
+
+// GetAllFlaggedMessages retrieves a paginated list of the flagged messages
+func (a *FlaggedMessageApplication) GetAllFlaggedMessages(status string, page int, perPage int) ([]entity.FlaggedMessage, error) {
+	return a.flaggedMessageRepo.GetAllFlaggedMessages(status, page, perPage)
+}
+
+// CountFlaggedMessages counts the flagged messages
+func (a *FlaggedMessageApplication) CountFlaggedMessages(status string) (int64, error) {
+	return a.flaggedMessageRepo.CountFlaggedMessages(status)
+}
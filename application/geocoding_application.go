@@ -0,0 +1,83 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// GeocodingApplication handles the business logic for the regions, cities and districts of the geocoding dataset
+type GeocodingApplication struct {
+	geocodingRepo repository.GeocodingRepository
+}
+
+var _ GeocodingApplicationInterface = &GeocodingApplication{}
+
+// GeocodingApplicationInterface defines the methods available for GeocodingApplication
+type GeocodingApplicationInterface interface {
+	SaveRegion(region *entity.Region) error
+	SaveCity(city *entity.City) error
+	SaveDistrict(district *entity.District) error
+	SaveLocation(location *entity.Location) error
+	SearchGeoPlaces(query string, limit int) ([]entity.GeoPlace, error)
+	GetRegionByID(id uint64) (*entity.Region, error)
+	GetRegionByCoordinates(longitude float64, latitude float64) (*entity.Region, error)
+	GetCityByCoordinates(longitude float64, latitude float64) (*entity.City, error)
+	GetDistrictByCoordinates(longitude float64, latitude float64) (*entity.District, error)
+	FillOrderCities() error
+	FillLocationCities() error
+}
+
+// SaveRegion creates the region or replaces the one with the same ID, along with its boundary
+func (a *GeocodingApplication) SaveRegion(region *entity.Region) error {
+	return a.geocodingRepo.SaveRegion(region)
+}
+
+// SaveCity creates the city or replaces the one with the same ID, along with its boundary
+func (a *GeocodingApplication) SaveCity(city *entity.City) error {
+	return a.geocodingRepo.SaveCity(city)
+}
+
+// SaveDistrict creates the district or replaces the one with the same ID, along with its boundary
+func (a *GeocodingApplication) SaveDistrict(district *entity.District) error {
+	return a.geocodingRepo.SaveDistrict(district)
+}
+
+// SaveLocation creates the location or replaces the one with the same ID
+func (a *GeocodingApplication) SaveLocation(location *entity.Location) error {
+	return a.geocodingRepo.SaveLocation(location)
+}
+
+// SearchGeoPlaces retrieves the regions, cities and districts whose Arabic or English name contains the query
+func (a *GeocodingApplication) SearchGeoPlaces(query string, limit int) ([]entity.GeoPlace, error) {
+	return a.geocodingRepo.SearchGeoPlaces(query, limit)
+}
+
+// GetRegionByID retrieves a region by its ID
+func (a *GeocodingApplication) GetRegionByID(id uint64) (*entity.Region, error) {
+	return a.geocodingRepo.GetRegionByID(id)
+}
+
+// GetRegionByCoordinates retrieves the region covering the given point
+func (a *GeocodingApplication) GetRegionByCoordinates(longitude float64, latitude float64) (*entity.Region, error) {
+	return a.geocodingRepo.GetRegionByCoordinates(longitude, latitude)
+}
+
+// GetCityByCoordinates retrieves the city covering the given point
+func (a *GeocodingApplication) GetCityByCoordinates(longitude float64, latitude float64) (*entity.City, error) {
+	return a.geocodingRepo.GetCityByCoordinates(longitude, latitude)
+}
+
+// GetDistrictByCoordinates retrieves the district covering the given point
+func (a *GeocodingApplication) GetDistrictByCoordinates(longitude float64, latitude float64) (*entity.District, error) {
+	return a.geocodingRepo.GetDistrictByCoordinates(longitude, latitude)
+}
+
+// FillOrderCities sets the cities and regions of the orders, and of the recurring orders, placed before they were known, from the districts, cities and regions covering their pickup and destination points.
+func (a *GeocodingApplication) FillOrderCities() error {
+	return a.geocodingRepo.FillOrderCities()
+}
+
+// FillLocationCities sets the cities and districts of the locations imported or seeded without them, from the district or the city covering their point.
+func (a *GeocodingApplication) FillLocationCities() error {
+	return a.geocodingRepo.FillLocationCities()
+}
@@ -0,0 +1,77 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// HubApplication handles the business logic for hubs
+type HubApplication struct {
+	hubRepo repository.HubRepository
+}
+
+var _ HubApplicationInterface = &HubApplication{}
+
+// HubApplicationInterface defines the methods available for HubApplication
+type HubApplicationInterface interface {
+	CreateHub(hub *entity.Hub) (*entity.Hub, error)
+	UpdateHubByID(id uint64, hub *entity.Hub) (*entity.Hub, error)
+	CountHubs(cityID uint64) (int64, error)
+	GetAllHubs(cityID uint64, page int, perPage int) ([]entity.Hub, error)
+	GetHubByID(id uint64) (*entity.Hub, error)
+	GetNearestActiveHubByCityID(cityID uint64, latitude float64, longitude float64) (*entity.Hub, error)
+	CreateHubMember(hubMember *entity.HubMember) (*entity.HubMember, error)
+	DeleteHubMemberByID(id uint64) error
+	GetHubMemberByHubIDAndUserID(hubID uint64, userID uint64) (*entity.HubMember, error)
+	GetAllHubMembersByHubID(hubID uint64) ([]entity.HubMember, error)
+}
+
+// CreateHub creates a new hub in the database
+func (a *HubApplication) CreateHub(hub *entity.Hub) (*entity.Hub, error) {
+	return a.hubRepo.CreateHub(hub)
+}
+
+// UpdateHubByID updates the hub
+func (a *HubApplication) UpdateHubByID(id uint64, hub *entity.Hub) (*entity.Hub, error) {
+	return a.hubRepo.UpdateHubByID(id, hub)
+}
+
+// CountHubs counts the hubs, of a city when its ID isn't 0
+func (a *HubApplication) CountHubs(cityID uint64) (int64, error) {
+	return a.hubRepo.CountHubs(cityID)
+}
+
+// GetAllHubs retrieves a page of the hubs, of a city when its ID isn't 0
+func (a *HubApplication) GetAllHubs(cityID uint64, page int, perPage int) ([]entity.Hub, error) {
+	return a.hubRepo.GetAllHubs(cityID, page, perPage)
+}
+
+// GetHubByID retrieves a hub by its ID
+func (a *HubApplication) GetHubByID(id uint64) (*entity.Hub, error) {
+	return a.hubRepo.GetHubByID(id)
+}
+
+// GetNearestActiveHubByCityID retrieves the active hub of a city closest to the given point
+func (a *HubApplication) GetNearestActiveHubByCityID(cityID uint64, latitude float64, longitude float64) (*entity.Hub, error) {
+	return a.hubRepo.GetNearestActiveHubByCityID(cityID, latitude, longitude)
+}
+
+// CreateHubMember adds a user to the staff of a hub
+func (a *HubApplication) CreateHubMember(hubMember *entity.HubMember) (*entity.HubMember, error) {
+	return a.hubRepo.CreateHubMember(hubMember)
+}
+
+// DeleteHubMemberByID removes a user from the staff of a hub
+func (a *HubApplication) DeleteHubMemberByID(id uint64) error {
+	return a.hubRepo.DeleteHubMemberByID(id)
+}
+
+// GetHubMemberByHubIDAndUserID retrieves the membership of a user in the staff of a hub
+func (a *HubApplication) GetHubMemberByHubIDAndUserID(hubID uint64, userID uint64) (*entity.HubMember, error) {
+	return a.hubRepo.GetHubMemberByHubIDAndUserID(hubID, userID)
+}
+
+// GetAllHubMembersByHubID retrieves the staff of a hub
+func (a *HubApplication) GetAllHubMembersByHubID(hubID uint64) ([]entity.HubMember, error) {
+	return a.hubRepo.GetAllHubMembersByHubID(hubID)
+}
@@ -0,0 +1,33 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// InvoiceApplication handles the business logic for invoices
+type InvoiceApplication struct {
+	invoiceRepo repository.InvoiceRepository
+}
+
+var _ InvoiceApplicationInterface = &InvoiceApplication{}
+
+// InvoiceApplicationInterface defines the methods available for InvoiceApplication
+type InvoiceApplicationInterface interface {
+	CreateInvoice(invoice *entity.Invoice) (*entity.Invoice, error)
+	UpdateInvoiceByID(id uint64, invoice *entity.Invoice) (*entity.Invoice, error)
+	GetInvoiceByOrderID(orderID uint64) (*entity.Invoice, error)
+}
+
+// CreateInvoice creates a new invoice in the database
+func (a *InvoiceApplication) CreateInvoice(invoice *entity.Invoice) (*entity.Invoice, error) {
+	return a.invoiceRepo.CreateInvoice(invoice)
+}
+
+func (a *InvoiceApplication) UpdateInvoiceByID(id uint64, invoice *entity.Invoice) (*entity.Invoice, error) {
+	return a.invoiceRepo.UpdateInvoiceByID(id, invoice)
+}
+
+func (a *InvoiceApplication) GetInvoiceByOrderID(orderID uint64) (*entity.Invoice, error) {
+	return a.invoiceRepo.GetInvoiceByOrderID(orderID)
+}
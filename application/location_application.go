@@ -14,18 +14,18 @@ var _ LocationApplicationInterface = &LocationApplication{}
 
 // LocationApplicationInterface defines the methods available for LocationApplication
 type LocationApplicationInterface interface {
-	CountLocations() (int64, error)
-	GetAllLocations(page int, perPage int) ([]entity.Location, error)
+	CountLocations(regionID uint64, cityID uint64, districtID uint64) (int64, error)
+	GetAllLocations(regionID uint64, cityID uint64, districtID uint64, page int, perPage int) ([]entity.Location, error)
 	GetLocationByID(uint64) (*entity.Location, error)
 	GetLocationByCoordinates(float64, float64, *float64) (*entity.Location, error)
 }
 
-func (a *LocationApplication) CountLocations() (int64, error) {
-	return a.locationRepo.CountLocations()
+func (a *LocationApplication) CountLocations(regionID uint64, cityID uint64, districtID uint64) (int64, error) {
+	return a.locationRepo.CountLocations(regionID, cityID, districtID)
 }
 
-func (a *LocationApplication) GetAllLocations(page int, perPage int) ([]entity.Location, error) {
-	return a.locationRepo.GetAllLocations(page, perPage)
+func (a *LocationApplication) GetAllLocations(regionID uint64, cityID uint64, districtID uint64, page int, perPage int) ([]entity.Location, error) {
+	return a.locationRepo.GetAllLocations(regionID, cityID, districtID, page, perPage)
 }
 
 // GetByID returns a location by its ID
@@ -16,11 +16,14 @@ var _ OfferApplicationInterface = &OfferApplication{}
 type OfferApplicationInterface interface {
 	CreateOffer(balance *entity.Offer) (*entity.Offer, error)
 	UpdateOfferByID(id uint64, offer *entity.Offer) (*entity.Offer, error)
+	UpdateOfferByIDAndStatus(id uint64, status entity.OfferStatus, offer *entity.Offer) (*entity.Offer, error)
 	CountOffersByDriverIDAndStatus(driverID uint64, status entity.OfferStatus) (int64, error)
 	CountOffersByStatusAndUserID(status entity.OfferStatus, userID uint64) (int64, error)
 	GetAllOffersByStatusAndUserID(status entity.OfferStatus, userID uint64, page int, perPage int) ([]entity.Offer, error)
 	GetOfferByIDAndUserID(id uint64, userID uint64) (*entity.Offer, error)
 	GetAllOffersByStatusAndOrderID(status entity.OfferStatus, orderID uint64) ([]entity.Offer, error)
+	CountOffersByDriverIDAndOrderLegID(driverID uint64, orderLegID uint64) (int64, error)
+	GetAllOffersByStatusAndOrderLegID(status entity.OfferStatus, orderLegID uint64) ([]entity.Offer, error)
 }
 
 // CreateOffer creates a new user in the database
@@ -32,6 +35,11 @@ func (a *OfferApplication) UpdateOfferByID(id uint64, offer *entity.Offer) (*ent
 	return a.offerRepo.UpdateOfferByID(id, offer)
 }
 
+// UpdateOfferByIDAndStatus updates the offer only if it's still in the given status
+func (a *OfferApplication) UpdateOfferByIDAndStatus(id uint64, status entity.OfferStatus, offer *entity.Offer) (*entity.Offer, error) {
+	return a.offerRepo.UpdateOfferByIDAndStatus(id, status, offer)
+}
+
 func (a *OfferApplication) CountOffersByDriverIDAndStatus(driverID uint64, status entity.OfferStatus) (int64, error) {
 	return a.offerRepo.CountOffersByDriverIDAndStatus(driverID, status)
 }
@@ -51,3 +59,13 @@ func (a *OfferApplication) GetOfferByIDAndUserID(id uint64, userID uint64) (*ent
 func (a *OfferApplication) GetAllOffersByStatusAndOrderID(status entity.OfferStatus, orderID uint64) ([]entity.Offer, error) {
 	return a.offerRepo.GetAllOffersByStatusAndOrderID(status, orderID)
 }
+
+// CountOffersByDriverIDAndOrderLegID counts the offers a driver made on a leg of an intercity order
+func (a *OfferApplication) CountOffersByDriverIDAndOrderLegID(driverID uint64, orderLegID uint64) (int64, error) {
+	return a.offerRepo.CountOffersByDriverIDAndOrderLegID(driverID, orderLegID)
+}
+
+// GetAllOffersByStatusAndOrderLegID retrieves the offers made on a leg of an intercity order
+func (a *OfferApplication) GetAllOffersByStatusAndOrderLegID(status entity.OfferStatus, orderLegID uint64) ([]entity.Offer, error) {
+	return a.offerRepo.GetAllOffersByStatusAndOrderLegID(status, orderLegID)
+}
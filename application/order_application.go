@@ -1,6 +1,8 @@
 package application
 
 import (
+	"time"
+
 	"github.com/OmarBader7/web-service-jayeek/domain/entity"
 	"github.com/OmarBader7/web-service-jayeek/domain/repository"
 )
@@ -16,20 +18,32 @@ var _ OrderApplicationInterface = &OrderApplication{}
 type OrderApplicationInterface interface {
 	CreateOrder(order *entity.Order) (*entity.Order, error)
 	UpdateOrderByID(id uint64, order *entity.Order) (*entity.Order, error)
+	ReleaseOrderDriverPoolByID(id uint64) error
 	UpdateOrderDriverPoolByOrderIDAndDriverID(orderID uint64, driverID uint64, orderDriverPool *entity.OrderDriverPool) (*entity.OrderDriverPool, error)
 	CountOrdersByUserIDExcludingStatus(userID uint64, status []entity.OrderStatus) (int64, error)
 	CountOrdersByUserIDAndRecipientIDExcludingStatus(userID uint64, recipientID uint64, status []entity.OrderStatus) (int64, error)
 	CountOrdersByDriverIDExcludingStatus(driverID uint64, status []entity.OrderStatus) (int64, error)
 	GetAllOrdersByUserIDExcludingStatus(userID uint64, status []entity.OrderStatus, page int, perPage int) ([]entity.Order, error)
 	GetAllOrdersByUserIDAndRecipientIDExcludingStatus(userID uint64, recipientID uint64, status []entity.OrderStatus, page int, perPage int) ([]entity.Order, error)
+	CountOrdersByOrganizationID(organizationID uint64) (int64, error)
+	GetAllOrdersByOrganizationID(organizationID uint64, page int, perPage int) ([]entity.Order, error)
 	GetAllOrdersByDriverIDExcludingStatus(driverID uint64, status []entity.OrderStatus, page int, perPage int) ([]entity.Order, error)
 	GetAllOrdersByRecipientPhoneNumber(recipientPhoneNumber string) ([]entity.Order, error)
+	UpdateOrderByIDAndStatus(id uint64, status entity.OrderStatus, order *entity.Order) (*entity.Order, error)
+	GetAllScheduledOrdersDueBefore(before time.Time) ([]entity.Order, error)
+	GetAllOrdersDeliveredBefore(before time.Time) ([]entity.Order, error)
+	GetAllOrdersPreferredBefore(before time.Time) ([]entity.Order, error)
+	ReleasePreferredOrderByID(id uint64) error
+	GetAllOrdersByStatusAfterID(status []entity.OrderStatus, afterID uint64, limit int) ([]entity.Order, error)
 	CountDriverPoolsByDriverIDAndCategoryID(uint64, uint64, *string) (int64, error)
 	GetAllDriverPoolsByDriverIDAndCategoryID(driverID uint64, categoryID uint64, page int, perPage int, orderBy *string, byArrival *string) ([]entity.Order, error)
 	GetOrderByID(uint64) (*entity.Order, error)
 	GetOrderByIDAndUserID(id uint64, userID uint64) (*entity.Order, error)
 	GetOrderByIDAndRecipientID(id uint64, recipientID uint64) (*entity.Order, error)
 	GetOrderByIDAndDriverID(id uint64, driverID uint64) (*entity.Order, error)
+	GetOrderByTrackingNumber(trackingNumber string) (*entity.Order, error)
+	GetOrderByShareToken(shareToken string) (*entity.Order, error)
+	UpdateOrderShareTokenByID(id uint64, shareToken *string) error
 	GetOrderDriverPoolByOrderIDAndDriverID(uint64, uint64) (*entity.OrderDriverPool, error)
 }
 
@@ -42,6 +56,11 @@ func (a *OrderApplication) UpdateOrderByID(id uint64, order *entity.Order) (*ent
 	return a.orderRepo.UpdateOrderByID(id, order)
 }
 
+// ReleaseOrderDriverPoolByID dispatches an order to the available drivers that have not been offered it yet
+func (a *OrderApplication) ReleaseOrderDriverPoolByID(id uint64) error {
+	return a.orderRepo.ReleaseOrderDriverPoolByID(id)
+}
+
 func (a *OrderApplication) UpdateOrderDriverPoolByOrderIDAndDriverID(orderID uint64, driverID uint64, orderDriverPool *entity.OrderDriverPool) (*entity.OrderDriverPool, error) {
 	return a.orderRepo.UpdateOrderDriverPoolByOrderIDAndDriverID(orderID, driverID, orderDriverPool)
 }
@@ -66,6 +85,16 @@ func (a *OrderApplication) GetAllOrdersByUserIDAndRecipientIDExcludingStatus(use
 	return a.orderRepo.GetAllOrdersByUserIDAndRecipientIDExcludingStatus(userID, recipientID, status, page, perPage)
 }
 
+// CountOrdersByOrganizationID counts the orders created for an organization
+func (a *OrderApplication) CountOrdersByOrganizationID(organizationID uint64) (int64, error) {
+	return a.orderRepo.CountOrdersByOrganizationID(organizationID)
+}
+
+// GetAllOrdersByOrganizationID retrieves a paginated list of the orders created for an organization
+func (a *OrderApplication) GetAllOrdersByOrganizationID(organizationID uint64, page int, perPage int) ([]entity.Order, error) {
+	return a.orderRepo.GetAllOrdersByOrganizationID(organizationID, page, perPage)
+}
+
 func (a *OrderApplication) GetAllOrdersByDriverIDExcludingStatus(driverID uint64, status []entity.OrderStatus, page int, perPage int) ([]entity.Order, error) {
 	return a.orderRepo.GetAllOrdersByDriverIDExcludingStatus(driverID, status, page, perPage)
 }
@@ -74,6 +103,33 @@ func (a *OrderApplication) GetAllOrdersByRecipientPhoneNumber(recipientPhoneNumb
 	return a.orderRepo.GetAllOrdersByRecipientPhoneNumber(recipientPhoneNumber)
 }
 
+// UpdateOrderByIDAndStatus updates the order only if it's still in the given status
+func (a *OrderApplication) UpdateOrderByIDAndStatus(id uint64, status entity.OrderStatus, order *entity.Order) (*entity.Order, error) {
+	return a.orderRepo.UpdateOrderByIDAndStatus(id, status, order)
+}
+
+func (a *OrderApplication) GetAllScheduledOrdersDueBefore(before time.Time) ([]entity.Order, error) {
+	return a.orderRepo.GetAllScheduledOrdersDueBefore(before)
+}
+
+func (a *OrderApplication) GetAllOrdersDeliveredBefore(before time.Time) ([]entity.Order, error) {
+	return a.orderRepo.GetAllOrdersDeliveredBefore(before)
+}
+
+func (a *OrderApplication) GetAllOrdersPreferredBefore(before time.Time) ([]entity.Order, error) {
+	return a.orderRepo.GetAllOrdersPreferredBefore(before)
+}
+
+// ReleasePreferredOrderByID dispatches an order offered to its preferred driver first to every driver nearby
+func (a *OrderApplication) ReleasePreferredOrderByID(id uint64) error {
+	return a.orderRepo.ReleasePreferredOrderByID(id)
+}
+
+// GetAllOrdersByStatusAfterID retrieves the orders in the given statuses in batches, ordered by ID
+func (a *OrderApplication) GetAllOrdersByStatusAfterID(status []entity.OrderStatus, afterID uint64, limit int) ([]entity.Order, error) {
+	return a.orderRepo.GetAllOrdersByStatusAfterID(status, afterID, limit)
+}
+
 func (a *OrderApplication) CountDriverPoolsByDriverIDAndCategoryID(driverID uint64, categoryID uint64, byArrival *string) (int64, error) {
 	return a.orderRepo.CountDriverPoolsByDriverIDAndCategoryID(driverID, categoryID, byArrival)
 }
@@ -99,6 +155,22 @@ func (a *OrderApplication) GetOrderByIDAndDriverID(id uint64, driverID uint64) (
 	return a.orderRepo.GetOrderByIDAndDriverID(id, driverID)
 }
 
+// GetOrderByTrackingNumber retrieves the order with the given tracking number
+func (a *OrderApplication) GetOrderByTrackingNumber(trackingNumber string) (*entity.Order, error) {
+	return a.orderRepo.GetOrderByTrackingNumber(trackingNumber)
+}
+
+// GetOrderByShareToken retrieves the order shared through the tracking link with the given token
+func (a *OrderApplication) GetOrderByShareToken(shareToken string) (*entity.Order, error) {
+	return a.orderRepo.GetOrderByShareToken(shareToken)
+}
+
+// UpdateOrderShareTokenByID replaces the token of the tracking link of an order, a nil token
+// revokes the link
+func (a *OrderApplication) UpdateOrderShareTokenByID(id uint64, shareToken *string) error {
+	return a.orderRepo.UpdateOrderShareTokenByID(id, shareToken)
+}
+
 func (a *OrderApplication) GetOrderDriverPoolByOrderIDAndDriverID(orderID uint64, driverID uint64) (*entity.OrderDriverPool, error) {
 	return a.orderRepo.GetOrderDriverPoolByOrderIDAndDriverID(orderID, driverID)
 }
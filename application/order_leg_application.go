@@ -0,0 +1,83 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// OrderLegApplication handles the business logic for the legs of the intercity orders
+type OrderLegApplication struct {
+	orderLegRepo repository.OrderLegRepository
+}
+
+var _ OrderLegApplicationInterface = &OrderLegApplication{}
+
+// OrderLegApplicationInterface defines the methods available for OrderLegApplication
+type OrderLegApplicationInterface interface {
+	UpdateOrderLegByIDAndStatus(id uint64, status entity.OrderLegStatus, orderLeg *entity.OrderLeg) (*entity.OrderLeg, error)
+	CancelOrderLegsByOrderID(orderID uint64) error
+	UpdateLastMileOrderLegByOrderID(orderID uint64, latitude float64, longitude float64) error
+	CountAvailableOrderLegsByDriverID(driverID uint64) (int64, error)
+	GetAllAvailableOrderLegsByDriverID(driverID uint64, page int, perPage int) ([]entity.OrderLeg, error)
+	GetAvailableOrderLegByIDAndDriverID(id uint64, driverID uint64) (*entity.OrderLeg, error)
+	CountOrderLegsByDriverIDExcludingStatus(driverID uint64, status []entity.OrderLegStatus) (int64, error)
+	GetAllOrderLegsByDriverIDExcludingStatus(driverID uint64, status []entity.OrderLegStatus, page int, perPage int) ([]entity.OrderLeg, error)
+	GetAllOrderLegsByOrderID(orderID uint64) ([]entity.OrderLeg, error)
+	GetOrderLegByID(id uint64) (*entity.OrderLeg, error)
+	GetOrderLegByIDAndDriverID(id uint64, driverID uint64) (*entity.OrderLeg, error)
+}
+
+// UpdateOrderLegByIDAndStatus updates the leg only if it's still in the given status, as a compare and set
+func (a *OrderLegApplication) UpdateOrderLegByIDAndStatus(id uint64, status entity.OrderLegStatus, orderLeg *entity.OrderLeg) (*entity.OrderLeg, error) {
+	return a.orderLegRepo.UpdateOrderLegByIDAndStatus(id, status, orderLeg)
+}
+
+// CancelOrderLegsByOrderID cancels the legs of an order that weren't completed yet
+func (a *OrderLegApplication) CancelOrderLegsByOrderID(orderID uint64) error {
+	return a.orderLegRepo.CancelOrderLegsByOrderID(orderID)
+}
+
+// UpdateLastMileOrderLegByOrderID moves the end of the last mile of an order to the given point, unless it was already delivered
+func (a *OrderLegApplication) UpdateLastMileOrderLegByOrderID(orderID uint64, latitude float64, longitude float64) error {
+	return a.orderLegRepo.UpdateLastMileOrderLegByOrderID(orderID, latitude, longitude)
+}
+
+// CountAvailableOrderLegsByDriverID counts the legs waiting for a driver that the driver can make an offer on, see availableOrderLegs
+func (a *OrderLegApplication) CountAvailableOrderLegsByDriverID(driverID uint64) (int64, error) {
+	return a.orderLegRepo.CountAvailableOrderLegsByDriverID(driverID)
+}
+
+// GetAllAvailableOrderLegsByDriverID retrieves the legs waiting for a driver that the driver can make an offer on, see availableOrderLegs
+func (a *OrderLegApplication) GetAllAvailableOrderLegsByDriverID(driverID uint64, page int, perPage int) ([]entity.OrderLeg, error) {
+	return a.orderLegRepo.GetAllAvailableOrderLegsByDriverID(driverID, page, perPage)
+}
+
+// GetAvailableOrderLegByIDAndDriverID retrieves a leg by its ID if the driver can make an offer on it, see availableOrderLegs
+func (a *OrderLegApplication) GetAvailableOrderLegByIDAndDriverID(id uint64, driverID uint64) (*entity.OrderLeg, error) {
+	return a.orderLegRepo.GetAvailableOrderLegByIDAndDriverID(id, driverID)
+}
+
+// CountOrderLegsByDriverIDExcludingStatus counts the legs assigned to a driver
+func (a *OrderLegApplication) CountOrderLegsByDriverIDExcludingStatus(driverID uint64, status []entity.OrderLegStatus) (int64, error) {
+	return a.orderLegRepo.CountOrderLegsByDriverIDExcludingStatus(driverID, status)
+}
+
+// GetAllOrderLegsByDriverIDExcludingStatus retrieves the legs assigned to a driver
+func (a *OrderLegApplication) GetAllOrderLegsByDriverIDExcludingStatus(driverID uint64, status []entity.OrderLegStatus, page int, perPage int) ([]entity.OrderLeg, error) {
+	return a.orderLegRepo.GetAllOrderLegsByDriverIDExcludingStatus(driverID, status, page, perPage)
+}
+
+// GetAllOrderLegsByOrderID retrieves the legs of an order in the order they are carried
+func (a *OrderLegApplication) GetAllOrderLegsByOrderID(orderID uint64) ([]entity.OrderLeg, error) {
+	return a.orderLegRepo.GetAllOrderLegsByOrderID(orderID)
+}
+
+// GetOrderLegByID retrieves a leg by its ID
+func (a *OrderLegApplication) GetOrderLegByID(id uint64) (*entity.OrderLeg, error) {
+	return a.orderLegRepo.GetOrderLegByID(id)
+}
+
+// GetOrderLegByIDAndDriverID retrieves a leg assigned to a driver by its ID
+func (a *OrderLegApplication) GetOrderLegByIDAndDriverID(id uint64, driverID uint64) (*entity.OrderLeg, error) {
+	return a.orderLegRepo.GetOrderLegByIDAndDriverID(id, driverID)
+}
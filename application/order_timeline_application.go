@@ -0,0 +1,28 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// OrderTimelineApplication handles the business logic for order timelines
+type OrderTimelineApplication struct {
+	orderTimelineRepo repository.OrderTimelineRepository
+}
+
+var _ OrderTimelineApplicationInterface = &OrderTimelineApplication{}
+
+// OrderTimelineApplicationInterface defines the methods available for OrderTimelineApplication
+type OrderTimelineApplicationInterface interface {
+	CreateOrderTimeline(orderTimeline *entity.OrderTimeline) (*entity.OrderTimeline, error)
+	GetAllOrderTimelinesByOrderID(orderID uint64) ([]entity.OrderTimeline, error)
+}
+
+// CreateOrderTimeline creates a new order timeline entry in the database
+func (a *OrderTimelineApplication) CreateOrderTimeline(orderTimeline *entity.OrderTimeline) (*entity.OrderTimeline, error) {
+	return a.orderTimelineRepo.CreateOrderTimeline(orderTimeline)
+}
+
+func (a *OrderTimelineApplication) GetAllOrderTimelinesByOrderID(orderID uint64) ([]entity.OrderTimeline, error) {
+	return a.orderTimelineRepo.GetAllOrderTimelinesByOrderID(orderID)
+}
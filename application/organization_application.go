@@ -0,0 +1,77 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// OrganizationApplication handles the business logic for the organizations and their members
+type OrganizationApplication struct {
+	organizationRepo repository.OrganizationRepository
+}
+
+var _ OrganizationApplicationInterface = &OrganizationApplication{}
+
+// OrganizationApplicationInterface defines the methods available for OrganizationApplication
+type OrganizationApplicationInterface interface {
+	CreateOrganization(organization *entity.Organization, ownerID uint64) (*entity.Organization, error)
+	UpdateOrganizationByID(id uint64, organization *entity.Organization) (*entity.Organization, error)
+	GetOrganizationByID(id uint64) (*entity.Organization, error)
+	GetAllOrganizationsByUserID(userID uint64) ([]entity.Organization, error)
+	CreateOrganizationMember(organizationMember *entity.OrganizationMember) (*entity.OrganizationMember, error)
+	UpdateOrganizationMemberByID(id uint64, organizationMember *entity.OrganizationMember) (*entity.OrganizationMember, error)
+	DeleteOrganizationMemberByID(id uint64) error
+	GetOrganizationMemberByOrganizationIDAndUserID(organizationID uint64, userID uint64) (*entity.OrganizationMember, error)
+	GetAllOrganizationMembersByOrganizationID(organizationID uint64) ([]entity.OrganizationMember, error)
+	CountOrganizationMembersByOrganizationIDAndRole(organizationID uint64, role entity.OrganizationRole) (int64, error)
+}
+
+// CreateOrganization creates a new organization in the database along with its owner
+func (a *OrganizationApplication) CreateOrganization(organization *entity.Organization, ownerID uint64) (*entity.Organization, error) {
+	return a.organizationRepo.CreateOrganization(organization, ownerID)
+}
+
+// UpdateOrganizationByID updates the organization
+func (a *OrganizationApplication) UpdateOrganizationByID(id uint64, organization *entity.Organization) (*entity.Organization, error) {
+	return a.organizationRepo.UpdateOrganizationByID(id, organization)
+}
+
+// GetOrganizationByID retrieves an organization by its ID
+func (a *OrganizationApplication) GetOrganizationByID(id uint64) (*entity.Organization, error) {
+	return a.organizationRepo.GetOrganizationByID(id)
+}
+
+// GetAllOrganizationsByUserID retrieves the organizations a user is a member of
+func (a *OrganizationApplication) GetAllOrganizationsByUserID(userID uint64) ([]entity.Organization, error) {
+	return a.organizationRepo.GetAllOrganizationsByUserID(userID)
+}
+
+// CreateOrganizationMember adds a user to an organization
+func (a *OrganizationApplication) CreateOrganizationMember(organizationMember *entity.OrganizationMember) (*entity.OrganizationMember, error) {
+	return a.organizationRepo.CreateOrganizationMember(organizationMember)
+}
+
+// UpdateOrganizationMemberByID updates the role of an organization member
+func (a *OrganizationApplication) UpdateOrganizationMemberByID(id uint64, organizationMember *entity.OrganizationMember) (*entity.OrganizationMember, error) {
+	return a.organizationRepo.UpdateOrganizationMemberByID(id, organizationMember)
+}
+
+// DeleteOrganizationMemberByID removes a user from an organization
+func (a *OrganizationApplication) DeleteOrganizationMemberByID(id uint64) error {
+	return a.organizationRepo.DeleteOrganizationMemberByID(id)
+}
+
+// GetOrganizationMemberByOrganizationIDAndUserID retrieves the membership of a user in an organization
+func (a *OrganizationApplication) GetOrganizationMemberByOrganizationIDAndUserID(organizationID uint64, userID uint64) (*entity.OrganizationMember, error) {
+	return a.organizationRepo.GetOrganizationMemberByOrganizationIDAndUserID(organizationID, userID)
+}
+
+// GetAllOrganizationMembersByOrganizationID retrieves the members of an organization
+func (a *OrganizationApplication) GetAllOrganizationMembersByOrganizationID(organizationID uint64) ([]entity.OrganizationMember, error) {
+	return a.organizationRepo.GetAllOrganizationMembersByOrganizationID(organizationID)
+}
+
+// CountOrganizationMembersByOrganizationIDAndRole counts the members of an organization with a role
+func (a *OrganizationApplication) CountOrganizationMembersByOrganizationIDAndRole(organizationID uint64, role entity.OrganizationRole) (int64, error) {
+	return a.organizationRepo.CountOrganizationMembersByOrganizationIDAndRole(organizationID, role)
+}
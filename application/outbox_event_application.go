@@ -0,0 +1,37 @@
+package application
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// OutboxEventApplication handles the business logic for the domain events of the outbox
+type OutboxEventApplication struct {
+	outboxEventRepo repository.OutboxEventRepository
+}
+
+var _ OutboxEventApplicationInterface = &OutboxEventApplication{}
+
+// OutboxEventApplicationInterface defines the methods available for OutboxEventApplication
+type OutboxEventApplicationInterface interface {
+	CreateOutboxEvent(event *entity.OutboxEvent) (*entity.OutboxEvent, error)
+	UpdateOutboxEventByID(id uint64, event *entity.OutboxEvent) (*entity.OutboxEvent, error)
+	GetAllDueOutboxEvents(now time.Time, limit int) ([]entity.OutboxEvent, error)
+}
+
+// CreateOutboxEvent stores a domain event to be dispatched
+func (a *OutboxEventApplication) CreateOutboxEvent(event *entity.OutboxEvent) (*entity.OutboxEvent, error) {
+	return a.outboxEventRepo.CreateOutboxEvent(event)
+}
+
+// UpdateOutboxEventByID updates the delivery state of a domain event
+func (a *OutboxEventApplication) UpdateOutboxEventByID(id uint64, event *entity.OutboxEvent) (*entity.OutboxEvent, error) {
+	return a.outboxEventRepo.UpdateOutboxEventByID(id, event)
+}
+
+// GetAllDueOutboxEvents retrieves the oldest event of each aggregate when it's due for delivery
+func (a *OutboxEventApplication) GetAllDueOutboxEvents(now time.Time, limit int) ([]entity.OutboxEvent, error) {
+	return a.outboxEventRepo.GetAllDueOutboxEvents(now, limit)
+}
@@ -0,0 +1,63 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// PromotionApplication handles the business logic for promotions
+type PromotionApplication struct {
+	promotionRepo repository.PromotionRepository
+}
+
+var _ PromotionApplicationInterface = &PromotionApplication{}
+
+// PromotionApplicationInterface defines the methods available for PromotionApplication
+type PromotionApplicationInterface interface {
+	CreatePromotion(promotion *entity.Promotion) (*entity.Promotion, error)
+	UpdatePromotionByID(id uint64, promotion *entity.Promotion) (*entity.Promotion, error)
+	GetAllPromotions() ([]entity.Promotion, error)
+	GetPromotionByID(id uint64) (*entity.Promotion, error)
+	GetPromotionByCode(code string) (*entity.Promotion, error)
+	RedeemPromotion(promotionRedemption *entity.PromotionRedemption) (*entity.PromotionRedemption, error)
+	CountPromotionRedemptionsByPromotionID(promotionID uint64) (int64, error)
+	CountPromotionRedemptionsByPromotionIDAndUserID(promotionID uint64, userID uint64) (int64, error)
+	BackfillPromotionRedemptionsCounts() error
+}
+
+// CreatePromotion creates a new promotion in the database
+func (a *PromotionApplication) CreatePromotion(promotion *entity.Promotion) (*entity.Promotion, error) {
+	return a.promotionRepo.CreatePromotion(promotion)
+}
+
+func (a *PromotionApplication) UpdatePromotionByID(id uint64, promotion *entity.Promotion) (*entity.Promotion, error) {
+	return a.promotionRepo.UpdatePromotionByID(id, promotion)
+}
+
+func (a *PromotionApplication) GetAllPromotions() ([]entity.Promotion, error) {
+	return a.promotionRepo.GetAllPromotions()
+}
+
+func (a *PromotionApplication) GetPromotionByID(id uint64) (*entity.Promotion, error) {
+	return a.promotionRepo.GetPromotionByID(id)
+}
+
+func (a *PromotionApplication) GetPromotionByCode(code string) (*entity.Promotion, error) {
+	return a.promotionRepo.GetPromotionByCode(code)
+}
+
+func (a *PromotionApplication) RedeemPromotion(promotionRedemption *entity.PromotionRedemption) (*entity.PromotionRedemption, error) {
+	return a.promotionRepo.RedeemPromotion(promotionRedemption)
+}
+
+func (a *PromotionApplication) CountPromotionRedemptionsByPromotionID(promotionID uint64) (int64, error) {
+	return a.promotionRepo.CountPromotionRedemptionsByPromotionID(promotionID)
+}
+
+func (a *PromotionApplication) CountPromotionRedemptionsByPromotionIDAndUserID(promotionID uint64, userID uint64) (int64, error) {
+	return a.promotionRepo.CountPromotionRedemptionsByPromotionIDAndUserID(promotionID, userID)
+}
+
+func (a *PromotionApplication) BackfillPromotionRedemptionsCounts() error {
+	return a.promotionRepo.BackfillPromotionRedemptionsCounts()
+}
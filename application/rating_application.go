@@ -15,9 +15,24 @@ var _ RatingApplicationInterface = &RatingApplication{}
 // RatingApplicationInterface defines the methods available for RatingApplication
 type RatingApplicationInterface interface {
 	CreateRating(balance *entity.Rating) (*entity.Rating, error)
+	GetRatingByOrderIDAndType(orderID uint64, ratingType entity.RatingType) (*entity.Rating, error)
+	CountRatingsByRatedUserIDAndType(ratedUserID uint64, ratingType entity.RatingType) (int64, error)
+	GetAllRatingsByRatedUserIDAndType(ratedUserID uint64, ratingType entity.RatingType, page int, perPage int) ([]entity.Rating, error)
 }
 
 // CreateUser creates a new user in the database
 func (a *RatingApplication) CreateRating(rating *entity.Rating) (*entity.Rating, error) {
 	return a.ratingRepo.CreateRating(rating)
 }
+
+func (a *RatingApplication) GetRatingByOrderIDAndType(orderID uint64, ratingType entity.RatingType) (*entity.Rating, error) {
+	return a.ratingRepo.GetRatingByOrderIDAndType(orderID, ratingType)
+}
+
+func (a *RatingApplication) CountRatingsByRatedUserIDAndType(ratedUserID uint64, ratingType entity.RatingType) (int64, error) {
+	return a.ratingRepo.CountRatingsByRatedUserIDAndType(ratedUserID, ratingType)
+}
+
+func (a *RatingApplication) GetAllRatingsByRatedUserIDAndType(ratedUserID uint64, ratingType entity.RatingType, page int, perPage int) ([]entity.Rating, error) {
+	return a.ratingRepo.GetAllRatingsByRatedUserIDAndType(ratedUserID, ratingType, page, perPage)
+}
@@ -0,0 +1,50 @@
+package application
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// RecurringOrderApplication handles the business logic for recurring orders
+type RecurringOrderApplication struct {
+	recurringOrderRepo repository.RecurringOrderRepository
+}
+
+var _ RecurringOrderApplicationInterface = &RecurringOrderApplication{}
+
+// RecurringOrderApplicationInterface defines the methods available for RecurringOrderApplication
+type RecurringOrderApplicationInterface interface {
+	CreateRecurringOrder(recurringOrder *entity.RecurringOrder) (*entity.RecurringOrder, error)
+	UpdateRecurringOrderByID(id uint64, recurringOrder *entity.RecurringOrder) (*entity.RecurringOrder, error)
+	CountRecurringOrdersByUserID(userID uint64) (int64, error)
+	GetAllRecurringOrdersByUserID(userID uint64, page int, perPage int) ([]entity.RecurringOrder, error)
+	GetAllActiveRecurringOrdersDueBefore(before time.Time) ([]entity.RecurringOrder, error)
+	GetRecurringOrderByIDAndUserID(id uint64, userID uint64) (*entity.RecurringOrder, error)
+}
+
+// CreateRecurringOrder creates a new recurring order in the database
+func (a *RecurringOrderApplication) CreateRecurringOrder(recurringOrder *entity.RecurringOrder) (*entity.RecurringOrder, error) {
+	return a.recurringOrderRepo.CreateRecurringOrder(recurringOrder)
+}
+
+func (a *RecurringOrderApplication) UpdateRecurringOrderByID(id uint64, recurringOrder *entity.RecurringOrder) (*entity.RecurringOrder, error) {
+	return a.recurringOrderRepo.UpdateRecurringOrderByID(id, recurringOrder)
+}
+
+func (a *RecurringOrderApplication) CountRecurringOrdersByUserID(userID uint64) (int64, error) {
+	return a.recurringOrderRepo.CountRecurringOrdersByUserID(userID)
+}
+
+func (a *RecurringOrderApplication) GetAllRecurringOrdersByUserID(userID uint64, page int, perPage int) ([]entity.RecurringOrder, error) {
+	return a.recurringOrderRepo.GetAllRecurringOrdersByUserID(userID, page, perPage)
+}
+
+func (a *RecurringOrderApplication) GetAllActiveRecurringOrdersDueBefore(before time.Time) ([]entity.RecurringOrder, error) {
+	return a.recurringOrderRepo.GetAllActiveRecurringOrdersDueBefore(before)
+}
+
+func (a *RecurringOrderApplication) GetRecurringOrderByIDAndUserID(id uint64, userID uint64) (*entity.RecurringOrder, error) {
+	return a.recurringOrderRepo.GetRecurringOrderByIDAndUserID(id, userID)
+}
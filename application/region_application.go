@@ -0,0 +1,35 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// RegionApplication handles the business logic for regions
+type RegionApplication struct {
+	regionRepo repository.RegionRepository
+}
+
+var _ RegionApplicationInterface = &RegionApplication{}
+
+// RegionApplicationInterface defines the methods available for RegionApplication
+type RegionApplicationInterface interface {
+	CountRegions() (int64, error)
+	GetAllRegions(page int, perPage int) ([]entity.Region, error)
+	GetRegionByID(uint64) (*entity.Region, error)
+}
+
+// CountRegions counts the regions
+func (a *RegionApplication) CountRegions() (int64, error) {
+	return a.regionRepo.CountRegions()
+}
+
+// GetAllRegions retrieves a page of the regions
+func (a *RegionApplication) GetAllRegions(page int, perPage int) ([]entity.Region, error) {
+	return a.regionRepo.GetAllRegions(page, perPage)
+}
+
+// GetRegionByID retrieves a region by its ID
+func (a *RegionApplication) GetRegionByID(regionID uint64) (*entity.Region, error) {
+	return a.regionRepo.GetRegionByID(regionID)
+}
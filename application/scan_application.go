@@ -0,0 +1,23 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// ScanApplication handles the business logic for the scans of the parcel labels
+type ScanApplication struct {
+	scanRepo repository.ScanRepository
+}
+
+var _ ScanApplicationInterface = &ScanApplication{}
+
+// ScanApplicationInterface defines the methods available for ScanApplication
+type ScanApplicationInterface interface {
+	CreateScan(scan *entity.Scan) (*entity.Scan, error)
+}
+
+// CreateScan records a scan of a parcel label and adds it to the timeline of its order
+func (a *ScanApplication) CreateScan(scan *entity.Scan) (*entity.Scan, error) {
+	return a.scanRepo.CreateScan(scan)
+}
@@ -0,0 +1,68 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// ServiceAreaApplication handles the business logic for service areas
+type ServiceAreaApplication struct {
+	serviceAreaRepo repository.ServiceAreaRepository
+}
+
+var _ ServiceAreaApplicationInterface = &ServiceAreaApplication{}
+
+// ServiceAreaApplicationInterface defines the methods available for ServiceAreaApplication
+type ServiceAreaApplicationInterface interface {
+	CreateServiceArea(serviceArea *entity.ServiceArea) (*entity.ServiceArea, error)
+	UpdateServiceAreaByID(id uint64, serviceArea *entity.ServiceArea) (*entity.ServiceArea, error)
+	DeleteServiceAreaByID(id uint64) error
+	UpdateServiceAreaSurgeByID(id uint64, surgeMultiplier float64) error
+	CountOpenOrdersByServiceAreaID(id uint64) (int64, error)
+	CountAvailableDriversByServiceAreaID(id uint64) (int64, error)
+	CountActiveServiceAreas() (int64, error)
+	GetAllServiceAreas() ([]entity.ServiceArea, error)
+	GetServiceAreaByID(id uint64) (*entity.ServiceArea, error)
+	GetActiveServiceAreaByCoordinates(latitude float64, longitude float64) (*entity.ServiceArea, error)
+}
+
+// CreateServiceArea creates a new service area in the database
+func (a *ServiceAreaApplication) CreateServiceArea(serviceArea *entity.ServiceArea) (*entity.ServiceArea, error) {
+	return a.serviceAreaRepo.CreateServiceArea(serviceArea)
+}
+
+func (a *ServiceAreaApplication) UpdateServiceAreaByID(id uint64, serviceArea *entity.ServiceArea) (*entity.ServiceArea, error) {
+	return a.serviceAreaRepo.UpdateServiceAreaByID(id, serviceArea)
+}
+
+func (a *ServiceAreaApplication) DeleteServiceAreaByID(id uint64) error {
+	return a.serviceAreaRepo.DeleteServiceAreaByID(id)
+}
+
+func (a *ServiceAreaApplication) UpdateServiceAreaSurgeByID(id uint64, surgeMultiplier float64) error {
+	return a.serviceAreaRepo.UpdateServiceAreaSurgeByID(id, surgeMultiplier)
+}
+
+func (a *ServiceAreaApplication) CountOpenOrdersByServiceAreaID(id uint64) (int64, error) {
+	return a.serviceAreaRepo.CountOpenOrdersByServiceAreaID(id)
+}
+
+func (a *ServiceAreaApplication) CountAvailableDriversByServiceAreaID(id uint64) (int64, error) {
+	return a.serviceAreaRepo.CountAvailableDriversByServiceAreaID(id)
+}
+
+func (a *ServiceAreaApplication) CountActiveServiceAreas() (int64, error) {
+	return a.serviceAreaRepo.CountActiveServiceAreas()
+}
+
+func (a *ServiceAreaApplication) GetAllServiceAreas() ([]entity.ServiceArea, error) {
+	return a.serviceAreaRepo.GetAllServiceAreas()
+}
+
+func (a *ServiceAreaApplication) GetServiceAreaByID(id uint64) (*entity.ServiceArea, error) {
+	return a.serviceAreaRepo.GetServiceAreaByID(id)
+}
+
+func (a *ServiceAreaApplication) GetActiveServiceAreaByCoordinates(latitude float64, longitude float64) (*entity.ServiceArea, error) {
+	return a.serviceAreaRepo.GetActiveServiceAreaByCoordinates(latitude, longitude)
+}
@@ -0,0 +1,71 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// TicketApplication handles the business logic for support tickets
+type TicketApplication struct {
+	ticketRepo repository.TicketRepository
+}
+
+var _ TicketApplicationInterface = &TicketApplication{}
+
+// TicketApplicationInterface defines the methods available for TicketApplication
+type TicketApplicationInterface interface {
+	CreateTicket(ticket *entity.Ticket) (*entity.Ticket, error)
+	UpdateTicketByID(id uint64, ticket *entity.Ticket) (*entity.Ticket, error)
+	GetTicketByID(id uint64) (*entity.Ticket, error)
+	GetAllTickets(status string, page int, perPage int) ([]entity.Ticket, error)
+	CountTickets(status string) (int64, error)
+	GetAllTicketsByUserID(userID uint64, page int, perPage int) ([]entity.Ticket, error)
+	CountTicketsByUserID(userID uint64) (int64, error)
+	CreateTicketMessage(message *entity.TicketMessage) (*entity.TicketMessage, error)
+	ResolveTicket(ticket *entity.Ticket, credit *entity.Credit, balance *entity.Balance) (*entity.Ticket, error)
+}
+
+// CreateTicket creates a new ticket in the database
+func (a *TicketApplication) CreateTicket(ticket *entity.Ticket) (*entity.Ticket, error) {
+	return a.ticketRepo.CreateTicket(ticket)
+}
+
+// UpdateTicketByID updates the ticket
+func (a *TicketApplication) UpdateTicketByID(id uint64, ticket *entity.Ticket) (*entity.Ticket, error) {
+	return a.ticketRepo.UpdateTicketByID(id, ticket)
+}
+
+// GetTicketByID retrieves a ticket by its ID
+func (a *TicketApplication) GetTicketByID(id uint64) (*entity.Ticket, error) {
+	return a.ticketRepo.GetTicketByID(id)
+}
+
+// GetAllTickets retrieves a paginated list of the tickets
+func (a *TicketApplication) GetAllTickets(status string, page int, perPage int) ([]entity.Ticket, error) {
+	return a.ticketRepo.GetAllTickets(status, page, perPage)
+}
+
+// CountTickets counts the tickets
+func (a *TicketApplication) CountTickets(status string) (int64, error) {
+	return a.ticketRepo.CountTickets(status)
+}
+
+// GetAllTicketsByUserID retrieves a paginated list of the tickets of a user
+func (a *TicketApplication) GetAllTicketsByUserID(userID uint64, page int, perPage int) ([]entity.Ticket, error) {
+	return a.ticketRepo.GetAllTicketsByUserID(userID, page, perPage)
+}
+
+// CountTicketsByUserID counts the tickets of a user
+func (a *TicketApplication) CountTicketsByUserID(userID uint64) (int64, error) {
+	return a.ticketRepo.CountTicketsByUserID(userID)
+}
+
+// CreateTicketMessage adds a message to the history of a ticket
+func (a *TicketApplication) CreateTicketMessage(message *entity.TicketMessage) (*entity.TicketMessage, error) {
+	return a.ticketRepo.CreateTicketMessage(message)
+}
+
+// ResolveTicket resolves the ticket, recording its refund and driver adjustment
+func (a *TicketApplication) ResolveTicket(ticket *entity.Ticket, credit *entity.Credit, balance *entity.Balance) (*entity.Ticket, error) {
+	return a.ticketRepo.ResolveTicket(ticket, credit, balance)
+}
@@ -0,0 +1,48 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// TripApplication handles the business logic for trips
+type TripApplication struct {
+	tripRepo repository.TripRepository
+}
+
+var _ TripApplicationInterface = &TripApplication{}
+
+// TripApplicationInterface defines the methods available for TripApplication
+type TripApplicationInterface interface {
+	AddOrderToTrip(driverID uint64, order *entity.Order) (*entity.Trip, error)
+	RemoveOrderFromTrips(orderID uint64) error
+	UpdateTripByID(id uint64, trip *entity.Trip) (*entity.Trip, error)
+	UpdateTripStopByID(id uint64, tripStop *entity.TripStop) (*entity.TripStop, error)
+	GetActiveTripByDriverID(driverID uint64) (*entity.Trip, error)
+	GetTripStopByIDAndTripID(id uint64, tripID uint64) (*entity.TripStop, error)
+}
+
+// AddOrderToTrip appends the stops of an order to the driver's active trip
+func (a *TripApplication) AddOrderToTrip(driverID uint64, order *entity.Order) (*entity.Trip, error) {
+	return a.tripRepo.AddOrderToTrip(driverID, order)
+}
+
+func (a *TripApplication) RemoveOrderFromTrips(orderID uint64) error {
+	return a.tripRepo.RemoveOrderFromTrips(orderID)
+}
+
+func (a *TripApplication) UpdateTripByID(id uint64, trip *entity.Trip) (*entity.Trip, error) {
+	return a.tripRepo.UpdateTripByID(id, trip)
+}
+
+func (a *TripApplication) UpdateTripStopByID(id uint64, tripStop *entity.TripStop) (*entity.TripStop, error) {
+	return a.tripRepo.UpdateTripStopByID(id, tripStop)
+}
+
+func (a *TripApplication) GetActiveTripByDriverID(driverID uint64) (*entity.Trip, error) {
+	return a.tripRepo.GetActiveTripByDriverID(driverID)
+}
+
+func (a *TripApplication) GetTripStopByIDAndTripID(id uint64, tripID uint64) (*entity.TripStop, error) {
+	return a.tripRepo.GetTripStopByIDAndTripID(id, tripID)
+}
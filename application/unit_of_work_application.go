@@ -0,0 +1,23 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// UnitOfWorkApplication runs multi-step changes in a single transaction
+type UnitOfWorkApplication struct {
+	unitOfWork repository.UnitOfWork
+}
+
+var _ UnitOfWorkApplicationInterface = &UnitOfWorkApplication{}
+
+// UnitOfWorkApplicationInterface defines the methods available for UnitOfWorkApplication
+type UnitOfWorkApplicationInterface interface {
+	Do(fn func(tx repository.Transaction) error) error
+}
+
+// Do runs fn in a transaction. The changes made through the repositories of tx, including the
+// events emitted to the outbox, are committed together if fn returns nil and rolled back otherwise.
+func (a *UnitOfWorkApplication) Do(fn func(tx repository.Transaction) error) error {
+	return a.unitOfWork.Do(fn)
+}
@@ -22,6 +22,9 @@ type UserApplicationInterface interface {
 	GetAllUsers(page int, perPage int) ([]entity.User, error)
 	GetUserByID(uint64) (*entity.User, error)
 	GetUserByPhone(string) (*entity.User, error)
+	GetUserByReferralCode(string) (*entity.User, error)
+	AssignUserReferralCodeByID(uint64) (string, error)
+	LockUserByID(uint64) error
 }
 
 // CreateUser creates a new user in the database
@@ -58,3 +61,17 @@ func (a *UserApplication) GetUserByID(id uint64) (*entity.User, error) {
 func (a *UserApplication) GetUserByPhone(phone string) (*entity.User, error) {
 	return a.userRepo.GetUserByPhone(phone)
 }
+
+func (a *UserApplication) GetUserByReferralCode(referralCode string) (*entity.User, error) {
+	return a.userRepo.GetUserByReferralCode(referralCode)
+}
+
+// AssignUserReferralCodeByID gives a referral code to a user registered before referral codes existed
+func (a *UserApplication) AssignUserReferralCodeByID(id uint64) (string, error) {
+	return a.userRepo.AssignUserReferralCodeByID(id)
+}
+
+// LockUserByID locks the user's row until the end of the transaction
+func (a *UserApplication) LockUserByID(id uint64) error {
+	return a.userRepo.LockUserByID(id)
+}
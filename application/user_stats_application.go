@@ -0,0 +1,23 @@
+package application
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// UserStatsApplication handles the business logic for user statistics
+type UserStatsApplication struct {
+	userStatsRepo repository.UserStatsRepository
+}
+
+var _ UserStatsApplicationInterface = &UserStatsApplication{}
+
+// UserStatsApplicationInterface defines the methods available for UserStatsApplication
+type UserStatsApplicationInterface interface {
+	GetUserStatsByUserID(userID uint64) (*entity.UserStats, error)
+}
+
+// GetUserStatsByUserID calculates the statistics of a user
+func (a *UserStatsApplication) GetUserStatsByUserID(userID uint64) (*entity.UserStats, error) {
+	return a.userStatsRepo.GetUserStatsByUserID(userID)
+}
@@ -0,0 +1,91 @@
+package application
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// WebhookApplication handles the business logic for the webhook subscriptions and their deliveries
+type WebhookApplication struct {
+	webhookRepo repository.WebhookRepository
+}
+
+var _ WebhookApplicationInterface = &WebhookApplication{}
+
+// WebhookApplicationInterface defines the methods available for WebhookApplication
+type WebhookApplicationInterface interface {
+	CreateWebhookSubscription(webhookSubscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error)
+	UpdateWebhookSubscriptionByID(id uint64, webhookSubscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error)
+	DeleteWebhookSubscriptionByID(id uint64) error
+	GetWebhookSubscriptionByIDAndUserID(id uint64, userID uint64) (*entity.WebhookSubscription, error)
+	GetAllWebhookSubscriptionsByUserID(userID uint64) ([]entity.WebhookSubscription, error)
+	GetAllActiveWebhookSubscriptionsByUserID(userID uint64) ([]entity.WebhookSubscription, error)
+	CreateWebhookDeliveries(webhookDeliveries []entity.WebhookDelivery) error
+	UpdateWebhookDeliveryByID(id uint64, webhookDelivery *entity.WebhookDelivery) (*entity.WebhookDelivery, error)
+	GetWebhookDeliveryByID(id uint64) (*entity.WebhookDelivery, error)
+	GetAllDueWebhookDeliveries(now time.Time, limit int) ([]entity.WebhookDelivery, error)
+	GetAllWebhookDeliveries(subscriptionID uint64, status string, page int, perPage int) ([]entity.WebhookDelivery, error)
+	CountWebhookDeliveries(subscriptionID uint64, status string) (int64, error)
+}
+
+// CreateWebhookSubscription creates a new webhook subscription in the database
+func (a *WebhookApplication) CreateWebhookSubscription(webhookSubscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	return a.webhookRepo.CreateWebhookSubscription(webhookSubscription)
+}
+
+// UpdateWebhookSubscriptionByID updates the webhook subscription
+func (a *WebhookApplication) UpdateWebhookSubscriptionByID(id uint64, webhookSubscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	return a.webhookRepo.UpdateWebhookSubscriptionByID(id, webhookSubscription)
+}
+
+// DeleteWebhookSubscriptionByID deletes the webhook subscription along with its deliveries
+func (a *WebhookApplication) DeleteWebhookSubscriptionByID(id uint64) error {
+	return a.webhookRepo.DeleteWebhookSubscriptionByID(id)
+}
+
+// GetWebhookSubscriptionByIDAndUserID retrieves a webhook subscription of a user by its ID
+func (a *WebhookApplication) GetWebhookSubscriptionByIDAndUserID(id uint64, userID uint64) (*entity.WebhookSubscription, error) {
+	return a.webhookRepo.GetWebhookSubscriptionByIDAndUserID(id, userID)
+}
+
+// GetAllWebhookSubscriptionsByUserID retrieves the webhook subscriptions of a user
+func (a *WebhookApplication) GetAllWebhookSubscriptionsByUserID(userID uint64) ([]entity.WebhookSubscription, error) {
+	return a.webhookRepo.GetAllWebhookSubscriptionsByUserID(userID)
+}
+
+// GetAllActiveWebhookSubscriptionsByUserID retrieves the active webhook subscriptions of a user
+func (a *WebhookApplication) GetAllActiveWebhookSubscriptionsByUserID(userID uint64) ([]entity.WebhookSubscription, error) {
+	return a.webhookRepo.GetAllActiveWebhookSubscriptionsByUserID(userID)
+}
+
+// CreateWebhookDeliveries stores the deliveries of an event
+func (a *WebhookApplication) CreateWebhookDeliveries(webhookDeliveries []entity.WebhookDelivery) error {
+	return a.webhookRepo.CreateWebhookDeliveries(webhookDeliveries)
+}
+
+// UpdateWebhookDeliveryByID updates the webhook delivery
+func (a *WebhookApplication) UpdateWebhookDeliveryByID(id uint64, webhookDelivery *entity.WebhookDelivery) (*entity.WebhookDelivery, error) {
+	return a.webhookRepo.UpdateWebhookDeliveryByID(id, webhookDelivery)
+}
+
+// GetWebhookDeliveryByID retrieves a webhook delivery by its ID
+func (a *WebhookApplication) GetWebhookDeliveryByID(id uint64) (*entity.WebhookDelivery, error) {
+	return a.webhookRepo.GetWebhookDeliveryByID(id)
+}
+
+// GetAllDueWebhookDeliveries retrieves the pending webhook deliveries due for an attempt, in the order they were made
+func (a *WebhookApplication) GetAllDueWebhookDeliveries(now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	return a.webhookRepo.GetAllDueWebhookDeliveries(now, limit)
+}
+
+// GetAllWebhookDeliveries retrieves a paginated list of the webhook deliveries, optionally filtered by subscription and status
+func (a *WebhookApplication) GetAllWebhookDeliveries(subscriptionID uint64, status string, page int, perPage int) ([]entity.WebhookDelivery, error) {
+	return a.webhookRepo.GetAllWebhookDeliveries(subscriptionID, status, page, perPage)
+}
+
+// CountWebhookDeliveries counts the webhook deliveries, optionally filtered by subscription and status
+func (a *WebhookApplication) CountWebhookDeliveries(subscriptionID uint64, status string) (int64, error) {
+	return a.webhookRepo.CountWebhookDeliveries(subscriptionID, status)
+}
@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Address represent a place saved in a user's address book, picked instead of retyping the
+// coordinates of an order
+type Address struct {
+	ID          uint64    `gorm:"primary_key;auto_increment" json:"id"`
+	UserID      uint64    `gorm:"index;" json:"user_id"`
+	LocationID  uint64    `gorm:"index;" json:"location_id"`
+	Label       string    `gorm:"size:100;not null;" json:"label" validate:"required,max=100"`
+	Latitude    float64   `gorm:"type:decimal(10,8);not null;" json:"latitude" validate:"required,latitude"`
+	Longitude   float64   `gorm:"type:decimal(11,8);not null;" json:"longitude" validate:"required,longitude"`
+	AddressLine *string   `gorm:"type:varchar(255);default:null" json:"address_line" validate:"omitempty,max=255"`
+	Building    *string   `gorm:"type:varchar(100);default:null" json:"building" validate:"omitempty,max=100"`
+	Floor       *string   `gorm:"type:varchar(50);default:null" json:"floor" validate:"omitempty,max=50"`
+	Notes       *string   `gorm:"type:varchar(255);default:null" json:"notes" validate:"omitempty,max=255"`
+	IsDefault   bool      `gorm:"default:false" json:"is_default"`
+	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	Location    Location  `gorm:"foreignKey:LocationID" json:"location"`
+}
+
+type AddressPublicData struct {
+	ID          uint64              `json:"id"`
+	LocationID  uint64              `json:"location_id"`
+	Label       string              `json:"label"`
+	Latitude    float64             `json:"latitude"`
+	Longitude   float64             `json:"longitude"`
+	AddressLine *string             `json:"address_line"`
+	Building    *string             `json:"building"`
+	Floor       *string             `json:"floor"`
+	Notes       *string             `json:"notes"`
+	IsDefault   bool                `json:"is_default"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	Location    *LocationPublicData `json:"location"`
+}
+
+// AddressPlace is the copy of an address kept on the orders it was picked for, so editing or
+// deleting the address doesn't change the orders already placed
+type AddressPlace struct {
+	AddressID   uint64  `json:"address_id"`
+	Label       string  `json:"label"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	AddressLine *string `json:"address_line"`
+	Building    *string `json:"building"`
+	Floor       *string `json:"floor"`
+	Notes       *string `json:"notes"`
+}
+
+// Place returns the copy of the address kept on an order
+func (a *Address) Place() datatypes.JSON {
+	place, _ := json.Marshal(&AddressPlace{
+		AddressID:   a.ID,
+		Label:       a.Label,
+		Latitude:    a.Latitude,
+		Longitude:   a.Longitude,
+		AddressLine: a.AddressLine,
+		Building:    a.Building,
+		Floor:       a.Floor,
+		Notes:       a.Notes,
+	})
+	return datatypes.JSON(place)
+}
+
+// Line returns the text of the address given to the driver, its label when it has no address line
+func (a *Address) Line() *string {
+	if a.AddressLine != nil && *a.AddressLine != "" {
+		return a.AddressLine
+	}
+	label := a.Label
+	return &label
+}
+
+// PublicData returns a copy of the address's public information
+func (a *Address) PublicData(languageCode string) interface{} {
+	var locationPublicData *LocationPublicData
+	if a.Location.ID != 0 {
+		locationPublicData = a.Location.PublicData(languageCode).(*LocationPublicData)
+	}
+
+	return &AddressPublicData{
+		ID:          a.ID,
+		LocationID:  a.LocationID,
+		Label:       a.Label,
+		Latitude:    a.Latitude,
+		Longitude:   a.Longitude,
+		AddressLine: a.AddressLine,
+		Building:    a.Building,
+		Floor:       a.Floor,
+		Notes:       a.Notes,
+		IsDefault:   a.IsDefault,
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+		Location:    locationPublicData,
+	}
+}
+
+// placePublicData decodes the copy of a saved address kept on an order, nil when none was picked
+func placePublicData(place datatypes.JSON) *AddressPlace {
+	if len(place) == 0 {
+		return nil
+	}
+
+	var addressPlace AddressPlace
+	if err := json.Unmarshal(place, &addressPlace); err != nil {
+		return nil
+	}
+	return &addressPlace
+}
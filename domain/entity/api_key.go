@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// APIKey represent a key an organization's systems authenticate with instead of a user's tokens.
+// Requests made with the key act as the member who created it. Only the SHA-256 hash of the key
+// is stored, the key itself is shown once when it's created.
+type APIKey struct {
+	ID             uint64     `gorm:"primary_key;auto_increment" json:"id"`
+	OrganizationID uint64     `gorm:"index;" json:"organization_id"`
+	UserID         uint64     `gorm:"index;" json:"user_id"`
+	Name           string     `gorm:"size:255;" json:"name" validate:"required,max=255"`
+	Prefix         string     `gorm:"size:16;" json:"prefix"`
+	KeyHash        string     `gorm:"size:64;uniqueIndex;" json:"-"`
+	LastUsedAt     *time.Time `gorm:"default:null" json:"last_used_at"`
+	RevokedAt      *time.Time `gorm:"default:null" json:"revoked_at"`
+	CreatedAt      time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+type APIKeyPublicData struct {
+	ID             uint64     `json:"id"`
+	OrganizationID uint64     `json:"organization_id"`
+	UserID         uint64     `json:"user_id"`
+	Name           string     `json:"name"`
+	Prefix         string     `json:"prefix"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// PublicData returns a copy of the API key's public information
+func (k *APIKey) PublicData() interface{} {
+	return &APIKeyPublicData{
+		ID:             k.ID,
+		OrganizationID: k.OrganizationID,
+		UserID:         k.UserID,
+		Name:           k.Name,
+		Prefix:         k.Prefix,
+		LastUsedAt:     k.LastUsedAt,
+		RevokedAt:      k.RevokedAt,
+		CreatedAt:      k.CreatedAt,
+	}
+}
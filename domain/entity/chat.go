@@ -0,0 +1,82 @@
+package entity
+
+import "time"
+
+// ChatChannel represent a conversation hosted by the local chat provider, such as the chat of an order
+type ChatChannel struct {
+	ID          string       `gorm:"primaryKey;size:255" json:"id"`
+	CreatedByID string       `gorm:"size:255;" json:"created_by_id"`
+	CreatedAt   time.Time    `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	Members     []ChatMember `gorm:"foreignKey:ChannelID" json:"members"`
+}
+
+type ChatChannelPublicData struct {
+	ID        string        `json:"id"`
+	CreatedAt time.Time     `json:"created_at"`
+	Members   []interface{} `json:"members"`
+}
+
+// ChatMember represent a participant of a chat channel. Chat user IDs are prefixed with the
+// side of the order they take part as, such as "client-1" or "driver-2".
+type ChatMember struct {
+	ID                uint64    `gorm:"primary_key;auto_increment" json:"id"`
+	ChannelID         string    `gorm:"size:255;uniqueIndex:idx_chat_members_channel_id_user_id;" json:"channel_id"`
+	UserID            string    `gorm:"size:255;uniqueIndex:idx_chat_members_channel_id_user_id;index;" json:"user_id"`
+	LastReadMessageID uint64    `gorm:"default:0" json:"last_read_message_id"`
+	CreatedAt         time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+type ChatMemberPublicData struct {
+	UserID            string `json:"user_id"`
+	LastReadMessageID uint64 `json:"last_read_message_id"`
+}
+
+// ChatMessage represent a message sent to a chat channel
+type ChatMessage struct {
+	ID        uint64    `gorm:"primary_key;auto_increment" json:"id"`
+	ChannelID string    `gorm:"size:255;index;" json:"channel_id"`
+	UserID    string    `gorm:"size:255;" json:"user_id"`
+	Text      string    `gorm:"type:text;not null;" json:"text" validate:"required,max=5000"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+type ChatMessagePublicData struct {
+	ID        uint64    `json:"id"`
+	ChannelID string    `json:"channel_id"`
+	UserID    string    `json:"user_id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PublicData returns a copy of the chat channel's public information
+func (c *ChatChannel) PublicData() interface{} {
+	var memberPublicData []interface{}
+	for _, member := range c.Members {
+		memberPublicData = append(memberPublicData, member.PublicData())
+	}
+
+	return &ChatChannelPublicData{
+		ID:        c.ID,
+		CreatedAt: c.CreatedAt,
+		Members:   memberPublicData,
+	}
+}
+
+// PublicData returns a copy of the chat member's public information
+func (m *ChatMember) PublicData() interface{} {
+	return &ChatMemberPublicData{
+		UserID:            m.UserID,
+		LastReadMessageID: m.LastReadMessageID,
+	}
+}
+
+// PublicData returns a copy of the chat message's public information
+func (m *ChatMessage) PublicData() interface{} {
+	return &ChatMessagePublicData{
+		ID:        m.ID,
+		ChannelID: m.ChannelID,
+		UserID:    m.UserID,
+		Text:      m.Text,
+		CreatedAt: m.CreatedAt,
+	}
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// ChatOutbox represent a pending change to the members of a chat channel. The changes are written
+// along with the order updates and applied to the chat provider by a worker, retrying the ones that fail.
+type ChatOutbox struct {
+	ID            uint64           `gorm:"primary_key;auto_increment" json:"id"`
+	Action        ChatOutboxAction `gorm:"size:255;" json:"action"`
+	ChannelID     string           `gorm:"size:255;index;" json:"channel_id"`
+	UserID        string           `gorm:"size:255;" json:"user_id"`
+	Attempts      int              `gorm:"default:0" json:"attempts"`
+	LastError     string           `gorm:"type:text;" json:"last_error"`
+	NextAttemptAt time.Time        `gorm:"default:CURRENT_TIMESTAMP;index;" json:"next_attempt_at"`
+	ProcessedAt   *time.Time       `gorm:"default:null;index;" json:"processed_at"`
+	CreatedAt     time.Time        `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+type ChatOutboxAction string
+
+const (
+	// ChatCreateChannelAction creates a channel with the user as its first member
+	ChatCreateChannelAction ChatOutboxAction = "create_channel"
+	// ChatAddMemberAction adds the user to a channel
+	ChatAddMemberAction ChatOutboxAction = "add_member"
+	// ChatRemoveMemberAction removes the user from a channel
+	ChatRemoveMemberAction ChatOutboxAction = "remove_member"
+)
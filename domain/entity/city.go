@@ -2,15 +2,21 @@ package entity
 
 import (
 	"time"
+
+	"gorm.io/datatypes"
 )
 
 // City represents a city with relevant fields.
 type City struct {
-	ID       uint64 `gorm:"primary_key;auto_increment" json:"id"`
+	ID uint64 `gorm:"primary_key;auto_increment" json:"id"`
 	// Name     string `gorm:"not null;" json:"name"`
-	RegionID uint64 `gorm:"not null;" json:"region_id"`
-	NameAr   string `gorm:"not null;" json:"name_ar"`
-	NameEn   string `gorm:"not null;" json:"name_en"`
+	RegionID  uint64         `gorm:"not null;" json:"region_id"`
+	NameAr    string         `gorm:"not null;" json:"name_ar"`
+	NameEn    string         `gorm:"not null;" json:"name_en"`
+	Latitude  float64        `gorm:"type:decimal(10,8);default:null;" json:"latitude"`
+	Longitude float64        `gorm:"type:decimal(11,8);default:null;" json:"longitude"`
+	Area      string         `gorm:"type:geography(MultiPolygon,4326);<-:false;->:false" json:"-"`
+	Boundary  datatypes.JSON `gorm:"->;-:migration" json:"-"`
 
 	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt time.Time `gorm:"default:null" json:"updated_at"`
@@ -18,20 +24,24 @@ type City struct {
 
 // CityPublicData defines the public data structure for a city.
 type CityPublicData struct {
-	ID       uint64 `json:"city_id"`
+	ID uint64 `json:"city_id"`
 	// Name     string `json:"name"`
-	RegionID uint64 `json:"region_id"`
-	NameAr   string `json:"name_ar"`
-	NameEn   string `json:"name_en"`
+	RegionID  uint64  `json:"region_id"`
+	NameAr    string  `json:"name_ar"`
+	NameEn    string  `json:"name_en"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
 }
 
 // PublicData returns a copy of the city's public information.
 func (c *City) PublicData() interface{} {
 	return &CityPublicData{
-		ID:       c.ID,
+		ID: c.ID,
 		// Name:     c.Name,
-		RegionID: c.RegionID,
-		NameAr:   c.NameAr,
-		NameEn:   c.NameEn,
+		RegionID:  c.RegionID,
+		NameAr:    c.NameAr,
+		NameEn:    c.NameEn,
+		Latitude:  c.Latitude,
+		Longitude: c.Longitude,
 	}
 }
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Credit represent an amount credited to a user that can be spent on orders
+type Credit struct {
+	ID        uint64       `gorm:"primary_key;auto_increment" json:"id"`
+	UserID    uint64       `gorm:"index;" json:"user_id"`
+	OrderID   uint64       `gorm:"default:null;index;" json:"order_id"`
+	Amount    float64      `gorm:"type:decimal(10,2);" json:"amount"`
+	Reason    CreditReason `gorm:"size:255;index;" json:"reason"`
+	CreatedAt time.Time    `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+type CreditReason string
+
+const (
+	ReferrerCreditReason CreditReason = "referrer"
+	RefereeCreditReason  CreditReason = "referee"
+	// RefundCreditReason is an amount refunded to the sender when resolving a support ticket
+	RefundCreditReason CreditReason = "refund"
+	// OrderCreditReason is an amount spent on an order, recorded as a negative credit
+	OrderCreditReason CreditReason = "order"
+)
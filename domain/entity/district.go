@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// District represent a district of a city in the geocoding dataset
+type District struct {
+	ID        uint64         `gorm:"primary_key;auto_increment" json:"id"`
+	CityID    uint64         `gorm:"not null;index;" json:"city_id"`
+	RegionID  uint64         `gorm:"not null;index;" json:"region_id"`
+	NameAr    string         `gorm:"size:255;not null;index;" json:"name_ar"`
+	NameEn    string         `gorm:"size:255;not null;index;" json:"name_en"`
+	Latitude  float64        `gorm:"type:decimal(10,8);default:null;" json:"latitude"`
+	Longitude float64        `gorm:"type:decimal(11,8);default:null;" json:"longitude"`
+	Area      string         `gorm:"type:geography(MultiPolygon,4326);<-:false;->:false" json:"-"`
+	Boundary  datatypes.JSON `gorm:"->;-:migration" json:"-"`
+	CreatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"default:null" json:"updated_at"`
+}
+
+type DistrictPublicData struct {
+	ID        uint64  `json:"district_id"`
+	CityID    uint64  `json:"city_id"`
+	RegionID  uint64  `json:"region_id"`
+	NameAr    string  `json:"name_ar"`
+	NameEn    string  `json:"name_en"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// PublicData returns a copy of the district's public information
+func (d *District) PublicData() interface{} {
+	return &DistrictPublicData{
+		ID:        d.ID,
+		CityID:    d.CityID,
+		RegionID:  d.RegionID,
+		NameAr:    d.NameAr,
+		NameEn:    d.NameEn,
+		Latitude:  d.Latitude,
+		Longitude: d.Longitude,
+	}
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"time"
+)
+
+// DriverCoverage represent a city, or a whole region, a driver declared they serve. A driver is
+// offered the orders picked up in the cities and regions they cover.
+type DriverCoverage struct {
+	ID        uint64    `gorm:"primary_key;auto_increment" json:"id"`
+	DriverID  uint64    `gorm:"index;" json:"driver_id"`
+	CityID    uint64    `gorm:"default:null;index;" json:"city_id"`
+	RegionID  uint64    `gorm:"default:null;index;" json:"region_id"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	City      City      `gorm:"foreignKey:CityID" json:"city"`
+	Region    Region    `gorm:"foreignKey:RegionID" json:"region"`
+}
+
+type DriverCoveragePublicData struct {
+	ID       uint64            `json:"id"`
+	CityID   uint64            `json:"city_id"`
+	RegionID uint64            `json:"region_id"`
+	City     *CityPublicData   `json:"city"`
+	Region   *RegionPublicData `json:"region"`
+}
+
+// PublicData returns a copy of the coverage's public information
+func (c *DriverCoverage) PublicData() interface{} {
+	var cityPublicData *CityPublicData
+	if c.City.ID != 0 {
+		cityPublicData = c.City.PublicData().(*CityPublicData)
+	}
+
+	var regionPublicData *RegionPublicData
+	if c.Region.ID != 0 {
+		regionPublicData = c.Region.PublicData().(*RegionPublicData)
+	}
+
+	return &DriverCoveragePublicData{
+		ID:       c.ID,
+		CityID:   c.CityID,
+		RegionID: c.RegionID,
+		City:     cityPublicData,
+		Region:   regionPublicData,
+	}
+}
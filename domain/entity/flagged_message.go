@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// FlaggedMessage represent a chat message held for admin review, because it shared contact
+// details or contained abusive words
+type FlaggedMessage struct {
+	ID           uint64               `gorm:"primary_key;auto_increment" json:"id"`
+	Provider     string               `gorm:"size:255;" json:"provider"`
+	ChannelID    string               `gorm:"size:255;index;" json:"channel_id"`
+	UserID       string               `gorm:"size:255;index;" json:"user_id"`
+	MessageID    string               `gorm:"size:255;" json:"message_id"`
+	Text         string               `gorm:"type:text;" json:"text"`
+	MaskedText   string               `gorm:"type:text;" json:"masked_text"`
+	Reasons      datatypes.JSON       `gorm:"type:json" json:"reasons"`
+	Words        datatypes.JSON       `gorm:"type:json" json:"words"`
+	Status       FlaggedMessageStatus `gorm:"size:255;index;" json:"status"`
+	ReviewedByID *uint64              `gorm:"default:null" json:"reviewed_by_id"`
+	ReviewedAt   *time.Time           `gorm:"default:null" json:"reviewed_at"`
+	CreatedAt    time.Time            `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+type FlaggedMessagePublicData struct {
+	ID           uint64               `json:"id"`
+	Provider     string               `json:"provider"`
+	ChannelID    string               `json:"channel_id"`
+	UserID       string               `json:"user_id"`
+	MessageID    string               `json:"message_id"`
+	Text         string               `json:"text"`
+	MaskedText   string               `json:"masked_text"`
+	Reasons      []string             `json:"reasons"`
+	Words        []string             `json:"words"`
+	Status       FlaggedMessageStatus `json:"status"`
+	ReviewedByID *uint64              `json:"reviewed_by_id"`
+	ReviewedAt   *time.Time           `json:"reviewed_at"`
+	CreatedAt    time.Time            `json:"created_at"`
+}
+
+type FlaggedMessageStatus string
+
+const (
+	// FlaggedMessagePendingStatus is a message waiting for an admin to review it
+	FlaggedMessagePendingStatus FlaggedMessageStatus = "pending"
+	// FlaggedMessageDismissedStatus is a message an admin found acceptable
+	FlaggedMessageDismissedStatus FlaggedMessageStatus = "dismissed"
+	// FlaggedMessageConfirmedStatus is a message an admin confirmed as a violation
+	FlaggedMessageConfirmedStatus FlaggedMessageStatus = "confirmed"
+)
+
+// PublicData returns a copy of the flagged message's public information
+func (m *FlaggedMessage) PublicData() interface{} {
+	var reasons, words []string
+	_ = json.Unmarshal(m.Reasons, &reasons)
+	_ = json.Unmarshal(m.Words, &words)
+
+	return &FlaggedMessagePublicData{
+		ID:           m.ID,
+		Provider:     m.Provider,
+		ChannelID:    m.ChannelID,
+		UserID:       m.UserID,
+		MessageID:    m.MessageID,
+		Text:         m.Text,
+		MaskedText:   m.MaskedText,
+		Reasons:      reasons,
+		Words:        words,
+		Status:       m.Status,
+		ReviewedByID: m.ReviewedByID,
+		ReviewedAt:   m.ReviewedAt,
+		CreatedAt:    m.CreatedAt,
+	}
+}
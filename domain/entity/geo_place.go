@@ -0,0 +1,56 @@
+package entity
+
+// GeoPlace represent a region, a city or a district matching an address search
+type GeoPlace struct {
+	Type       GeoPlaceType `json:"type"`
+	ID         uint64       `json:"id"`
+	NameAr     string       `json:"name_ar"`
+	NameEn     string       `json:"name_en"`
+	RegionID   uint64       `json:"region_id"`
+	CityID     uint64       `json:"city_id"`
+	DistrictID uint64       `json:"district_id"`
+	Latitude   float64      `json:"latitude"`
+	Longitude  float64      `json:"longitude"`
+}
+
+type GeoPlacePublicData struct {
+	Type       GeoPlaceType `json:"type"`
+	ID         uint64       `json:"id"`
+	Name       string       `json:"name"`
+	NameAr     string       `json:"name_ar"`
+	NameEn     string       `json:"name_en"`
+	RegionID   uint64       `json:"region_id"`
+	CityID     uint64       `json:"city_id"`
+	DistrictID uint64       `json:"district_id"`
+	Latitude   float64      `json:"latitude"`
+	Longitude  float64      `json:"longitude"`
+}
+
+type GeoPlaceType string
+
+const (
+	RegionGeoPlaceType   GeoPlaceType = "region"
+	CityGeoPlaceType     GeoPlaceType = "city"
+	DistrictGeoPlaceType GeoPlaceType = "district"
+)
+
+// PublicData returns a copy of the place's public information, named in the given language
+func (p *GeoPlace) PublicData(languageCode string) interface{} {
+	name := p.NameEn
+	if languageCode == "ar" && p.NameAr != "" {
+		name = p.NameAr
+	}
+
+	return &GeoPlacePublicData{
+		Type:       p.Type,
+		ID:         p.ID,
+		Name:       name,
+		NameAr:     p.NameAr,
+		NameEn:     p.NameEn,
+		RegionID:   p.RegionID,
+		CityID:     p.CityID,
+		DistrictID: p.DistrictID,
+		Latitude:   p.Latitude,
+		Longitude:  p.Longitude,
+	}
+}
@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"time"
+)
+
+// Hub represent a warehouse of a city where the parcels of the intercity orders are handed over
+// between the first-mile, the line-haul and the last-mile drivers
+type Hub struct {
+	ID        uint64    `gorm:"primary_key;auto_increment" json:"id"`
+	CityID    uint64    `gorm:"not null;index;" json:"city_id" validate:"required,numeric"`
+	NameAr    string    `gorm:"size:255;not null;" json:"name_ar" validate:"required"`
+	NameEn    string    `gorm:"size:255;not null;" json:"name_en" validate:"required"`
+	Address   *string   `gorm:"type:varchar(255);default:null" json:"address"`
+	Latitude  float64   `gorm:"type:decimal(10,8);not null;" json:"latitude" validate:"required,latitude"`
+	Longitude float64   `gorm:"type:decimal(11,8);not null;" json:"longitude" validate:"required,longitude"`
+	IsActive  bool      `gorm:"default:true;index;" json:"is_active"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:null" json:"updated_at"`
+	City      City      `gorm:"foreignKey:CityID" json:"city"`
+}
+
+type HubPublicData struct {
+	ID        uint64          `json:"id"`
+	CityID    uint64          `json:"city_id"`
+	NameAr    string          `json:"name_ar"`
+	NameEn    string          `json:"name_en"`
+	Address   *string         `json:"address"`
+	Latitude  float64         `json:"latitude"`
+	Longitude float64         `json:"longitude"`
+	IsActive  bool            `json:"is_active"`
+	City      *CityPublicData `json:"city"`
+}
+
+// HubMember represent a user of the hub staff, who scans the parcels going through the hub
+type HubMember struct {
+	ID        uint64    `gorm:"primary_key;auto_increment" json:"id"`
+	HubID     uint64    `gorm:"uniqueIndex:idx_hub_members_hub_user;" json:"hub_id"`
+	UserID    uint64    `gorm:"uniqueIndex:idx_hub_members_hub_user;index;" json:"user_id"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	User      User      `gorm:"foreignKey:UserID" json:"user"`
+}
+
+type HubMemberPublicData struct {
+	ID        uint64          `json:"id"`
+	HubID     uint64          `json:"hub_id"`
+	UserID    uint64          `json:"user_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	User      *UserPublicData `json:"user"`
+}
+
+// PublicData returns a copy of the hub's public information
+func (h *Hub) PublicData() interface{} {
+	var cityPublicData *CityPublicData
+	if h.City.ID != 0 {
+		cityPublicData = h.City.PublicData().(*CityPublicData)
+	}
+
+	return &HubPublicData{
+		ID:        h.ID,
+		CityID:    h.CityID,
+		NameAr:    h.NameAr,
+		NameEn:    h.NameEn,
+		Address:   h.Address,
+		Latitude:  h.Latitude,
+		Longitude: h.Longitude,
+		IsActive:  h.IsActive,
+		City:      cityPublicData,
+	}
+}
+
+// PublicData returns a copy of the hub member's public information
+func (m *HubMember) PublicData(languageCode string) interface{} {
+	var userPublicData *UserPublicData
+	if m.User.ID != 0 {
+		userPublicData = m.User.PublicData(languageCode).(*UserPublicData)
+	}
+
+	return &HubMemberPublicData{
+		ID:        m.ID,
+		HubID:     m.HubID,
+		UserID:    m.UserID,
+		CreatedAt: m.CreatedAt,
+		User:      userPublicData,
+	}
+}
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Invoice represent the tax invoice issued for a completed order
+type Invoice struct {
+	ID             uint64    `gorm:"primary_key;auto_increment" json:"id"`
+	Sequence       uint64    `gorm:"uniqueIndex;" json:"sequence"`
+	Number         string    `gorm:"size:255;not null;uniqueIndex;" json:"number"`
+	OrderID        uint64    `gorm:"uniqueIndex;" json:"order_id"`
+	UserID         uint64    `gorm:"index;" json:"user_id"`
+	DriverID       uint64    `gorm:"index;" json:"driver_id"`
+	Fare           float64   `gorm:"type:decimal(10,2);" json:"fare"`
+	Extras         float64   `gorm:"type:decimal(10,2);" json:"extras"`
+	Discount       float64   `gorm:"type:decimal(10,2);" json:"discount"`
+	Subtotal       float64   `gorm:"type:decimal(10,2);" json:"subtotal"`
+	CommissionRate float64   `gorm:"type:decimal(5,2);" json:"commission_rate"`
+	Commission     float64   `gorm:"type:decimal(10,2);" json:"commission"`
+	VATRate        float64   `gorm:"type:decimal(5,2);" json:"vat_rate"`
+	VAT            float64   `gorm:"type:decimal(10,2);" json:"vat"`
+	Total          float64   `gorm:"type:decimal(10,2);" json:"total"`
+	Currency       string    `gorm:"size:3;" json:"currency"`
+	FilePath       string    `gorm:"size:255;default:null" json:"-"`
+	IssuedAt       time.Time `json:"issued_at"`
+	CreatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"default:null" json:"updated_at"`
+}
+
+type InvoicePublicData struct {
+	ID         uint64    `json:"id"`
+	Number     string    `json:"number"`
+	OrderID    uint64    `json:"order_id"`
+	Fare       float64   `json:"fare"`
+	Extras     float64   `json:"extras"`
+	Discount   float64   `json:"discount"`
+	Subtotal   float64   `json:"subtotal"`
+	Commission float64   `json:"commission"`
+	VATRate    float64   `json:"vat_rate"`
+	VAT        float64   `json:"vat"`
+	Total      float64   `json:"total"`
+	Currency   string    `json:"currency"`
+	IssuedAt   time.Time `json:"issued_at"`
+}
+
+// NewInvoiceNumber formats the sequence of an invoice as its number
+func NewInvoiceNumber(sequence uint64) string {
+	return fmt.Sprintf("INV-%08d", sequence)
+}
+
+// CalculateAmounts fills in the amount breakdown of the invoice from its fare, extras and discount.
+// The commission is the platform's share of the subtotal and is included in it.
+func (i *Invoice) CalculateAmounts() {
+	i.Subtotal = roundAmount(math.Max(i.Fare+i.Extras-i.Discount, 0))
+	i.Commission = roundAmount(i.Subtotal * i.CommissionRate / 100)
+	i.VAT = roundAmount(i.Subtotal * i.VATRate / 100)
+	i.Total = roundAmount(i.Subtotal + i.VAT)
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// PublicData returns a copy of the invoice's public information
+func (i *Invoice) PublicData() interface{} {
+	return &InvoicePublicData{
+		ID:         i.ID,
+		Number:     i.Number,
+		OrderID:    i.OrderID,
+		Fare:       i.Fare,
+		Extras:     i.Extras,
+		Discount:   i.Discount,
+		Subtotal:   i.Subtotal,
+		Commission: i.Commission,
+		VATRate:    i.VATRate,
+		VAT:        i.VAT,
+		Total:      i.Total,
+		Currency:   i.Currency,
+		IssuedAt:   i.IssuedAt,
+	}
+}
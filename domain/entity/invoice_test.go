@@ -0,0 +1,51 @@
+package entity
+
+import "testing"
+
+func TestInvoiceCalculateAmounts(t *testing.T) {
+	tests := []struct {
+		name           string
+		fare           float64
+		extras         float64
+		discount       float64
+		commissionRate float64
+		vatRate        float64
+		want           Invoice
+	}{
+		{name: "fare only", fare: 100, commissionRate: 10, vatRate: 15, want: Invoice{Subtotal: 100, Commission: 10, VAT: 15, Total: 115}},
+		{name: "fare and extras", fare: 80, extras: 20, commissionRate: 10, vatRate: 15, want: Invoice{Subtotal: 100, Commission: 10, VAT: 15, Total: 115}},
+		{name: "discounted", fare: 100, extras: 10, discount: 30, commissionRate: 10, vatRate: 15, want: Invoice{Subtotal: 80, Commission: 8, VAT: 12, Total: 92}},
+		{name: "discount above the amount", fare: 20, discount: 50, commissionRate: 10, vatRate: 15, want: Invoice{Subtotal: 0, Commission: 0, VAT: 0, Total: 0}},
+		{name: "rounded to cents", fare: 33.33, commissionRate: 12.5, vatRate: 15, want: Invoice{Subtotal: 33.33, Commission: 4.17, VAT: 5, Total: 38.33}},
+		{name: "no VAT", fare: 50, commissionRate: 10, vatRate: 0, want: Invoice{Subtotal: 50, Commission: 5, VAT: 0, Total: 50}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := Invoice{Fare: tt.fare, Extras: tt.extras, Discount: tt.discount, CommissionRate: tt.commissionRate, VATRate: tt.vatRate}
+			invoice.CalculateAmounts()
+
+			got := Invoice{Subtotal: invoice.Subtotal, Commission: invoice.Commission, VAT: invoice.VAT, Total: invoice.Total}
+			if got != tt.want {
+				t.Errorf("CalculateAmounts() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewInvoiceNumber(t *testing.T) {
+	tests := []struct {
+		sequence uint64
+		want     string
+	}{
+		{sequence: 1, want: "INV-00000001"},
+		{sequence: 12345, want: "INV-00012345"},
+		{sequence: 123456789, want: "INV-123456789"},
+	}
+
+	for _, tt := range tests {
+		if got := NewInvoiceNumber(tt.sequence); got != tt.want {
+			t.Errorf("NewInvoiceNumber(%d) = %q, want %q", tt.sequence, got, tt.want)
+		}
+	}
+}
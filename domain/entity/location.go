@@ -7,12 +7,14 @@ import (
 
 // Location represent a geographical location
 type Location struct {
-	ID        uint64    `gorm:"primary_key;auto_increment" json:"id"`
-	Name      string    `gorm:"type:json;not null;" json:"name" validate:"required"`
-	Latitude  float64   `gorm:"type:decimal(10,8);not null;" json:"latitude" validate:"required"`
-	Longitude float64   `gorm:"type:decimal(11,8);not null;" json:"longitude" validate:"required"`
-	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt time.Time `gorm:"default:null" json:"updated_at"`
+	ID         uint64    `gorm:"primary_key;auto_increment" json:"id"`
+	Name       string    `gorm:"type:json;not null;" json:"name" validate:"required"`
+	Latitude   float64   `gorm:"type:decimal(10,8);not null;" json:"latitude" validate:"required"`
+	Longitude  float64   `gorm:"type:decimal(11,8);not null;" json:"longitude" validate:"required"`
+	CityID     uint64    `gorm:"default:null;index;" json:"city_id"`
+	DistrictID uint64    `gorm:"default:null;index;" json:"district_id"`
+	CreatedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"default:null" json:"updated_at"`
 }
 
 // UnmarshalJSON custom unmarshal function for Location
@@ -62,10 +64,12 @@ func (l *Location) MarshalJSON() ([]byte, error) {
 }
 
 type LocationPublicData struct {
-	ID        uint64  `json:"id"`
-	Name      string  `json:"name"`
-	Latitude  float64 `json:"latitude"`
-	Longitude float64 `json:"longitude"`
+	ID         uint64  `json:"id"`
+	Name       string  `json:"name"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	CityID     uint64  `json:"city_id"`
+	DistrictID uint64  `json:"district_id"`
 }
 
 // PublicData returns a copy of the location's public information
@@ -82,9 +86,11 @@ func (l *Location) PublicData(languageCode string) interface{} {
 	}
 
 	return &LocationPublicData{
-		ID:        l.ID,
-		Name:      name,
-		Latitude:  l.Latitude,
-		Longitude: l.Longitude,
+		ID:         l.ID,
+		Name:       name,
+		Latitude:   l.Latitude,
+		Longitude:  l.Longitude,
+		CityID:     l.CityID,
+		DistrictID: l.DistrictID,
 	}
 }
@@ -1,15 +1,22 @@
 package entity
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"math"
+	"strings"
 	"time"
 
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
 // Order represent an order
 type Order struct {
 	ID                   uint64              `gorm:"primary_key;auto_increment" json:"id"`
+	TrackingNumber       string              `gorm:"size:32;default:null;uniqueIndex;" json:"tracking_number"`
+	ShareToken           *string             `gorm:"size:64;default:null;uniqueIndex;" json:"-"`
 	LocationID           uint64              `gorm:"index;" json:"location_id" validate:"required,numeric"`
 	UserID               uint64              `gorm:"index;" json:"user_id" validate:"numeric"`
 	DriverID             uint64              `gorm:"default:null;index;" json:"driver_id" validate:"numeric"`
@@ -21,15 +28,46 @@ type Order struct {
 	DeliveryTimeID       uint64              `gorm:"index;" json:"delivery_time_id" validate:"required,numeric"`
 	ShipmentContentIDs   []uint64            `gorm:"-" json:"shipment_content_ids" validate:"required"`
 	ExtraServiceIDs      *[]uint64           `gorm:"-" json:"extra_service_ids"`
-	DestinationID        uint64              `gorm:"index;" json:"destination_id" validate:"required,numeric"`
+	DestinationID        uint64              `gorm:"index;" json:"destination_id" validate:"omitempty,numeric"`
 	Quantity             uint64              `gorm:"default:1" json:"quantity" validate:"required,numeric"`
 	RecipientPhoneNumber string              `gorm:"type:varchar(255)" json:"recipient_phone_number" validate:"required,e164"`
 	Notes                *string             `gorm:"type:varchar(255);default:null" json:"notes"`
 	Amount               *float64            `gorm:"default:null" json:"amount" validate:"omitempty,numeric"`
 	Latitude             float64             `gorm:"type:decimal(10,8);not null;index;" json:"latitude" validate:"required"`
 	Longitude            float64             `gorm:"type:decimal(11,8);not null;index;" json:"longitude" validate:"required"`
+	PickupAddressID      uint64              `gorm:"default:null;index;" json:"pickup_address_id" validate:"omitempty,numeric"`
+	PickupPlace          datatypes.JSON      `gorm:"type:json;default:null" json:"pickup_place"`
+	CityID               uint64              `gorm:"default:null;index;" json:"city_id"`
+	RegionID             uint64              `gorm:"default:null;index;" json:"region_id"`
+	DestinationLatitude  float64             `gorm:"type:decimal(10,8);default:null;index;" json:"destination_latitude" validate:"required_without=DestinationID"`
+	DestinationLongitude float64             `gorm:"type:decimal(11,8);default:null;index;" json:"destination_longitude" validate:"required_without=DestinationID"`
+	DestinationAddress   *string             `gorm:"type:varchar(255);default:null" json:"destination_address"`
+	DestinationAddressID uint64              `gorm:"default:null;index;" json:"destination_address_id" validate:"omitempty,numeric"`
+	DestinationPlace     datatypes.JSON      `gorm:"type:json;default:null" json:"destination_place"`
+	DestinationCityID    uint64              `gorm:"default:null;index;" json:"destination_city_id"`
+	DestinationRegionID  uint64              `gorm:"default:null;index;" json:"destination_region_id"`
+	IsIntercity          bool                `gorm:"default:false;index;" json:"is_intercity"`
 	PaymentMethod        *OrderPaymentMethod `gorm:"size:255;default:null" json:"payment_method" validate:"omitempty,oneof=cash"`
-	Status               OrderStatus         `gorm:"size:255;default:order_created;index;" json:"status" validate:"oneof=order_created order_accepted pickup_in_progress shipment_picked_up in_transit at_destination_city out_for_delivery delivery_attempted delivery_rescheduled shipment_delivered order_completed order_canceled shipment_returned"`
+	Status               OrderStatus         `gorm:"size:255;default:order_created;index;" json:"status" validate:"oneof=order_created order_accepted pickup_in_progress shipment_picked_up in_transit at_destination_city out_for_delivery delivery_attempted delivery_rescheduled shipment_delivered order_completed order_canceled shipment_returned order_scheduled"`
+	ReturnOfOrderID      uint64              `gorm:"default:null;index;" json:"return_of_order_id" validate:"omitempty,numeric"`
+	ReturnReason         *string             `gorm:"type:varchar(255);default:null" json:"return_reason"`
+	PreferredDriverID    uint64              `gorm:"default:null;index;" json:"preferred_driver_id" validate:"omitempty,numeric"`
+	PreferredUntil       *time.Time          `gorm:"default:null;index;" json:"preferred_until"`
+	DeliveredAt          *time.Time          `gorm:"default:null" json:"delivered_at"`
+	PickupWindowStart    *time.Time          `gorm:"default:null;index;" json:"pickup_window_start"`
+	PickupWindowEnd      *time.Time          `gorm:"default:null" json:"pickup_window_end"`
+	RecurringOrderID     uint64              `gorm:"default:null;index;" json:"recurring_order_id" validate:"omitempty,numeric"`
+	RouteDistance        *float64            `gorm:"default:null" json:"route_distance"`
+	RouteDuration        *float64            `gorm:"default:null" json:"route_duration"`
+	RoutePolyline        *string             `gorm:"type:text;default:null" json:"route_polyline"`
+	DriverETA            *float64            `gorm:"-" json:"driver_eta"`
+	ServiceAreaID        uint64              `gorm:"default:null;index;" json:"service_area_id" validate:"omitempty,numeric"`
+	SurgeMultiplier      *float64            `gorm:"type:decimal(5,2);default:null" json:"surge_multiplier"`
+	PromotionID          uint64              `gorm:"default:null;index;" json:"promotion_id" validate:"omitempty,numeric"`
+	PromoCode            *string             `gorm:"-" json:"promo_code"`
+	Discount             *float64            `gorm:"type:decimal(10,2);default:null" json:"discount"`
+	CreditUsed           *float64            `gorm:"type:decimal(10,2);default:null" json:"credit_used"`
+	OrganizationID       uint64              `gorm:"default:null;index;" json:"organization_id" validate:"omitempty,numeric"`
 	IsSender             bool                `gorm:"-" json:"is_sender"`
 	IsReceiver           bool                `gorm:"-" json:"is_receiver"`
 	CreatedAt            time.Time           `gorm:"default:CURRENT_TIMESTAMP;index;" json:"created_at"`
@@ -46,46 +84,83 @@ type Order struct {
 	ShipmentContents     []ShipmentContent   `gorm:"many2many:order_shipment_contents" json:"shipment_contents"`
 	ExtraServices        *[]ExtraService     `gorm:"many2many:order_extra_services" json:"extra_services"`
 	Destination          Location            `gorm:"foreignKey:DestinationID" json:"destination"`
+	City                 City                `gorm:"foreignKey:CityID" json:"city"`
+	DestinationCity      City                `gorm:"foreignKey:DestinationCityID" json:"destination_city"`
+	DropOffs             []OrderDropOff      `gorm:"foreignKey:OrderID" json:"drop_offs" validate:"omitempty,dive"`
+	Legs                 []OrderLeg          `gorm:"foreignKey:OrderID" json:"legs"`
 	Rating               *Rating             `gorm:"-" json:"rating"`
 }
 
 type OrderPublicData struct {
-	ID                 uint64                       `json:"id"`
-	LocationID         uint64                       `json:"location_id"`
-	UserID             uint64                       `json:"user_id"`
-	DriverID           uint64                       `json:"driver_id"`
-	RecipientID        uint64                       `json:"recipient_id"`
-	CategoryID         uint64                       `json:"category_id"`
-	SizeID             uint64                       `json:"size_id"`
-	TruckTypeID        uint64                       `json:"truck_type_id"`
-	TruckModelID       uint64                       `json:"truck_model_id"`
-	DeliveryTimeID     uint64                       `json:"delivery_time_id"`
-	ShipmentContentIDs []uint64                     `json:"shipment_content_ids"`
-	ExtraServiceIDs    *[]uint64                    `json:"extra_service_ids"`
-	DestinationID      uint64                       `json:"destination_id"`
-	Quantity           uint64                       `json:"quantity"`
-	Notes              *string                      `json:"notes"`
-	Amount             *float64                     `json:"amount"`
-	Latitude           float64                      `json:"latitude"`
-	Longitude          float64                      `json:"longitude"`
-	CreatedAt          time.Time                    `json:"created_at"`
-	Status             OrderStatus                  `json:"status"`
-	PaymentMethod      *OrderPaymentMethod          `json:"payment_method"`
-	IsSender           bool                         `json:"is_sender"`
-	IsReceiver         bool                         `json:"is_receiver"`
-	Location           *LocationPublicData          `json:"location"`
-	User               *UserPublicData              `json:"user"`
-	Driver             *DriverPublicData            `json:"driver"`
-	Recipient          *UserPublicData              `json:"recipient"`
-	Category           *CategoryPublicData          `json:"category"`
-	Size               *SizePublicData              `json:"size"`
-	TruckType          *TruckTypePublicData         `json:"truck_type"`
-	TruckModel         *TruckModelPublicData        `json:"truck_model"`
-	DeliveryTime       *DeliveryTimePublicData      `json:"delivery_time"`
-	ShipmentContents   []*ShipmentContentPublicData `json:"shipment_contents"`
-	ExtraServices      []*ExtraServicePublicData    `json:"extra_services"`
-	Destination        *LocationPublicData          `json:"destination"`
-	Rating             *Rating                      `json:"rating"`
+	ID                   uint64                       `json:"id"`
+	TrackingNumber       string                       `json:"tracking_number"`
+	LocationID           uint64                       `json:"location_id"`
+	UserID               uint64                       `json:"user_id"`
+	DriverID             uint64                       `json:"driver_id"`
+	RecipientID          uint64                       `json:"recipient_id"`
+	CategoryID           uint64                       `json:"category_id"`
+	SizeID               uint64                       `json:"size_id"`
+	TruckTypeID          uint64                       `json:"truck_type_id"`
+	TruckModelID         uint64                       `json:"truck_model_id"`
+	DeliveryTimeID       uint64                       `json:"delivery_time_id"`
+	ShipmentContentIDs   []uint64                     `json:"shipment_content_ids"`
+	ExtraServiceIDs      *[]uint64                    `json:"extra_service_ids"`
+	DestinationID        uint64                       `json:"destination_id"`
+	Quantity             uint64                       `json:"quantity"`
+	Notes                *string                      `json:"notes"`
+	Amount               *float64                     `json:"amount"`
+	Latitude             float64                      `json:"latitude"`
+	Longitude            float64                      `json:"longitude"`
+	PickupAddressID      uint64                       `json:"pickup_address_id"`
+	PickupPlace          *AddressPlace                `json:"pickup_place"`
+	CityID               uint64                       `json:"city_id"`
+	RegionID             uint64                       `json:"region_id"`
+	DestinationLatitude  float64                      `json:"destination_latitude"`
+	DestinationLongitude float64                      `json:"destination_longitude"`
+	DestinationAddress   *string                      `json:"destination_address"`
+	DestinationAddressID uint64                       `json:"destination_address_id"`
+	DestinationPlace     *AddressPlace                `json:"destination_place"`
+	DestinationCityID    uint64                       `json:"destination_city_id"`
+	DestinationRegionID  uint64                       `json:"destination_region_id"`
+	IsIntercity          bool                         `json:"is_intercity"`
+	CreatedAt            time.Time                    `json:"created_at"`
+	Status               OrderStatus                  `json:"status"`
+	PaymentMethod        *OrderPaymentMethod          `json:"payment_method"`
+	ReturnOfOrderID      uint64                       `json:"return_of_order_id"`
+	ReturnReason         *string                      `json:"return_reason"`
+	DeliveredAt          *time.Time                   `json:"delivered_at"`
+	PickupWindowStart    *time.Time                   `json:"pickup_window_start"`
+	PickupWindowEnd      *time.Time                   `json:"pickup_window_end"`
+	RecurringOrderID     uint64                       `json:"recurring_order_id"`
+	RouteDistance        *float64                     `json:"route_distance"`
+	RouteDuration        *float64                     `json:"route_duration"`
+	RoutePolyline        *string                      `json:"route_polyline"`
+	DriverETA            *float64                     `json:"driver_eta"`
+	ServiceAreaID        uint64                       `json:"service_area_id"`
+	SurgeMultiplier      *float64                     `json:"surge_multiplier"`
+	PromotionID          uint64                       `json:"promotion_id"`
+	Discount             *float64                     `json:"discount"`
+	CreditUsed           *float64                     `json:"credit_used"`
+	OrganizationID       uint64                       `json:"organization_id"`
+	IsSender             bool                         `json:"is_sender"`
+	IsReceiver           bool                         `json:"is_receiver"`
+	Location             *LocationPublicData          `json:"location"`
+	User                 *UserPublicData              `json:"user"`
+	Driver               *DriverPublicData            `json:"driver"`
+	Recipient            *UserPublicData              `json:"recipient"`
+	Category             *CategoryPublicData          `json:"category"`
+	Size                 *SizePublicData              `json:"size"`
+	TruckType            *TruckTypePublicData         `json:"truck_type"`
+	TruckModel           *TruckModelPublicData        `json:"truck_model"`
+	DeliveryTime         *DeliveryTimePublicData      `json:"delivery_time"`
+	ShipmentContents     []*ShipmentContentPublicData `json:"shipment_contents"`
+	ExtraServices        []*ExtraServicePublicData    `json:"extra_services"`
+	Destination          *LocationPublicData          `json:"destination"`
+	City                 *CityPublicData              `json:"city"`
+	DestinationCity      *CityPublicData              `json:"destination_city"`
+	DropOffs             []*OrderDropOffPublicData    `json:"drop_offs"`
+	Legs                 []*OrderLegPublicData        `json:"legs"`
+	Rating               *Rating                      `json:"rating"`
 }
 
 type OrderPaymentMethod string
@@ -110,16 +185,99 @@ const (
 	OrderCompletedStatus      OrderStatus = "order_completed"
 	OrderCanceledStatus       OrderStatus = "order_canceled"
 	ShipmentReturnedStatus    OrderStatus = "shipment_returned"
+	OrderScheduledStatus      OrderStatus = "order_scheduled"
 )
 
+// BeforeCreate is a gorm hook that gives every new order its own tracking number and the token
+// of its public tracking link
+func (o *Order) BeforeCreate(tx *gorm.DB) (err error) {
+	if o.TrackingNumber, err = NewTrackingNumber(); err != nil {
+		return
+	}
+
+	shareToken, err := NewShareToken()
+	if err != nil {
+		return
+	}
+	o.ShareToken = &shareToken
+
+	return
+}
+
+// NewTrackingNumber returns a random tracking number, made of the JY prefix and 10 hexadecimal
+// digits. It's printed on the parcel labels and scanned by the drivers and the hub staff.
+func NewTrackingNumber() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "JY" + strings.ToUpper(hex.EncodeToString(b)), nil
+}
+
+// NewShareToken returns a random token for the public tracking link of an order, long enough that
+// it can't be guessed
+func NewShareToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// shareTokenLifetimeAfterDelivery is how long the tracking link keeps working once the order is
+// delivered, so the recipient can still see that it arrived
+const shareTokenLifetimeAfterDelivery = 24 * time.Hour
+
+// ShareTokenExpired reports whether the public tracking link of the order stopped working. It
+// expires once the order is completed, canceled or returned, and a day after it was delivered.
+func (o *Order) ShareTokenExpired(now time.Time) bool {
+	switch o.Status {
+	case OrderCompletedStatus, OrderCanceledStatus, ShipmentReturnedStatus:
+		return true
+	case ShipmentDeliveredStatus:
+		return o.DeliveredAt == nil || now.Sub(*o.DeliveredAt) > shareTokenLifetimeAfterDelivery
+	}
+	return false
+}
+
+// IsOutForDelivery reports whether the driver is on the way to the recipient. The driver of an
+// intercity order heads to the recipient on the last mile only.
+func (o *Order) IsOutForDelivery() bool {
+	if o.Status == OutForDeliveryStatus {
+		return true
+	}
+	return !o.IsIntercity && o.Status == ShipmentPickedUpStatus
+}
+
+// RefundableAmount returns the part of the order's amount paid by the sender that wasn't refunded
+// yet, given the sum of the refunds already granted. The discount of a promotion was never paid,
+// so it isn't refunded. Nothing can be refunded when the order has no amount.
+func (o *Order) RefundableAmount(refunded float64) float64 {
+	if o.Amount == nil {
+		return 0
+	}
+
+	paid := *o.Amount
+	if o.Discount != nil {
+		paid -= *o.Discount
+	}
+
+	// The amounts are kept in cents, the difference is rounded so refunds adding up to the amount leave nothing
+	refundable := math.Round((paid-refunded)*100) / 100
+	if refundable < 0 {
+		return 0
+	}
+	return refundable
+}
+
 // AfterFind is a gorm hook that sets the value of the IsDriver field
 // based on whether a driver with the same user_id exists
 func (o *Order) AfterFind(tx *gorm.DB) (err error) {
 	// Rating struct to store the retrieved driver data
 	var rating Rating
 
-	// Retrieve the rating data using the order ID
-	if err := tx.Table("ratings").Where("order_id = ?", o.ID).First(&rating).Error; err != nil {
+	// Retrieve the sender's rating of the driver using the order ID
+	if err := tx.Table("ratings").Where("order_id = ?", o.ID).Where("type = ?", DriverRatingType).First(&rating).Error; err != nil {
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
 			// Handle the error appropriately, excluding the "record not found" error
 			return err
@@ -194,42 +352,110 @@ func (o *Order) PublicData(languageCode string) interface{} {
 
 	destinationPublicData := o.Destination.PublicData(languageCode).(*LocationPublicData)
 
+	var cityPublicData *CityPublicData
+	if o.City.ID != 0 {
+		cityPublicData = o.City.PublicData().(*CityPublicData)
+	}
+
+	var destinationCityPublicData *CityPublicData
+	if o.DestinationCity.ID != 0 {
+		destinationCityPublicData = o.DestinationCity.PublicData().(*CityPublicData)
+	}
+
+	dropOffPublicDataList := make([]*OrderDropOffPublicData, len(o.DropOffs))
+	for i, dropOff := range o.DropOffs {
+		dropOffPublicDataList[i] = dropOff.PublicData().(*OrderDropOffPublicData)
+	}
+
+	legPublicDataList := make([]*OrderLegPublicData, len(o.Legs))
+	for i, leg := range o.Legs {
+		legPublicDataList[i] = leg.PublicData(languageCode).(*OrderLegPublicData)
+	}
+
 	return &OrderPublicData{
-		ID:                 o.ID,
-		LocationID:         o.LocationID,
-		UserID:             o.UserID,
-		DriverID:           o.DriverID,
-		RecipientID:        o.RecipientID,
-		CategoryID:         o.CategoryID,
-		SizeID:             o.SizeID,
-		TruckTypeID:        o.TruckTypeID,
-		TruckModelID:       o.TruckModelID,
-		DeliveryTimeID:     o.DeliveryTimeID,
-		ShipmentContentIDs: o.ShipmentContentIDs,
-		ExtraServiceIDs:    o.ExtraServiceIDs,
-		DestinationID:      o.DestinationID,
-		Quantity:           o.Quantity,
-		Notes:              o.Notes,
-		Amount:             o.Amount,
-		Latitude:           o.Latitude,
-		Longitude:          o.Longitude,
-		PaymentMethod:      o.PaymentMethod,
-		IsSender:           o.IsSender,
-		IsReceiver:         o.IsReceiver,
-		Status:             o.Status,
-		CreatedAt:          o.CreatedAt,
-		Location:           locationPublicData,
-		User:               userPublicData,
-		Driver:             driverPublicData,
-		Recipient:          recipientPublicData,
-		Category:           categoryPublicData,
-		Size:               sizePublicData,
-		TruckType:          truckTypePublicData,
-		TruckModel:         truckModelPublicData,
-		DeliveryTime:       deliveryTimePublicData,
-		ShipmentContents:   shipmentContentPublicDataList,
-		ExtraServices:      extraServicePublicDataList,
-		Destination:        destinationPublicData,
-		Rating:             o.Rating,
+		ID:                   o.ID,
+		TrackingNumber:       o.TrackingNumber,
+		LocationID:           o.LocationID,
+		UserID:               o.UserID,
+		DriverID:             o.DriverID,
+		RecipientID:          o.RecipientID,
+		CategoryID:           o.CategoryID,
+		SizeID:               o.SizeID,
+		TruckTypeID:          o.TruckTypeID,
+		TruckModelID:         o.TruckModelID,
+		DeliveryTimeID:       o.DeliveryTimeID,
+		ShipmentContentIDs:   o.ShipmentContentIDs,
+		ExtraServiceIDs:      o.ExtraServiceIDs,
+		DestinationID:        o.DestinationID,
+		Quantity:             o.Quantity,
+		Notes:                o.Notes,
+		Amount:               o.Amount,
+		Latitude:             o.Latitude,
+		Longitude:            o.Longitude,
+		PickupAddressID:      o.PickupAddressID,
+		PickupPlace:          placePublicData(o.PickupPlace),
+		CityID:               o.CityID,
+		RegionID:             o.RegionID,
+		DestinationLatitude:  o.DestinationLatitude,
+		DestinationLongitude: o.DestinationLongitude,
+		DestinationAddress:   o.DestinationAddress,
+		DestinationAddressID: o.DestinationAddressID,
+		DestinationPlace:     placePublicData(o.DestinationPlace),
+		DestinationCityID:    o.DestinationCityID,
+		DestinationRegionID:  o.DestinationRegionID,
+		IsIntercity:          o.IsIntercity,
+		PaymentMethod:        o.PaymentMethod,
+		ReturnOfOrderID:      o.ReturnOfOrderID,
+		ReturnReason:         o.ReturnReason,
+		DeliveredAt:          o.DeliveredAt,
+		PickupWindowStart:    o.PickupWindowStart,
+		PickupWindowEnd:      o.PickupWindowEnd,
+		RecurringOrderID:     o.RecurringOrderID,
+		RouteDistance:        o.RouteDistance,
+		RouteDuration:        o.RouteDuration,
+		RoutePolyline:        o.RoutePolyline,
+		DriverETA:            o.DriverETA,
+		ServiceAreaID:        o.ServiceAreaID,
+		SurgeMultiplier:      o.SurgeMultiplier,
+		PromotionID:          o.PromotionID,
+		Discount:             o.Discount,
+		CreditUsed:           o.CreditUsed,
+		OrganizationID:       o.OrganizationID,
+		IsSender:             o.IsSender,
+		IsReceiver:           o.IsReceiver,
+		Status:               o.Status,
+		CreatedAt:            o.CreatedAt,
+		Location:             locationPublicData,
+		User:                 userPublicData,
+		Driver:               driverPublicData,
+		Recipient:            recipientPublicData,
+		Category:             categoryPublicData,
+		Size:                 sizePublicData,
+		TruckType:            truckTypePublicData,
+		TruckModel:           truckModelPublicData,
+		DeliveryTime:         deliveryTimePublicData,
+		ShipmentContents:     shipmentContentPublicDataList,
+		ExtraServices:        extraServicePublicDataList,
+		Destination:          destinationPublicData,
+		City:                 cityPublicData,
+		DestinationCity:      destinationCityPublicData,
+		DropOffs:             dropOffPublicDataList,
+		Legs:                 legPublicDataList,
+		Rating:               o.Rating,
+	}
+}
+
+// CreditToSpend returns the part of the user's credit spent on the order, at most what's left to
+// pay once the discount is taken off its amount
+func (o *Order) CreditToSpend(credit float64) float64 {
+	if o.Amount == nil || credit <= 0 {
+		return 0
 	}
+
+	due := *o.Amount
+	if o.Discount != nil {
+		due -= *o.Discount
+	}
+
+	return math.Max(math.Round(math.Min(credit, due)*100)/100, 0)
 }
@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// OrderDropOff represent an additional drop-off point of an order
+type OrderDropOff struct {
+	ID                   uint64    `gorm:"primary_key;auto_increment" json:"id"`
+	OrderID              uint64    `gorm:"index;" json:"order_id"`
+	Sequence             uint64    `gorm:"default:0" json:"sequence"`
+	Latitude             float64   `gorm:"type:decimal(10,8);not null;" json:"latitude" validate:"required"`
+	Longitude            float64   `gorm:"type:decimal(11,8);not null;" json:"longitude" validate:"required"`
+	RecipientPhoneNumber *string   `gorm:"type:varchar(255);default:null" json:"recipient_phone_number" validate:"omitempty,e164"`
+	Notes                *string   `gorm:"type:varchar(255);default:null" json:"notes"`
+	CreatedAt            time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+type OrderDropOffPublicData struct {
+	ID                   uint64  `json:"id"`
+	OrderID              uint64  `json:"order_id"`
+	Sequence             uint64  `json:"sequence"`
+	Latitude             float64 `json:"latitude"`
+	Longitude            float64 `json:"longitude"`
+	RecipientPhoneNumber *string `json:"recipient_phone_number"`
+	Notes                *string `json:"notes"`
+}
+
+// PublicData returns a copy of the order drop-off's public information
+func (d *OrderDropOff) PublicData() interface{} {
+	return &OrderDropOffPublicData{
+		ID:                   d.ID,
+		OrderID:              d.OrderID,
+		Sequence:             d.Sequence,
+		Latitude:             d.Latitude,
+		Longitude:            d.Longitude,
+		RecipientPhoneNumber: d.RecipientPhoneNumber,
+		Notes:                d.Notes,
+	}
+}
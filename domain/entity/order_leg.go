@@ -0,0 +1,141 @@
+package entity
+
+import "time"
+
+// OrderLeg represent a stretch of an intercity order carried by a single driver. The first mile
+// goes from the pickup point to the hub of the origin city, the line haul from that hub to the hub
+// of the destination city and the last mile from there to the recipient.
+type OrderLeg struct {
+	ID            uint64         `gorm:"primary_key;auto_increment" json:"id"`
+	OrderID       uint64         `gorm:"index;" json:"order_id"`
+	Sequence      uint64         `gorm:"not null;" json:"sequence"`
+	Type          OrderLegType   `gorm:"size:255;not null;index;" json:"type"`
+	CityID        uint64         `gorm:"default:null;index;" json:"city_id"`
+	FromHubID     uint64         `gorm:"default:null;index;" json:"from_hub_id"`
+	ToHubID       uint64         `gorm:"default:null;index;" json:"to_hub_id"`
+	FromLatitude  float64        `gorm:"type:decimal(10,8);not null;" json:"from_latitude"`
+	FromLongitude float64        `gorm:"type:decimal(11,8);not null;" json:"from_longitude"`
+	ToLatitude    float64        `gorm:"type:decimal(10,8);not null;" json:"to_latitude"`
+	ToLongitude   float64        `gorm:"type:decimal(11,8);not null;" json:"to_longitude"`
+	DriverID      uint64         `gorm:"default:null;index;" json:"driver_id"`
+	Amount        *float64       `gorm:"default:null" json:"amount"`
+	Status        OrderLegStatus `gorm:"size:255;default:leg_pending;index;" json:"status"`
+	PickedUpAt    *time.Time     `gorm:"default:null" json:"picked_up_at"`
+	CompletedAt   *time.Time     `gorm:"default:null" json:"completed_at"`
+	CreatedAt     time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"default:null" json:"updated_at"`
+	FromHub       Hub            `gorm:"foreignKey:FromHubID" json:"from_hub"`
+	ToHub         Hub            `gorm:"foreignKey:ToHubID" json:"to_hub"`
+	Driver        Driver         `gorm:"foreignKey:DriverID" json:"driver"`
+}
+
+type OrderLegPublicData struct {
+	ID            uint64            `json:"id"`
+	OrderID       uint64            `json:"order_id"`
+	Sequence      uint64            `json:"sequence"`
+	Type          OrderLegType      `json:"type"`
+	CityID        uint64            `json:"city_id"`
+	FromHubID     uint64            `json:"from_hub_id"`
+	ToHubID       uint64            `json:"to_hub_id"`
+	FromLatitude  float64           `json:"from_latitude"`
+	FromLongitude float64           `json:"from_longitude"`
+	ToLatitude    float64           `json:"to_latitude"`
+	ToLongitude   float64           `json:"to_longitude"`
+	DriverID      uint64            `json:"driver_id"`
+	Amount        *float64          `json:"amount"`
+	Status        OrderLegStatus    `json:"status"`
+	PickedUpAt    *time.Time        `json:"picked_up_at"`
+	CompletedAt   *time.Time        `json:"completed_at"`
+	FromHub       *HubPublicData    `json:"from_hub"`
+	ToHub         *HubPublicData    `json:"to_hub"`
+	Driver        *DriverPublicData `json:"driver"`
+}
+
+type OrderLegType string
+
+const (
+	FirstMileOrderLegType OrderLegType = "first_mile"
+	LineHaulOrderLegType  OrderLegType = "line_haul"
+	LastMileOrderLegType  OrderLegType = "last_mile"
+)
+
+type OrderLegStatus string
+
+const (
+	OrderLegPendingStatus   OrderLegStatus = "leg_pending"
+	OrderLegAcceptedStatus  OrderLegStatus = "leg_accepted"
+	OrderLegPickedUpStatus  OrderLegStatus = "leg_picked_up"
+	OrderLegCompletedStatus OrderLegStatus = "leg_completed"
+	OrderLegCanceledStatus  OrderLegStatus = "leg_canceled"
+)
+
+// orderLegStatuses maps the progress of each type of leg to the status of its order. The progress
+// missing from it leaves the order in the status reached by the previous legs.
+var orderLegStatuses = map[OrderLegType]map[OrderLegStatus]OrderStatus{
+	FirstMileOrderLegType: {
+		OrderLegAcceptedStatus:  OrderAcceptedStatus,
+		OrderLegPickedUpStatus:  ShipmentPickedUpStatus,
+		OrderLegCompletedStatus: ShipmentPickedUpStatus,
+	},
+	LineHaulOrderLegType: {
+		OrderLegPickedUpStatus:  InTransitStatus,
+		OrderLegCompletedStatus: AtDestinationCityStatus,
+	},
+	LastMileOrderLegType: {
+		OrderLegPickedUpStatus:  OutForDeliveryStatus,
+		OrderLegCompletedStatus: ShipmentDeliveredStatus,
+	},
+}
+
+// OrderStatusFromLegs derives the status of an intercity order from the progress of its legs,
+// given in sequence. The furthest leg that moved decides it, an order whose legs didn't move yet
+// is still waiting for its first-mile driver.
+func OrderStatusFromLegs(legs []OrderLeg) OrderStatus {
+	status := OrderCreatedStatus
+	for _, leg := range legs {
+		if legStatus, ok := orderLegStatuses[leg.Type][leg.Status]; ok {
+			status = legStatus
+		}
+	}
+	return status
+}
+
+// PublicData returns a copy of the leg's public information
+func (l *OrderLeg) PublicData(languageCode string) interface{} {
+	var fromHubPublicData *HubPublicData
+	if l.FromHub.ID != 0 {
+		fromHubPublicData = l.FromHub.PublicData().(*HubPublicData)
+	}
+
+	var toHubPublicData *HubPublicData
+	if l.ToHub.ID != 0 {
+		toHubPublicData = l.ToHub.PublicData().(*HubPublicData)
+	}
+
+	var driverPublicData *DriverPublicData
+	if l.Driver.ID != 0 {
+		driverPublicData = l.Driver.PublicData(languageCode).(*DriverPublicData)
+	}
+
+	return &OrderLegPublicData{
+		ID:            l.ID,
+		OrderID:       l.OrderID,
+		Sequence:      l.Sequence,
+		Type:          l.Type,
+		CityID:        l.CityID,
+		FromHubID:     l.FromHubID,
+		ToHubID:       l.ToHubID,
+		FromLatitude:  l.FromLatitude,
+		FromLongitude: l.FromLongitude,
+		ToLatitude:    l.ToLatitude,
+		ToLongitude:   l.ToLongitude,
+		DriverID:      l.DriverID,
+		Amount:        l.Amount,
+		Status:        l.Status,
+		PickedUpAt:    l.PickedUpAt,
+		CompletedAt:   l.CompletedAt,
+		FromHub:       fromHubPublicData,
+		ToHub:         toHubPublicData,
+		Driver:        driverPublicData,
+	}
+}
@@ -0,0 +1,40 @@
+package entity
+
+import "testing"
+
+func TestOrderStatusFromLegs(t *testing.T) {
+	legs := func(firstMile, lineHaul, lastMile OrderLegStatus) []OrderLeg {
+		return []OrderLeg{
+			{Sequence: 1, Type: FirstMileOrderLegType, Status: firstMile},
+			{Sequence: 2, Type: LineHaulOrderLegType, Status: lineHaul},
+			{Sequence: 3, Type: LastMileOrderLegType, Status: lastMile},
+		}
+	}
+
+	tests := []struct {
+		name string
+		legs []OrderLeg
+		want OrderStatus
+	}{
+		{name: "no legs", legs: nil, want: OrderCreatedStatus},
+		{name: "nothing moved", legs: legs(OrderLegPendingStatus, OrderLegPendingStatus, OrderLegPendingStatus), want: OrderCreatedStatus},
+		{name: "first mile accepted", legs: legs(OrderLegAcceptedStatus, OrderLegPendingStatus, OrderLegPendingStatus), want: OrderAcceptedStatus},
+		{name: "first mile picked up", legs: legs(OrderLegPickedUpStatus, OrderLegPendingStatus, OrderLegPendingStatus), want: ShipmentPickedUpStatus},
+		{name: "at the origin hub", legs: legs(OrderLegCompletedStatus, OrderLegPendingStatus, OrderLegPendingStatus), want: ShipmentPickedUpStatus},
+		{name: "line haul accepted", legs: legs(OrderLegCompletedStatus, OrderLegAcceptedStatus, OrderLegPendingStatus), want: ShipmentPickedUpStatus},
+		{name: "in transit", legs: legs(OrderLegCompletedStatus, OrderLegPickedUpStatus, OrderLegPendingStatus), want: InTransitStatus},
+		{name: "at the destination hub", legs: legs(OrderLegCompletedStatus, OrderLegCompletedStatus, OrderLegPendingStatus), want: AtDestinationCityStatus},
+		{name: "last mile accepted", legs: legs(OrderLegCompletedStatus, OrderLegCompletedStatus, OrderLegAcceptedStatus), want: AtDestinationCityStatus},
+		{name: "out for delivery", legs: legs(OrderLegCompletedStatus, OrderLegCompletedStatus, OrderLegPickedUpStatus), want: OutForDeliveryStatus},
+		{name: "delivered", legs: legs(OrderLegCompletedStatus, OrderLegCompletedStatus, OrderLegCompletedStatus), want: ShipmentDeliveredStatus},
+		{name: "canceled leg", legs: legs(OrderLegCompletedStatus, OrderLegCanceledStatus, OrderLegCanceledStatus), want: ShipmentPickedUpStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OrderStatusFromLegs(tt.legs); got != tt.want {
+				t.Errorf("OrderStatusFromLegs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package entity
+
+// OrderQuote represent the estimated route and price of an order before it is created
+type OrderQuote struct {
+	Distance        float64 `json:"distance"`
+	Duration        float64 `json:"duration"`
+	Polyline        string  `json:"polyline"`
+	ServiceAreaID   uint64  `json:"service_area_id"`
+	PriceMultiplier float64 `json:"price_multiplier"`
+	SurgeMultiplier float64 `json:"surge_multiplier"`
+	Amount          float64 `json:"amount"`
+}
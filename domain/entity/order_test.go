@@ -0,0 +1,123 @@
+package entity
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestOrderRefundableAmount(t *testing.T) {
+	amount := func(amount float64) *float64 { return &amount }
+
+	tests := []struct {
+		name     string
+		amount   *float64
+		discount *float64
+		refunded float64
+		want     float64
+	}{
+		{name: "no amount", amount: nil, refunded: 0, want: 0},
+		{name: "nothing refunded", amount: amount(50), refunded: 0, want: 50},
+		{name: "partly refunded", amount: amount(50), refunded: 20.25, want: 29.75},
+		{name: "refunds adding up to the amount", amount: amount(10), refunded: 3.3 + 6.7, want: 0},
+		{name: "refunds in cents", amount: amount(0.3), refunded: 0.1 + 0.1, want: 0.1},
+		{name: "refunded more than the amount", amount: amount(10), refunded: 12, want: 0},
+		{name: "discounted", amount: amount(50), discount: amount(10.5), refunded: 0, want: 39.5},
+		{name: "discounted and partly refunded", amount: amount(50), discount: amount(10), refunded: 15, want: 25},
+		{name: "discounted and fully refunded", amount: amount(50), discount: amount(10), refunded: 40, want: 0},
+		{name: "fully discounted", amount: amount(50), discount: amount(50), refunded: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := Order{Amount: tt.amount, Discount: tt.discount}
+			if got := order.RefundableAmount(tt.refunded); got != tt.want {
+				t.Errorf("RefundableAmount(%v) = %v, want %v", tt.refunded, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderCreditToSpend(t *testing.T) {
+	amount := func(amount float64) *float64 { return &amount }
+
+	tests := []struct {
+		name     string
+		amount   *float64
+		discount *float64
+		credit   float64
+		want     float64
+	}{
+		{name: "no amount", amount: nil, credit: 20, want: 0},
+		{name: "no credit", amount: amount(50), credit: 0, want: 0},
+		{name: "negative credit", amount: amount(50), credit: -5, want: 0},
+		{name: "credit below the amount", amount: amount(50), credit: 20, want: 20},
+		{name: "credit above the amount", amount: amount(50), credit: 80, want: 50},
+		{name: "credit above the discounted amount", amount: amount(50), discount: amount(10.5), credit: 80, want: 39.5},
+		{name: "fully discounted", amount: amount(50), discount: amount(50), credit: 20, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := Order{Amount: tt.amount, Discount: tt.discount}
+			if got := order.CreditToSpend(tt.credit); got != tt.want {
+				t.Errorf("CreditToSpend(%v) = %v, want %v", tt.credit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderShareTokenExpired(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	deliveredAt := func(ago time.Duration) *time.Time {
+		deliveredAt := now.Add(-ago)
+		return &deliveredAt
+	}
+
+	tests := []struct {
+		name        string
+		status      OrderStatus
+		deliveredAt *time.Time
+		want        bool
+	}{
+		{name: "created", status: OrderCreatedStatus, want: false},
+		{name: "out for delivery", status: OutForDeliveryStatus, want: false},
+		{name: "scheduled", status: OrderScheduledStatus, want: false},
+		{name: "delivered an hour ago", status: ShipmentDeliveredStatus, deliveredAt: deliveredAt(time.Hour), want: false},
+		{name: "delivered a day ago", status: ShipmentDeliveredStatus, deliveredAt: deliveredAt(24 * time.Hour), want: false},
+		{name: "delivered over a day ago", status: ShipmentDeliveredStatus, deliveredAt: deliveredAt(25 * time.Hour), want: true},
+		{name: "delivered at an unknown time", status: ShipmentDeliveredStatus, want: true},
+		{name: "completed", status: OrderCompletedStatus, deliveredAt: deliveredAt(time.Hour), want: true},
+		{name: "canceled", status: OrderCanceledStatus, want: true},
+		{name: "returned", status: ShipmentReturnedStatus, deliveredAt: deliveredAt(time.Hour), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := Order{Status: tt.status, DeliveredAt: tt.deliveredAt}
+			if got := order.ShareTokenExpired(now); got != tt.want {
+				t.Errorf("ShareTokenExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTrackingNumber(t *testing.T) {
+	format := regexp.MustCompile(`^JY[0-9A-F]{10}$`)
+	seen := make(map[string]bool)
+
+	for i := 0; i < 1000; i++ {
+		trackingNumber, err := NewTrackingNumber()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !format.MatchString(trackingNumber) {
+			t.Fatalf("NewTrackingNumber() = %q, want JY and 10 uppercase hexadecimal digits", trackingNumber)
+		}
+		if seen[trackingNumber] {
+			t.Fatalf("NewTrackingNumber() drew %q twice", trackingNumber)
+		}
+		seen[trackingNumber] = true
+	}
+}
@@ -0,0 +1,57 @@
+package entity
+
+import "time"
+
+// OrderTimeline represent an entry in an order's timeline
+type OrderTimeline struct {
+	ID             uint64             `gorm:"primary_key;auto_increment" json:"id"`
+	OrderID        uint64             `gorm:"index;" json:"order_id" validate:"required,numeric"`
+	Event          OrderTimelineEvent `gorm:"size:255;index;" json:"event" validate:"required,oneof=status_changed return_requested return_created parcel_scanned"`
+	Status         OrderStatus        `gorm:"size:255;default:null" json:"status"`
+	RelatedOrderID uint64             `gorm:"default:null;index;" json:"related_order_id" validate:"omitempty,numeric"`
+	ScanID         uint64             `gorm:"default:null;index;" json:"scan_id"`
+	Note           *string            `gorm:"type:varchar(255);default:null" json:"note"`
+	CreatedAt      time.Time          `gorm:"default:CURRENT_TIMESTAMP;index;" json:"created_at"`
+	Scan           Scan               `gorm:"foreignKey:ScanID" json:"scan"`
+}
+
+type OrderTimelinePublicData struct {
+	ID             uint64             `json:"id"`
+	OrderID        uint64             `json:"order_id"`
+	Event          OrderTimelineEvent `json:"event"`
+	Status         OrderStatus        `json:"status"`
+	RelatedOrderID uint64             `json:"related_order_id"`
+	ScanID         uint64             `json:"scan_id"`
+	Note           *string            `json:"note"`
+	CreatedAt      time.Time          `json:"created_at"`
+	Scan           *ScanPublicData    `json:"scan"`
+}
+
+type OrderTimelineEvent string
+
+const (
+	OrderStatusChangedEvent   OrderTimelineEvent = "status_changed"
+	OrderReturnRequestedEvent OrderTimelineEvent = "return_requested"
+	OrderReturnCreatedEvent   OrderTimelineEvent = "return_created"
+	OrderParcelScannedEvent   OrderTimelineEvent = "parcel_scanned"
+)
+
+// PublicData returns a copy of the order timeline's public information
+func (t *OrderTimeline) PublicData() interface{} {
+	var scanPublicData *ScanPublicData
+	if t.Scan.ID != 0 {
+		scanPublicData = t.Scan.PublicData().(*ScanPublicData)
+	}
+
+	return &OrderTimelinePublicData{
+		ID:             t.ID,
+		OrderID:        t.OrderID,
+		Event:          t.Event,
+		Status:         t.Status,
+		RelatedOrderID: t.RelatedOrderID,
+		ScanID:         t.ScanID,
+		Note:           t.Note,
+		CreatedAt:      t.CreatedAt,
+		Scan:           scanPublicData,
+	}
+}
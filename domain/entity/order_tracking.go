@@ -0,0 +1,110 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// OrderTracking represent the view of an order given through its public tracking link. It leaves
+// out the parties' contact details, the addresses and the amounts, since anyone with the link can
+// see it.
+type OrderTracking struct {
+	TrackingNumber  string                `json:"tracking_number"`
+	Status          OrderStatus           `json:"status"`
+	IsIntercity     bool                  `json:"is_intercity"`
+	City            string                `json:"city"`
+	DestinationCity string                `json:"destination_city"`
+	ETA             *float64              `json:"eta"`
+	DeliveredAt     *time.Time            `json:"delivered_at"`
+	CreatedAt       time.Time             `json:"created_at"`
+	Driver          *OrderTrackingDriver  `json:"driver"`
+	DriverLocation  *OrderTrackingPoint   `json:"driver_location"`
+	Timeline        []*OrderTrackingEvent `json:"timeline"`
+}
+
+// OrderTrackingDriver holds what the tracking link shows of the driver carrying the order
+type OrderTrackingDriver struct {
+	FirstName          string                        `json:"first_name"`
+	Car                string                        `json:"car"`
+	TransportationMode *TransportationModePublicData `json:"transportation_mode"`
+}
+
+type OrderTrackingPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// OrderTrackingEvent is an entry of the order timeline, without its notes
+type OrderTrackingEvent struct {
+	Event     OrderTimelineEvent `json:"event"`
+	Status    OrderStatus        `json:"status"`
+	ScanType  ScanType           `json:"scan_type,omitempty"`
+	Hub       string             `json:"hub,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// TrackingData returns the view of the order given through its public tracking link. The driver's
+// location is only shared while they're on their way to the recipient.
+func (o *Order) TrackingData(timelines []OrderTimeline, eta *float64, languageCode string) *OrderTracking {
+	tracking := &OrderTracking{
+		TrackingNumber:  o.TrackingNumber,
+		Status:          o.Status,
+		IsIntercity:     o.IsIntercity,
+		City:            cityName(o.City, languageCode),
+		DestinationCity: cityName(o.DestinationCity, languageCode),
+		ETA:             eta,
+		DeliveredAt:     o.DeliveredAt,
+		CreatedAt:       o.CreatedAt,
+		Timeline:        make([]*OrderTrackingEvent, 0, len(timelines)),
+	}
+
+	if o.Driver.ID != 0 {
+		var firstName string
+		if names := strings.Fields(o.Driver.User.Name); len(names) > 0 {
+			firstName = names[0]
+		}
+
+		tracking.Driver = &OrderTrackingDriver{
+			FirstName: firstName,
+			Car:       o.Driver.Car,
+		}
+		if transportationMode, ok := o.Driver.TransportationMode.PublicData(languageCode).(*TransportationModePublicData); ok {
+			tracking.Driver.TransportationMode = transportationMode
+		}
+
+		if o.IsOutForDelivery() {
+			tracking.DriverLocation = &OrderTrackingPoint{
+				Latitude:  o.Driver.Latitude,
+				Longitude: o.Driver.Longitude,
+			}
+		}
+	}
+
+	for _, timeline := range timelines {
+		event := &OrderTrackingEvent{
+			Event:     timeline.Event,
+			Status:    timeline.Status,
+			CreatedAt: timeline.CreatedAt,
+		}
+		if timeline.Scan.ID != 0 {
+			event.ScanType = timeline.Scan.Type
+			if timeline.Scan.Hub.ID != 0 {
+				event.Hub = timeline.Scan.Hub.NameEn
+				if languageCode == "ar" {
+					event.Hub = timeline.Scan.Hub.NameAr
+				}
+			}
+		}
+		tracking.Timeline = append(tracking.Timeline, event)
+	}
+
+	return tracking
+}
+
+// cityName returns the name of a city in the given language, or an empty name when it isn't known
+func cityName(city City, languageCode string) string {
+	if languageCode == "ar" && city.NameAr != "" {
+		return city.NameAr
+	}
+	return city.NameEn
+}
@@ -0,0 +1,88 @@
+package entity
+
+import "time"
+
+// Organization represent a business customer whose member users create orders together, and
+// integrate with the API through API keys
+type Organization struct {
+	ID                     uint64    `gorm:"primary_key;auto_increment" json:"id"`
+	Name                   string    `gorm:"size:255;not null;" json:"name" validate:"required,max=255"`
+	CommercialRegistration *string   `gorm:"size:255;default:null" json:"commercial_registration" validate:"omitempty,max=255"`
+	VATNumber              *string   `gorm:"size:255;default:null" json:"vat_number" validate:"omitempty,max=255"`
+	CreatedAt              time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt              time.Time `gorm:"default:null" json:"updated_at"`
+}
+
+type OrganizationPublicData struct {
+	ID                     uint64    `json:"id"`
+	Name                   string    `json:"name"`
+	CommercialRegistration *string   `json:"commercial_registration"`
+	VATNumber              *string   `json:"vat_number"`
+	CreatedAt              time.Time `json:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at"`
+}
+
+// OrganizationMember represent the membership of a user in an organization
+type OrganizationMember struct {
+	ID             uint64           `gorm:"primary_key;auto_increment" json:"id"`
+	OrganizationID uint64           `gorm:"uniqueIndex:idx_organization_members_organization_user;" json:"organization_id"`
+	UserID         uint64           `gorm:"uniqueIndex:idx_organization_members_organization_user;index;" json:"user_id"`
+	Role           OrganizationRole `gorm:"size:255;default:member" json:"role" validate:"required,oneof=owner admin member"`
+	CreatedAt      time.Time        `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	Organization   Organization     `gorm:"foreignKey:OrganizationID" json:"organization"`
+	User           User             `gorm:"foreignKey:UserID" json:"user"`
+}
+
+type OrganizationMemberPublicData struct {
+	ID             uint64           `json:"id"`
+	OrganizationID uint64           `json:"organization_id"`
+	UserID         uint64           `json:"user_id"`
+	Role           OrganizationRole `json:"role"`
+	CreatedAt      time.Time        `json:"created_at"`
+	User           *UserPublicData  `json:"user"`
+}
+
+type OrganizationRole string
+
+const (
+	// OrganizationOwnerRole manages the organization, its members and its API keys
+	OrganizationOwnerRole OrganizationRole = "owner"
+	// OrganizationAdminRole manages the members and the API keys, except for the owners
+	OrganizationAdminRole OrganizationRole = "admin"
+	// OrganizationMemberRole creates and follows the orders of the organization
+	OrganizationMemberRole OrganizationRole = "member"
+)
+
+// CanManage reports whether the member can manage the members and the API keys of the organization
+func (m *OrganizationMember) CanManage() bool {
+	return m.Role == OrganizationOwnerRole || m.Role == OrganizationAdminRole
+}
+
+// PublicData returns a copy of the organization's public information
+func (o *Organization) PublicData() interface{} {
+	return &OrganizationPublicData{
+		ID:                     o.ID,
+		Name:                   o.Name,
+		CommercialRegistration: o.CommercialRegistration,
+		VATNumber:              o.VATNumber,
+		CreatedAt:              o.CreatedAt,
+		UpdatedAt:              o.UpdatedAt,
+	}
+}
+
+// PublicData returns a copy of the organization member's public information
+func (m *OrganizationMember) PublicData(languageCode string) interface{} {
+	var userPublicData *UserPublicData
+	if m.User.ID != 0 {
+		userPublicData = m.User.PublicData(languageCode).(*UserPublicData)
+	}
+
+	return &OrganizationMemberPublicData{
+		ID:             m.ID,
+		OrganizationID: m.OrganizationID,
+		UserID:         m.UserID,
+		Role:           m.Role,
+		CreatedAt:      m.CreatedAt,
+		User:           userPublicData,
+	}
+}
@@ -0,0 +1,183 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// OutboxEvent represent a domain event. Events are written in the same transaction as the change
+// they describe, then delivered to the subscribers by a dispatcher, so a change is never committed
+// without its event or announced without being committed.
+type OutboxEvent struct {
+	ID uint64 `gorm:"primary_key;auto_increment" json:"id"`
+	// AggregateID groups the events of an entity, such as order-12, which are delivered in order
+	AggregateID string            `gorm:"size:255;index;" json:"aggregate_id"`
+	Type        EventType         `gorm:"size:255;index;" json:"type"`
+	Payload     datatypes.JSON    `gorm:"type:json" json:"payload"`
+	Status      OutboxEventStatus `gorm:"size:255;default:pending;index;" json:"status"`
+	Attempts    int               `gorm:"default:0" json:"attempts"`
+	// HandledBy lists the subscribers that handled the event, so a retry only goes to the others
+	HandledBy     datatypes.JSON `gorm:"type:json" json:"handled_by"`
+	LastError     string         `gorm:"type:text;" json:"last_error"`
+	NextAttemptAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"next_attempt_at"`
+	DispatchedAt  *time.Time     `gorm:"default:null" json:"dispatched_at"`
+	CreatedAt     time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName overrides the table name of the outbox events
+func (OutboxEvent) TableName() string {
+	return "outbox"
+}
+
+type EventType string
+
+const (
+	// OrderAcceptedEvent is emitted when the sender accepts a driver's offer
+	OrderAcceptedEvent EventType = "order.accepted"
+	// OrderCanceledEvent is emitted when the sender cancels an order
+	OrderCanceledEvent EventType = "order.canceled"
+	// OrderDeliveredEvent is emitted when the driver delivers an order
+	OrderDeliveredEvent EventType = "order.delivered"
+	// OrderDriverRatedEvent is emitted when the sender rates the driver of an order
+	OrderDriverRatedEvent EventType = "order.driver_rated"
+	// OrderRecipientLinkedEvent is emitted when the recipient of an order signs up
+	OrderRecipientLinkedEvent EventType = "order.recipient_linked"
+	// OrderReturnedEvent is emitted when the recipient sends a delivered order back to its sender
+	OrderReturnedEvent EventType = "order.returned"
+	// OrderStatusUpdatedEvent is emitted on every change of an order's status
+	OrderStatusUpdatedEvent EventType = "order.status_changed"
+	// OfferCreatedEvent is emitted when a driver makes an offer on an order
+	OfferCreatedEvent EventType = "offer.created"
+	// OfferAcceptedEvent is emitted when the sender accepts an offer
+	OfferAcceptedEvent EventType = "offer.accepted"
+	// OfferDeclinedEvent is emitted when an offer is declined, by the sender or because another one was accepted
+	OfferDeclinedEvent EventType = "offer.declined"
+)
+
+type OutboxEventStatus string
+
+const (
+	// OutboxEventPendingStatus is an event waiting to be delivered
+	OutboxEventPendingStatus OutboxEventStatus = "pending"
+	// OutboxEventDispatchedStatus is an event delivered to all its subscribers
+	OutboxEventDispatchedStatus OutboxEventStatus = "dispatched"
+	// OutboxEventFailedStatus is an event given up on after too many attempts
+	OutboxEventFailedStatus OutboxEventStatus = "failed"
+)
+
+// OrderEventPayload is the payload of the order events
+type OrderEventPayload struct {
+	OrderID      uint64      `json:"order_id"`
+	Status       OrderStatus `json:"status"`
+	UserID       uint64      `json:"user_id"`
+	DriverID     uint64      `json:"driver_id,omitempty"`
+	DriverUserID uint64      `json:"driver_user_id,omitempty"`
+	RecipientID  uint64      `json:"recipient_id,omitempty"`
+}
+
+// OfferEventPayload is the payload of the offer events
+type OfferEventPayload struct {
+	OfferID    uint64      `json:"offer_id"`
+	OrderID    uint64      `json:"order_id"`
+	OrderLegID uint64      `json:"order_leg_id,omitempty"`
+	Status     OfferStatus `json:"status"`
+	Amount     float64     `json:"amount"`
+	UserID     uint64      `json:"user_id"`
+	DriverID   uint64      `json:"driver_id"`
+}
+
+// NewOrderEvent creates an event about an order. The driver's user ID is given separately, as the
+// driver association isn't always loaded.
+func NewOrderEvent(eventType EventType, order *Order, driverUserID uint64) (*OutboxEvent, error) {
+	return NewOrderEventFromPayload(eventType, &OrderEventPayload{
+		OrderID:      order.ID,
+		Status:       order.Status,
+		UserID:       order.UserID,
+		DriverID:     order.DriverID,
+		DriverUserID: driverUserID,
+		RecipientID:  order.RecipientID,
+	})
+}
+
+// NewOrderEventFromPayload creates an event about an order from its payload
+func NewOrderEventFromPayload(eventType EventType, payload *OrderEventPayload) (*OutboxEvent, error) {
+	return newEvent(fmt.Sprintf("order-%d", payload.OrderID), eventType, payload)
+}
+
+// NewOfferEvent creates an event about an offer made on an order of the given sender. The events of
+// the offers are grouped with the ones of their order.
+func NewOfferEvent(eventType EventType, offer *Offer, userID uint64) (*OutboxEvent, error) {
+	return newEvent(fmt.Sprintf("order-%d", offer.OrderID), eventType, &OfferEventPayload{
+		OfferID:    offer.ID,
+		OrderID:    offer.OrderID,
+		OrderLegID: offer.OrderLegID,
+		Status:     offer.Status,
+		Amount:     offer.Amount,
+		UserID:     userID,
+		DriverID:   offer.DriverID,
+	})
+}
+
+func newEvent(aggregateID string, eventType EventType, payload interface{}) (*OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxEvent{
+		AggregateID: aggregateID,
+		Type:        eventType,
+		Payload:     datatypes.JSON(data),
+		Status:      OutboxEventPendingStatus,
+	}, nil
+}
+
+// UserID returns the ID of the sender of the order an event is about
+func (e *OutboxEvent) UserID() (uint64, error) {
+	var payload struct {
+		UserID uint64 `json:"user_id"`
+	}
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return 0, err
+	}
+	return payload.UserID, nil
+}
+
+// IsHandledBy reports whether the subscriber of the given name handled the event
+func (e *OutboxEvent) IsHandledBy(name string) bool {
+	var handledBy []string
+	_ = json.Unmarshal(e.HandledBy, &handledBy)
+
+	for _, handler := range handledBy {
+		if handler == name {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkHandledBy records that the subscriber of the given name handled the event
+func (e *OutboxEvent) MarkHandledBy(name string) error {
+	var handledBy []string
+	_ = json.Unmarshal(e.HandledBy, &handledBy)
+
+	data, err := json.Marshal(append(handledBy, name))
+	if err != nil {
+		return err
+	}
+
+	e.HandledBy = datatypes.JSON(data)
+	return nil
+}
+
+// OrderPayload decodes the payload of an order event
+func (e *OutboxEvent) OrderPayload() (*OrderEventPayload, error) {
+	var payload OrderEventPayload
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Promotion represent a promo code that discounts the amount of an order
+type Promotion struct {
+	ID                uint64                `gorm:"primary_key;auto_increment" json:"id"`
+	Code              string                `gorm:"size:255;not null;uniqueIndex;" json:"code" validate:"required,alphanum"`
+	DiscountType      PromotionDiscountType `gorm:"size:255;" json:"discount_type" validate:"required,oneof=percentage fixed"`
+	DiscountValue     float64               `gorm:"type:decimal(10,2);" json:"discount_value" validate:"required,gt=0"`
+	MaxDiscount       *float64              `gorm:"type:decimal(10,2);default:null" json:"max_discount" validate:"omitempty,gt=0"`
+	GlobalUsageLimit  *int64                `gorm:"default:null" json:"global_usage_limit" validate:"omitempty,gt=0"`
+	PerUserUsageLimit *int64                `gorm:"default:null" json:"per_user_usage_limit" validate:"omitempty,gt=0"`
+	RedemptionsCount  int64                 `gorm:"not null;default:0" json:"-"`
+	StartsAt          *time.Time            `gorm:"default:null" json:"starts_at"`
+	EndsAt            *time.Time            `gorm:"default:null" json:"ends_at"`
+	CategoryIDs       datatypes.JSON        `gorm:"type:json" json:"category_ids"`
+	IsActive          bool                  `gorm:"default:true;index;" json:"is_active"`
+	CreatedAt         time.Time             `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt         time.Time             `gorm:"default:null" json:"updated_at"`
+}
+
+type PromotionPublicData struct {
+	ID                uint64                `json:"id"`
+	Code              string                `json:"code"`
+	DiscountType      PromotionDiscountType `json:"discount_type"`
+	DiscountValue     float64               `json:"discount_value"`
+	MaxDiscount       *float64              `json:"max_discount"`
+	GlobalUsageLimit  *int64                `json:"global_usage_limit"`
+	PerUserUsageLimit *int64                `json:"per_user_usage_limit"`
+	RedemptionsCount  int64                 `json:"redemptions_count"`
+	StartsAt          *time.Time            `json:"starts_at"`
+	EndsAt            *time.Time            `json:"ends_at"`
+	CategoryIDs       []uint64              `json:"category_ids"`
+	IsActive          bool                  `json:"is_active"`
+}
+
+type PromotionDiscountType string
+
+const (
+	PercentageDiscountType PromotionDiscountType = "percentage"
+	FixedDiscountType      PromotionDiscountType = "fixed"
+)
+
+// PromotionRedemption represent the use of a promotion on an order
+type PromotionRedemption struct {
+	ID          uint64    `gorm:"primary_key;auto_increment" json:"id"`
+	PromotionID uint64    `gorm:"index;" json:"promotion_id"`
+	UserID      uint64    `gorm:"index;" json:"user_id"`
+	OrderID     uint64    `gorm:"uniqueIndex;" json:"order_id"`
+	Discount    float64   `gorm:"type:decimal(10,2);" json:"discount"`
+	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// IsValidAt reports whether the promotion can be used at the given time
+func (p *Promotion) IsValidAt(t time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.StartsAt != nil && t.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !t.Before(*p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// AllowsCategory reports whether the promotion applies to orders of the category.
+// A promotion without categories applies to all of them.
+func (p *Promotion) AllowsCategory(categoryID uint64) bool {
+	var categoryIDs []uint64
+	if err := json.Unmarshal(p.CategoryIDs, &categoryIDs); err != nil || len(categoryIDs) == 0 {
+		return true
+	}
+
+	for _, id := range categoryIDs {
+		if id == categoryID {
+			return true
+		}
+	}
+	return false
+}
+
+// CalculateDiscount returns the discount the promotion grants on an amount
+func (p *Promotion) CalculateDiscount(amount float64) float64 {
+	discount := p.DiscountValue
+	if p.DiscountType == PercentageDiscountType {
+		discount = amount * p.DiscountValue / 100
+	}
+
+	if p.MaxDiscount != nil && discount > *p.MaxDiscount {
+		discount = *p.MaxDiscount
+	}
+
+	if discount > amount {
+		discount = amount
+	}
+
+	return math.Round(discount*100) / 100
+}
+
+// PublicData returns a copy of the promotion's public information
+func (p *Promotion) PublicData() interface{} {
+	var categoryIDs []uint64
+	_ = json.Unmarshal(p.CategoryIDs, &categoryIDs)
+
+	return &PromotionPublicData{
+		ID:                p.ID,
+		Code:              p.Code,
+		DiscountType:      p.DiscountType,
+		DiscountValue:     p.DiscountValue,
+		MaxDiscount:       p.MaxDiscount,
+		GlobalUsageLimit:  p.GlobalUsageLimit,
+		PerUserUsageLimit: p.PerUserUsageLimit,
+		RedemptionsCount:  p.RedemptionsCount,
+		StartsAt:          p.StartsAt,
+		EndsAt:            p.EndsAt,
+		CategoryIDs:       categoryIDs,
+		IsActive:          p.IsActive,
+	}
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+func TestPromotionCalculateDiscount(t *testing.T) {
+	maxDiscount := func(maxDiscount float64) *float64 { return &maxDiscount }
+
+	tests := []struct {
+		name          string
+		discountType  PromotionDiscountType
+		discountValue float64
+		maxDiscount   *float64
+		amount        float64
+		want          float64
+	}{
+		{name: "percentage", discountType: PercentageDiscountType, discountValue: 10, amount: 80, want: 8},
+		{name: "percentage rounded to cents", discountType: PercentageDiscountType, discountValue: 15, amount: 33.33, want: 5},
+		{name: "percentage capped", discountType: PercentageDiscountType, discountValue: 50, maxDiscount: maxDiscount(20), amount: 100, want: 20},
+		{name: "percentage under the cap", discountType: PercentageDiscountType, discountValue: 10, maxDiscount: maxDiscount(20), amount: 100, want: 10},
+		{name: "fixed", discountType: FixedDiscountType, discountValue: 15, amount: 80, want: 15},
+		{name: "fixed above the amount", discountType: FixedDiscountType, discountValue: 15, amount: 9.5, want: 9.5},
+		{name: "fixed capped", discountType: FixedDiscountType, discountValue: 15, maxDiscount: maxDiscount(12), amount: 80, want: 12},
+		{name: "no amount", discountType: FixedDiscountType, discountValue: 15, amount: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promotion := Promotion{DiscountType: tt.discountType, DiscountValue: tt.discountValue, MaxDiscount: tt.maxDiscount}
+			if got := promotion.CalculateDiscount(tt.amount); got != tt.want {
+				t.Errorf("CalculateDiscount(%v) = %v, want %v", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotionIsValidAt(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { t := now.Add(d); return &t }
+
+	tests := []struct {
+		name     string
+		isActive bool
+		startsAt *time.Time
+		endsAt   *time.Time
+		want     bool
+	}{
+		{name: "active without dates", isActive: true, want: true},
+		{name: "inactive", isActive: false, want: false},
+		{name: "started", isActive: true, startsAt: at(-time.Hour), want: true},
+		{name: "starting now", isActive: true, startsAt: at(0), want: true},
+		{name: "not started yet", isActive: true, startsAt: at(time.Hour), want: false},
+		{name: "not ended yet", isActive: true, endsAt: at(time.Hour), want: true},
+		{name: "ending now", isActive: true, endsAt: at(0), want: false},
+		{name: "ended", isActive: true, startsAt: at(-2 * time.Hour), endsAt: at(-time.Hour), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promotion := Promotion{IsActive: tt.isActive, StartsAt: tt.startsAt, EndsAt: tt.endsAt}
+			if got := promotion.IsValidAt(now); got != tt.want {
+				t.Errorf("IsValidAt(%v) = %v, want %v", now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotionAllowsCategory(t *testing.T) {
+	tests := []struct {
+		name        string
+		categoryIDs datatypes.JSON
+		categoryID  uint64
+		want        bool
+	}{
+		{name: "no categories", categoryIDs: nil, categoryID: 3, want: true},
+		{name: "empty categories", categoryIDs: datatypes.JSON(`[]`), categoryID: 3, want: true},
+		{name: "listed category", categoryIDs: datatypes.JSON(`[1,3]`), categoryID: 3, want: true},
+		{name: "unlisted category", categoryIDs: datatypes.JSON(`[1,2]`), categoryID: 3, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promotion := Promotion{CategoryIDs: tt.categoryIDs}
+			if got := promotion.AllowsCategory(tt.categoryID); got != tt.want {
+				t.Errorf("AllowsCategory(%v) = %v, want %v", tt.categoryID, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,22 +1,59 @@
 package entity
 
+import (
+	"math"
+	"time"
+)
+
 // Rating represents a rating in the system
 type Rating struct {
-	ID                uint64   `gorm:"primary_key;auto_increment" json:"id"`
-	UserID            uint64   `json:"user_id" validate:"required,numeric"`
-	OrderID           uint64   `json:"order_id" validate:"required,numeric"`
-	FastRating        *float64 `gorm:"default:null" json:"fast_rating" validate:"omitempty,numeric"`
-	ExperienceRating  *float64 `gorm:"default:null" json:"experience_rating" validate:"omitempty,numeric"`
-	RecommendedRating *float64 `gorm:"default:null" json:"recommended_rating" validate:"omitempty,numeric"`
+	ID                uint64     `gorm:"primary_key;auto_increment" json:"id"`
+	UserID            uint64     `json:"user_id" validate:"required,numeric"`
+	OrderID           uint64     `gorm:"uniqueIndex:idx_ratings_order_id_type;" json:"order_id" validate:"required,numeric"`
+	RatedUserID       uint64     `gorm:"default:null;index:idx_ratings_rated_user_id_type;" json:"rated_user_id"`
+	Type              RatingType `gorm:"size:255;uniqueIndex:idx_ratings_order_id_type;index:idx_ratings_rated_user_id_type;" json:"type"`
+	FastRating        *float64   `gorm:"default:null" json:"fast_rating" validate:"omitempty,min=1,max=5"`
+	ExperienceRating  *float64   `gorm:"default:null" json:"experience_rating" validate:"omitempty,min=1,max=5"`
+	RecommendedRating *float64   `gorm:"default:null" json:"recommended_rating" validate:"omitempty,min=1,max=5"`
+	Score             *float64   `gorm:"type:decimal(2,1);default:null" json:"score" validate:"omitempty,min=1,max=5"`
+	Review            *string    `gorm:"type:text;default:null" json:"review" validate:"omitempty,max=1000"`
+	CreatedAt         time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	User              User       `gorm:"foreignKey:UserID" json:"-"`
 }
 
 type RatingPublicData struct {
-	ID                uint64   `json:"id"`
-	UserID            uint64   `json:"user_id"`
-	OrderID           uint64   `json:"order_id"`
-	FastRating        *float64 `json:"fast_rating"`
-	ExperienceRating  *float64 `json:"experience_rating"`
-	RecommendedRating *float64 `json:"recommended_rating"`
+	ID                uint64     `json:"id"`
+	UserID            uint64     `json:"user_id"`
+	UserName          string     `json:"user_name,omitempty"`
+	OrderID           uint64     `json:"order_id"`
+	RatedUserID       uint64     `json:"rated_user_id"`
+	Type              RatingType `json:"type"`
+	FastRating        *float64   `json:"fast_rating"`
+	ExperienceRating  *float64   `json:"experience_rating"`
+	RecommendedRating *float64   `json:"recommended_rating"`
+	Score             *float64   `json:"score"`
+	Review            *string    `json:"review"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+type RatingType string
+
+const (
+	// DriverRatingType is the sender's rating of the driver who delivered the order
+	DriverRatingType RatingType = "driver"
+	// CustomerRatingType is the driver's rating of the sender of the order
+	CustomerRatingType RatingType = "customer"
+)
+
+// CalculateScore sets the overall score of a driver rating to the average of its criteria,
+// rounded to one decimal place.
+func (r *Rating) CalculateScore() {
+	if r.FastRating == nil || r.ExperienceRating == nil || r.RecommendedRating == nil {
+		return
+	}
+
+	score := math.Round((*r.FastRating+*r.ExperienceRating+*r.RecommendedRating)/3*10) / 10
+	r.Score = &score
 }
 
 // PublicData returns a copy of the rating's public information
@@ -24,9 +61,15 @@ func (r *Rating) PublicData() interface{} {
 	return &RatingPublicData{
 		ID:                r.ID,
 		UserID:            r.UserID,
+		UserName:          r.User.Name,
 		OrderID:           r.OrderID,
+		RatedUserID:       r.RatedUserID,
+		Type:              r.Type,
 		FastRating:        r.FastRating,
 		ExperienceRating:  r.ExperienceRating,
 		RecommendedRating: r.RecommendedRating,
+		Score:             r.Score,
+		Review:            r.Review,
+		CreatedAt:         r.CreatedAt,
 	}
 }
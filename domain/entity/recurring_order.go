@@ -0,0 +1,248 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// RecurringOrder represent a template that generates orders on a schedule
+type RecurringOrder struct {
+	ID                   uint64                  `gorm:"primary_key;auto_increment" json:"id"`
+	UserID               uint64                  `gorm:"index;" json:"user_id" validate:"numeric"`
+	LocationID           uint64                  `gorm:"index;" json:"location_id" validate:"numeric"`
+	CategoryID           uint64                  `gorm:"index;" json:"category_id" validate:"numeric"`
+	SizeID               uint64                  `gorm:"default:null;index;" json:"size_id" validate:"omitempty,numeric"`
+	TruckTypeID          uint64                  `gorm:"default:null;index;" json:"truck_type_id" validate:"omitempty,numeric"`
+	TruckModelID         uint64                  `gorm:"default:null;index;" json:"truck_model_id" validate:"omitempty,numeric"`
+	DeliveryTimeID       uint64                  `gorm:"index;" json:"delivery_time_id" validate:"numeric"`
+	DestinationID        uint64                  `gorm:"index;" json:"destination_id" validate:"numeric"`
+	ShipmentContentIDs   datatypes.JSON          `gorm:"type:json" json:"shipment_content_ids"`
+	ExtraServiceIDs      datatypes.JSON          `gorm:"type:json" json:"extra_service_ids"`
+	Quantity             uint64                  `gorm:"default:1" json:"quantity" validate:"numeric"`
+	RecipientPhoneNumber string                  `gorm:"type:varchar(255)" json:"recipient_phone_number"`
+	Notes                *string                 `gorm:"type:varchar(255);default:null" json:"notes"`
+	Latitude             float64                 `gorm:"type:decimal(10,8);not null;" json:"latitude"`
+	Longitude            float64                 `gorm:"type:decimal(11,8);not null;" json:"longitude"`
+	DestinationLatitude  float64                 `gorm:"type:decimal(10,8);default:null;" json:"destination_latitude"`
+	DestinationLongitude float64                 `gorm:"type:decimal(11,8);default:null;" json:"destination_longitude"`
+	DestinationAddress   *string                 `gorm:"type:varchar(255);default:null" json:"destination_address"`
+	CityID               uint64                  `gorm:"default:null;" json:"city_id"`
+	RegionID             uint64                  `gorm:"default:null;" json:"region_id"`
+	DestinationCityID    uint64                  `gorm:"default:null;" json:"destination_city_id"`
+	DestinationRegionID  uint64                  `gorm:"default:null;" json:"destination_region_id"`
+	IsIntercity          bool                    `gorm:"default:false;" json:"is_intercity"`
+	PaymentMethod        *OrderPaymentMethod     `gorm:"size:255;default:null" json:"payment_method"`
+	Frequency            RecurringOrderFrequency `gorm:"size:255;" json:"frequency" validate:"required,oneof=daily weekly"`
+	Weekday              *int64                  `gorm:"default:null" json:"weekday" validate:"omitempty,min=0,max=6"`
+	PickupTime           string                  `gorm:"size:5;" json:"pickup_time" validate:"required,datetime=15:04"`
+	PickupWindowMinutes  uint64                  `gorm:"default:60" json:"pickup_window_minutes" validate:"omitempty,numeric,min=15,max=720"`
+	Timezone             string                  `gorm:"size:255;default:Asia/Riyadh" json:"timezone" validate:"omitempty,timezone"`
+	IsActive             bool                    `gorm:"default:true;index;" json:"is_active"`
+	NextRunAt            time.Time               `gorm:"index;" json:"next_run_at"`
+	LastRunAt            *time.Time              `gorm:"default:null" json:"last_run_at"`
+	CreatedAt            time.Time               `gorm:"default:CURRENT_TIMESTAMP;index;" json:"created_at"`
+	UpdatedAt            time.Time               `gorm:"default:null" json:"updated_at"`
+}
+
+type RecurringOrderPublicData struct {
+	ID                   uint64                  `json:"id"`
+	UserID               uint64                  `json:"user_id"`
+	LocationID           uint64                  `json:"location_id"`
+	CategoryID           uint64                  `json:"category_id"`
+	SizeID               uint64                  `json:"size_id"`
+	TruckTypeID          uint64                  `json:"truck_type_id"`
+	TruckModelID         uint64                  `json:"truck_model_id"`
+	DeliveryTimeID       uint64                  `json:"delivery_time_id"`
+	DestinationID        uint64                  `json:"destination_id"`
+	ShipmentContentIDs   []uint64                `json:"shipment_content_ids"`
+	ExtraServiceIDs      []uint64                `json:"extra_service_ids"`
+	Quantity             uint64                  `json:"quantity"`
+	RecipientPhoneNumber string                  `json:"recipient_phone_number"`
+	Notes                *string                 `json:"notes"`
+	Latitude             float64                 `json:"latitude"`
+	Longitude            float64                 `json:"longitude"`
+	DestinationLatitude  float64                 `json:"destination_latitude"`
+	DestinationLongitude float64                 `json:"destination_longitude"`
+	DestinationAddress   *string                 `json:"destination_address"`
+	IsIntercity          bool                    `json:"is_intercity"`
+	PaymentMethod        *OrderPaymentMethod     `json:"payment_method"`
+	Frequency            RecurringOrderFrequency `json:"frequency"`
+	Weekday              *int64                  `json:"weekday"`
+	PickupTime           string                  `json:"pickup_time"`
+	PickupWindowMinutes  uint64                  `json:"pickup_window_minutes"`
+	Timezone             string                  `json:"timezone"`
+	IsActive             bool                    `json:"is_active"`
+	NextRunAt            time.Time               `json:"next_run_at"`
+	LastRunAt            *time.Time              `json:"last_run_at"`
+	CreatedAt            time.Time               `json:"created_at"`
+}
+
+type RecurringOrderFrequency string
+
+const (
+	DailyFrequency  RecurringOrderFrequency = "daily"
+	WeeklyFrequency RecurringOrderFrequency = "weekly"
+)
+
+// NewRecurringOrderFromOrder copies the shipment details of an order into a recurring order template
+func NewRecurringOrderFromOrder(order *Order, recurringOrder *RecurringOrder) error {
+	shipmentContentIDs, err := json.Marshal(order.ShipmentContentIDs)
+	if err != nil {
+		return err
+	}
+
+	extraServiceIDs := []uint64{}
+	if order.ExtraServiceIDs != nil {
+		extraServiceIDs = *order.ExtraServiceIDs
+	}
+
+	extraServiceIDsJSON, err := json.Marshal(extraServiceIDs)
+	if err != nil {
+		return err
+	}
+
+	recurringOrder.UserID = order.UserID
+	recurringOrder.LocationID = order.LocationID
+	recurringOrder.CategoryID = order.CategoryID
+	recurringOrder.SizeID = order.SizeID
+	recurringOrder.TruckTypeID = order.TruckTypeID
+	recurringOrder.TruckModelID = order.TruckModelID
+	recurringOrder.DeliveryTimeID = order.DeliveryTimeID
+	recurringOrder.DestinationID = order.DestinationID
+	recurringOrder.ShipmentContentIDs = datatypes.JSON(shipmentContentIDs)
+	recurringOrder.ExtraServiceIDs = datatypes.JSON(extraServiceIDsJSON)
+	recurringOrder.Quantity = order.Quantity
+	recurringOrder.RecipientPhoneNumber = order.RecipientPhoneNumber
+	recurringOrder.Notes = order.Notes
+	recurringOrder.Latitude = order.Latitude
+	recurringOrder.Longitude = order.Longitude
+	recurringOrder.DestinationLatitude = order.DestinationLatitude
+	recurringOrder.DestinationLongitude = order.DestinationLongitude
+	recurringOrder.DestinationAddress = order.DestinationAddress
+	recurringOrder.CityID = order.CityID
+	recurringOrder.RegionID = order.RegionID
+	recurringOrder.DestinationCityID = order.DestinationCityID
+	recurringOrder.DestinationRegionID = order.DestinationRegionID
+	recurringOrder.IsIntercity = order.IsIntercity
+	recurringOrder.PaymentMethod = order.PaymentMethod
+
+	return nil
+}
+
+// NewOrder builds the order generated by the template for a pickup window starting at pickupAt
+func (r *RecurringOrder) NewOrder(pickupAt time.Time) (*Order, error) {
+	var shipmentContentIDs []uint64
+	if err := json.Unmarshal(r.ShipmentContentIDs, &shipmentContentIDs); err != nil {
+		return nil, err
+	}
+
+	extraServiceIDs := []uint64{}
+	if len(r.ExtraServiceIDs) > 0 {
+		if err := json.Unmarshal(r.ExtraServiceIDs, &extraServiceIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	pickupWindowEnd := pickupAt.Add(time.Duration(r.PickupWindowMinutes) * time.Minute)
+
+	return &Order{
+		UserID:               r.UserID,
+		LocationID:           r.LocationID,
+		CategoryID:           r.CategoryID,
+		SizeID:               r.SizeID,
+		TruckTypeID:          r.TruckTypeID,
+		TruckModelID:         r.TruckModelID,
+		DeliveryTimeID:       r.DeliveryTimeID,
+		DestinationID:        r.DestinationID,
+		ShipmentContentIDs:   shipmentContentIDs,
+		ExtraServiceIDs:      &extraServiceIDs,
+		Quantity:             r.Quantity,
+		RecipientPhoneNumber: r.RecipientPhoneNumber,
+		Notes:                r.Notes,
+		Latitude:             r.Latitude,
+		Longitude:            r.Longitude,
+		DestinationLatitude:  r.DestinationLatitude,
+		DestinationLongitude: r.DestinationLongitude,
+		DestinationAddress:   r.DestinationAddress,
+		CityID:               r.CityID,
+		RegionID:             r.RegionID,
+		DestinationCityID:    r.DestinationCityID,
+		DestinationRegionID:  r.DestinationRegionID,
+		IsIntercity:          r.IsIntercity,
+		PaymentMethod:        r.PaymentMethod,
+		PickupWindowStart:    &pickupAt,
+		PickupWindowEnd:      &pickupWindowEnd,
+		RecurringOrderID:     r.ID,
+	}, nil
+}
+
+// NextOccurrence returns the first pickup time of the schedule strictly after the given time
+func (r *RecurringOrder) NextOccurrence(after time.Time) (time.Time, error) {
+	timezone := r.Timezone
+	if timezone == "" {
+		timezone = "Asia/Riyadh"
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	pickupTime, err := time.Parse("15:04", r.PickupTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	local := after.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), pickupTime.Hour(), pickupTime.Minute(), 0, 0, loc)
+
+	for !next.After(after) || (r.Frequency == WeeklyFrequency && r.Weekday != nil && int64(next.Weekday()) != *r.Weekday) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return next, nil
+}
+
+// PublicData returns a copy of the recurring order's public information
+func (r *RecurringOrder) PublicData() interface{} {
+	var shipmentContentIDs []uint64
+	_ = json.Unmarshal(r.ShipmentContentIDs, &shipmentContentIDs)
+
+	var extraServiceIDs []uint64
+	_ = json.Unmarshal(r.ExtraServiceIDs, &extraServiceIDs)
+
+	return &RecurringOrderPublicData{
+		ID:                   r.ID,
+		UserID:               r.UserID,
+		LocationID:           r.LocationID,
+		CategoryID:           r.CategoryID,
+		SizeID:               r.SizeID,
+		TruckTypeID:          r.TruckTypeID,
+		TruckModelID:         r.TruckModelID,
+		DeliveryTimeID:       r.DeliveryTimeID,
+		DestinationID:        r.DestinationID,
+		ShipmentContentIDs:   shipmentContentIDs,
+		ExtraServiceIDs:      extraServiceIDs,
+		Quantity:             r.Quantity,
+		RecipientPhoneNumber: r.RecipientPhoneNumber,
+		Notes:                r.Notes,
+		Latitude:             r.Latitude,
+		Longitude:            r.Longitude,
+		DestinationLatitude:  r.DestinationLatitude,
+		DestinationLongitude: r.DestinationLongitude,
+		DestinationAddress:   r.DestinationAddress,
+		IsIntercity:          r.IsIntercity,
+		PaymentMethod:        r.PaymentMethod,
+		Frequency:            r.Frequency,
+		Weekday:              r.Weekday,
+		PickupTime:           r.PickupTime,
+		PickupWindowMinutes:  r.PickupWindowMinutes,
+		Timezone:             r.Timezone,
+		IsActive:             r.IsActive,
+		NextRunAt:            r.NextRunAt,
+		LastRunAt:            r.LastRunAt,
+		CreatedAt:            r.CreatedAt,
+	}
+}
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecurringOrderNextOccurrence(t *testing.T) {
+	riyadh, err := time.LoadLocation("Asia/Riyadh")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	weekday := func(weekday time.Weekday) *int64 { w := int64(weekday); return &w }
+
+	// Friday, 1 March 2024 at 10:00 in Riyadh
+	after := time.Date(2024, 3, 1, 10, 0, 0, 0, riyadh)
+
+	tests := []struct {
+		name      string
+		frequency RecurringOrderFrequency
+		weekday   *int64
+		time      string
+		timezone  string
+		want      time.Time
+	}{
+		{name: "daily later today", frequency: DailyFrequency, time: "14:30", timezone: "Asia/Riyadh", want: time.Date(2024, 3, 1, 14, 30, 0, 0, riyadh)},
+		{name: "daily earlier today", frequency: DailyFrequency, time: "08:00", timezone: "Asia/Riyadh", want: time.Date(2024, 3, 2, 8, 0, 0, 0, riyadh)},
+		{name: "daily right now", frequency: DailyFrequency, time: "10:00", timezone: "Asia/Riyadh", want: time.Date(2024, 3, 2, 10, 0, 0, 0, riyadh)},
+		{name: "weekly later today", frequency: WeeklyFrequency, weekday: weekday(time.Friday), time: "14:30", timezone: "Asia/Riyadh", want: time.Date(2024, 3, 1, 14, 30, 0, 0, riyadh)},
+		{name: "weekly earlier today", frequency: WeeklyFrequency, weekday: weekday(time.Friday), time: "08:00", timezone: "Asia/Riyadh", want: time.Date(2024, 3, 8, 8, 0, 0, 0, riyadh)},
+		{name: "weekly on another day", frequency: WeeklyFrequency, weekday: weekday(time.Sunday), time: "09:00", timezone: "Asia/Riyadh", want: time.Date(2024, 3, 3, 9, 0, 0, 0, riyadh)},
+		{name: "default timezone", frequency: DailyFrequency, time: "14:30", timezone: "", want: time.Date(2024, 3, 1, 14, 30, 0, 0, riyadh)},
+		{name: "other timezone", frequency: DailyFrequency, time: "06:00", timezone: "UTC", want: time.Date(2024, 3, 2, 6, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recurringOrder := RecurringOrder{Frequency: tt.frequency, Weekday: tt.weekday, PickupTime: tt.time, Timezone: tt.timezone}
+			got, err := recurringOrder.NextOccurrence(after)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("NextOccurrence(%v) = %v, want %v", after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurringOrderNextOccurrenceInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		time     string
+		timezone string
+	}{
+		{name: "invalid pickup time", time: "25:00", timezone: "Asia/Riyadh"},
+		{name: "invalid timezone", time: "09:00", timezone: "Mars/Olympus"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recurringOrder := RecurringOrder{Frequency: DailyFrequency, PickupTime: tt.time, Timezone: tt.timezone}
+			if _, err := recurringOrder.NextOccurrence(time.Now()); err == nil {
+				t.Errorf("NextOccurrence() with pickup time %q in %q didn't fail", tt.time, tt.timezone)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Region represent an administrative region of the geocoding dataset
+type Region struct {
+	ID        uint64         `gorm:"primary_key;auto_increment" json:"id"`
+	NameAr    string         `gorm:"size:255;not null;index;" json:"name_ar"`
+	NameEn    string         `gorm:"size:255;not null;index;" json:"name_en"`
+	Latitude  float64        `gorm:"type:decimal(10,8);default:null;" json:"latitude"`
+	Longitude float64        `gorm:"type:decimal(11,8);default:null;" json:"longitude"`
+	Area      string         `gorm:"type:geography(MultiPolygon,4326);<-:false;->:false" json:"-"`
+	Boundary  datatypes.JSON `gorm:"->;-:migration" json:"-"`
+	CreatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"default:null" json:"updated_at"`
+}
+
+type RegionPublicData struct {
+	ID        uint64  `json:"region_id"`
+	NameAr    string  `json:"name_ar"`
+	NameEn    string  `json:"name_en"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// PublicData returns a copy of the region's public information
+func (r *Region) PublicData() interface{} {
+	return &RegionPublicData{
+		ID:        r.ID,
+		NameAr:    r.NameAr,
+		NameEn:    r.NameEn,
+		Latitude:  r.Latitude,
+		Longitude: r.Longitude,
+	}
+}
@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+// Scan represent a scan of a parcel label by a driver or by the hub staff, recording where the
+// parcel was seen along its way. Every scan is added to the timeline of its order.
+type Scan struct {
+	ID             uint64    `gorm:"primary_key;auto_increment" json:"id"`
+	OrderID        uint64    `gorm:"index;" json:"order_id"`
+	OrderLegID     uint64    `gorm:"default:null;index;" json:"order_leg_id"`
+	HubID          uint64    `gorm:"default:null;index;" json:"hub_id" validate:"omitempty,numeric"`
+	UserID         uint64    `gorm:"index;" json:"user_id"`
+	Type           ScanType  `gorm:"size:255;not null;index;" json:"type" validate:"required,oneof=picked_up arrived_at_hub loaded delivered"`
+	Latitude       *float64  `gorm:"type:decimal(10,8);default:null" json:"latitude" validate:"omitempty,latitude"`
+	Longitude      *float64  `gorm:"type:decimal(11,8);default:null" json:"longitude" validate:"omitempty,longitude"`
+	TrackingNumber string    `gorm:"-" json:"tracking_number" validate:"required"`
+	CreatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP;index;" json:"created_at"`
+	Hub            Hub       `gorm:"foreignKey:HubID" json:"hub"`
+}
+
+type ScanPublicData struct {
+	ID         uint64         `json:"id"`
+	OrderID    uint64         `json:"order_id"`
+	OrderLegID uint64         `json:"order_leg_id"`
+	HubID      uint64         `json:"hub_id"`
+	Type       ScanType       `json:"type"`
+	Latitude   *float64       `json:"latitude"`
+	Longitude  *float64       `json:"longitude"`
+	CreatedAt  time.Time      `json:"created_at"`
+	Hub        *HubPublicData `json:"hub"`
+}
+
+type ScanType string
+
+const (
+	PickedUpScanType     ScanType = "picked_up"
+	ArrivedAtHubScanType ScanType = "arrived_at_hub"
+	LoadedScanType       ScanType = "loaded"
+	DeliveredScanType    ScanType = "delivered"
+)
+
+// PublicData returns a copy of the scan's public information
+func (s *Scan) PublicData() interface{} {
+	var hubPublicData *HubPublicData
+	if s.Hub.ID != 0 {
+		hubPublicData = s.Hub.PublicData().(*HubPublicData)
+	}
+
+	return &ScanPublicData{
+		ID:         s.ID,
+		OrderID:    s.OrderID,
+		OrderLegID: s.OrderLegID,
+		HubID:      s.HubID,
+		Type:       s.Type,
+		Latitude:   s.Latitude,
+		Longitude:  s.Longitude,
+		CreatedAt:  s.CreatedAt,
+		Hub:        hubPublicData,
+	}
+}
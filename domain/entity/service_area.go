@@ -0,0 +1,99 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// ServiceArea represent a polygon zone where orders can be picked up and delivered
+type ServiceArea struct {
+	ID              uint64         `gorm:"primary_key;auto_increment" json:"id"`
+	Name            string         `gorm:"size:255;not null;" json:"name" validate:"required"`
+	Area            string         `gorm:"type:geography(Polygon,4326);<-:false;->:false" json:"-"`
+	Boundary        datatypes.JSON `gorm:"->;-:migration" json:"boundary" validate:"required"`
+	PriceMultiplier float64        `gorm:"type:decimal(5,2);default:1" json:"price_multiplier" validate:"omitempty,gt=0"`
+	SurgeMultiplier float64        `gorm:"type:decimal(5,2);default:1" json:"surge_multiplier"`
+	CategoryIDs     datatypes.JSON `gorm:"type:json" json:"category_ids"`
+	OpensAt         *string        `gorm:"size:5;default:null" json:"opens_at" validate:"omitempty,datetime=15:04"`
+	ClosesAt        *string        `gorm:"size:5;default:null" json:"closes_at" validate:"omitempty,datetime=15:04"`
+	Timezone        string         `gorm:"size:255;default:Asia/Riyadh" json:"timezone" validate:"omitempty,timezone"`
+	IsActive        bool           `gorm:"default:true;index;" json:"is_active"`
+	CreatedAt       time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt       time.Time      `gorm:"default:null" json:"updated_at"`
+}
+
+type ServiceAreaPublicData struct {
+	ID              uint64          `json:"id"`
+	Name            string          `json:"name"`
+	Boundary        json.RawMessage `json:"boundary"`
+	PriceMultiplier float64         `json:"price_multiplier"`
+	SurgeMultiplier float64         `json:"surge_multiplier"`
+	CategoryIDs     []uint64        `json:"category_ids"`
+	OpensAt         *string         `json:"opens_at"`
+	ClosesAt        *string         `json:"closes_at"`
+	Timezone        string          `json:"timezone"`
+	IsActive        bool            `json:"is_active"`
+}
+
+// AllowsCategory reports whether orders of the category can be placed in the service area.
+// A service area without categories allows all of them.
+func (s *ServiceArea) AllowsCategory(categoryID uint64) bool {
+	var categoryIDs []uint64
+	if err := json.Unmarshal(s.CategoryIDs, &categoryIDs); err != nil || len(categoryIDs) == 0 {
+		return true
+	}
+
+	for _, id := range categoryIDs {
+		if id == categoryID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpenAt reports whether the service area operates at the given time.
+// A service area without operating hours is always open.
+func (s *ServiceArea) IsOpenAt(t time.Time) bool {
+	if s.OpensAt == nil || s.ClosesAt == nil {
+		return true
+	}
+
+	timezone := s.Timezone
+	if timezone == "" {
+		timezone = "Asia/Riyadh"
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return true
+	}
+
+	local := t.In(loc).Format("15:04")
+
+	// Operating hours can span midnight, e.g. 18:00 to 02:00
+	if *s.OpensAt <= *s.ClosesAt {
+		return local >= *s.OpensAt && local < *s.ClosesAt
+	}
+	return local >= *s.OpensAt || local < *s.ClosesAt
+}
+
+// PublicData returns a copy of the service area's public information
+func (s *ServiceArea) PublicData() interface{} {
+	var categoryIDs []uint64
+	_ = json.Unmarshal(s.CategoryIDs, &categoryIDs)
+
+	return &ServiceAreaPublicData{
+		ID:              s.ID,
+		Name:            s.Name,
+		Boundary:        json.RawMessage(s.Boundary),
+		PriceMultiplier: s.PriceMultiplier,
+		SurgeMultiplier: s.SurgeMultiplier,
+		CategoryIDs:     categoryIDs,
+		OpensAt:         s.OpensAt,
+		ClosesAt:        s.ClosesAt,
+		Timezone:        s.Timezone,
+		IsActive:        s.IsActive,
+	}
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestServiceAreaIsOpenAt(t *testing.T) {
+	riyadh, err := time.LoadLocation("Asia/Riyadh")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hour := func(hour string) *string { return &hour }
+	at := func(h, m int) time.Time { return time.Date(2024, 3, 1, h, m, 0, 0, riyadh) }
+
+	tests := []struct {
+		name     string
+		opensAt  *string
+		closesAt *string
+		timezone string
+		t        time.Time
+		want     bool
+	}{
+		{name: "no operating hours", t: at(3, 0), want: true},
+		{name: "only opening hour", opensAt: hour("08:00"), t: at(3, 0), want: true},
+		{name: "within the day", opensAt: hour("08:00"), closesAt: hour("22:00"), t: at(12, 0), want: true},
+		{name: "at opening", opensAt: hour("08:00"), closesAt: hour("22:00"), t: at(8, 0), want: true},
+		{name: "at closing", opensAt: hour("08:00"), closesAt: hour("22:00"), t: at(22, 0), want: false},
+		{name: "before opening", opensAt: hour("08:00"), closesAt: hour("22:00"), t: at(7, 59), want: false},
+		{name: "across midnight in the evening", opensAt: hour("18:00"), closesAt: hour("02:00"), t: at(23, 30), want: true},
+		{name: "across midnight after midnight", opensAt: hour("18:00"), closesAt: hour("02:00"), t: at(1, 30), want: true},
+		{name: "across midnight at closing", opensAt: hour("18:00"), closesAt: hour("02:00"), t: at(2, 0), want: false},
+		{name: "across midnight during the day", opensAt: hour("18:00"), closesAt: hour("02:00"), t: at(12, 0), want: false},
+		{name: "time in another zone", opensAt: hour("08:00"), closesAt: hour("22:00"), t: time.Date(2024, 3, 1, 4, 0, 0, 0, time.UTC), want: false},
+		{name: "area in another zone before opening", opensAt: hour("08:00"), closesAt: hour("22:00"), timezone: "UTC", t: at(9, 0), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serviceArea := ServiceArea{OpensAt: tt.opensAt, ClosesAt: tt.closesAt, Timezone: tt.timezone}
+			if got := serviceArea.IsOpenAt(tt.t); got != tt.want {
+				t.Errorf("IsOpenAt(%v) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/config"
+)
+
+// Ticket represent a support request, optionally disputing an order
+type Ticket struct {
+	ID               uint64          `gorm:"primary_key;auto_increment" json:"id"`
+	UserID           uint64          `gorm:"index;" json:"user_id"`
+	OrderID          *uint64         `gorm:"default:null;index;" json:"order_id" validate:"omitempty,numeric"`
+	Category         TicketCategory  `gorm:"size:255;index;" json:"category" validate:"required,oneof=damaged_item late_delivery wrong_amount other"`
+	Subject          string          `gorm:"size:255;not null;" json:"subject" validate:"required,max=255"`
+	Status           TicketStatus    `gorm:"size:255;index;" json:"status"`
+	AssigneeID       *uint64         `gorm:"default:null;index;" json:"assignee_id"`
+	Resolution       *string         `gorm:"type:text;default:null" json:"resolution"`
+	RefundAmount     *float64        `gorm:"type:decimal(10,2);default:null" json:"refund_amount"`
+	DriverAdjustment *float64        `gorm:"type:decimal(10,2);default:null" json:"driver_adjustment"`
+	ResolvedAt       *time.Time      `gorm:"default:null" json:"resolved_at"`
+	CreatedAt        time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time       `gorm:"default:null" json:"updated_at"`
+	User             User            `gorm:"foreignKey:UserID" json:"-"`
+	Messages         []TicketMessage `gorm:"foreignKey:TicketID" json:"messages"`
+}
+
+type TicketPublicData struct {
+	ID               uint64         `json:"id"`
+	UserID           uint64         `json:"user_id"`
+	UserName         string         `json:"user_name,omitempty"`
+	OrderID          *uint64        `json:"order_id"`
+	Category         TicketCategory `json:"category"`
+	Subject          string         `json:"subject"`
+	Status           TicketStatus   `json:"status"`
+	AssigneeID       *uint64        `json:"assignee_id"`
+	Resolution       *string        `json:"resolution"`
+	RefundAmount     *float64       `json:"refund_amount"`
+	DriverAdjustment *float64       `json:"driver_adjustment"`
+	ResolvedAt       *time.Time     `json:"resolved_at"`
+	CreatedAt        time.Time      `json:"created_at"`
+	Messages         []interface{}  `json:"messages,omitempty"`
+}
+
+type TicketCategory string
+
+const (
+	DamagedItemTicketCategory  TicketCategory = "damaged_item"
+	LateDeliveryTicketCategory TicketCategory = "late_delivery"
+	WrongAmountTicketCategory  TicketCategory = "wrong_amount"
+	OtherTicketCategory        TicketCategory = "other"
+)
+
+type TicketStatus string
+
+const (
+	// TicketOpenStatus is a ticket waiting for an admin to pick it up
+	TicketOpenStatus TicketStatus = "open"
+	// TicketInProgressStatus is a ticket assigned to an admin
+	TicketInProgressStatus TicketStatus = "in_progress"
+	// TicketResolvedStatus is a ticket an admin has resolved
+	TicketResolvedStatus TicketStatus = "resolved"
+	// TicketClosedStatus is a ticket its user has closed without a resolution
+	TicketClosedStatus TicketStatus = "closed"
+)
+
+// TicketMessage represent a message in the history of a ticket
+type TicketMessage struct {
+	ID         uint64    `gorm:"primary_key;auto_increment" json:"id"`
+	TicketID   uint64    `gorm:"index;" json:"ticket_id"`
+	UserID     uint64    `gorm:"index;" json:"user_id"`
+	Body       string    `gorm:"type:text;not null;" json:"body" validate:"required,max=5000"`
+	Attachment *string   `gorm:"size:255;default:null" json:"attachment"`
+	CreatedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	User       User      `gorm:"foreignKey:UserID" json:"-"`
+}
+
+type TicketMessagePublicData struct {
+	ID         uint64    `json:"id"`
+	UserID     uint64    `json:"user_id"`
+	UserName   string    `json:"user_name,omitempty"`
+	IsAdmin    bool      `json:"is_admin"`
+	Body       string    `json:"body"`
+	Attachment *string   `json:"attachment"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// IsFinished reports whether the ticket has been resolved or closed
+func (t *Ticket) IsFinished() bool {
+	return t.Status == TicketResolvedStatus || t.Status == TicketClosedStatus
+}
+
+// PublicData returns a copy of the ticket's public information
+func (t *Ticket) PublicData() interface{} {
+	var messagePublicData []interface{}
+	for _, message := range t.Messages {
+		messagePublicData = append(messagePublicData, message.PublicData())
+	}
+
+	return &TicketPublicData{
+		ID:               t.ID,
+		UserID:           t.UserID,
+		UserName:         t.User.Name,
+		OrderID:          t.OrderID,
+		Category:         t.Category,
+		Subject:          t.Subject,
+		Status:           t.Status,
+		AssigneeID:       t.AssigneeID,
+		Resolution:       t.Resolution,
+		RefundAmount:     t.RefundAmount,
+		DriverAdjustment: t.DriverAdjustment,
+		ResolvedAt:       t.ResolvedAt,
+		CreatedAt:        t.CreatedAt,
+		Messages:         messagePublicData,
+	}
+}
+
+// PublicData returns a copy of the ticket message's public information
+func (m *TicketMessage) PublicData() interface{} {
+	conf := config.NewConfig()
+
+	var attachment *string
+	if m.Attachment != nil {
+		url := conf.BaseStorageURL + "/" + *m.Attachment
+		attachment = &url
+	}
+
+	return &TicketMessagePublicData{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		UserName:   m.User.Name,
+		IsAdmin:    m.User.Role == AdminRole,
+		Body:       m.Body,
+		Attachment: attachment,
+		CreatedAt:  m.CreatedAt,
+	}
+}
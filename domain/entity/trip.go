@@ -0,0 +1,220 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/pkg/geoutil"
+)
+
+// Trip represent the batch of orders a driver is carrying at once
+type Trip struct {
+	ID        uint64     `gorm:"primary_key;auto_increment" json:"id"`
+	DriverID  uint64     `gorm:"index;" json:"driver_id"`
+	Status    TripStatus `gorm:"size:255;default:trip_active;index;" json:"status" validate:"oneof=trip_active trip_completed"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP;index;" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"default:null" json:"updated_at"`
+	Stops     []TripStop `gorm:"foreignKey:TripID" json:"stops"`
+}
+
+type TripPublicData struct {
+	ID        uint64                `json:"id"`
+	DriverID  uint64                `json:"driver_id"`
+	Status    TripStatus            `json:"status"`
+	CreatedAt time.Time             `json:"created_at"`
+	Stops     []*TripStopPublicData `json:"stops"`
+}
+
+type TripStatus string
+
+const (
+	TripActiveStatus    TripStatus = "trip_active"
+	TripCompletedStatus TripStatus = "trip_completed"
+)
+
+// TripStop represent a pickup or drop-off point of an order within a trip
+type TripStop struct {
+	ID             uint64         `gorm:"primary_key;auto_increment" json:"id"`
+	TripID         uint64         `gorm:"index;" json:"trip_id"`
+	OrderID        uint64         `gorm:"index;" json:"order_id"`
+	OrderDropOffID uint64         `gorm:"default:null;index;" json:"order_drop_off_id"`
+	Type           TripStopType   `gorm:"size:255;" json:"type" validate:"oneof=pickup drop_off"`
+	Sequence       uint64         `gorm:"default:0" json:"sequence"`
+	Latitude       float64        `gorm:"type:decimal(10,8);not null;" json:"latitude"`
+	Longitude      float64        `gorm:"type:decimal(11,8);not null;" json:"longitude"`
+	Status         TripStopStatus `gorm:"size:255;default:stop_pending;index;" json:"status" validate:"oneof=stop_pending stop_done"`
+	CompletedAt    *time.Time     `gorm:"default:null" json:"completed_at"`
+	CreatedAt      time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"default:null" json:"updated_at"`
+}
+
+type TripStopPublicData struct {
+	ID             uint64         `json:"id"`
+	TripID         uint64         `json:"trip_id"`
+	OrderID        uint64         `json:"order_id"`
+	OrderDropOffID uint64         `json:"order_drop_off_id"`
+	Type           TripStopType   `json:"type"`
+	Sequence       uint64         `json:"sequence"`
+	Latitude       float64        `json:"latitude"`
+	Longitude      float64        `json:"longitude"`
+	Status         TripStopStatus `json:"status"`
+	CompletedAt    *time.Time     `json:"completed_at"`
+}
+
+type TripStopType string
+
+const (
+	PickupStopType  TripStopType = "pickup"
+	DropOffStopType TripStopType = "drop_off"
+)
+
+type TripStopStatus string
+
+const (
+	TripStopPendingStatus TripStopStatus = "stop_pending"
+	TripStopDoneStatus    TripStopStatus = "stop_done"
+)
+
+// NewTripStops returns the pickup stop and the drop-off stops of an order
+func NewTripStops(order *Order) []TripStop {
+	stops := []TripStop{
+		{OrderID: order.ID, Type: PickupStopType, Latitude: order.Latitude, Longitude: order.Longitude},
+	}
+
+	// Orders without explicit drop-offs are delivered to their destination
+	if len(order.DropOffs) == 0 {
+		return append(stops, TripStop{OrderID: order.ID, Type: DropOffStopType, Latitude: order.DestinationLatitude, Longitude: order.DestinationLongitude})
+	}
+
+	for _, dropOff := range order.DropOffs {
+		stops = append(stops, TripStop{OrderID: order.ID, OrderDropOffID: dropOff.ID, Type: DropOffStopType, Latitude: dropOff.Latitude, Longitude: dropOff.Longitude})
+	}
+
+	return stops
+}
+
+// IsCompleted reports whether every stop of the trip is done
+func (t *Trip) IsCompleted() bool {
+	for _, stop := range t.Stops {
+		if stop.Status != TripStopDoneStatus {
+			return false
+		}
+	}
+	return true
+}
+
+// tripStopOrderStatuses lists, for each status the stops move an order to, the statuses the order
+// moves from. An order already further along, or finished, is left as it is.
+var tripStopOrderStatuses = map[OrderStatus][]OrderStatus{
+	ShipmentPickedUpStatus: {OrderAcceptedStatus, PickupInProgressStatus},
+	OutForDeliveryStatus:   {OrderAcceptedStatus, PickupInProgressStatus, ShipmentPickedUpStatus, InTransitStatus},
+	ShipmentDeliveredStatus: {OrderAcceptedStatus, PickupInProgressStatus, ShipmentPickedUpStatus, InTransitStatus,
+		OutForDeliveryStatus, DeliveryAttemptedStatus, DeliveryRescheduledStatus},
+}
+
+// OrderStatusAfterStop returns the status the order moves to once the stop, already marked as done
+// in the trip, is done: picked up after its pickup, out for delivery while some of its drop-offs are
+// left and delivered after the last one. It reports false when the order doesn't move.
+func (t *Trip) OrderStatusAfterStop(order *Order, stop *TripStop) (OrderStatus, bool) {
+	status := ShipmentPickedUpStatus
+	if stop.Type == DropOffStopType {
+		status = ShipmentDeliveredStatus
+		for _, tripStop := range t.Stops {
+			if tripStop.OrderID == stop.OrderID && tripStop.Type == DropOffStopType && tripStop.Status != TripStopDoneStatus {
+				status = OutForDeliveryStatus
+			}
+		}
+	}
+
+	for _, from := range tripStopOrderStatuses[status] {
+		if order.Status == from {
+			return status, true
+		}
+	}
+	return "", false
+}
+
+// SuggestedSequence orders the pending stops of the trip with a nearest neighbour heuristic
+// starting from the given position. A drop-off is only visited once its order has been picked up.
+func (t *Trip) SuggestedSequence(latitude float64, longitude float64) []TripStop {
+	pickedUp := make(map[uint64]bool)
+	var pending []TripStop
+
+	for _, stop := range t.Stops {
+		if stop.Status == TripStopDoneStatus {
+			if stop.Type == PickupStopType {
+				pickedUp[stop.OrderID] = true
+			}
+			continue
+		}
+		pending = append(pending, stop)
+	}
+
+	sequence := make([]TripStop, 0, len(pending))
+	for len(pending) > 0 {
+		next := -1
+		nextDistance := 0.0
+
+		for i, stop := range pending {
+			if stop.Type == DropOffStopType && !pickedUp[stop.OrderID] {
+				continue
+			}
+
+			distance := geoutil.CalculateDistance(latitude, longitude, stop.Latitude, stop.Longitude)
+			if next == -1 || distance < nextDistance {
+				next = i
+				nextDistance = distance
+			}
+		}
+
+		// Drop-offs whose pickup is missing from the trip can't be reached, keep them last
+		if next == -1 {
+			sequence = append(sequence, pending...)
+			break
+		}
+
+		stop := pending[next]
+		stop.Sequence = uint64(len(sequence) + 1)
+		sequence = append(sequence, stop)
+
+		if stop.Type == PickupStopType {
+			pickedUp[stop.OrderID] = true
+		}
+
+		latitude, longitude = stop.Latitude, stop.Longitude
+		pending = append(pending[:next], pending[next+1:]...)
+	}
+
+	return sequence
+}
+
+// PublicData returns a copy of the trip's public information
+func (t *Trip) PublicData() interface{} {
+	stopPublicDataList := make([]*TripStopPublicData, len(t.Stops))
+	for i, stop := range t.Stops {
+		stopPublicDataList[i] = stop.PublicData().(*TripStopPublicData)
+	}
+
+	return &TripPublicData{
+		ID:        t.ID,
+		DriverID:  t.DriverID,
+		Status:    t.Status,
+		CreatedAt: t.CreatedAt,
+		Stops:     stopPublicDataList,
+	}
+}
+
+// PublicData returns a copy of the trip stop's public information
+func (s *TripStop) PublicData() interface{} {
+	return &TripStopPublicData{
+		ID:             s.ID,
+		TripID:         s.TripID,
+		OrderID:        s.OrderID,
+		OrderDropOffID: s.OrderDropOffID,
+		Type:           s.Type,
+		Sequence:       s.Sequence,
+		Latitude:       s.Latitude,
+		Longitude:      s.Longitude,
+		Status:         s.Status,
+		CompletedAt:    s.CompletedAt,
+	}
+}
@@ -0,0 +1,38 @@
+package entity
+
+import "testing"
+
+func TestTripOrderStatusAfterStop(t *testing.T) {
+	pickup := TripStop{ID: 1, OrderID: 1, Type: PickupStopType, Status: TripStopDoneStatus}
+	firstDropOff := TripStop{ID: 2, OrderID: 1, Type: DropOffStopType, Status: TripStopDoneStatus}
+	lastDropOff := TripStop{ID: 3, OrderID: 1, Type: DropOffStopType, Status: TripStopPendingStatus}
+	otherDropOff := TripStop{ID: 4, OrderID: 2, Type: DropOffStopType, Status: TripStopPendingStatus}
+
+	tests := []struct {
+		name   string
+		stops  []TripStop
+		stop   TripStop
+		status OrderStatus
+		want   OrderStatus
+		moves  bool
+	}{
+		{name: "pickup", stops: []TripStop{pickup, firstDropOff}, stop: pickup, status: OrderAcceptedStatus, want: ShipmentPickedUpStatus, moves: true},
+		{name: "pickup in progress", stops: []TripStop{pickup, firstDropOff}, stop: pickup, status: PickupInProgressStatus, want: ShipmentPickedUpStatus, moves: true},
+		{name: "pickup of an order further along", stops: []TripStop{pickup, firstDropOff}, stop: pickup, status: OutForDeliveryStatus, moves: false},
+		{name: "drop-off with others left", stops: []TripStop{pickup, firstDropOff, lastDropOff}, stop: firstDropOff, status: ShipmentPickedUpStatus, want: OutForDeliveryStatus, moves: true},
+		{name: "drop-off with others left out for delivery", stops: []TripStop{pickup, firstDropOff, lastDropOff}, stop: firstDropOff, status: OutForDeliveryStatus, moves: false},
+		{name: "last drop-off", stops: []TripStop{pickup, firstDropOff, otherDropOff}, stop: firstDropOff, status: OutForDeliveryStatus, want: ShipmentDeliveredStatus, moves: true},
+		{name: "drop-off of a delivered order", stops: []TripStop{pickup, firstDropOff}, stop: firstDropOff, status: ShipmentDeliveredStatus, moves: false},
+		{name: "drop-off of a canceled order", stops: []TripStop{pickup, firstDropOff}, stop: firstDropOff, status: OrderCanceledStatus, moves: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trip := Trip{Stops: tt.stops}
+			got, moves := trip.OrderStatusAfterStop(&Order{ID: tt.stop.OrderID, Status: tt.status}, &tt.stop)
+			if got != tt.want || moves != tt.moves {
+				t.Errorf("OrderStatusAfterStop() = %q, %v, want %q, %v", got, moves, tt.want, tt.moves)
+			}
+		})
+	}
+}
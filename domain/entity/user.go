@@ -1,10 +1,9 @@
 package entity
 
 import (
+	"crypto/rand"
 	"encoding/json"
 	"errors"
-	"fmt"
-	"strconv"
 	"time"
 
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/config"
@@ -28,6 +27,9 @@ type User struct {
 	CreatedAt                  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt                  time.Time      `gorm:"default:null" json:"updated_at"`
 	Role                       Role           `gorm:"size:255;default:user" json:"role" validate:"required,oneof=admin user"`
+	ReferralCode               *string        `gorm:"size:255;uniqueIndex;default:null" json:"-"`
+	ReferredByID               uint64         `gorm:"default:null;index;" json:"-"`
+	ReferredByCode             *string        `gorm:"-" json:"referral_code"`
 	Location                   Location       `gorm:"foreignKey:LocationID" json:"location"`
 	IsDriver                   bool           `gorm:"-" json:"is_driver"`
 	PurchasesCount             int64          `gorm:"-" json:"purchases_count"`
@@ -36,11 +38,13 @@ type User struct {
 	TripsCount                 int64          `gorm:"-" json:"trips_count"`
 	BalancesSumBalance         int64          `gorm:"-" json:"balances_sum_balance"`
 	MonthlyRevenue             int64          `gorm:"-" json:"monthly_revenue"`
-	RatingsAvgScore            float64        `gorm:"-" json:"ratings_avg_score"`
-	FastRatingsAvgScore        float64        `gorm:"-" json:"fast_ratings_avg_score"`
-	ExperienceRatingsAvgScore  float64        `gorm:"-" json:"experience_ratings_avg_score"`
-	RecommendedRatingsAvgScore float64        `gorm:"-" json:"recommended_ratings_avg_score"`
-	ReviewsCount               int64          `gorm:"-" json:"reviews_count"`
+	RatingsAvgScore            float64        `gorm:"type:decimal(2,1);default:0;<-:false" json:"ratings_avg_score"`
+	FastRatingsAvgScore        float64        `gorm:"type:decimal(2,1);default:0;<-:false" json:"fast_ratings_avg_score"`
+	ExperienceRatingsAvgScore  float64        `gorm:"type:decimal(2,1);default:0;<-:false" json:"experience_ratings_avg_score"`
+	RecommendedRatingsAvgScore float64        `gorm:"type:decimal(2,1);default:0;<-:false" json:"recommended_ratings_avg_score"`
+	ReviewsCount               int64          `gorm:"default:0;<-:false" json:"reviews_count"`
+	CustomerRatingsAvgScore    float64        `gorm:"type:decimal(2,1);default:0;<-:false" json:"customer_ratings_avg_score"`
+	CustomerReviewsCount       int64          `gorm:"default:0;<-:false" json:"customer_reviews_count"`
 }
 
 type UserPublicData struct {
@@ -65,6 +69,8 @@ type UserPublicData struct {
 	ExperienceRatingsAvgScore  float64                  `json:"experience_ratings_avg_score"`
 	RecommendedRatingsAvgScore float64                  `json:"recommended_ratings_avg_score"`
 	ReviewsCount               int64                    `json:"reviews_count"`
+	CustomerRatingsAvgScore    float64                  `json:"customer_ratings_avg_score"`
+	CustomerReviewsCount       int64                    `json:"customer_reviews_count"`
 }
 
 type Role string
@@ -74,6 +80,11 @@ const (
 	UserRole  Role = "user"
 )
 
+// IsAdmin reports whether the user manages the service
+func (u *User) IsAdmin() bool {
+	return u.Role == AdminRole
+}
+
 // PublicData returns a copy of the user's public information
 func (u *User) PublicData(languageCode string, currentUserID ...uint64) interface{} {
 	conf := config.NewConfig()
@@ -116,6 +127,8 @@ func (u *User) PublicData(languageCode string, currentUserID ...uint64) interfac
 		ExperienceRatingsAvgScore:  u.ExperienceRatingsAvgScore,
 		RecommendedRatingsAvgScore: u.RecommendedRatingsAvgScore,
 		ReviewsCount:               u.ReviewsCount,
+		CustomerRatingsAvgScore:    u.CustomerRatingsAvgScore,
+		CustomerReviewsCount:       u.CustomerReviewsCount,
 	}
 }
 
@@ -145,222 +158,33 @@ func (u *User) BeforeSave(tx *gorm.DB) error {
 	return nil
 }
 
-// AfterFind is a gorm hook that sets the value of the IsDriver field
-// based on whether a driver with the same user_id exists
-func (u *User) AfterFind(tx *gorm.DB) (err error) {
-	var driversCount int64
-	err = tx.Table("drivers").Where("user_id = ?", u.ID).Count(&driversCount).Error
-	if err != nil {
-		return err
-	}
-
-	if driversCount > 0 {
-		// Set the value of the IsDriver field
-		u.IsDriver = true
-	} else {
-		// Set the value of the IsDriver field
-		u.IsDriver = false
-	}
-
-	var purchasesCount int64
-	err = tx.Table("orders").Where("user_id = ?", u.ID).Count(&purchasesCount).Error
+// BeforeCreate is a gorm hook that gives every new user their own referral code
+func (u *User) BeforeCreate(tx *gorm.DB) error {
+	referralCode, err := NewReferralCode()
 	if err != nil {
 		return err
 	}
-	u.PurchasesCount = purchasesCount
-
-	// Driver struct to store the retrieved driver data
-	var driver Driver
-
-	// Retrieve the driver data using the user ID
-	if err := tx.Table("drivers").Where("user_id = ?", u.ID).First(&driver).Error; err != nil {
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			// Handle the error appropriately, excluding the "record not found" error
-			return err
-		}
-
-		// Handle the "record not found" case separately
-		driver = Driver{}
-	}
-
-	var ordersCount int64
-
-	if driver.ID != 0 {
-		// Count the number of orders associated with the driver
-		if err := tx.Table("orders").Where("driver_id = ?", driver.ID).Count(&ordersCount).Error; err != nil {
-			// Handle the error appropriately
-			return err
-		}
-		u.OrdersCount = ordersCount
-	} else {
-		u.OrdersCount = 0
-	}
-
-	var inProgressOffersCount, inProgressOrdersCount int64
-
-	if driver.ID != 0 {
-		if err := tx.Table("offers").Where("driver_id = ?", driver.ID).Where("status IN (?)", OfferStatusPending).Count(&inProgressOffersCount).Error; err != nil {
-			// Handle the error appropriately
-			return err
-		}
-		if err := tx.Table("orders").Where("driver_id = ?", driver.ID).Where("status NOT IN (?, ?, ?)", OrderCompletedStatus, OrderCanceledStatus, ShipmentReturnedStatus).Count(&inProgressOrdersCount).Error; err != nil {
-			// Handle the error appropriately
-			return err
-		}
-		u.InProgressOrdersCount = inProgressOffersCount + inProgressOrdersCount
-	} else {
-		u.InProgressOrdersCount = 0
-	}
-
-	var tripsCount int64
-
-	if driver.ID != 0 {
-		// Count the number of orders associated with the driver
-		if err := tx.Table("orders").Where("driver_id = ?", driver.ID).Where("status IN (?, ?)", ShipmentDeliveredStatus, OrderCompletedStatus).Count(&tripsCount).Error; err != nil {
-			// Handle the error appropriately
-			return err
-		}
-		u.TripsCount = tripsCount
-	} else {
-		u.TripsCount = 0
-	}
-
-	if driver.ID != 0 {
-		var balancesSumBalance int64
-		var monthlyRevenue int64
-
-		// Calculate the sum of balances associated with the driver
-		result := tx.Table("balances").Select("COALESCE(SUM(balance), 0)").Where("driver_id = ?", driver.ID).Row()
-		if err := result.Scan(&balancesSumBalance); err != nil {
-			// Handle the error appropriately
-			return err
-		}
-		u.BalancesSumBalance = balancesSumBalance
-
-		result = tx.Table("balances").Select("COALESCE(SUM(balance), 0)").
-			Where("driver_id = ? AND DATE_TRUNC('month', created_at) = DATE_TRUNC('month', CURRENT_DATE)", driver.ID).
-			Row()
-		if err := result.Scan(&monthlyRevenue); err != nil {
-			// Handle the error appropriately
-			return err
-		}
-		u.MonthlyRevenue = monthlyRevenue
-	} else {
-		u.BalancesSumBalance = 0
-		u.MonthlyRevenue = 0
-	}
-
-	if driver.ID != 0 {
-		var ratingsAvgScore float64
-		var fastRatingsAvgScore float64
-		var experienceRatingsAvgScore float64
-		var recommendedRatingsAvgScore float64
-
-		subQuery := fmt.Sprintf("SELECT id FROM orders WHERE driver_id = %d", driver.ID)
+	u.ReferralCode = &referralCode
 
-		err := tx.Raw(fmt.Sprintf(`
-			SELECT LEAST(COALESCE((AVG(fast_rating) + AVG(experience_rating) + AVG(recommended_rating)) / 3, 0), 5)
-			FROM ratings
-			WHERE order_id IN (%s)
-		`, subQuery)).Scan(&ratingsAvgScore).Error
-		if err != nil {
-			return err
-		}
-
-		err = tx.Raw(fmt.Sprintf(`
-			SELECT LEAST(COALESCE(AVG(fast_rating), 0), 5)
-			FROM ratings
-			WHERE order_id IN (%s)
-		`, subQuery)).Scan(&fastRatingsAvgScore).Error
-		if err != nil {
-			return err
-		}
-
-		err = tx.Raw(fmt.Sprintf(`
-			SELECT LEAST(COALESCE(AVG(experience_rating), 0), 5)
-			FROM ratings
-			WHERE order_id IN (%s)
-		`, subQuery)).Scan(&experienceRatingsAvgScore).Error
-		if err != nil {
-			return err
-		}
-
-		err = tx.Raw(fmt.Sprintf(`
-			SELECT LEAST(COALESCE(AVG(recommended_rating), 0), 5)
-			FROM ratings
-			WHERE order_id IN (%s)
-		`, subQuery)).Scan(&recommendedRatingsAvgScore).Error
-		if err != nil {
-			return err
-		}
-
-		// Format the floating-point number with one decimal place
-		formattedRating := fmt.Sprintf("%.1f", ratingsAvgScore)
-
-		// Convert the formatted string back to float64
-		formattedRatingFloat, err := strconv.ParseFloat(formattedRating, 64)
-		if err != nil {
-			return err
-		}
-
-		// Format the floating-point number with one decimal place
-		formattedFastRating := fmt.Sprintf("%.1f", fastRatingsAvgScore)
-
-		// Convert the formatted string back to float64
-		formattedFastRatingFloat, err := strconv.ParseFloat(formattedFastRating, 64)
-		if err != nil {
-			return err
-		}
-
-		// Format the floating-point number with one decimal place
-		formattedExperienceRating := fmt.Sprintf("%.1f", experienceRatingsAvgScore)
-
-		// Convert the formatted string back to float64
-		formattedExperienceRatingFloat, err := strconv.ParseFloat(formattedExperienceRating, 64)
-		if err != nil {
-			return err
-		}
-
-		// Format the floating-point number with one decimal place
-		formattedRecommendedRating := fmt.Sprintf("%.1f", recommendedRatingsAvgScore)
+	return nil
+}
 
-		// Convert the formatted string back to float64
-		formattedRecommendedRatingFloat, err := strconv.ParseFloat(formattedRecommendedRating, 64)
-		if err != nil {
-			return err
-		}
+// NewReferralCode generates a random referral code of 8 characters, leaving out the ones that are
+// easily mistaken for each other
+func NewReferralCode() (string, error) {
+	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
 
-		u.RatingsAvgScore = formattedRatingFloat
-		u.FastRatingsAvgScore = formattedFastRatingFloat
-		u.ExperienceRatingsAvgScore = formattedExperienceRatingFloat
-		u.RecommendedRatingsAvgScore = formattedRecommendedRatingFloat
-	} else {
-		u.RatingsAvgScore = 0
-		u.FastRatingsAvgScore = 0
-		u.ExperienceRatingsAvgScore = 0
-		u.RecommendedRatingsAvgScore = 0
+	// The alphabet has 32 characters, so each random byte picks one of them evenly
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
 
-	var reviewsCount int64
-
-	if driver.ID != 0 {
-		subQuery := fmt.Sprintf("SELECT id FROM orders WHERE driver_id = %d", driver.ID)
-
-		err := tx.Raw(fmt.Sprintf(`
-		SELECT COALESCE(COUNT(ratings.id), 0)
-		FROM ratings
-		WHERE order_id IN (%s)
-		`, subQuery)).Scan(&reviewsCount).Error
-		if err != nil {
-			return err
-		}
-
-		u.ReviewsCount = reviewsCount
-	} else {
-		u.ReviewsCount = 0
+	code := make([]byte, len(b))
+	for i := range b {
+		code[i] = alphabet[int(b[i])%len(alphabet)]
 	}
-
-	return
+	return string(code), nil
 }
 
 func (u *User) AddSetting(key string, value interface{}) error {
@@ -0,0 +1,23 @@
+package entity
+
+// UserStats holds the activity statistics of a user, including their activity as a driver
+type UserStats struct {
+	IsDriver              bool  `json:"is_driver"`
+	PurchasesCount        int64 `json:"purchases_count"`
+	OrdersCount           int64 `json:"orders_count"`
+	InProgressOrdersCount int64 `json:"in_progress_orders_count"`
+	TripsCount            int64 `json:"trips_count"`
+	BalancesSumBalance    int64 `json:"balances_sum_balance"`
+	MonthlyRevenue        int64 `json:"monthly_revenue"`
+}
+
+// SetStats copies the statistics onto the user so they're included in its public data
+func (u *User) SetStats(stats *UserStats) {
+	u.IsDriver = stats.IsDriver
+	u.PurchasesCount = stats.PurchasesCount
+	u.OrdersCount = stats.OrdersCount
+	u.InProgressOrdersCount = stats.InProgressOrdersCount
+	u.TripsCount = stats.TripsCount
+	u.BalancesSumBalance = stats.BalancesSumBalance
+	u.MonthlyRevenue = stats.MonthlyRevenue
+}
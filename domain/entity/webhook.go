@@ -0,0 +1,134 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// WebhookSubscription represent an endpoint of a business customer notified of the events of
+// their orders. The deliveries are signed with the subscription's secret.
+type WebhookSubscription struct {
+	ID         uint64         `gorm:"primary_key;auto_increment" json:"id"`
+	UserID     uint64         `gorm:"index;" json:"user_id"`
+	URL        string         `gorm:"size:2048;" json:"url"`
+	Secret     string         `gorm:"size:255;" json:"-"`
+	EventTypes datatypes.JSON `gorm:"type:json" json:"event_types"`
+	IsActive   bool           `gorm:"default:true" json:"is_active"`
+	CreatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+type WebhookSubscriptionPublicData struct {
+	ID         uint64      `json:"id"`
+	URL        string      `json:"url"`
+	EventTypes []EventType `json:"event_types"`
+	IsActive   bool        `json:"is_active"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+// WebhookDelivery represent the delivery of an event to a webhook subscription, kept as a log of
+// the attempts so failed deliveries can be replayed.
+type WebhookDelivery struct {
+	ID             uint64                `gorm:"primary_key;auto_increment" json:"id"`
+	SubscriptionID uint64                `gorm:"uniqueIndex:idx_webhook_deliveries_subscription_event;" json:"subscription_id"`
+	EventID        uint64                `gorm:"uniqueIndex:idx_webhook_deliveries_subscription_event;" json:"event_id"`
+	EventType      EventType             `gorm:"size:255;" json:"event_type"`
+	Payload        datatypes.JSON        `gorm:"type:json" json:"payload"`
+	Status         WebhookDeliveryStatus `gorm:"size:255;index;" json:"status"`
+	Attempts       int                   `gorm:"default:0" json:"attempts"`
+	ResponseStatus int                   `gorm:"default:0" json:"response_status"`
+	LastError      string                `gorm:"type:text;" json:"last_error"`
+	NextAttemptAt  time.Time             `gorm:"default:CURRENT_TIMESTAMP;index;" json:"next_attempt_at"`
+	DeliveredAt    *time.Time            `gorm:"default:null" json:"delivered_at"`
+	CreatedAt      time.Time             `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	Subscription   WebhookSubscription   `gorm:"foreignKey:SubscriptionID" json:"subscription"`
+}
+
+type WebhookDeliveryPublicData struct {
+	ID             uint64                `json:"id"`
+	SubscriptionID uint64                `json:"subscription_id"`
+	URL            string                `json:"url,omitempty"`
+	EventID        uint64                `json:"event_id"`
+	EventType      EventType             `json:"event_type"`
+	Payload        datatypes.JSON        `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	ResponseStatus int                   `json:"response_status"`
+	LastError      string                `json:"last_error"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPendingStatus is a delivery waiting for its first or next attempt
+	WebhookDeliveryPendingStatus WebhookDeliveryStatus = "pending"
+	// WebhookDeliverySucceededStatus is a delivery the endpoint answered with a 2xx status
+	WebhookDeliverySucceededStatus WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryFailedStatus is a delivery given up on after its last attempt, until it's replayed
+	WebhookDeliveryFailedStatus WebhookDeliveryStatus = "failed"
+)
+
+// WebhookEventTypes are the events a webhook subscription can select
+var WebhookEventTypes = []EventType{
+	OrderStatusUpdatedEvent,
+	OrderAcceptedEvent,
+	OrderCanceledEvent,
+	OrderDeliveredEvent,
+	OrderReturnedEvent,
+	OfferCreatedEvent,
+	OfferAcceptedEvent,
+	OfferDeclinedEvent,
+}
+
+// Selects reports whether the subscription selected the events of the given type
+func (s *WebhookSubscription) Selects(eventType EventType) bool {
+	var eventTypes []EventType
+	_ = json.Unmarshal(s.EventTypes, &eventTypes)
+
+	for _, selected := range eventTypes {
+		if selected == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicData returns a copy of the webhook subscription's public information
+func (s *WebhookSubscription) PublicData() interface{} {
+	var eventTypes []EventType
+	_ = json.Unmarshal(s.EventTypes, &eventTypes)
+
+	return &WebhookSubscriptionPublicData{
+		ID:         s.ID,
+		URL:        s.URL,
+		EventTypes: eventTypes,
+		IsActive:   s.IsActive,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+	}
+}
+
+// PublicData returns a copy of the webhook delivery's public information
+func (d *WebhookDelivery) PublicData() interface{} {
+	return &WebhookDeliveryPublicData{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		URL:            d.Subscription.URL,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		NextAttemptAt:  d.NextAttemptAt,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// AddressRepository defines the methods for interacting with the saved addresses of the users
+type AddressRepository interface {
+	CreateAddress(address *entity.Address) (*entity.Address, error)
+	UpdateAddressByID(id uint64, address *entity.Address) (*entity.Address, error)
+	DeleteAddressByID(id uint64) error
+	GetAddressByIDAndUserID(id uint64, userID uint64) (*entity.Address, error)
+	GetAllAddressesByUserID(userID uint64) ([]entity.Address, error)
+}
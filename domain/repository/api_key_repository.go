@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// APIKeyRepository defines the methods for interacting with the API keys of the organizations
+type APIKeyRepository interface {
+	CreateAPIKey(apiKey *entity.APIKey) (*entity.APIKey, error)
+	GetActiveAPIKeyByKeyHash(keyHash string) (*entity.APIKey, error)
+	GetAPIKeyByIDAndOrganizationID(id uint64, organizationID uint64) (*entity.APIKey, error)
+	GetAllAPIKeysByOrganizationID(organizationID uint64) ([]entity.APIKey, error)
+	UpdateAPIKeyLastUsedAtByID(id uint64, lastUsedAt time.Time) error
+	RevokeAPIKeyByID(id uint64, revokedAt time.Time) error
+	RevokeAPIKeysByOrganizationIDAndUserID(organizationID uint64, userID uint64, revokedAt time.Time) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// ChatOutboxRepository defines the methods for interacting with the pending chat membership changes
+type ChatOutboxRepository interface {
+	CreateChatOutboxes(chatOutboxes []entity.ChatOutbox) error
+	UpdateChatOutboxByID(id uint64, chatOutbox *entity.ChatOutbox) (*entity.ChatOutbox, error)
+	GetAllDueChatOutboxes(now time.Time, limit int) ([]entity.ChatOutbox, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// ChatRepository defines the methods for interacting with the data of the local chat provider
+type ChatRepository interface {
+	CreateChannel(channel *entity.ChatChannel) error
+	GetAllChannelsByUserID(userID string) ([]entity.ChatChannel, error)
+	AddMember(member *entity.ChatMember) error
+	RemoveMember(channelID string, userID string) error
+	GetMember(channelID string, userID string) (*entity.ChatMember, error)
+	GetAllMembersByChannelID(channelID string) ([]entity.ChatMember, error)
+	UpdateMemberLastReadMessageID(channelID string, userID string, messageID uint64) error
+	CreateMessage(message *entity.ChatMessage) (*entity.ChatMessage, error)
+	GetAllMessagesByChannelID(channelID string, beforeID uint64, limit int) ([]entity.ChatMessage, error)
+}
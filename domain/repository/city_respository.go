@@ -4,7 +4,7 @@ import "github.com/OmarBader7/web-service-jayeek/domain/entity"
 
 // CityRepository defines the methods for interacting with city data.
 type CityRepository interface {
-	CountCities() (int64, error)
-	GetAllCities(page int, perPage int) ([]entity.City, error)
+	CountCities(regionID uint64) (int64, error)
+	GetAllCities(regionID uint64, page int, perPage int) ([]entity.City, error)
 	GetCityByID(uint64) (*entity.City, error)
 }
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// CreditRepository defines the methods for interacting with credit data
+type CreditRepository interface {
+	CreateCredit(credit *entity.Credit) (*entity.Credit, error)
+	CountCreditsByUserIDAndReason(userID uint64, reason entity.CreditReason) (int64, error)
+	SumCreditsByUserID(userID uint64) (float64, error)
+	DeleteCreditsByOrderIDAndReason(orderID uint64, reason entity.CreditReason) error
+}
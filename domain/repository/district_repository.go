@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/OmarBader7/web-service-jayeek/domain/entity"
+
+// DistrictRepository defines the methods for interacting with district data.
+type DistrictRepository interface {
+	CountDistricts(regionID uint64, cityID uint64) (int64, error)
+	GetAllDistricts(regionID uint64, cityID uint64, page int, perPage int) ([]entity.District, error)
+	GetDistrictByID(uint64) (*entity.District, error)
+}
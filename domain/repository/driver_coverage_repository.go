@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// DriverCoverageRepository defines the methods for interacting with the cities and regions drivers serve
+type DriverCoverageRepository interface {
+	GetAllDriverCoveragesByDriverID(driverID uint64) ([]entity.DriverCoverage, error)
+	ReplaceDriverCoveragesByDriverID(driverID uint64, coverages []entity.DriverCoverage) ([]entity.DriverCoverage, error)
+}
@@ -12,6 +12,7 @@ type DriverRepository interface {
 	GetAllDrivers(page int, perPage int) ([]entity.Driver, error)
 	GetDriverByID(uint64) (*entity.Driver, error)
 	GetDriverByUserID(uint64) (*entity.Driver, error)
+	LockDriverByID(id uint64) error
 	CountDriversByUserLocationID(userLocationID uint64) (int64, error)
 	GetDriversByUserLocationID(userLocationID uint64, page int, perPage int) ([]entity.Driver, error)
 }
@@ -0,0 +1,17 @@
+package repository
+
+import "errors"
+
+// ErrConflict is returned when a record was changed by another request between reading and
+// updating it, such as an order accepted twice at once, or when it was already created, such as
+// an order rated twice by the same party.
+var ErrConflict = errors.New("the record was changed by another request")
+
+// ErrRefundExceedsAmount is returned when a refund is more than what's left to refund on its order
+var ErrRefundExceedsAmount = errors.New("the refund exceeds the amount left to refund on the order")
+
+// ErrPromotionUsageLimit is returned when a promotion was redeemed as many times as it can be
+var ErrPromotionUsageLimit = errors.New("the promotion reached its usage limit")
+
+// ErrPromotionUserUsageLimit is returned when a user redeemed a promotion as many times as they can
+var ErrPromotionUserUsageLimit = errors.New("the promotion reached its usage limit for the user")
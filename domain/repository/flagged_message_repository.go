@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// FlaggedMessageRepository defines the methods for interacting with flagged chat message data
+type FlaggedMessageRepository interface {
+	CreateFlaggedMessage(flaggedMessage *entity.FlaggedMessage) (*entity.FlaggedMessage, error)
+	UpdateFlaggedMessageByID(id uint64, flaggedMessage *entity.FlaggedMessage) (*entity.FlaggedMessage, error)
+	GetFlaggedMessageByID(id uint64) (*entity.FlaggedMessage, error)
+	GetAllFlaggedMessages(status string, page int, perPage int) ([]entity.FlaggedMessage, error)
+	CountFlaggedMessages(status string) (int64, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// GeocodingRepository defines the methods for interacting with the regions, cities and districts of the geocoding dataset
+type GeocodingRepository interface {
+	SaveRegion(region *entity.Region) error
+	SaveCity(city *entity.City) error
+	SaveDistrict(district *entity.District) error
+	SaveLocation(location *entity.Location) error
+	SearchGeoPlaces(query string, limit int) ([]entity.GeoPlace, error)
+	GetRegionByID(id uint64) (*entity.Region, error)
+	GetRegionByCoordinates(longitude float64, latitude float64) (*entity.Region, error)
+	GetCityByCoordinates(longitude float64, latitude float64) (*entity.City, error)
+	GetDistrictByCoordinates(longitude float64, latitude float64) (*entity.District, error)
+	FillOrderCities() error
+	FillLocationCities() error
+}
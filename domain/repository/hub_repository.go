@@ -0,0 +1,17 @@
+package repository
+
+import "github.com/OmarBader7/web-service-jayeek/domain/entity"
+
+// HubRepository defines the methods for interacting with hub data.
+type HubRepository interface {
+	CreateHub(hub *entity.Hub) (*entity.Hub, error)
+	UpdateHubByID(id uint64, hub *entity.Hub) (*entity.Hub, error)
+	CountHubs(cityID uint64) (int64, error)
+	GetAllHubs(cityID uint64, page int, perPage int) ([]entity.Hub, error)
+	GetHubByID(id uint64) (*entity.Hub, error)
+	GetNearestActiveHubByCityID(cityID uint64, latitude float64, longitude float64) (*entity.Hub, error)
+	CreateHubMember(hubMember *entity.HubMember) (*entity.HubMember, error)
+	DeleteHubMemberByID(id uint64) error
+	GetHubMemberByHubIDAndUserID(hubID uint64, userID uint64) (*entity.HubMember, error)
+	GetAllHubMembersByHubID(hubID uint64) ([]entity.HubMember, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// InvoiceRepository defines the methods for interacting with invoice data
+type InvoiceRepository interface {
+	CreateInvoice(invoice *entity.Invoice) (*entity.Invoice, error)
+	UpdateInvoiceByID(id uint64, invoice *entity.Invoice) (*entity.Invoice, error)
+	GetInvoiceByOrderID(orderID uint64) (*entity.Invoice, error)
+}
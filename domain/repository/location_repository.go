@@ -6,8 +6,8 @@ import (
 
 // LocationRepository defines the methods for interacting with location data
 type LocationRepository interface {
-	CountLocations() (int64, error)
-	GetAllLocations(page int, perPage int) ([]entity.Location, error)
+	CountLocations(regionID uint64, cityID uint64, districtID uint64) (int64, error)
+	GetAllLocations(regionID uint64, cityID uint64, districtID uint64, page int, perPage int) ([]entity.Location, error)
 	GetLocationByID(uint64) (*entity.Location, error)
 	GetLocationByCoordinates(float64, float64, *float64) (*entity.Location, error)
 }
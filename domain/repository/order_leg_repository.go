@@ -0,0 +1,18 @@
+package repository
+
+import "github.com/OmarBader7/web-service-jayeek/domain/entity"
+
+// OrderLegRepository defines the methods for interacting with the legs of the intercity orders.
+type OrderLegRepository interface {
+	UpdateOrderLegByIDAndStatus(id uint64, status entity.OrderLegStatus, orderLeg *entity.OrderLeg) (*entity.OrderLeg, error)
+	CancelOrderLegsByOrderID(orderID uint64) error
+	UpdateLastMileOrderLegByOrderID(orderID uint64, latitude float64, longitude float64) error
+	CountAvailableOrderLegsByDriverID(driverID uint64) (int64, error)
+	GetAllAvailableOrderLegsByDriverID(driverID uint64, page int, perPage int) ([]entity.OrderLeg, error)
+	GetAvailableOrderLegByIDAndDriverID(id uint64, driverID uint64) (*entity.OrderLeg, error)
+	CountOrderLegsByDriverIDExcludingStatus(driverID uint64, status []entity.OrderLegStatus) (int64, error)
+	GetAllOrderLegsByDriverIDExcludingStatus(driverID uint64, status []entity.OrderLegStatus, page int, perPage int) ([]entity.OrderLeg, error)
+	GetAllOrderLegsByOrderID(orderID uint64) ([]entity.OrderLeg, error)
+	GetOrderLegByID(id uint64) (*entity.OrderLeg, error)
+	GetOrderLegByIDAndDriverID(id uint64, driverID uint64) (*entity.OrderLeg, error)
+}
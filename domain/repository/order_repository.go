@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/OmarBader7/web-service-jayeek/domain/entity"
 )
 
@@ -8,19 +10,31 @@ import (
 type OrderRepository interface {
 	CreateOrder(order *entity.Order) (*entity.Order, error)
 	UpdateOrderByID(id uint64, order *entity.Order) (*entity.Order, error)
+	ReleaseOrderDriverPoolByID(id uint64) error
 	UpdateOrderDriverPoolByOrderIDAndDriverID(orderID uint64, driverID uint64, orderDriverPool *entity.OrderDriverPool) (*entity.OrderDriverPool, error)
 	CountOrdersByUserIDExcludingStatus(userID uint64, status []entity.OrderStatus) (int64, error)
 	CountOrdersByUserIDAndRecipientIDExcludingStatus(userID uint64, recipientID uint64, status []entity.OrderStatus) (int64, error)
 	CountOrdersByDriverIDExcludingStatus(driverID uint64, status []entity.OrderStatus) (int64, error)
 	GetAllOrdersByUserIDExcludingStatus(userID uint64, status []entity.OrderStatus, page int, perPage int) ([]entity.Order, error)
 	GetAllOrdersByUserIDAndRecipientIDExcludingStatus(userID uint64, recipientID uint64, status []entity.OrderStatus, page int, perPage int) ([]entity.Order, error)
+	CountOrdersByOrganizationID(organizationID uint64) (int64, error)
+	GetAllOrdersByOrganizationID(organizationID uint64, page int, perPage int) ([]entity.Order, error)
 	GetAllOrdersByDriverIDExcludingStatus(driverID uint64, status []entity.OrderStatus, page int, perPage int) ([]entity.Order, error)
 	GetAllOrdersByRecipientPhoneNumber(recipientPhoneNumber string) ([]entity.Order, error)
+	UpdateOrderByIDAndStatus(id uint64, status entity.OrderStatus, order *entity.Order) (*entity.Order, error)
+	GetAllScheduledOrdersDueBefore(before time.Time) ([]entity.Order, error)
+	GetAllOrdersDeliveredBefore(before time.Time) ([]entity.Order, error)
+	GetAllOrdersPreferredBefore(before time.Time) ([]entity.Order, error)
+	ReleasePreferredOrderByID(id uint64) error
+	GetAllOrdersByStatusAfterID(status []entity.OrderStatus, afterID uint64, limit int) ([]entity.Order, error)
 	CountDriverPoolsByDriverIDAndCategoryID(driverID uint64, categoryID uint64, byArrival *string) (int64, error)
 	GetAllDriverPoolsByDriverIDAndCategoryID(driverID uint64, categoryID uint64, page int, perPage int, orderBy *string, byArrival *string) ([]entity.Order, error)
 	GetOrderByID(uint64) (*entity.Order, error)
 	GetOrderByIDAndUserID(id uint64, userID uint64) (*entity.Order, error)
 	GetOrderByIDAndRecipientID(id uint64, recipientID uint64) (*entity.Order, error)
 	GetOrderByIDAndDriverID(id uint64, driverID uint64) (*entity.Order, error)
+	GetOrderByTrackingNumber(trackingNumber string) (*entity.Order, error)
+	GetOrderByShareToken(shareToken string) (*entity.Order, error)
+	UpdateOrderShareTokenByID(id uint64, shareToken *string) error
 	GetOrderDriverPoolByOrderIDAndDriverID(uint64, uint64) (*entity.OrderDriverPool, error)
 }
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// OrderTimelineRepository defines the methods for interacting with order timeline data
+type OrderTimelineRepository interface {
+	CreateOrderTimeline(orderTimeline *entity.OrderTimeline) (*entity.OrderTimeline, error)
+	GetAllOrderTimelinesByOrderID(orderID uint64) ([]entity.OrderTimeline, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// OrganizationRepository defines the methods for interacting with the organizations and their members
+type OrganizationRepository interface {
+	CreateOrganization(organization *entity.Organization, ownerID uint64) (*entity.Organization, error)
+	UpdateOrganizationByID(id uint64, organization *entity.Organization) (*entity.Organization, error)
+	GetOrganizationByID(id uint64) (*entity.Organization, error)
+	GetAllOrganizationsByUserID(userID uint64) ([]entity.Organization, error)
+	CreateOrganizationMember(organizationMember *entity.OrganizationMember) (*entity.OrganizationMember, error)
+	UpdateOrganizationMemberByID(id uint64, organizationMember *entity.OrganizationMember) (*entity.OrganizationMember, error)
+	DeleteOrganizationMemberByID(id uint64) error
+	GetOrganizationMemberByOrganizationIDAndUserID(organizationID uint64, userID uint64) (*entity.OrganizationMember, error)
+	GetAllOrganizationMembersByOrganizationID(organizationID uint64) ([]entity.OrganizationMember, error)
+	CountOrganizationMembersByOrganizationIDAndRole(organizationID uint64, role entity.OrganizationRole) (int64, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// OutboxEventRepository defines the methods for interacting with the domain events of the outbox
+type OutboxEventRepository interface {
+	CreateOutboxEvent(event *entity.OutboxEvent) (*entity.OutboxEvent, error)
+	UpdateOutboxEventByID(id uint64, event *entity.OutboxEvent) (*entity.OutboxEvent, error)
+	GetAllDueOutboxEvents(now time.Time, limit int) ([]entity.OutboxEvent, error)
+}
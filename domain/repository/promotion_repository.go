@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// PromotionRepository defines the methods for interacting with promotion data
+type PromotionRepository interface {
+	CreatePromotion(promotion *entity.Promotion) (*entity.Promotion, error)
+	UpdatePromotionByID(id uint64, promotion *entity.Promotion) (*entity.Promotion, error)
+	GetAllPromotions() ([]entity.Promotion, error)
+	GetPromotionByID(id uint64) (*entity.Promotion, error)
+	GetPromotionByCode(code string) (*entity.Promotion, error)
+	RedeemPromotion(promotionRedemption *entity.PromotionRedemption) (*entity.PromotionRedemption, error)
+	ReleasePromotionRedemptionByOrderID(orderID uint64) error
+	CountPromotionRedemptionsByPromotionID(promotionID uint64) (int64, error)
+	CountPromotionRedemptionsByPromotionIDAndUserID(promotionID uint64, userID uint64) (int64, error)
+	BackfillPromotionRedemptionsCounts() error
+}
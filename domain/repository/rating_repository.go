@@ -5,4 +5,7 @@ import "github.com/OmarBader7/web-service-jayeek/domain/entity"
 // RatingRepository defines the methods that a rating repository should implement
 type RatingRepository interface {
 	CreateRating(*entity.Rating) (*entity.Rating, error)
+	GetRatingByOrderIDAndType(orderID uint64, ratingType entity.RatingType) (*entity.Rating, error)
+	CountRatingsByRatedUserIDAndType(ratedUserID uint64, ratingType entity.RatingType) (int64, error)
+	GetAllRatingsByRatedUserIDAndType(ratedUserID uint64, ratingType entity.RatingType, page int, perPage int) ([]entity.Rating, error)
 }
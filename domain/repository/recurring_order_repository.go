@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// RecurringOrderRepository defines the methods for interacting with recurring order data
+type RecurringOrderRepository interface {
+	CreateRecurringOrder(recurringOrder *entity.RecurringOrder) (*entity.RecurringOrder, error)
+	UpdateRecurringOrderByID(id uint64, recurringOrder *entity.RecurringOrder) (*entity.RecurringOrder, error)
+	UpdateRecurringOrderRunByIDAndNextRunAt(id uint64, nextRunAt time.Time, recurringOrder *entity.RecurringOrder) (*entity.RecurringOrder, error)
+	CountRecurringOrdersByUserID(userID uint64) (int64, error)
+	GetAllRecurringOrdersByUserID(userID uint64, page int, perPage int) ([]entity.RecurringOrder, error)
+	GetAllActiveRecurringOrdersDueBefore(before time.Time) ([]entity.RecurringOrder, error)
+	GetRecurringOrderByIDAndUserID(id uint64, userID uint64) (*entity.RecurringOrder, error)
+}
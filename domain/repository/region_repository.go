@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/OmarBader7/web-service-jayeek/domain/entity"
+
+// RegionRepository defines the methods for interacting with region data.
+type RegionRepository interface {
+	CountRegions() (int64, error)
+	GetAllRegions(page int, perPage int) ([]entity.Region, error)
+	GetRegionByID(uint64) (*entity.Region, error)
+}
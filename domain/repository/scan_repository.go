@@ -0,0 +1,8 @@
+package repository
+
+import "github.com/OmarBader7/web-service-jayeek/domain/entity"
+
+// ScanRepository defines the methods for interacting with the scans of the parcel labels.
+type ScanRepository interface {
+	CreateScan(scan *entity.Scan) (*entity.Scan, error)
+}
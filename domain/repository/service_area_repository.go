@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// ServiceAreaRepository defines the methods for interacting with service area data
+type ServiceAreaRepository interface {
+	CreateServiceArea(serviceArea *entity.ServiceArea) (*entity.ServiceArea, error)
+	UpdateServiceAreaByID(id uint64, serviceArea *entity.ServiceArea) (*entity.ServiceArea, error)
+	DeleteServiceAreaByID(id uint64) error
+	UpdateServiceAreaSurgeByID(id uint64, surgeMultiplier float64) error
+	CountOpenOrdersByServiceAreaID(id uint64) (int64, error)
+	CountAvailableDriversByServiceAreaID(id uint64) (int64, error)
+	CountActiveServiceAreas() (int64, error)
+	GetAllServiceAreas() ([]entity.ServiceArea, error)
+	GetServiceAreaByID(id uint64) (*entity.ServiceArea, error)
+	GetActiveServiceAreaByCoordinates(latitude float64, longitude float64) (*entity.ServiceArea, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// TicketRepository defines the methods for interacting with support ticket data
+type TicketRepository interface {
+	CreateTicket(ticket *entity.Ticket) (*entity.Ticket, error)
+	UpdateTicketByID(id uint64, ticket *entity.Ticket) (*entity.Ticket, error)
+	GetTicketByID(id uint64) (*entity.Ticket, error)
+	GetAllTickets(status string, page int, perPage int) ([]entity.Ticket, error)
+	CountTickets(status string) (int64, error)
+	GetAllTicketsByUserID(userID uint64, page int, perPage int) ([]entity.Ticket, error)
+	CountTicketsByUserID(userID uint64) (int64, error)
+	CreateTicketMessage(message *entity.TicketMessage) (*entity.TicketMessage, error)
+	ResolveTicket(ticket *entity.Ticket, credit *entity.Credit, balance *entity.Balance) (*entity.Ticket, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// TripRepository defines the methods for interacting with trip data
+type TripRepository interface {
+	AddOrderToTrip(driverID uint64, order *entity.Order) (*entity.Trip, error)
+	RemoveOrderFromTrips(orderID uint64) error
+	CompleteOrderTripStops(orderID uint64, stopTypes []entity.TripStopType, completedAt time.Time) error
+	UpdateOrderDropOffTripStops(orderID uint64, latitude float64, longitude float64) error
+	UpdateTripByID(id uint64, trip *entity.Trip) (*entity.Trip, error)
+	UpdateTripStopByID(id uint64, tripStop *entity.TripStop) (*entity.TripStop, error)
+	GetActiveTripByDriverID(driverID uint64) (*entity.Trip, error)
+	GetTripStopByIDAndTripID(id uint64, tripID uint64) (*entity.TripStop, error)
+}
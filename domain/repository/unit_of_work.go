@@ -0,0 +1,25 @@
+package repository
+
+// UnitOfWork runs a set of changes in a single database transaction
+type UnitOfWork interface {
+	// Do runs fn in a transaction, committed if fn returns nil and rolled back otherwise
+	Do(fn func(tx Transaction) error) error
+}
+
+// Transaction gives the repositories bound to a running transaction
+type Transaction interface {
+	Order() OrderRepository
+	Offer() OfferRepository
+	User() UserRepository
+	Driver() DriverRepository
+	Balance() BalanceRepository
+	Credit() CreditRepository
+	Promotion() PromotionRepository
+	Rating() RatingRepository
+	Trip() TripRepository
+	OutboxEvent() OutboxEventRepository
+	OrderLeg() OrderLegRepository
+	OrderTimeline() OrderTimelineRepository
+	RecurringOrder() RecurringOrderRepository
+	Webhook() WebhookRepository
+}
@@ -14,4 +14,7 @@ type UserRepository interface {
 	GetAllUsers(page int, perPage int) ([]entity.User, error)
 	GetUserByID(id uint64) (*entity.User, error)
 	GetUserByPhone(phone string) (*entity.User, error)
+	GetUserByReferralCode(referralCode string) (*entity.User, error)
+	AssignUserReferralCodeByID(id uint64) (string, error)
+	LockUserByID(id uint64) error
 }
@@ -0,0 +1,8 @@
+package repository
+
+import "github.com/OmarBader7/web-service-jayeek/domain/entity"
+
+// UserStatsRepository defines the methods for calculating user statistics
+type UserStatsRepository interface {
+	GetUserStatsByUserID(userID uint64) (*entity.UserStats, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+)
+
+// WebhookRepository defines the methods for interacting with the webhook subscriptions and their deliveries
+type WebhookRepository interface {
+	CreateWebhookSubscription(webhookSubscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error)
+	UpdateWebhookSubscriptionByID(id uint64, webhookSubscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error)
+	DeleteWebhookSubscriptionByID(id uint64) error
+	GetWebhookSubscriptionByIDAndUserID(id uint64, userID uint64) (*entity.WebhookSubscription, error)
+	GetAllWebhookSubscriptionsByUserID(userID uint64) ([]entity.WebhookSubscription, error)
+	GetAllActiveWebhookSubscriptionsByUserID(userID uint64) ([]entity.WebhookSubscription, error)
+	CreateWebhookDeliveries(webhookDeliveries []entity.WebhookDelivery) error
+	UpdateWebhookDeliveryByID(id uint64, webhookDelivery *entity.WebhookDelivery) (*entity.WebhookDelivery, error)
+	GetWebhookDeliveryByID(id uint64) (*entity.WebhookDelivery, error)
+	GetAllDueWebhookDeliveries(now time.Time, limit int) ([]entity.WebhookDelivery, error)
+	GetAllWebhookDeliveries(subscriptionID uint64, status string, page int, perPage int) ([]entity.WebhookDelivery, error)
+	CountWebhookDeliveries(subscriptionID uint64, status string) (int64, error)
+}
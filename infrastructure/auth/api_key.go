@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/go-redis/redis/v9"
+)
+
+// APIKeyHeader carries the API key of the requests made by an organization's systems.
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyPrefix starts every API key, so leaked keys are easy to recognize.
+const apiKeyPrefix = "jyk_"
+
+// apiKeySessionTTL is how long the access token UUID of a request made with an API key lasts.
+const apiKeySessionTTL = time.Minute
+
+// APIKeyToken authenticates the requests carrying an organization's API key, and the other
+// requests with the JWT tokens of the wrapped TokenInterface.
+type APIKeyToken struct {
+	TokenInterface
+	RedisClient *redis.Client
+	APIKeyApp   application.APIKeyApplicationInterface
+}
+
+// Ensure that APIKeyToken implements TokenInterface.
+var _ TokenInterface = &APIKeyToken{}
+
+// NewAPIKeyToken creates and returns a new instance of APIKeyToken.
+func NewAPIKeyToken(token TokenInterface, redisClient *redis.Client, apiKeyApp application.APIKeyApplicationInterface) *APIKeyToken {
+	return &APIKeyToken{
+		TokenInterface: token,
+		RedisClient:    redisClient,
+		APIKeyApp:      apiKeyApp,
+	}
+}
+
+// ExtractTokenMetadata extracts the metadata of the API key or the token of the request
+func (t *APIKeyToken) ExtractTokenMetadata(r *http.Request) (*AccessDetails, error) {
+	key := ExtractAPIKey(r)
+	if key == "" {
+		return t.TokenInterface.ExtractTokenMetadata(r)
+	}
+
+	keyHash := HashAPIKey(key)
+
+	apiKey, err := t.APIKeyApp.GetActiveAPIKeyByKeyHash(keyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// The handlers look the user up by the access token UUID, so the key is given one for a short while
+	accessTokenUUID := "api-key:" + keyHash
+	if err := t.RedisClient.Set(ctx, accessTokenUUID, strconv.FormatUint(apiKey.UserID, 10), apiKeySessionTTL).Err(); err != nil {
+		return nil, err
+	}
+
+	// The last use is only recorded once a minute, so busy integrations don't write on every request
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > time.Minute {
+		_ = t.APIKeyApp.UpdateAPIKeyLastUsedAtByID(apiKey.ID, now)
+	}
+
+	return &AccessDetails{
+		AccessTokenUUID: accessTokenUUID,
+		UserID:          apiKey.UserID,
+		OrganizationID:  apiKey.OrganizationID,
+	}, nil
+}
+
+// ExtractAPIKey gets the API key from the request header
+func ExtractAPIKey(r *http.Request) string {
+	return strings.TrimSpace(r.Header.Get(APIKeyHeader))
+}
+
+// GenerateAPIKey returns a new random API key, the prefix it's shown with and its hash
+func GenerateAPIKey() (key string, prefix string, keyHash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	key = apiKeyPrefix + hex.EncodeToString(secret)
+	return key, key[:len(apiKeyPrefix)+8], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hash an API key is stored as
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
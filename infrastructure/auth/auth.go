@@ -38,6 +38,8 @@ func NewAuthService(redisClient *redis.Client) *AuthService {
 type AccessDetails struct {
 	AccessTokenUUID string
 	UserID          uint64
+	// OrganizationID is set when the request is authenticated with an organization's API key
+	OrganizationID uint64
 }
 
 // TokenDetails represents the token details of a user.
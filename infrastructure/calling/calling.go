@@ -0,0 +1,113 @@
+package calling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/go-redis/redis/v9"
+)
+
+// sessionTTL is how long a masked number keeps forwarding a caller to the same callee.
+const sessionTTL = 4 * time.Hour
+
+var ctx = context.Background()
+
+// ErrNotConfigured is returned when no masked numbers are configured.
+var ErrNotConfigured = errors.New("masked calling is not configured")
+
+// ErrNoNumberAvailable is returned when every masked number is already used by the caller.
+var ErrNoNumberAvailable = errors.New("no masked number available")
+
+// ErrSessionNotFound is returned when a call to a masked number has no callee to forward to.
+var ErrSessionNotFound = errors.New("masked call session not found")
+
+// CallingServiceInterface defines the methods that a calling service should implement. The users
+// call each other through masked numbers, so their real phone numbers are never shared.
+type CallingServiceInterface interface {
+	GetMaskedNumber(callerPhone string, calleePhone string) (string, error)
+	ResolveCall(callerPhone string, maskedNumber string) (string, error)
+}
+
+// PoolCallingService hands out the numbers of the masked_calling_numbers setting, a comma separated
+// list of the numbers the telephony provider forwards to the API. A number can be reused for
+// different callers, as the callee is looked up by the masked number and the caller's phone.
+type PoolCallingService struct {
+	RedisClient *redis.Client
+	SettingApp  application.SettingApplicationInterface
+}
+
+// Ensure that PoolCallingService implements CallingServiceInterface.
+var _ CallingServiceInterface = &PoolCallingService{}
+
+// NewPoolCallingService creates and returns a new instance of PoolCallingService.
+func NewPoolCallingService(redisClient *redis.Client, settingApp application.SettingApplicationInterface) *PoolCallingService {
+	return &PoolCallingService{
+		RedisClient: redisClient,
+		SettingApp:  settingApp,
+	}
+}
+
+// GetMaskedNumber returns the number the caller dials to reach the callee.
+func (s *PoolCallingService) GetMaskedNumber(callerPhone string, calleePhone string) (string, error) {
+	pool, err := s.SettingApp.GetSettingByKey("masked_calling_numbers")
+	if err != nil {
+		return "", err
+	}
+
+	var numbers []string
+	for _, number := range strings.Split(pool, ",") {
+		if number = strings.TrimSpace(number); number != "" {
+			numbers = append(numbers, number)
+		}
+	}
+	if len(numbers) == 0 {
+		return "", ErrNotConfigured
+	}
+
+	for _, number := range numbers {
+		key := sessionKey(number, callerPhone)
+
+		// A number the caller already uses for the same callee is extended
+		callee, err := s.RedisClient.Get(ctx, key).Result()
+		if err == nil && callee == calleePhone {
+			if err := s.RedisClient.Expire(ctx, key, sessionTTL).Err(); err != nil {
+				return "", err
+			}
+			return number, nil
+		}
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return "", err
+		}
+		if err == nil {
+			continue
+		}
+
+		ok, err := s.RedisClient.SetNX(ctx, key, calleePhone, sessionTTL).Result()
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return number, nil
+		}
+	}
+
+	return "", ErrNoNumberAvailable
+}
+
+// ResolveCall returns the phone number a call from the caller to a masked number is forwarded to.
+func (s *PoolCallingService) ResolveCall(callerPhone string, maskedNumber string) (string, error) {
+	callee, err := s.RedisClient.Get(ctx, sessionKey(maskedNumber, callerPhone)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrSessionNotFound
+	}
+
+	return callee, err
+}
+
+func sessionKey(maskedNumber string, callerPhone string) string {
+	return fmt.Sprintf("masked-call:%s:%s", maskedNumber, callerPhone)
+}
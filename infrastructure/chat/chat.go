@@ -2,16 +2,27 @@ package chat
 
 import (
 	"context"
-	"fmt"
-	"time"
+	"errors"
 
-	stream "github.com/GetStream/stream-chat-go/v5"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
 )
 
 var (
 	ctx = context.Background()
 )
 
+// The chat providers that can be selected with the CHAT_PROVIDER environment variable.
+const (
+	StreamProvider = "stream"
+	LocalProvider  = "local"
+)
+
+// ErrNotMember is returned when a user acts on a channel they're not a member of.
+var ErrNotMember = errors.New("not a member of the chat channel")
+
+// ErrInvalidToken is returned when a chat token is unknown or has expired.
+var ErrInvalidToken = errors.New("invalid chat token")
+
 // ChatServiceInterface defines the methods that a chat service should implement.
 type ChatServiceInterface interface {
 	GetStreamToken(userID string) (*TokenDetails, error)
@@ -20,9 +31,21 @@ type ChatServiceInterface interface {
 	RemoveMember(channelID string, userID string) error
 }
 
-// ChatService represents the chat service implementation
-type ChatService struct {
-	streamClient *stream.Client
+// MessagingServiceInterface defines the methods of the chat services that host the conversations
+// themselves, rather than leaving them to the clients of a third party such as Stream.
+type MessagingServiceInterface interface {
+	ChatServiceInterface
+	Authenticate(token string) (string, error)
+	GetChannels(userID string) ([]entity.ChatChannel, error)
+	GetMessages(channelID string, userID string, beforeID uint64, limit int) ([]entity.ChatMessage, error)
+	SendMessage(channelID string, userID string, text string) (*entity.ChatMessage, error)
+	MarkRead(channelID string, userID string, messageID uint64) error
+	Subscribe(userID string) (<-chan Event, func(), error)
+}
+
+// WebhookVerifierInterface defines the methods of the chat services that call the API back with webhooks.
+type WebhookVerifierInterface interface {
+	VerifyWebhook(body []byte, signature []byte) bool
 }
 
 // TokenDetails represents the details of a stream token.
@@ -30,118 +53,19 @@ type TokenDetails struct {
 	Token string
 }
 
-var _ ChatServiceInterface = &ChatService{}
-
-// NewChatService creates and returns a new instance of ChatService
-func NewChatService(streamClient *stream.Client) *ChatService {
-	return &ChatService{streamClient: streamClient}
-}
-
-func (s *ChatService) GetStreamToken(userID string) (*TokenDetails, error) {
-	token, err := s.streamClient.CreateToken(userID, time.Time{})
-	if err != nil {
-		return nil, err
-	}
-
-	return &TokenDetails{
-		Token: token,
-	}, nil
-}
-
-// CreateChannel creates a new Stream Chat channel with the given parameters.
-func (s *ChatService) CreateChannel(channelID string, userID string) error {
-	resp, err := s.streamClient.CreateChannel(ctx, "messaging", channelID, userID, nil)
-	if err != nil {
-		fmt.Println("Error creating channel:", err)
-		return err
-	}
-
-	_, err = resp.Channel.AddMembers(ctx, []string{userID})
-	if err != nil {
-		fmt.Println("Error adding members:", err)
-		return err
-	}
-
-	return nil
-}
-
-func (s *ChatService) AddMember(channelID string, userID string) error {
-	// Check if the user exists
-	userExists, err := s.checkUserExists(userID)
-	if err != nil {
-		fmt.Println("Error checking user existence:", err)
-		return err
-	}
-
-	if !userExists {
-		// Handle the case where the user doesn't exist
-		// User does not exist, create the user
-		err = s.createUser(userID)
-		if err != nil {
-			fmt.Println("Error creating user:", err)
-			return err
-		}
-	}
-
-	channel := s.streamClient.Channel("messaging", channelID)
-
-	_, err = channel.AddMembers(ctx, []string{userID})
-	if err != nil {
-		fmt.Println("Error adding members:", err)
-		return err
-	}
-
-	return nil
-}
-
-func (s *ChatService) RemoveMember(channelID string, userID string) error {
-	channel := s.streamClient.Channel("messaging", channelID)
-
-	_, err := channel.RemoveMembers(ctx, []string{userID}, nil)
-	if err != nil {
-		fmt.Println("Error removing members:", err)
-		return err
-	}
-
-	return nil
-}
-
-func (s *ChatService) checkUserExists(userID string) (bool, error) {
-	// Create a query option to search for the specific user by their ID
-	query := &stream.QueryOption{
-		Filter: map[string]interface{}{
-			"id": userID,
-		},
-	}
-
-	// Make a request to the GetStream.io API to search for users
-	users, err := s.streamClient.QueryUsers(ctx, query)
-	if err != nil {
-		fmt.Println("Error querying users:", err)
-		return false, err
-	}
-
-	// Check if the user exists
-	if len(users.Users) > 0 {
-		// User exists
-		return true, nil
-	}
-
-	// User does not exist
-	return false, nil
-}
-
-func (s *ChatService) createUser(userID string) error {
-	// Create a new user object
-	user := &stream.User{
-		ID: userID,
-	}
-
-	// Make a request to the GetStream.io API to create or update the user
-	_, err := s.streamClient.UpsertUser(ctx, user)
-	if err != nil {
-		return err
-	}
+// The types of the events delivered to the members of a channel.
+const (
+	MessageNewEvent    = "message.new"
+	MessageReadEvent   = "message.read"
+	MemberAddedEvent   = "member.added"
+	MemberRemovedEvent = "member.removed"
+)
 
-	return nil
+// Event represents a change to a channel delivered in real time to its members.
+type Event struct {
+	Type      string              `json:"type"`
+	ChannelID string              `json:"channel_id"`
+	UserID    string              `json:"user_id,omitempty"`
+	MessageID uint64              `json:"message_id,omitempty"`
+	Message   *entity.ChatMessage `json:"message,omitempty"`
 }
@@ -0,0 +1,226 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/go-redis/redis/v9"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// tokenTTL is how long a chat token can be used to open the real-time socket.
+const tokenTTL = 24 * time.Hour
+
+// LocalChatService implements MessagingServiceInterface with the conversations stored in Postgres.
+// Events are published to Redis so the member's socket receives them whichever instance serves it.
+type LocalChatService struct {
+	ChatApp     application.ChatApplicationInterface
+	RedisClient *redis.Client
+}
+
+var _ MessagingServiceInterface = &LocalChatService{}
+
+// NewLocalChatService creates and returns a new instance of LocalChatService
+func NewLocalChatService(chatApp application.ChatApplicationInterface, redisClient *redis.Client) *LocalChatService {
+	return &LocalChatService{
+		ChatApp:     chatApp,
+		RedisClient: redisClient,
+	}
+}
+
+// GetStreamToken issues a token the user opens the real-time socket with.
+func (s *LocalChatService) GetStreamToken(userID string) (*TokenDetails, error) {
+	token := uuid.NewString()
+
+	if err := s.RedisClient.Set(ctx, tokenKey(token), userID, tokenTTL).Err(); err != nil {
+		return nil, err
+	}
+
+	return &TokenDetails{
+		Token: token,
+	}, nil
+}
+
+// Authenticate returns the chat user ID a token was issued to.
+func (s *LocalChatService) Authenticate(token string) (string, error) {
+	userID, err := s.RedisClient.Get(ctx, tokenKey(token)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrInvalidToken
+	}
+
+	return userID, err
+}
+
+// CreateChannel creates a channel with the user as its first member.
+func (s *LocalChatService) CreateChannel(channelID string, userID string) error {
+	return s.AddMember(channelID, userID)
+}
+
+func (s *LocalChatService) AddMember(channelID string, userID string) error {
+	// Adding a member to a missing channel creates it
+	if err := s.ChatApp.CreateChannel(&entity.ChatChannel{ID: channelID, CreatedByID: userID}); err != nil {
+		return err
+	}
+
+	if err := s.ChatApp.AddMember(&entity.ChatMember{ChannelID: channelID, UserID: userID}); err != nil {
+		return err
+	}
+
+	s.publish(channelID, Event{Type: MemberAddedEvent, ChannelID: channelID, UserID: userID})
+
+	return nil
+}
+
+func (s *LocalChatService) RemoveMember(channelID string, userID string) error {
+	// The removed member is notified along with the remaining ones
+	members, err := s.ChatApp.GetAllMembersByChannelID(channelID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ChatApp.RemoveMember(channelID, userID); err != nil {
+		return err
+	}
+
+	s.publishTo(members, Event{Type: MemberRemovedEvent, ChannelID: channelID, UserID: userID})
+
+	return nil
+}
+
+// GetChannels retrieves the channels the user is a member of.
+func (s *LocalChatService) GetChannels(userID string) ([]entity.ChatChannel, error) {
+	return s.ChatApp.GetAllChannelsByUserID(userID)
+}
+
+// GetMessages retrieves the messages of a channel, newest first, sent before the given message.
+func (s *LocalChatService) GetMessages(channelID string, userID string, beforeID uint64, limit int) ([]entity.ChatMessage, error) {
+	if err := s.checkMember(channelID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.ChatApp.GetAllMessagesByChannelID(channelID, beforeID, limit)
+}
+
+// SendMessage stores a message and delivers it to the members of the channel.
+func (s *LocalChatService) SendMessage(channelID string, userID string, text string) (*entity.ChatMessage, error) {
+	if err := s.checkMember(channelID, userID); err != nil {
+		return nil, err
+	}
+
+	message, err := s.ChatApp.CreateMessage(&entity.ChatMessage{ChannelID: channelID, UserID: userID, Text: text})
+	if err != nil {
+		return nil, err
+	}
+
+	// The sender has read their own message
+	if err := s.ChatApp.UpdateMemberLastReadMessageID(channelID, userID, message.ID); err != nil {
+		return nil, err
+	}
+
+	s.publish(channelID, Event{Type: MessageNewEvent, ChannelID: channelID, UserID: userID, MessageID: message.ID, Message: message})
+
+	return message, nil
+}
+
+// MarkRead records that the user has read the messages of a channel up to the given one.
+func (s *LocalChatService) MarkRead(channelID string, userID string, messageID uint64) error {
+	if err := s.checkMember(channelID, userID); err != nil {
+		return err
+	}
+
+	if err := s.ChatApp.UpdateMemberLastReadMessageID(channelID, userID, messageID); err != nil {
+		return err
+	}
+
+	s.publish(channelID, Event{Type: MessageReadEvent, ChannelID: channelID, UserID: userID, MessageID: messageID})
+
+	return nil
+}
+
+// Subscribe delivers the events of the user's channels until the returned function is called.
+func (s *LocalChatService) Subscribe(userID string) (<-chan Event, func(), error) {
+	pubsub := s.RedisClient.Subscribe(ctx, userEventsKey(userID))
+
+	// Wait for the subscription to be confirmed so no event is missed after returning
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, nil, err
+	}
+
+	events := make(chan Event)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(events)
+
+		for message := range pubsub.Channel() {
+			var event Event
+			if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+				log.Println("Error decoding chat event: ", err)
+				continue
+			}
+
+			select {
+			case events <- event:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return events, func() {
+		close(done)
+		pubsub.Close()
+	}, nil
+}
+
+func (s *LocalChatService) checkMember(channelID string, userID string) error {
+	if _, err := s.ChatApp.GetMember(channelID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotMember
+		}
+		return err
+	}
+
+	return nil
+}
+
+// publish delivers an event to the members of a channel. Members who aren't connected miss it
+// and catch up through the channel history.
+func (s *LocalChatService) publish(channelID string, event Event) {
+	members, err := s.ChatApp.GetAllMembersByChannelID(channelID)
+	if err != nil {
+		log.Println("Error fetching chat members: ", err)
+		return
+	}
+
+	s.publishTo(members, event)
+}
+
+func (s *LocalChatService) publishTo(members []entity.ChatMember, event Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Println("Error encoding chat event: ", err)
+		return
+	}
+
+	for _, member := range members {
+		if err := s.RedisClient.Publish(ctx, userEventsKey(member.UserID), payload).Err(); err != nil {
+			log.Println("Error publishing chat event: ", err)
+		}
+	}
+}
+
+func tokenKey(token string) string {
+	return fmt.Sprintf("chat-token:%s", token)
+}
+
+func userEventsKey(userID string) string {
+	return fmt.Sprintf("chat-events:%s", userID)
+}
@@ -0,0 +1,250 @@
+package chat
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/google/uuid"
+)
+
+// MemoryChatService implements MessagingServiceInterface in memory. It's meant for tests, where the
+// order flows need a chat provider without Stream, Postgres or Redis.
+type MemoryChatService struct {
+	mu          sync.Mutex
+	tokens      map[string]string
+	channels    map[string]*entity.ChatChannel
+	messages    map[string][]entity.ChatMessage
+	subscribers map[string][]chan Event
+	lastID      uint64
+}
+
+var _ MessagingServiceInterface = &MemoryChatService{}
+
+// NewMemoryChatService creates and returns a new instance of MemoryChatService
+func NewMemoryChatService() *MemoryChatService {
+	return &MemoryChatService{
+		tokens:      make(map[string]string),
+		channels:    make(map[string]*entity.ChatChannel),
+		messages:    make(map[string][]entity.ChatMessage),
+		subscribers: make(map[string][]chan Event),
+	}
+}
+
+func (s *MemoryChatService) GetStreamToken(userID string) (*TokenDetails, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token := uuid.NewString()
+	s.tokens[token] = userID
+
+	return &TokenDetails{
+		Token: token,
+	}, nil
+}
+
+func (s *MemoryChatService) Authenticate(token string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userID, ok := s.tokens[token]
+	if !ok {
+		return "", ErrInvalidToken
+	}
+
+	return userID, nil
+}
+
+func (s *MemoryChatService) CreateChannel(channelID string, userID string) error {
+	s.mu.Lock()
+	if _, ok := s.channels[channelID]; !ok {
+		s.channels[channelID] = &entity.ChatChannel{ID: channelID, CreatedByID: userID, CreatedAt: time.Now()}
+	}
+	s.mu.Unlock()
+
+	return s.AddMember(channelID, userID)
+}
+
+func (s *MemoryChatService) AddMember(channelID string, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Adding a member to a missing channel creates it
+	channel, ok := s.channels[channelID]
+	if !ok {
+		channel = &entity.ChatChannel{ID: channelID, CreatedByID: userID, CreatedAt: time.Now()}
+		s.channels[channelID] = channel
+	}
+
+	if s.findMember(channel, userID) < 0 {
+		channel.Members = append(channel.Members, entity.ChatMember{ChannelID: channelID, UserID: userID, CreatedAt: time.Now()})
+	}
+
+	s.publish(channel.Members, Event{Type: MemberAddedEvent, ChannelID: channelID, UserID: userID})
+
+	return nil
+}
+
+func (s *MemoryChatService) RemoveMember(channelID string, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channel, ok := s.channels[channelID]
+	if !ok {
+		return nil
+	}
+
+	// The removed member is notified along with the remaining ones
+	members := channel.Members
+	if i := s.findMember(channel, userID); i >= 0 {
+		channel.Members = append(channel.Members[:i:i], channel.Members[i+1:]...)
+	}
+
+	s.publish(members, Event{Type: MemberRemovedEvent, ChannelID: channelID, UserID: userID})
+
+	return nil
+}
+
+func (s *MemoryChatService) GetChannels(userID string) ([]entity.ChatChannel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var channels []entity.ChatChannel
+	for _, channel := range s.channels {
+		if s.findMember(channel, userID) >= 0 {
+			c := *channel
+			c.Members = append([]entity.ChatMember(nil), channel.Members...)
+			channels = append(channels, c)
+		}
+	}
+
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].CreatedAt.After(channels[j].CreatedAt)
+	})
+
+	return channels, nil
+}
+
+func (s *MemoryChatService) GetMessages(channelID string, userID string, beforeID uint64, limit int) ([]entity.ChatMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.member(channelID, userID); err != nil {
+		return nil, err
+	}
+
+	var messages []entity.ChatMessage
+	history := s.messages[channelID]
+	for i := len(history) - 1; i >= 0 && len(messages) < limit; i-- {
+		if beforeID == 0 || history[i].ID < beforeID {
+			messages = append(messages, history[i])
+		}
+	}
+
+	return messages, nil
+}
+
+func (s *MemoryChatService) SendMessage(channelID string, userID string, text string) (*entity.ChatMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	member, err := s.member(channelID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lastID++
+	message := entity.ChatMessage{ID: s.lastID, ChannelID: channelID, UserID: userID, Text: text, CreatedAt: time.Now()}
+	s.messages[channelID] = append(s.messages[channelID], message)
+
+	// The sender has read their own message
+	member.LastReadMessageID = message.ID
+
+	s.publish(s.channels[channelID].Members, Event{Type: MessageNewEvent, ChannelID: channelID, UserID: userID, MessageID: message.ID, Message: &message})
+
+	return &message, nil
+}
+
+func (s *MemoryChatService) MarkRead(channelID string, userID string, messageID uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	member, err := s.member(channelID, userID)
+	if err != nil {
+		return err
+	}
+
+	if messageID > member.LastReadMessageID {
+		member.LastReadMessageID = messageID
+	}
+
+	s.publish(s.channels[channelID].Members, Event{Type: MessageReadEvent, ChannelID: channelID, UserID: userID, MessageID: messageID})
+
+	return nil
+}
+
+func (s *MemoryChatService) Subscribe(userID string) (<-chan Event, func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Events are buffered so publishing never blocks on a slow subscriber
+	events := make(chan Event, 100)
+	s.subscribers[userID] = append(s.subscribers[userID], events)
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			subscribers := s.subscribers[userID]
+			for i, subscriber := range subscribers {
+				if subscriber == events {
+					s.subscribers[userID] = append(subscribers[:i:i], subscribers[i+1:]...)
+					break
+				}
+			}
+			close(events)
+		})
+	}
+
+	return events, unsubscribe, nil
+}
+
+// member returns the membership of a user in a channel. The caller must hold the lock.
+func (s *MemoryChatService) member(channelID string, userID string) (*entity.ChatMember, error) {
+	channel, ok := s.channels[channelID]
+	if !ok {
+		return nil, ErrNotMember
+	}
+
+	i := s.findMember(channel, userID)
+	if i < 0 {
+		return nil, ErrNotMember
+	}
+
+	return &channel.Members[i], nil
+}
+
+func (s *MemoryChatService) findMember(channel *entity.ChatChannel, userID string) int {
+	for i, member := range channel.Members {
+		if member.UserID == userID {
+			return i
+		}
+	}
+	return -1
+}
+
+// publish delivers an event to the subscribers of the members. The caller must hold the lock.
+func (s *MemoryChatService) publish(members []entity.ChatMember, event Event) {
+	for _, member := range members {
+		for _, subscriber := range s.subscribers[member.UserID] {
+			select {
+			case subscriber <- event:
+			default:
+				// Events are dropped for subscribers that stopped reading
+			}
+		}
+	}
+}
@@ -1,18 +1,154 @@
 package chat
 
-import stream "github.com/GetStream/stream-chat-go/v5"
+import (
+	"fmt"
+	"time"
+
+	stream "github.com/GetStream/stream-chat-go/v5"
+)
 
 // StreamService struct contains the stream client, and chat service
 type StreamService struct {
 	StreamClient *stream.Client
-	ChatService  ChatServiceInterface
+	ChatService  *StreamChatService
 }
 
 // NewStreamService creates a new instance of StreamService with the provided host, port, and password
 func NewStreamService(apiKey, apiSecret string) (*StreamService, error) {
-	streamClient, _ := stream.NewClient(apiKey, apiSecret)
+	streamClient, err := stream.NewClient(apiKey, apiSecret)
+	if err != nil {
+		return nil, err
+	}
+
 	return &StreamService{
 		StreamClient: streamClient,
-		ChatService:  NewChatService(streamClient),
+		ChatService:  NewStreamChatService(streamClient),
+	}, nil
+}
+
+// StreamChatService implements ChatServiceInterface with the GetStream.io chat API
+type StreamChatService struct {
+	streamClient *stream.Client
+}
+
+var _ ChatServiceInterface = &StreamChatService{}
+var _ WebhookVerifierInterface = &StreamChatService{}
+
+// NewStreamChatService creates and returns a new instance of StreamChatService
+func NewStreamChatService(streamClient *stream.Client) *StreamChatService {
+	return &StreamChatService{streamClient: streamClient}
+}
+
+func (s *StreamChatService) GetStreamToken(userID string) (*TokenDetails, error) {
+	token, err := s.streamClient.CreateToken(userID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenDetails{
+		Token: token,
 	}, nil
 }
+
+// CreateChannel creates a new Stream Chat channel with the given parameters.
+func (s *StreamChatService) CreateChannel(channelID string, userID string) error {
+	resp, err := s.streamClient.CreateChannel(ctx, "messaging", channelID, userID, nil)
+	if err != nil {
+		fmt.Println("Error creating channel:", err)
+		return err
+	}
+
+	_, err = resp.Channel.AddMembers(ctx, []string{userID})
+	if err != nil {
+		fmt.Println("Error adding members:", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *StreamChatService) AddMember(channelID string, userID string) error {
+	// Check if the user exists
+	userExists, err := s.checkUserExists(userID)
+	if err != nil {
+		fmt.Println("Error checking user existence:", err)
+		return err
+	}
+
+	if !userExists {
+		// Handle the case where the user doesn't exist
+		// User does not exist, create the user
+		err = s.createUser(userID)
+		if err != nil {
+			fmt.Println("Error creating user:", err)
+			return err
+		}
+	}
+
+	channel := s.streamClient.Channel("messaging", channelID)
+
+	_, err = channel.AddMembers(ctx, []string{userID})
+	if err != nil {
+		fmt.Println("Error adding members:", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *StreamChatService) RemoveMember(channelID string, userID string) error {
+	channel := s.streamClient.Channel("messaging", channelID)
+
+	_, err := channel.RemoveMembers(ctx, []string{userID}, nil)
+	if err != nil {
+		fmt.Println("Error removing members:", err)
+		return err
+	}
+
+	return nil
+}
+
+// VerifyWebhook reports whether a webhook request was signed with the Stream API secret.
+func (s *StreamChatService) VerifyWebhook(body []byte, signature []byte) bool {
+	return s.streamClient.VerifyWebhook(body, signature)
+}
+
+func (s *StreamChatService) checkUserExists(userID string) (bool, error) {
+	// Create a query option to search for the specific user by their ID
+	query := &stream.QueryOption{
+		Filter: map[string]interface{}{
+			"id": userID,
+		},
+	}
+
+	// Make a request to the GetStream.io API to search for users
+	users, err := s.streamClient.QueryUsers(ctx, query)
+	if err != nil {
+		fmt.Println("Error querying users:", err)
+		return false, err
+	}
+
+	// Check if the user exists
+	if len(users.Users) > 0 {
+		// User exists
+		return true, nil
+	}
+
+	// User does not exist
+	return false, nil
+}
+
+func (s *StreamChatService) createUser(userID string) error {
+	// Create a new user object
+	user := &stream.User{
+		ID: userID,
+	}
+
+	// Make a request to the GetStream.io API to create or update the user
+	_, err := s.streamClient.UpsertUser(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,295 @@
+package chatsync
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/chat"
+	"gorm.io/gorm"
+)
+
+// batchSize is the number of changes applied, or orders reconciled, at once.
+const batchSize = 100
+
+// maxBackoff caps the delay between the attempts of a failing change.
+const maxBackoff = time.Hour
+
+// activeStatuses are the statuses of the orders whose sender, driver and recipient chat together.
+var activeStatuses = []entity.OrderStatus{
+	entity.OrderAcceptedStatus,
+	entity.PickupInProgressStatus,
+	entity.ShipmentPickedUpStatus,
+	entity.InTransitStatus,
+	entity.AtDestinationCityStatus,
+	entity.OutForDeliveryStatus,
+	entity.DeliveryAttemptedStatus,
+	entity.DeliveryRescheduledStatus,
+}
+
+// deliveredStatuses are the statuses of the orders whose sender chats until they rate the driver.
+var deliveredStatuses = []entity.OrderStatus{
+	entity.ShipmentDeliveredStatus,
+	entity.OrderCompletedStatus,
+}
+
+// closedStatuses are the statuses of the orders whose sender and recipient left the chat.
+var closedStatuses = []entity.OrderStatus{
+	entity.OrderCanceledStatus,
+	entity.ShipmentReturnedStatus,
+}
+
+// ChatSyncServiceInterface defines the methods that a chat sync service should implement.
+type ChatSyncServiceInterface interface {
+	Start(interval time.Duration)
+	ProcessDueChanges(now time.Time) error
+	Reconcile() error
+	HandleOrderEvent(event *entity.OutboxEvent) error
+}
+
+// ChatSyncService applies the chat membership changes of the outbox to the chat provider.
+type ChatSyncService struct {
+	ChatService   chat.ChatServiceInterface
+	ChatOutboxApp application.ChatOutboxApplicationInterface
+	OrderApp      application.OrderApplicationInterface
+	RatingApp     application.RatingApplicationInterface
+}
+
+// Ensure that ChatSyncService implements ChatSyncServiceInterface.
+var _ ChatSyncServiceInterface = &ChatSyncService{}
+
+// NewChatSyncService creates and returns a new instance of ChatSyncService.
+func NewChatSyncService(chatService chat.ChatServiceInterface, chatOutboxApp application.ChatOutboxApplicationInterface, orderApp application.OrderApplicationInterface, ratingApp application.RatingApplicationInterface) *ChatSyncService {
+	return &ChatSyncService{
+		ChatService:   chatService,
+		ChatOutboxApp: chatOutboxApp,
+		OrderApp:      orderApp,
+		RatingApp:     ratingApp,
+	}
+}
+
+// Start applies the due changes every interval until the process exits.
+func (s *ChatSyncService) Start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if err := s.ProcessDueChanges(now); err != nil {
+			log.Println("Error applying chat membership changes: ", err)
+		}
+	}
+}
+
+// ProcessDueChanges applies the changes due for an attempt. The changes of a channel are applied in
+// the order they were made, so a failing change holds back the later ones of its channel.
+func (s *ChatSyncService) ProcessDueChanges(now time.Time) error {
+	changes, err := s.ChatOutboxApp.GetAllDueChatOutboxes(now, batchSize)
+	if err != nil {
+		return err
+	}
+
+	blocked := make(map[string]bool)
+
+	for _, change := range changes {
+		change := change
+
+		if blocked[change.ChannelID] {
+			continue
+		}
+
+		change.Attempts++
+
+		if err := s.apply(&change); err != nil {
+			blocked[change.ChannelID] = true
+
+			change.LastError = err.Error()
+			change.NextAttemptAt = now.Add(backoff(change.Attempts))
+			log.Printf("Error applying chat membership change %d (attempt %d): %v", change.ID, change.Attempts, err)
+		} else {
+			processedAt := time.Now()
+			change.LastError = ""
+			change.ProcessedAt = &processedAt
+		}
+
+		if _, err := s.ChatOutboxApp.UpdateChatOutboxByID(change.ID, &change); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Reconcile queues the changes bringing the chat channels of the existing orders in line with
+// their statuses. The chat provider's operations are idempotent, so changes that were already
+// applied are harmless.
+func (s *ChatSyncService) Reconcile() error {
+	for _, group := range []struct {
+		status  []entity.OrderStatus
+		changes func(order *entity.Order) ([]entity.ChatOutbox, error)
+	}{
+		{activeStatuses, s.activeChanges},
+		{deliveredStatuses, s.deliveredChanges},
+		{closedStatuses, s.closedChanges},
+	} {
+		var afterID uint64
+		for {
+			orders, err := s.OrderApp.GetAllOrdersByStatusAfterID(group.status, afterID, batchSize)
+			if err != nil {
+				return err
+			}
+			if len(orders) == 0 {
+				break
+			}
+
+			var changes []entity.ChatOutbox
+			for _, order := range orders {
+				order := order
+
+				orderChanges, err := group.changes(&order)
+				if err != nil {
+					return err
+				}
+				changes = append(changes, orderChanges...)
+			}
+
+			if err := s.ChatOutboxApp.CreateChatOutboxes(changes); err != nil {
+				return err
+			}
+
+			afterID = orders[len(orders)-1].ID
+		}
+	}
+
+	return nil
+}
+
+// HandleOrderEvent queues the chat membership changes following an order event. Queuing the same
+// changes twice only repeats idempotent operations, so the event can be delivered again.
+func (s *ChatSyncService) HandleOrderEvent(event *entity.OutboxEvent) error {
+	payload, err := event.OrderPayload()
+	if err != nil {
+		return err
+	}
+
+	channelID := fmt.Sprintf("order-%d", payload.OrderID)
+	sender := fmt.Sprintf("client-%d", payload.UserID)
+	driver := fmt.Sprintf("driver-%d", payload.DriverUserID)
+	recipient := fmt.Sprintf("client-%d", payload.RecipientID)
+
+	var changes []entity.ChatOutbox
+	switch event.Type {
+	case entity.OrderAcceptedEvent:
+		changes = append(changes,
+			entity.ChatOutbox{Action: entity.ChatCreateChannelAction, ChannelID: channelID, UserID: sender},
+			entity.ChatOutbox{Action: entity.ChatAddMemberAction, ChannelID: channelID, UserID: driver},
+		)
+		if payload.RecipientID != 0 {
+			changes = append(changes, entity.ChatOutbox{Action: entity.ChatAddMemberAction, ChannelID: channelID, UserID: recipient})
+		}
+	case entity.OrderRecipientLinkedEvent:
+		changes = append(changes, entity.ChatOutbox{Action: entity.ChatAddMemberAction, ChannelID: channelID, UserID: recipient})
+	case entity.OrderCanceledEvent:
+		changes = append(changes, entity.ChatOutbox{Action: entity.ChatRemoveMemberAction, ChannelID: channelID, UserID: sender})
+		if payload.RecipientID != 0 {
+			changes = append(changes, entity.ChatOutbox{Action: entity.ChatRemoveMemberAction, ChannelID: channelID, UserID: recipient})
+		}
+	case entity.OrderDeliveredEvent:
+		changes = append(changes, entity.ChatOutbox{Action: entity.ChatRemoveMemberAction, ChannelID: channelID, UserID: driver})
+		if payload.RecipientID != 0 {
+			changes = append(changes, entity.ChatOutbox{Action: entity.ChatRemoveMemberAction, ChannelID: channelID, UserID: recipient})
+		}
+	case entity.OrderDriverRatedEvent:
+		changes = append(changes, entity.ChatOutbox{Action: entity.ChatRemoveMemberAction, ChannelID: channelID, UserID: sender})
+	}
+
+	return s.ChatOutboxApp.CreateChatOutboxes(changes)
+}
+
+func (s *ChatSyncService) apply(change *entity.ChatOutbox) error {
+	switch change.Action {
+	case entity.ChatCreateChannelAction:
+		return s.ChatService.CreateChannel(change.ChannelID, change.UserID)
+	case entity.ChatAddMemberAction:
+		return s.ChatService.AddMember(change.ChannelID, change.UserID)
+	case entity.ChatRemoveMemberAction:
+		return s.ChatService.RemoveMember(change.ChannelID, change.UserID)
+	}
+
+	return fmt.Errorf("unknown chat membership action %q", change.Action)
+}
+
+// activeChanges returns the changes adding the sender, the driver and the recipient to the channel of an order.
+func (s *ChatSyncService) activeChanges(order *entity.Order) ([]entity.ChatOutbox, error) {
+	channelID := fmt.Sprintf("order-%d", order.ID)
+
+	changes := []entity.ChatOutbox{
+		{Action: entity.ChatCreateChannelAction, ChannelID: channelID, UserID: fmt.Sprintf("client-%d", order.UserID)},
+	}
+	if order.DriverID != 0 {
+		changes = append(changes, entity.ChatOutbox{Action: entity.ChatAddMemberAction, ChannelID: channelID, UserID: fmt.Sprintf("driver-%d", order.Driver.UserID)})
+	}
+	if order.RecipientID != 0 {
+		changes = append(changes, entity.ChatOutbox{Action: entity.ChatAddMemberAction, ChannelID: channelID, UserID: fmt.Sprintf("client-%d", order.RecipientID)})
+	}
+
+	return changes, nil
+}
+
+// deliveredChanges returns the changes removing the driver and the recipient from the channel of a
+// delivered order, and the sender once they rated the driver.
+func (s *ChatSyncService) deliveredChanges(order *entity.Order) ([]entity.ChatOutbox, error) {
+	channelID := fmt.Sprintf("order-%d", order.ID)
+
+	var changes []entity.ChatOutbox
+	if order.DriverID != 0 {
+		changes = append(changes, entity.ChatOutbox{Action: entity.ChatRemoveMemberAction, ChannelID: channelID, UserID: fmt.Sprintf("driver-%d", order.Driver.UserID)})
+	}
+	if order.RecipientID != 0 {
+		changes = append(changes, entity.ChatOutbox{Action: entity.ChatRemoveMemberAction, ChannelID: channelID, UserID: fmt.Sprintf("client-%d", order.RecipientID)})
+	}
+
+	_, err := s.RatingApp.GetRatingByOrderIDAndType(order.ID, entity.DriverRatingType)
+	if err == nil {
+		changes = append(changes, entity.ChatOutbox{Action: entity.ChatRemoveMemberAction, ChannelID: channelID, UserID: fmt.Sprintf("client-%d", order.UserID)})
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// closedChanges returns the changes removing the sender and the recipient from the channel of a
+// canceled or returned order.
+func (s *ChatSyncService) closedChanges(order *entity.Order) ([]entity.ChatOutbox, error) {
+	// Orders canceled before they were accepted never had a channel
+	if order.DriverID == 0 {
+		return nil, nil
+	}
+
+	channelID := fmt.Sprintf("order-%d", order.ID)
+
+	changes := []entity.ChatOutbox{
+		{Action: entity.ChatRemoveMemberAction, ChannelID: channelID, UserID: fmt.Sprintf("client-%d", order.UserID)},
+	}
+	if order.RecipientID != 0 {
+		changes = append(changes, entity.ChatOutbox{Action: entity.ChatRemoveMemberAction, ChannelID: channelID, UserID: fmt.Sprintf("client-%d", order.RecipientID)})
+	}
+
+	return changes, nil
+}
+
+// backoff returns the delay before the next attempt of a change that failed the given number of
+// times, doubling from 30 seconds up to an hour.
+func backoff(attempts int) time.Duration {
+	delay := 30 * time.Second
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
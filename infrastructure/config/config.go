@@ -17,27 +17,37 @@ type Config struct {
 	RedisHost        string
 	RedisPassword    string
 	RedisPort        string
+	ChatProvider     string
 	StreamApiKey     string
 	StreamApiSecret  string
+	OsrmURL          string
+	// CallingWebhookSecret authenticates the telephony provider forwarding the masked calls
+	CallingWebhookSecret string
+	// FirebaseCredentialsFile is the service account file push notifications are sent with
+	FirebaseCredentialsFile string
 }
 
 func NewConfig() *Config {
 	return &Config{
-		Host:             os.Getenv("HOST"),
-		Port:             os.Getenv("PORT"),
-		BaseURL:          os.Getenv("BASE_URL"),
-		BaseStorageURL:   os.Getenv("BASE_URL") + "/uploads",
-		PostgresHost:     os.Getenv("POSTGRES_HOST"),
-		PostgresPort:     os.Getenv("POSTGRES_PORT"),
-		PostgresDatabase: os.Getenv("POSTGRES_DATABASE"),
-		PostgresUsername: os.Getenv("POSTGRES_USERNAME"),
-		PostgresPassword: os.Getenv("POSTGRES_PASSWORD"),
-		PostgresSslMode:  os.Getenv("POSTGRES_SSL_MODE"),
-		PostgresTimeZone: os.Getenv("POSTGRES_TIME_ZONE"),
-		RedisHost:        os.Getenv("REDIS_HOST"),
-		RedisPassword:    os.Getenv("REDIS_PASSWORD"),
-		RedisPort:        os.Getenv("REDIS_PORT"),
-		StreamApiKey:     os.Getenv("STREAM_API_KEY"),
-		StreamApiSecret:  os.Getenv("STREAM_API_SECRET"),
+		Host:                    os.Getenv("HOST"),
+		Port:                    os.Getenv("PORT"),
+		BaseURL:                 os.Getenv("BASE_URL"),
+		BaseStorageURL:          os.Getenv("BASE_URL") + "/uploads",
+		PostgresHost:            os.Getenv("POSTGRES_HOST"),
+		PostgresPort:            os.Getenv("POSTGRES_PORT"),
+		PostgresDatabase:        os.Getenv("POSTGRES_DATABASE"),
+		PostgresUsername:        os.Getenv("POSTGRES_USERNAME"),
+		PostgresPassword:        os.Getenv("POSTGRES_PASSWORD"),
+		PostgresSslMode:         os.Getenv("POSTGRES_SSL_MODE"),
+		PostgresTimeZone:        os.Getenv("POSTGRES_TIME_ZONE"),
+		RedisHost:               os.Getenv("REDIS_HOST"),
+		RedisPassword:           os.Getenv("REDIS_PASSWORD"),
+		RedisPort:               os.Getenv("REDIS_PORT"),
+		ChatProvider:            os.Getenv("CHAT_PROVIDER"),
+		StreamApiKey:            os.Getenv("STREAM_API_KEY"),
+		StreamApiSecret:         os.Getenv("STREAM_API_SECRET"),
+		OsrmURL:                 os.Getenv("OSRM_URL"),
+		CallingWebhookSecret:    os.Getenv("CALLING_WEBHOOK_SECRET"),
+		FirebaseCredentialsFile: os.Getenv("FIREBASE_CREDENTIALS_FILE"),
 	}
 }
@@ -0,0 +1,181 @@
+package events
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// batchSize is the number of pending events looked at in each round.
+const batchSize = 100
+
+// maxAttempts is the number of times an event is delivered before it's given up on.
+const maxAttempts = 10
+
+// maxBackoff caps the delay between the attempts of a failing event.
+const maxBackoff = time.Hour
+
+// Handler handles an event delivered by the dispatcher. An event is delivered again to the
+// subscribers that failed, until they handle it. Events are delivered at least once, a handler can
+// still see an event twice when the dispatcher stops before recording it, so handlers should be
+// idempotent.
+type Handler func(event *entity.OutboxEvent) error
+
+// DispatcherInterface defines the methods that an event dispatcher should implement.
+type DispatcherInterface interface {
+	Subscribe(eventType entity.EventType, name string, handler Handler)
+	Start(interval time.Duration)
+	DispatchPendingEvents(now time.Time) error
+}
+
+type subscriber struct {
+	name    string
+	handler Handler
+}
+
+// Dispatcher delivers the events of the outbox to the in-process subscribers.
+type Dispatcher struct {
+	UnitOfWorkApp application.UnitOfWorkApplicationInterface
+
+	mu          sync.RWMutex
+	subscribers map[entity.EventType][]subscriber
+}
+
+// Ensure that Dispatcher implements DispatcherInterface.
+var _ DispatcherInterface = &Dispatcher{}
+
+// NewDispatcher creates and returns a new instance of Dispatcher.
+func NewDispatcher(unitOfWorkApp application.UnitOfWorkApplicationInterface) *Dispatcher {
+	return &Dispatcher{
+		UnitOfWorkApp: unitOfWorkApp,
+		subscribers:   make(map[entity.EventType][]subscriber),
+	}
+}
+
+// Subscribe registers a handler for the events of a type. The name identifies the subscriber in the
+// logs and in the events it handled, so it must not change once events are recorded with it.
+func (d *Dispatcher) Subscribe(eventType entity.EventType, name string, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.subscribers[eventType] = append(d.subscribers[eventType], subscriber{name: name, handler: handler})
+}
+
+// Start delivers the pending events every interval until the process exits.
+func (d *Dispatcher) Start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if err := d.DispatchPendingEvents(now); err != nil {
+			log.Println("Error dispatching events: ", err)
+		}
+	}
+}
+
+// DispatchPendingEvents delivers the due events to their subscribers. The events of an aggregate
+// are delivered in the order they were emitted, so an event waiting for a retry holds back the
+// later ones of its aggregate. The events are taken in rounds until none is due.
+func (d *Dispatcher) DispatchPendingEvents(now time.Time) error {
+	for {
+		count, err := d.dispatchDueEvents(now)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+	}
+}
+
+// dispatchDueEvents delivers a round of due events, locked while they're delivered so several
+// dispatchers don't deliver the same event. It returns the number of events delivered.
+func (d *Dispatcher) dispatchDueEvents(now time.Time) (int, error) {
+	var count int
+	err := d.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		events, err := tx.OutboxEvent().GetAllDueOutboxEvents(now, batchSize)
+		if err != nil {
+			return err
+		}
+		count = len(events)
+
+		for _, event := range events {
+			event := event
+
+			event.Attempts++
+
+			if err := d.deliver(&event); err != nil {
+				event.LastError = err.Error()
+				event.NextAttemptAt = now.Add(backoff(event.Attempts))
+
+				if event.Attempts >= maxAttempts {
+					event.Status = entity.OutboxEventFailedStatus
+				}
+
+				log.Printf("Error dispatching event %d %s (attempt %d): %v", event.ID, event.Type, event.Attempts, err)
+			} else {
+				dispatchedAt := time.Now()
+				event.Status = entity.OutboxEventDispatchedStatus
+				event.LastError = ""
+				event.DispatchedAt = &dispatchedAt
+			}
+
+			if _, err := tx.OutboxEvent().UpdateOutboxEventByID(event.ID, &event); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+// deliver hands an event to the subscribers that haven't handled it yet, even when one of them
+// fails. The subscribers that handle it are recorded in the event.
+func (d *Dispatcher) deliver(event *entity.OutboxEvent) error {
+	d.mu.RLock()
+	subscribers := d.subscribers[event.Type]
+	d.mu.RUnlock()
+
+	var failed []string
+	for _, subscriber := range subscribers {
+		if event.IsHandledBy(subscriber.name) {
+			continue
+		}
+
+		if err := subscriber.handler(event); err != nil {
+			log.Printf("Error handling event %d in %s: %v", event.ID, subscriber.name, err)
+			failed = append(failed, subscriber.name)
+			continue
+		}
+
+		if err := event.MarkHandledBy(subscriber.name); err != nil {
+			return err
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("subscribers failed: %v", failed)
+	}
+
+	return nil
+}
+
+// backoff returns the delay before the next attempt of an event that failed the given number of
+// times, doubling from 30 seconds up to an hour.
+func backoff(attempts int) time.Duration {
+	delay := 30 * time.Second
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
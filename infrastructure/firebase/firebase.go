@@ -8,11 +8,19 @@ import (
 	"google.golang.org/api/option"
 )
 
+// FirebaseServiceInterface defines the methods that a firebase service should implement.
+type FirebaseServiceInterface interface {
+	SendNotification(tokens []string, data map[string]string) error
+}
+
 // FirebaseService struct
 type FirebaseService struct {
 	FirebaseApp *firebase.App
 }
 
+// Ensure that FirebaseService implements FirebaseServiceInterface.
+var _ FirebaseServiceInterface = &FirebaseService{}
+
 // NewFirebaseService creates a new instance of FirebaseService
 func NewFirebaseService(filename string) (*FirebaseService, error) {
 	opt := option.WithCredentialsFile(filename)
@@ -0,0 +1,141 @@
+package geocoding
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/datatypes"
+)
+
+// datasetFeature is a region, a city or a district of the dataset, as a GeoJSON feature. Its
+// geometry is either its boundary or its centroid.
+type datasetFeature struct {
+	Type     string `json:"type"`
+	Geometry *struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+	Properties struct {
+		Level     entity.GeoPlaceType `json:"level"`
+		ID        uint64              `json:"id"`
+		RegionID  uint64              `json:"region_id"`
+		CityID    uint64              `json:"city_id"`
+		NameAr    string              `json:"name_ar"`
+		NameEn    string              `json:"name_en"`
+		Latitude  float64             `json:"latitude"`
+		Longitude float64             `json:"longitude"`
+	} `json:"properties"`
+}
+
+// datasetLevels is the order the features are imported in, so a city is saved after its region
+// and a district after its city
+var datasetLevels = map[entity.GeoPlaceType]int{
+	entity.RegionGeoPlaceType:   0,
+	entity.CityGeoPlaceType:     1,
+	entity.DistrictGeoPlaceType: 2,
+}
+
+// ImportDataset imports the regions, cities and districts of a GeoJSON feature collection, replacing
+// the ones with the same IDs, then fills the cities of the locations and the orders placed before.
+func (s *LocalGeocodingService) ImportDataset(reader io.Reader) error {
+	var collection struct {
+		Features []datasetFeature `json:"features"`
+	}
+	if err := json.NewDecoder(reader).Decode(&collection); err != nil {
+		return err
+	}
+
+	for i, feature := range collection.Features {
+		if _, ok := datasetLevels[feature.Properties.Level]; !ok {
+			return fmt.Errorf("feature %d: unknown level %q", i, feature.Properties.Level)
+		}
+		if feature.Properties.ID == 0 || feature.Properties.NameAr == "" || feature.Properties.NameEn == "" {
+			return fmt.Errorf("feature %d: the id, name_ar and name_en properties are required", i)
+		}
+	}
+
+	sort.SliceStable(collection.Features, func(i, j int) bool {
+		return datasetLevels[collection.Features[i].Properties.Level] < datasetLevels[collection.Features[j].Properties.Level]
+	})
+
+	for _, feature := range collection.Features {
+		latitude, longitude, boundary, err := feature.location()
+		if err != nil {
+			return fmt.Errorf("%s %d: %w", feature.Properties.Level, feature.Properties.ID, err)
+		}
+
+		switch feature.Properties.Level {
+		case entity.RegionGeoPlaceType:
+			err = s.GeocodingApp.SaveRegion(&entity.Region{
+				ID:        feature.Properties.ID,
+				NameAr:    feature.Properties.NameAr,
+				NameEn:    feature.Properties.NameEn,
+				Latitude:  latitude,
+				Longitude: longitude,
+				Boundary:  boundary,
+			})
+		case entity.CityGeoPlaceType:
+			err = s.GeocodingApp.SaveCity(&entity.City{
+				ID:        feature.Properties.ID,
+				RegionID:  feature.Properties.RegionID,
+				NameAr:    feature.Properties.NameAr,
+				NameEn:    feature.Properties.NameEn,
+				Latitude:  latitude,
+				Longitude: longitude,
+				Boundary:  boundary,
+			})
+		case entity.DistrictGeoPlaceType:
+			err = s.GeocodingApp.SaveDistrict(&entity.District{
+				ID:        feature.Properties.ID,
+				CityID:    feature.Properties.CityID,
+				RegionID:  feature.Properties.RegionID,
+				NameAr:    feature.Properties.NameAr,
+				NameEn:    feature.Properties.NameEn,
+				Latitude:  latitude,
+				Longitude: longitude,
+				Boundary:  boundary,
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("%s %d: %w", feature.Properties.Level, feature.Properties.ID, err)
+		}
+	}
+
+	if err := s.GeocodingApp.FillLocationCities(); err != nil {
+		return err
+	}
+
+	return s.GeocodingApp.FillOrderCities()
+}
+
+// location returns the centroid and the boundary of a feature. The centroid given in its properties
+// wins over a point geometry, and is derived from the boundary when neither is given.
+func (f *datasetFeature) location() (float64, float64, datatypes.JSON, error) {
+	latitude, longitude := f.Properties.Latitude, f.Properties.Longitude
+	if f.Geometry == nil {
+		return latitude, longitude, nil, nil
+	}
+
+	switch f.Geometry.Type {
+	case "Polygon", "MultiPolygon":
+		boundary, err := json.Marshal(f.Geometry)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		return latitude, longitude, datatypes.JSON(boundary), nil
+	case "Point":
+		var point []float64
+		if err := json.Unmarshal(f.Geometry.Coordinates, &point); err != nil || len(point) < 2 {
+			return 0, 0, nil, fmt.Errorf("invalid point")
+		}
+		if latitude == 0 && longitude == 0 {
+			longitude, latitude = point[0], point[1]
+		}
+		return latitude, longitude, nil, nil
+	default:
+		return 0, 0, nil, fmt.Errorf("unsupported geometry %q", f.Geometry.Type)
+	}
+}
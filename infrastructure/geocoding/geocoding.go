@@ -0,0 +1,109 @@
+package geocoding
+
+import (
+	"errors"
+	"io"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+)
+
+// GeocodingServiceInterface defines the methods that a geocoding service should implement.
+type GeocodingServiceInterface interface {
+	Search(query string, limit int) ([]entity.GeoPlace, error)
+	Reverse(latitude float64, longitude float64) (*Locality, error)
+	ImportDataset(reader io.Reader) error
+	ImportHierarchy(reader io.Reader, format string) error
+}
+
+// Locality is the district, city and region of a point. Each of them is nil when the dataset
+// doesn't cover the point at that level.
+type Locality struct {
+	Region   *entity.Region
+	City     *entity.City
+	District *entity.District
+}
+
+// CityID returns the ID of the city of the locality, 0 when it's unknown.
+func (l *Locality) CityID() uint64 {
+	if l.City == nil {
+		return 0
+	}
+	return l.City.ID
+}
+
+// RegionID returns the ID of the region of the locality, 0 when it's unknown.
+func (l *Locality) RegionID() uint64 {
+	if l.Region == nil {
+		return 0
+	}
+	return l.Region.ID
+}
+
+// LocalGeocodingService geocodes with the regions, cities and districts imported in the database,
+// without calling any external provider.
+type LocalGeocodingService struct {
+	GeocodingApp application.GeocodingApplicationInterface
+	CityApp      application.CityApplicationInterface
+	DistrictApp  application.DistrictApplicationInterface
+}
+
+// Ensure that LocalGeocodingService implements GeocodingServiceInterface.
+var _ GeocodingServiceInterface = &LocalGeocodingService{}
+
+// NewLocalGeocodingService creates and returns a new instance of LocalGeocodingService.
+func NewLocalGeocodingService(geocodingApp application.GeocodingApplicationInterface, cityApp application.CityApplicationInterface, districtApp application.DistrictApplicationInterface) *LocalGeocodingService {
+	return &LocalGeocodingService{
+		GeocodingApp: geocodingApp,
+		CityApp:      cityApp,
+		DistrictApp:  districtApp,
+	}
+}
+
+// Search returns the regions, cities and districts whose Arabic or English name contains the query.
+func (s *LocalGeocodingService) Search(query string, limit int) ([]entity.GeoPlace, error) {
+	return s.GeocodingApp.SearchGeoPlaces(query, limit)
+}
+
+// Reverse returns the district, city and region covering a point. The city and the region of a
+// district are the ones it belongs to, the boundaries are only looked at for the missing levels.
+func (s *LocalGeocodingService) Reverse(latitude float64, longitude float64) (*Locality, error) {
+	var locality Locality
+
+	district, err := s.GeocodingApp.GetDistrictByCoordinates(longitude, latitude)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		locality.District = district
+	}
+
+	var city *entity.City
+	if locality.District != nil {
+		city, err = s.CityApp.GetCityByID(locality.District.CityID)
+	} else {
+		city, err = s.GeocodingApp.GetCityByCoordinates(longitude, latitude)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		locality.City = city
+	}
+
+	var region *entity.Region
+	if locality.City != nil {
+		region, err = s.GeocodingApp.GetRegionByID(locality.City.RegionID)
+	} else {
+		region, err = s.GeocodingApp.GetRegionByCoordinates(longitude, latitude)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		locality.Region = region
+	}
+
+	return &locality, nil
+}
@@ -0,0 +1,236 @@
+package geocoding
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+)
+
+// The formats of the files the location hierarchy is imported from
+const (
+	JSONHierarchyFormat = "json"
+	CSVHierarchyFormat  = "csv"
+)
+
+// hierarchyColumns are the columns of a CSV hierarchy file, in the order of its header
+var hierarchyColumns = []string{"level", "id", "region_id", "city_id", "district_id", "name_ar", "name_en", "latitude", "longitude"}
+
+// hierarchyLevels is the order the rows are imported in, so every row is saved after its parents
+var hierarchyLevels = map[string]int{
+	"region":   0,
+	"city":     1,
+	"district": 2,
+	"location": 3,
+}
+
+// hierarchyRow is a region, a city, a district or a location of a hierarchy file. A city names
+// its region, a district its city and a location its city or its district; the levels above the
+// parent are derived from it.
+type hierarchyRow struct {
+	Level      string  `json:"level"`
+	ID         uint64  `json:"id"`
+	RegionID   uint64  `json:"region_id"`
+	CityID     uint64  `json:"city_id"`
+	DistrictID uint64  `json:"district_id"`
+	NameAr     string  `json:"name_ar"`
+	NameEn     string  `json:"name_en"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+}
+
+// ImportHierarchy imports the regions, cities, districts and locations of a JSON array or a CSV
+// file, replacing the ones with the same IDs, then fills the cities of the locations and the orders
+// placed before. The parent of every row must be in the file or already imported.
+func (s *LocalGeocodingService) ImportHierarchy(reader io.Reader, format string) error {
+	var rows []hierarchyRow
+	var err error
+
+	switch format {
+	case JSONHierarchyFormat:
+		err = json.NewDecoder(reader).Decode(&rows)
+	case CSVHierarchyFormat:
+		rows, err = readHierarchyCSV(reader)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	for i, row := range rows {
+		if _, ok := hierarchyLevels[row.Level]; !ok {
+			return fmt.Errorf("row %d: unknown level %q", i+1, row.Level)
+		}
+		if row.ID == 0 || row.NameAr == "" || row.NameEn == "" {
+			return fmt.Errorf("row %d: the id, name_ar and name_en columns are required", i+1)
+		}
+		if row.Level == "location" && (row.Latitude == 0 || row.Longitude == 0) {
+			return fmt.Errorf("row %d: the latitude and longitude of a location are required", i+1)
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return hierarchyLevels[rows[i].Level] < hierarchyLevels[rows[j].Level]
+	})
+
+	// The regions of the cities and the cities of the districts imported so far
+	cityRegions := make(map[uint64]uint64)
+	districtCities := make(map[uint64]uint64)
+	importedRegions := make(map[uint64]bool)
+
+	for _, row := range rows {
+		switch row.Level {
+		case "region":
+			err = s.GeocodingApp.SaveRegion(&entity.Region{
+				ID:        row.ID,
+				NameAr:    row.NameAr,
+				NameEn:    row.NameEn,
+				Latitude:  row.Latitude,
+				Longitude: row.Longitude,
+			})
+			importedRegions[row.ID] = true
+		case "city":
+			if !importedRegions[row.RegionID] {
+				if err = checkParent(s.GeocodingApp.GetRegionByID(row.RegionID)); err != nil {
+					break
+				}
+			}
+
+			err = s.GeocodingApp.SaveCity(&entity.City{
+				ID:        row.ID,
+				RegionID:  row.RegionID,
+				NameAr:    row.NameAr,
+				NameEn:    row.NameEn,
+				Latitude:  row.Latitude,
+				Longitude: row.Longitude,
+			})
+			cityRegions[row.ID] = row.RegionID
+		case "district":
+			regionID, ok := cityRegions[row.CityID]
+			if !ok {
+				city, cityErr := s.CityApp.GetCityByID(row.CityID)
+				if err = checkParent(city, cityErr); err != nil {
+					break
+				}
+				regionID = city.RegionID
+			}
+
+			err = s.GeocodingApp.SaveDistrict(&entity.District{
+				ID:        row.ID,
+				CityID:    row.CityID,
+				RegionID:  regionID,
+				NameAr:    row.NameAr,
+				NameEn:    row.NameEn,
+				Latitude:  row.Latitude,
+				Longitude: row.Longitude,
+			})
+			districtCities[row.ID] = row.CityID
+		case "location":
+			cityID := row.CityID
+			if row.DistrictID != 0 {
+				var ok bool
+				if cityID, ok = districtCities[row.DistrictID]; !ok {
+					district, districtErr := s.DistrictApp.GetDistrictByID(row.DistrictID)
+					if err = checkParent(district, districtErr); err != nil {
+						break
+					}
+					cityID = district.CityID
+				}
+			} else if _, ok := cityRegions[cityID]; !ok && cityID != 0 {
+				if err = checkParent(s.CityApp.GetCityByID(cityID)); err != nil {
+					break
+				}
+			}
+
+			name, _ := json.Marshal(map[string]string{"en": row.NameEn, "ar": row.NameAr})
+			err = s.GeocodingApp.SaveLocation(&entity.Location{
+				ID:         row.ID,
+				Name:       string(name),
+				Latitude:   row.Latitude,
+				Longitude:  row.Longitude,
+				CityID:     cityID,
+				DistrictID: row.DistrictID,
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("%s %d: %w", row.Level, row.ID, err)
+		}
+	}
+
+	if err := s.GeocodingApp.FillLocationCities(); err != nil {
+		return err
+	}
+
+	return s.GeocodingApp.FillOrderCities()
+}
+
+// checkParent turns the lookup of the parent of a row into the error reported for the row
+func checkParent(_ interface{}, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New("parent not found")
+	}
+	return err
+}
+
+// readHierarchyCSV reads the rows of a CSV hierarchy file, whose header names the columns
+func readHierarchyCSV(reader io.Reader) ([]hierarchyRow, error) {
+	records, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("the file is empty")
+	}
+
+	columns := make(map[string]int)
+	for i, column := range records[0] {
+		columns[strings.TrimSpace(column)] = i
+	}
+	for _, column := range hierarchyColumns {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("missing column %q", column)
+		}
+	}
+
+	rows := make([]hierarchyRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		value := func(column string) string {
+			return strings.TrimSpace(record[columns[column]])
+		}
+
+		var row hierarchyRow
+		row.Level = value("level")
+		row.NameAr = value("name_ar")
+		row.NameEn = value("name_en")
+
+		for column, id := range map[string]*uint64{"id": &row.ID, "region_id": &row.RegionID, "city_id": &row.CityID, "district_id": &row.DistrictID} {
+			if value(column) == "" {
+				continue
+			}
+			if *id, err = strconv.ParseUint(value(column), 10, 64); err != nil {
+				return nil, fmt.Errorf("row %d: invalid %s", i+1, column)
+			}
+		}
+
+		for column, coordinate := range map[string]*float64{"latitude": &row.Latitude, "longitude": &row.Longitude} {
+			if value(column) == "" {
+				continue
+			}
+			if *coordinate, err = strconv.ParseFloat(value(column), 64); err != nil {
+				return nil, fmt.Errorf("row %d: invalid %s", i+1, column)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,130 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/pkg/arabic"
+	"github.com/OmarBader7/web-service-jayeek/pkg/zatca"
+	"github.com/boombuler/barcode/qr"
+	"github.com/go-pdf/fpdf"
+	"github.com/go-pdf/fpdf/contrib/barcode"
+)
+
+// InvoiceServiceInterface defines the methods that an invoice service should implement.
+type InvoiceServiceInterface interface {
+	GeneratePDF(invoice *entity.Invoice, order *entity.Order, seller Seller) ([]byte, error)
+}
+
+// InvoiceService renders invoices as bilingual Arabic/English PDF documents.
+type InvoiceService struct {
+	FontPath string
+}
+
+// Seller represents the business details printed on an invoice.
+type Seller struct {
+	Name      string
+	VATNumber string
+	Address   string
+}
+
+// Ensure that InvoiceService implements InvoiceServiceInterface.
+var _ InvoiceServiceInterface = &InvoiceService{}
+
+// NewInvoiceService creates and returns a new instance of InvoiceService.
+// The font must contain Arabic glyphs, including the Arabic presentation forms.
+func NewInvoiceService(fontPath string) *InvoiceService {
+	return &InvoiceService{
+		FontPath: fontPath,
+	}
+}
+
+const (
+	pageWidth   = 210.0
+	margin      = 15.0
+	columnWidth = (pageWidth - 2*margin) / 3
+	rowHeight   = 8.0
+)
+
+// GeneratePDF renders the invoice of an order, including the QR code required for simplified tax invoices.
+func (s *InvoiceService) GeneratePDF(invoice *entity.Invoice, order *entity.Order, seller Seller) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.AddUTF8Font("DejaVu", "", s.FontPath)
+	pdf.AddPage()
+
+	// Title
+	pdf.SetFont("DejaVu", "", 18)
+	pdf.CellFormat(columnWidth*1.5, 12, "Tax Invoice", "", 0, "L", false, 0, "")
+	pdf.CellFormat(columnWidth*1.5, 12, arabic.Shape("فاتورة ضريبية"), "", 1, "R", false, 0, "")
+	pdf.Ln(4)
+
+	pdf.SetFont("DejaVu", "", 10)
+	row(pdf, "Invoice number", "رقم الفاتورة", invoice.Number)
+	row(pdf, "Issue date", "تاريخ الإصدار", invoice.IssuedAt.Format("2006-01-02 15:04"))
+	row(pdf, "Order number", "رقم الطلب", fmt.Sprintf("%d", order.ID))
+
+	section(pdf, "Seller", "البائع")
+	row(pdf, "Name", "الاسم", seller.Name)
+	row(pdf, "VAT number", "الرقم الضريبي", seller.VATNumber)
+	row(pdf, "Address", "العنوان", seller.Address)
+
+	section(pdf, "Buyer", "المشتري")
+	row(pdf, "Name", "الاسم", order.User.Name)
+	row(pdf, "Phone", "الجوال", order.User.Phone)
+
+	section(pdf, "Driver", "السائق")
+	row(pdf, "Name", "الاسم", order.Driver.User.Name)
+	row(pdf, "Vehicle", "المركبة", order.Driver.Car)
+
+	section(pdf, "Amount", "المبلغ")
+	row(pdf, "Fare", "أجرة التوصيل", amount(invoice.Fare, invoice.Currency))
+	row(pdf, "Extra services", "الخدمات الإضافية", amount(invoice.Extras, invoice.Currency))
+	if invoice.Discount > 0 {
+		row(pdf, "Discount", "الخصم", amount(-invoice.Discount, invoice.Currency))
+	}
+	row(pdf, "Subtotal (excl. VAT)", "المجموع غير شامل الضريبة", amount(invoice.Subtotal, invoice.Currency))
+	row(pdf, fmt.Sprintf("Platform commission (%g%%, included)", invoice.CommissionRate), "عمولة المنصة (مشمولة)", amount(invoice.Commission, invoice.Currency))
+	row(pdf, fmt.Sprintf("VAT (%g%%)", invoice.VATRate), "ضريبة القيمة المضافة", amount(invoice.VAT, invoice.Currency))
+
+	pdf.SetFont("DejaVu", "", 12)
+	row(pdf, "Total (incl. VAT)", "الإجمالي شامل الضريبة", amount(invoice.Total, invoice.Currency))
+
+	// QR code with the ZATCA TLV payload
+	payload := zatca.EncodeQR(seller.Name, seller.VATNumber, invoice.IssuedAt, invoice.Total, invoice.VAT)
+	key := barcode.RegisterQR(pdf, payload, qr.M, qr.Unicode)
+	pdf.Ln(6)
+	barcode.Barcode(pdf, key, pageWidth-margin-40, pdf.GetY(), 40, 40, false)
+
+	if err := pdf.Error(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// section prints a bilingual section heading.
+func section(pdf *fpdf.Fpdf, label string, arabicLabel string) {
+	pdf.Ln(4)
+	pdf.SetFillColor(235, 235, 235)
+	pdf.CellFormat(columnWidth*1.5, rowHeight, label, "", 0, "L", true, 0, "")
+	pdf.CellFormat(columnWidth*1.5, rowHeight, arabic.Shape(arabicLabel), "", 1, "R", true, 0, "")
+}
+
+// row prints a value between its English and Arabic labels.
+func row(pdf *fpdf.Fpdf, label string, arabicLabel string, value string) {
+	pdf.CellFormat(columnWidth, rowHeight, label, "B", 0, "L", false, 0, "")
+	pdf.CellFormat(columnWidth, rowHeight, arabic.Shape(value), "B", 0, "C", false, 0, "")
+	pdf.CellFormat(columnWidth, rowHeight, arabic.Shape(arabicLabel), "B", 1, "R", false, 0, "")
+}
+
+func amount(value float64, currency string) string {
+	return fmt.Sprintf("%.2f %s", value, currency)
+}
@@ -0,0 +1,145 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/pkg/arabic"
+	"github.com/boombuler/barcode/qr"
+	"github.com/go-pdf/fpdf"
+	"github.com/go-pdf/fpdf/contrib/barcode"
+)
+
+// LabelServiceInterface defines the methods that a label service should implement.
+type LabelServiceInterface interface {
+	GeneratePDF(order *entity.Order) ([]byte, error)
+}
+
+// LabelService renders the parcel labels as bilingual Arabic/English PDF documents.
+type LabelService struct {
+	FontPath string
+}
+
+// Ensure that LabelService implements LabelServiceInterface.
+var _ LabelServiceInterface = &LabelService{}
+
+// NewLabelService creates and returns a new instance of LabelService.
+// The font must contain Arabic glyphs, including the Arabic presentation forms.
+func NewLabelService(fontPath string) *LabelService {
+	return &LabelService{
+		FontPath: fontPath,
+	}
+}
+
+// The labels are printed on 4x6 inch thermal label stock
+const (
+	pageWidth   = 100.0
+	pageHeight  = 150.0
+	margin      = 5.0
+	columnWidth = (pageWidth - 2*margin) / 3
+	rowHeight   = 6.0
+)
+
+// GeneratePDF renders the label of an order, with its tracking number as a Code128 barcode for the
+// handheld scanners and as a QR code for the phones.
+func (s *LabelService) GeneratePDF(order *entity.Order) ([]byte, error) {
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           fpdf.SizeType{Wd: pageWidth, Ht: pageHeight},
+	})
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(false, margin)
+	pdf.AddUTF8Font("DejaVu", "", s.FontPath)
+	pdf.AddPage()
+
+	// Tracking number, as a barcode and in clear text
+	key := barcode.RegisterCode128(pdf, order.TrackingNumber)
+	barcode.Barcode(pdf, key, margin, margin, pageWidth-2*margin, 20, false)
+	pdf.SetY(margin + 21)
+	pdf.SetFont("DejaVu", "", 14)
+	pdf.CellFormat(pageWidth-2*margin, 8, order.TrackingNumber, "", 1, "C", false, 0, "")
+
+	pdf.SetFont("DejaVu", "", 9)
+	row(pdf, "Order number", "رقم الطلب", fmt.Sprintf("%d", order.ID))
+	row(pdf, "Date", "التاريخ", order.CreatedAt.Format("2006-01-02"))
+
+	section(pdf, "From", "المرسل")
+	row(pdf, "Name", "الاسم", order.User.Name)
+	row(pdf, "City", "المدينة", cityName(order.City))
+
+	section(pdf, "To", "المستلم")
+	row(pdf, "Name", "الاسم", recipientName(order))
+	row(pdf, "City", "المدينة", cityName(order.DestinationCity))
+
+	section(pdf, "Shipment", "الشحنة")
+	row(pdf, "Category", "الفئة", categoryName(order.Category))
+	if order.Size.ID != 0 {
+		row(pdf, "Size", "الحجم", sizeName(order.Size))
+	}
+	row(pdf, "Quantity", "الكمية", fmt.Sprintf("%d", order.Quantity))
+
+	// QR code with the tracking number
+	key = barcode.RegisterQR(pdf, order.TrackingNumber, qr.M, qr.Unicode)
+	barcode.Barcode(pdf, key, (pageWidth-30)/2, pageHeight-margin-30, 30, 30, false)
+
+	if err := pdf.Error(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// section prints a bilingual section heading.
+func section(pdf *fpdf.Fpdf, label string, arabicLabel string) {
+	pdf.Ln(2)
+	pdf.SetFillColor(235, 235, 235)
+	pdf.CellFormat(columnWidth*1.5, rowHeight, label, "", 0, "L", true, 0, "")
+	pdf.CellFormat(columnWidth*1.5, rowHeight, arabic.Shape(arabicLabel), "", 1, "R", true, 0, "")
+}
+
+// row prints a value between its English and Arabic labels.
+func row(pdf *fpdf.Fpdf, label string, arabicLabel string, value string) {
+	pdf.CellFormat(columnWidth*0.75, rowHeight, label, "B", 0, "L", false, 0, "")
+	pdf.CellFormat(columnWidth*1.5, rowHeight, arabic.Shape(value), "B", 0, "C", false, 0, "")
+	pdf.CellFormat(columnWidth*0.75, rowHeight, arabic.Shape(arabicLabel), "B", 1, "R", false, 0, "")
+}
+
+// recipientName returns the name of the recipient, or their phone number when they aren't registered.
+func recipientName(order *entity.Order) string {
+	if order.Recipient.ID != 0 && order.Recipient.Name != "" {
+		return order.Recipient.Name
+	}
+	return order.RecipientPhoneNumber
+}
+
+func cityName(city entity.City) string {
+	if city.ID == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%s - %s", city.NameEn, city.NameAr)
+}
+
+func categoryName(category entity.Category) string {
+	english, _ := category.PublicData("en").(*entity.CategoryPublicData)
+	arabicName, _ := category.PublicData("ar").(*entity.CategoryPublicData)
+	if english == nil || arabicName == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s - %s", english.Name, arabicName.Name)
+}
+
+func sizeName(size entity.Size) string {
+	english, _ := size.PublicData("en").(*entity.SizePublicData)
+	arabicName, _ := size.PublicData("ar").(*entity.SizePublicData)
+	if english == nil || arabicName == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s - %s", english.Name, arabicName.Name)
+}
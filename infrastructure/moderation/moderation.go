@@ -0,0 +1,207 @@
+package moderation
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+)
+
+// The reasons a chat message is flagged for.
+const (
+	PhoneReason   = "phone"
+	EmailReason   = "email"
+	AbusiveReason = "abusive"
+)
+
+// phonePattern matches the phone numbers written with a country code, with the Saudi country code
+// or trunk prefix, and the Saudi mobile numbers without a prefix. Their digits may be separated by
+// spaces, dots, dashes or parentheses, as people write them in chats to get around simple filters.
+var phonePattern = regexp.MustCompile(`(?:(?:\+|00)\s*)?\(?966(?:[\s.\-()]*\d){9}|(?:\+|00)\s*\d(?:[\s.\-()]*\d){7,13}|\(?0(?:[\s.\-()]*\d){9}|5(?:[\s.\-()]*\d){8}`)
+
+// trackingNumberPattern matches the tracking numbers of the orders, which the parties share in chats
+// and aren't phone numbers even when their digits look like one
+var trackingNumberPattern = regexp.MustCompile(`(?i)JY[0-9A-F]{10}`)
+
+var emailPattern = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+\s*(?:@|\(at\)|\[at\])\s*[a-z0-9.\-]+\s*(?:\.|\(dot\)|\[dot\])\s*[a-z]{2,}`)
+
+// ModerationServiceInterface defines the methods that a moderation service should implement.
+type ModerationServiceInterface interface {
+	Moderate(text string) (*Result, error)
+}
+
+// ModerationService masks the contact details shared in chat messages and flags abusive words.
+type ModerationService struct {
+	SettingApp application.SettingApplicationInterface
+}
+
+// Result represents the outcome of moderating a message.
+type Result struct {
+	// Text is the message with the phone numbers and emails masked
+	Text    string
+	Reasons []string
+	// Words are the abusive words found in the message
+	Words []string
+}
+
+// Ensure that ModerationService implements ModerationServiceInterface.
+var _ ModerationServiceInterface = &ModerationService{}
+
+// NewModerationService creates and returns a new instance of ModerationService.
+func NewModerationService(settingApp application.SettingApplicationInterface) *ModerationService {
+	return &ModerationService{
+		SettingApp: settingApp,
+	}
+}
+
+// Flagged reports whether the message should be reviewed by an admin.
+func (r *Result) Flagged() bool {
+	return len(r.Reasons) > 0
+}
+
+// Moderate masks the phone numbers and emails of a message and looks for the abusive words of the
+// chat_blocked_words setting, a comma separated list of Arabic and English words.
+func (s *ModerationService) Moderate(text string) (*Result, error) {
+	blockedWords, err := s.SettingApp.GetSettingByKey("chat_blocked_words")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Text: text}
+
+	// Arabic-Indic digits are matched like Latin ones
+	normalized := []rune(normalizeDigits(text))
+	masked := []rune(text)
+
+	for _, pattern := range []struct {
+		reason string
+		find   func(text string) [][]int
+	}{
+		{EmailReason, func(text string) [][]int { return emailPattern.FindAllStringIndex(text, -1) }},
+		{PhoneReason, findPhoneNumbers},
+	} {
+		matches := pattern.find(string(normalized))
+		if len(matches) == 0 {
+			continue
+		}
+
+		for _, match := range matches {
+			// The normalized text has the same runes as the original, only the byte offsets differ
+			start := len([]rune(string(normalized)[:match[0]]))
+			end := len([]rune(string(normalized)[:match[1]]))
+			for i := start; i < end; i++ {
+				if !unicode.IsSpace(masked[i]) {
+					masked[i] = '*'
+					normalized[i] = '*'
+				}
+			}
+		}
+
+		result.Reasons = append(result.Reasons, pattern.reason)
+	}
+
+	result.Text = string(masked)
+
+	if words := findWords(text, blockedWords); len(words) > 0 {
+		result.Reasons = append(result.Reasons, AbusiveReason)
+		result.Words = words
+	}
+
+	return result, nil
+}
+
+// findPhoneNumbers returns the positions of the phone numbers of the text. The numbers that are part
+// of a longer run of letters and digits, such as tracking numbers, are left out.
+func findPhoneNumbers(text string) [][]int {
+	trackingNumbers := trackingNumberPattern.FindAllStringIndex(text, -1)
+
+	var found [][]int
+	for _, match := range phonePattern.FindAllStringIndex(text, -1) {
+		before, _ := utf8.DecodeLastRuneInString(text[:match[0]])
+		after, _ := utf8.DecodeRuneInString(text[match[1]:])
+		if isWordRune(before) || isWordRune(after) {
+			continue
+		}
+
+		inTrackingNumber := false
+		for _, trackingNumber := range trackingNumbers {
+			if match[0] < trackingNumber[1] && trackingNumber[0] < match[1] {
+				inTrackingNumber = true
+			}
+		}
+		if !inTrackingNumber {
+			found = append(found, match)
+		}
+	}
+
+	return found
+}
+
+// isWordRune reports whether the rune is a letter or a digit
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// findWords returns the words of the comma separated list that appear in the text, ignoring case
+// and Arabic diacritics and letter variants.
+func findWords(text string, list string) []string {
+	blocked := make(map[string]string)
+	for _, word := range strings.Split(list, ",") {
+		if word = strings.TrimSpace(word); word != "" {
+			blocked[normalizeWord(word)] = word
+		}
+	}
+	if len(blocked) == 0 {
+		return nil
+	}
+
+	var found []string
+	seen := make(map[string]bool)
+
+	tokens := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r) && r != 'ـ'
+	})
+	for _, token := range tokens {
+		if word, ok := blocked[normalizeWord(token)]; ok && !seen[word] {
+			seen[word] = true
+			found = append(found, word)
+		}
+	}
+
+	return found
+}
+
+// normalizeWord lowercases a word and removes the Arabic diacritics and tatweel, unifying the
+// alef, yeh and teh marbuta variants people use interchangeably.
+func normalizeWord(word string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(word) {
+		switch {
+		case r >= 'ً' && r <= 'ٟ', r == 'ٰ', r == 'ـ':
+			continue
+		case r == 'أ', r == 'إ', r == 'آ':
+			r = 'ا'
+		case r == 'ى':
+			r = 'ي'
+		case r == 'ة':
+			r = 'ه'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// normalizeDigits replaces the Arabic-Indic and Persian digits with their Latin equivalents.
+func normalizeDigits(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '٠' && r <= '٩':
+			return '0' + (r - '٠')
+		case r >= '۰' && r <= '۹':
+			return '0' + (r - '۰')
+		}
+		return r
+	}, text)
+}
@@ -0,0 +1,63 @@
+package moderation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+)
+
+// fakeSettingApp returns the blocked words of the chat
+type fakeSettingApp struct {
+	application.SettingApplicationInterface
+	blockedWords string
+}
+
+func (a *fakeSettingApp) GetSettingByKey(key string) (string, error) {
+	return a.blockedWords, nil
+}
+
+func TestModerate(t *testing.T) {
+	service := NewModerationService(&fakeSettingApp{blockedWords: "idiot,غبي"})
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		reasons []string
+	}{
+		{name: "plain message", text: "I'm at the gate", want: "I'm at the gate"},
+		{name: "mobile number", text: "call 0512345678 now", want: "call ********** now", reasons: []string{PhoneReason}},
+		{name: "mobile number without the trunk prefix", text: "call 512345678", want: "call *********", reasons: []string{PhoneReason}},
+		{name: "country code", text: "+966512345678", want: "*************", reasons: []string{PhoneReason}},
+		{name: "country code with 00", text: "00966 51 234 5678", want: "***** ** *** ****", reasons: []string{PhoneReason}},
+		{name: "separated digits", text: "(05) 123-456.78", want: "**** **********", reasons: []string{PhoneReason}},
+		{name: "foreign number", text: "+44 20 7946 0958", want: "*** ** **** ****", reasons: []string{PhoneReason}},
+		{name: "two numbers", text: "+966512345678 0598765432", want: "************* **********", reasons: []string{PhoneReason}},
+		{name: "arabic-indic digits", text: "٠٥١٢٣٤٥٦٧٨", want: "**********", reasons: []string{PhoneReason}},
+		{name: "tracking number", text: "JY0512345678 was delivered", want: "JY0512345678 was delivered"},
+		{name: "lowercase tracking number", text: "jy5123456789", want: "jy5123456789"},
+		{name: "order amount", text: "it's 150 riyals for 2 boxes", want: "it's 150 riyals for 2 boxes"},
+		{name: "long run of digits", text: "ref 12345678901234567", want: "ref 12345678901234567"},
+		{name: "too many digits", text: "05123456789", want: "05123456789"},
+		{name: "email", text: "mail me at a.b@example.com", want: "mail me at ***************", reasons: []string{EmailReason}},
+		{name: "abusive word", text: "you idiot", want: "you idiot", reasons: []string{AbusiveReason}},
+		{name: "everything", text: "غبي 0512345678 a@b.co", want: "غبي ********** ******", reasons: []string{EmailReason, PhoneReason, AbusiveReason}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.Moderate(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Text != tt.want {
+				t.Errorf("Moderate(%q) masked %q, want %q", tt.text, result.Text, tt.want)
+			}
+			if !reflect.DeepEqual(result.Reasons, tt.reasons) {
+				t.Errorf("Moderate(%q) flagged %v, want %v", tt.text, result.Reasons, tt.reasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package notification
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/firebase"
+	"gorm.io/gorm"
+)
+
+// NotificationServiceInterface defines the methods that a notification service should implement.
+type NotificationServiceInterface interface {
+	HandleOrderEvent(event *entity.OutboxEvent) error
+}
+
+// NotificationService pushes the order events to the devices of the users concerned.
+type NotificationService struct {
+	FirebaseService firebase.FirebaseServiceInterface
+	DeviceApp       application.DeviceApplicationInterface
+}
+
+// Ensure that NotificationService implements NotificationServiceInterface.
+var _ NotificationServiceInterface = &NotificationService{}
+
+// NewNotificationService creates and returns a new instance of NotificationService.
+func NewNotificationService(firebaseService firebase.FirebaseServiceInterface, deviceApp application.DeviceApplicationInterface) *NotificationService {
+	return &NotificationService{
+		FirebaseService: firebaseService,
+		DeviceApp:       deviceApp,
+	}
+}
+
+// HandleOrderEvent notifies the driver when their offer is accepted or the order is canceled, the
+// sender and the recipient when the order is delivered, and the sender and the driver when it's
+// returned.
+func (s *NotificationService) HandleOrderEvent(event *entity.OutboxEvent) error {
+	payload, err := event.OrderPayload()
+	if err != nil {
+		return err
+	}
+
+	var userIDs []uint64
+	switch event.Type {
+	case entity.OrderAcceptedEvent, entity.OrderCanceledEvent:
+		userIDs = append(userIDs, payload.DriverUserID)
+	case entity.OrderDeliveredEvent:
+		userIDs = append(userIDs, payload.UserID, payload.RecipientID)
+	case entity.OrderReturnedEvent:
+		userIDs = append(userIDs, payload.UserID, payload.DriverUserID)
+	}
+
+	var tokens []string
+	for _, userID := range userIDs {
+		if userID == 0 {
+			continue
+		}
+
+		device, err := s.DeviceApp.GetDeviceByUserID(userID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if device.IsActive {
+			tokens = append(tokens, device.FCMToken)
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	return s.FirebaseService.SendNotification(tokens, map[string]string{
+		"type":     string(event.Type),
+		"order_id": strconv.FormatUint(payload.OrderID, 10),
+		"status":   string(payload.Status),
+	})
+}
@@ -0,0 +1,118 @@
+package persistence
+
+import (
+	"errors"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AddressRepository implements the repository.AddressRepository interface
+type AddressRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewAddressRepository creates a new instance of the AddressRepository
+func NewAddressRepository(db *gorm.DB) *AddressRepository {
+	return &AddressRepository{db: db}
+}
+
+// CreateAddress creates a new address in the database. The first address of a user becomes their
+// default one, and a new default address replaces the previous one.
+func (r *AddressRepository) CreateAddress(address *entity.Address) (*entity.Address, error) {
+	err := r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		var addressesCount int64
+		if err := tx.Model(&entity.Address{}).Where("user_id = ?", address.UserID).Count(&addressesCount).Error; err != nil {
+			return err
+		}
+
+		if addressesCount == 0 {
+			address.IsDefault = true
+		}
+
+		if address.IsDefault {
+			if err := unsetDefaultAddress(tx, address.UserID); err != nil {
+				return err
+			}
+		}
+
+		return tx.Omit(clause.Associations).Create(address).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetAddressByIDAndUserID(address.ID, address.UserID)
+}
+
+// UpdateAddressByID updates the address. An address made the default one replaces the previous one.
+func (r *AddressRepository) UpdateAddressByID(id uint64, address *entity.Address) (*entity.Address, error) {
+	err := r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		if address.IsDefault {
+			if err := unsetDefaultAddress(tx, address.UserID); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&entity.Address{}).Where("id = ?", id).Select("location_id", "label", "latitude", "longitude", "address_line", "building", "floor", "notes", "is_default", "updated_at").Updates(address).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetAddressByIDAndUserID(id, address.UserID)
+}
+
+// DeleteAddressByID deletes the address. When it was the default one, the latest other address of
+// the user becomes the default.
+func (r *AddressRepository) DeleteAddressByID(id uint64) error {
+	return r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		var address entity.Address
+		if err := tx.Where("id = ?", id).Take(&address).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("id = ?", id).Delete(&entity.Address{}).Error; err != nil {
+			return err
+		}
+
+		if !address.IsDefault {
+			return nil
+		}
+
+		var latest entity.Address
+		if err := tx.Where("user_id = ?", address.UserID).Order("id desc").Take(&latest).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		return tx.Model(&entity.Address{}).Where("id = ?", latest.ID).Update("is_default", true).Error
+	})
+}
+
+// GetAddressByIDAndUserID retrieves an address of a user by its ID
+func (r *AddressRepository) GetAddressByIDAndUserID(id uint64, userID uint64) (*entity.Address, error) {
+	var address entity.Address
+	if err := r.db.Debug().Where("id = ?", id).Where("user_id = ?", userID).Preload("Location").Take(&address).Error; err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// GetAllAddressesByUserID retrieves the addresses of a user, the default one first
+func (r *AddressRepository) GetAllAddressesByUserID(userID uint64) ([]entity.Address, error) {
+	var addresses []entity.Address
+	if err := r.db.Debug().Where("user_id = ?", userID).Preload("Location").Order("is_default desc").Order("id desc").Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// unsetDefaultAddress clears the default address of a user
+func unsetDefaultAddress(tx *gorm.DB, userID uint64) error {
+	return tx.Model(&entity.Address{}).Where("user_id = ?", userID).Where("is_default = ?", true).Update("is_default", false).Error
+}
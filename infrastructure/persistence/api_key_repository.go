@@ -0,0 +1,70 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository implements the repository.APIKeyRepository interface
+type APIKeyRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new instance of the APIKeyRepository
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// CreateAPIKey creates a new API key in the database
+func (r *APIKeyRepository) CreateAPIKey(apiKey *entity.APIKey) (*entity.APIKey, error) {
+	if err := r.db.Debug().Create(apiKey).Error; err != nil {
+		return nil, err
+	}
+
+	return apiKey, nil
+}
+
+// GetActiveAPIKeyByKeyHash retrieves the unrevoked API key with the given hash
+func (r *APIKeyRepository) GetActiveAPIKeyByKeyHash(keyHash string) (*entity.APIKey, error) {
+	var apiKey entity.APIKey
+	if err := r.db.Debug().Where("key_hash = ?", keyHash).Where("revoked_at IS NULL").Take(&apiKey).Error; err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// GetAPIKeyByIDAndOrganizationID retrieves an API key of an organization by its ID
+func (r *APIKeyRepository) GetAPIKeyByIDAndOrganizationID(id uint64, organizationID uint64) (*entity.APIKey, error) {
+	var apiKey entity.APIKey
+	if err := r.db.Debug().Where("id = ?", id).Where("organization_id = ?", organizationID).Take(&apiKey).Error; err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// GetAllAPIKeysByOrganizationID retrieves the API keys of an organization
+func (r *APIKeyRepository) GetAllAPIKeysByOrganizationID(organizationID uint64) ([]entity.APIKey, error) {
+	var apiKeys []entity.APIKey
+	if err := r.db.Debug().Where("organization_id = ?", organizationID).Order("id asc").Find(&apiKeys).Error; err != nil {
+		return nil, err
+	}
+	return apiKeys, nil
+}
+
+// UpdateAPIKeyLastUsedAtByID records when an API key was last used
+func (r *APIKeyRepository) UpdateAPIKeyLastUsedAtByID(id uint64, lastUsedAt time.Time) error {
+	return r.db.Debug().Model(&entity.APIKey{}).Where("id = ?", id).Update("last_used_at", lastUsedAt).Error
+}
+
+// RevokeAPIKeyByID revokes an API key
+func (r *APIKeyRepository) RevokeAPIKeyByID(id uint64, revokedAt time.Time) error {
+	return r.db.Debug().Model(&entity.APIKey{}).Where("id = ?", id).Where("revoked_at IS NULL").Update("revoked_at", revokedAt).Error
+}
+
+// RevokeAPIKeysByOrganizationIDAndUserID revokes the API keys a member created for an organization
+func (r *APIKeyRepository) RevokeAPIKeysByOrganizationIDAndUserID(organizationID uint64, userID uint64, revokedAt time.Time) error {
+	return r.db.Debug().Model(&entity.APIKey{}).Where("organization_id = ?", organizationID).Where("user_id = ?", userID).Where("revoked_at IS NULL").Update("revoked_at", revokedAt).Error
+}
@@ -0,0 +1,52 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+)
+
+// ChatOutboxRepository implements the repository.ChatOutboxRepository interface
+type ChatOutboxRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewChatOutboxRepository creates a new instance of the ChatOutboxRepository
+func NewChatOutboxRepository(db *gorm.DB) *ChatOutboxRepository {
+	return &ChatOutboxRepository{db: db}
+}
+
+// CreateChatOutboxes stores the chat membership changes at once, so they're all applied or none is
+func (r *ChatOutboxRepository) CreateChatOutboxes(chatOutboxes []entity.ChatOutbox) error {
+	if len(chatOutboxes) == 0 {
+		return nil
+	}
+
+	return r.db.Debug().Create(&chatOutboxes).Error
+}
+
+// UpdateChatOutboxByID updates the chat membership change
+func (r *ChatOutboxRepository) UpdateChatOutboxByID(id uint64, chatOutbox *entity.ChatOutbox) (*entity.ChatOutbox, error) {
+	if err := r.db.Debug().Model(&entity.ChatOutbox{}).Where("id = ?", id).Select("attempts", "last_error", "next_attempt_at", "processed_at").Updates(chatOutbox).Error; err != nil {
+		return nil, err
+	}
+
+	return chatOutbox, nil
+}
+
+// GetAllDueChatOutboxes retrieves the unprocessed chat membership changes due for an attempt, in
+// the order they were made. The changes of a channel are left out while an earlier one of the
+// channel is backing off.
+func (r *ChatOutboxRepository) GetAllDueChatOutboxes(now time.Time, limit int) ([]entity.ChatOutbox, error) {
+	var chatOutboxes []entity.ChatOutbox
+	if err := r.db.Debug().
+		Where("processed_at IS NULL").
+		Where("next_attempt_at <= ?", now).
+		Where("NOT EXISTS (SELECT 1 FROM chat_outboxes AS earlier WHERE earlier.channel_id = chat_outboxes.channel_id AND earlier.processed_at IS NULL AND earlier.id < chat_outboxes.id AND earlier.next_attempt_at > ?)", now).
+		Order("id asc").Limit(limit).Find(&chatOutboxes).Error; err != nil {
+		return nil, err
+	}
+	return chatOutboxes, nil
+}
@@ -0,0 +1,96 @@
+package persistence
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ChatRepository implements the repository.ChatRepository interface
+type ChatRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewChatRepository creates a new instance of the ChatRepository
+func NewChatRepository(db *gorm.DB) *ChatRepository {
+	return &ChatRepository{db: db}
+}
+
+// CreateChannel creates a chat channel, doing nothing if it already exists
+func (r *ChatRepository) CreateChannel(channel *entity.ChatChannel) error {
+	return r.db.Debug().Omit("Members").Clauses(clause.OnConflict{DoNothing: true}).Create(channel).Error
+}
+
+// GetAllChannelsByUserID retrieves the chat channels the user is a member of, most recent first
+func (r *ChatRepository) GetAllChannelsByUserID(userID string) ([]entity.ChatChannel, error) {
+	var channels []entity.ChatChannel
+	if err := r.db.Debug().
+		Where("id IN (?)", r.db.Model(&entity.ChatMember{}).Select("channel_id").Where("user_id = ?", userID)).
+		Order("created_at desc").
+		Preload("Members").
+		Find(&channels).Error; err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// AddMember adds a member to a chat channel, doing nothing if they're already a member
+func (r *ChatRepository) AddMember(member *entity.ChatMember) error {
+	return r.db.Debug().Clauses(clause.OnConflict{DoNothing: true}).Create(member).Error
+}
+
+// RemoveMember removes a member from a chat channel
+func (r *ChatRepository) RemoveMember(channelID string, userID string) error {
+	return r.db.Debug().Where("channel_id = ?", channelID).Where("user_id = ?", userID).Delete(&entity.ChatMember{}).Error
+}
+
+// GetMember retrieves the membership of a user in a chat channel
+func (r *ChatRepository) GetMember(channelID string, userID string) (*entity.ChatMember, error) {
+	var member entity.ChatMember
+	if err := r.db.Debug().Where("channel_id = ?", channelID).Where("user_id = ?", userID).Take(&member).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// GetAllMembersByChannelID retrieves the members of a chat channel
+func (r *ChatRepository) GetAllMembersByChannelID(channelID string) ([]entity.ChatMember, error) {
+	var members []entity.ChatMember
+	if err := r.db.Debug().Where("channel_id = ?", channelID).Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// UpdateMemberLastReadMessageID records the last message a member has read. Read receipts only
+// move forward, so acknowledging an older message has no effect.
+func (r *ChatRepository) UpdateMemberLastReadMessageID(channelID string, userID string, messageID uint64) error {
+	return r.db.Debug().Model(&entity.ChatMember{}).
+		Where("channel_id = ?", channelID).
+		Where("user_id = ?", userID).
+		Update("last_read_message_id", gorm.Expr("GREATEST(last_read_message_id, ?)", messageID)).Error
+}
+
+// CreateMessage creates a new chat message in the database
+func (r *ChatRepository) CreateMessage(message *entity.ChatMessage) (*entity.ChatMessage, error) {
+	if err := r.db.Debug().Create(message).Error; err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// GetAllMessagesByChannelID retrieves the latest messages of a chat channel sent before the given
+// message, or the latest ones if it's zero, newest first
+func (r *ChatRepository) GetAllMessagesByChannelID(channelID string, beforeID uint64, limit int) ([]entity.ChatMessage, error) {
+	query := r.db.Debug().Where("channel_id = ?", channelID)
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+
+	var messages []entity.ChatMessage
+	if err := query.Order("id desc").Limit(limit).Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
@@ -15,17 +15,19 @@ func NewCityRepository(db *gorm.DB) *CityRepository {
 	return &CityRepository{db: db}
 }
 
-func (r *CityRepository) CountCities() (int64, error) {
+// CountCities counts the cities, of a region when its ID isn't 0
+func (r *CityRepository) CountCities(regionID uint64) (int64, error) {
 	var count int64
-	if err := r.db.Debug().Model(&entity.City{}).Count(&count).Error; err != nil {
+	if err := r.filterCities(regionID).Count(&count).Error; err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
-func (r *CityRepository) GetAllCities(page int, perPage int) ([]entity.City, error) {
+// GetAllCities retrieves a page of the cities, of a region when its ID isn't 0
+func (r *CityRepository) GetAllCities(regionID uint64, page int, perPage int) ([]entity.City, error) {
 	var cities []entity.City
-	if err := r.db.Debug().Model(&entity.City{}).Limit(perPage).Offset((page - 1) * perPage).Find(&cities).Error; err != nil {
+	if err := r.filterCities(regionID).Order("id asc").Limit(perPage).Offset((page - 1) * perPage).Find(&cities).Error; err != nil {
 		return nil, err
 	}
 	return cities, nil
@@ -38,3 +40,11 @@ func (r *CityRepository) GetCityByID(id uint64) (*entity.City, error) {
 	}
 	return &city, nil
 }
+
+func (r *CityRepository) filterCities(regionID uint64) *gorm.DB {
+	db := r.db.Debug().Model(&entity.City{})
+	if regionID != 0 {
+		db = db.Where("region_id = ?", regionID)
+	}
+	return db
+}
@@ -0,0 +1,49 @@
+package persistence
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+)
+
+// CreditRepository implements the repository.CreditRepository interface
+type CreditRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewCreditRepository creates a new instance of the CreditRepository
+func NewCreditRepository(db *gorm.DB) *CreditRepository {
+	return &CreditRepository{db: db}
+}
+
+// CreateCredit creates a new credit in the database
+func (r *CreditRepository) CreateCredit(credit *entity.Credit) (*entity.Credit, error) {
+	if err := r.db.Debug().Model(&credit).Create(&credit).Error; err != nil {
+		return nil, err
+	}
+
+	return credit, nil
+}
+
+// CountCreditsByUserIDAndReason counts the credits granted to a user for a reason
+func (r *CreditRepository) CountCreditsByUserIDAndReason(userID uint64, reason entity.CreditReason) (int64, error) {
+	var count int64
+	if err := r.db.Debug().Model(&entity.Credit{}).Where("user_id = ?", userID).Where("reason = ?", reason).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// SumCreditsByUserID returns the total credit of a user
+func (r *CreditRepository) SumCreditsByUserID(userID uint64) (float64, error) {
+	var sum float64
+	if err := r.db.Debug().Model(&entity.Credit{}).Select("COALESCE(SUM(amount), 0)").Where("user_id = ?", userID).Row().Scan(&sum); err != nil {
+		return 0, err
+	}
+	return sum, nil
+}
+
+// DeleteCreditsByOrderIDAndReason deletes the credits recorded against an order for a reason
+func (r *CreditRepository) DeleteCreditsByOrderIDAndReason(orderID uint64, reason entity.CreditReason) error {
+	return r.db.Debug().Where("order_id = ?", orderID).Where("reason = ?", reason).Delete(&entity.Credit{}).Error
+}
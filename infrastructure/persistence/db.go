@@ -37,6 +37,31 @@ type Repositories struct {
 	Balance            repository.BalanceRepository
 	Offer              repository.OfferRepository
 	Device             repository.DeviceRepository
+	OrderTimeline      repository.OrderTimelineRepository
+	RecurringOrder     repository.RecurringOrderRepository
+	Trip               repository.TripRepository
+	ServiceArea        repository.ServiceAreaRepository
+	Promotion          repository.PromotionRepository
+	Credit             repository.CreditRepository
+	Invoice            repository.InvoiceRepository
+	UserStats          repository.UserStatsRepository
+	Ticket             repository.TicketRepository
+	Chat               repository.ChatRepository
+	FlaggedMessage     repository.FlaggedMessageRepository
+	ChatOutbox         repository.ChatOutboxRepository
+	OutboxEvent        repository.OutboxEventRepository
+	Webhook            repository.WebhookRepository
+	Organization       repository.OrganizationRepository
+	APIKey             repository.APIKeyRepository
+	Address            repository.AddressRepository
+	Geocoding          repository.GeocodingRepository
+	Region             repository.RegionRepository
+	District           repository.DistrictRepository
+	DriverCoverage     repository.DriverCoverageRepository
+	Hub                repository.HubRepository
+	OrderLeg           repository.OrderLegRepository
+	Scan               repository.ScanRepository
+	UnitOfWork         repository.UnitOfWork
 	db                 *gorm.DB
 }
 
@@ -71,13 +96,62 @@ func NewRepositories(host, port, user, password, dbname, sslMode, timeZone strin
 		Balance:            NewBalanceRepository(db),
 		Offer:              NewOfferRepository(db),
 		Device:             NewDeviceRepository(db),
+		OrderTimeline:      NewOrderTimelineRepository(db),
+		RecurringOrder:     NewRecurringOrderRepository(db),
+		Trip:               NewTripRepository(db),
+		ServiceArea:        NewServiceAreaRepository(db),
+		Promotion:          NewPromotionRepository(db),
+		Credit:             NewCreditRepository(db),
+		Invoice:            NewInvoiceRepository(db),
+		UserStats:          NewUserStatsRepository(db),
+		Ticket:             NewTicketRepository(db),
+		Chat:               NewChatRepository(db),
+		FlaggedMessage:     NewFlaggedMessageRepository(db),
+		ChatOutbox:         NewChatOutboxRepository(db),
+		OutboxEvent:        NewOutboxEventRepository(db),
+		Webhook:            NewWebhookRepository(db),
+		Organization:       NewOrganizationRepository(db),
+		APIKey:             NewAPIKeyRepository(db),
+		Address:            NewAddressRepository(db),
+		Geocoding:          NewGeocodingRepository(db),
+		Region:             NewRegionRepository(db),
+		District:           NewDistrictRepository(db),
+		DriverCoverage:     NewDriverCoverageRepository(db),
+		Hub:                NewHubRepository(db),
+		OrderLeg:           NewOrderLegRepository(db),
+		Scan:               NewScanRepository(db),
+		UnitOfWork:         NewUnitOfWork(db),
 		db:                 db,
 	}, nil
 }
 
 // AutoMigrate creates the necessary tables in the database
 func (r *Repositories) AutoMigrate() error {
-	return r.db.AutoMigrate(&entity.User{}, &entity.Category{}, &entity.Location{}, &entity.Size{}, &entity.ShipmentContent{}, &entity.ExtraService{}, &entity.TransportationMode{}, &entity.TruckType{}, &entity.TruckModel{}, &entity.DeliveryTime{}, &entity.Driver{}, &entity.Order{}, &entity.Rating{}, &entity.Page{}, &entity.FAQ{}, &entity.OrderShipmentContent{}, &entity.OrderExtraService{}, &entity.OrderDriverPool{}, &entity.OrderDriverPool{}, &entity.Setting{}, &entity.PasswordReset{}, &entity.PhoneVerification{}, &entity.IdentityDocument{}, &entity.Balance{}, &entity.Offer{}, &entity.Device{}, &entity.City{})
+	if err := r.db.AutoMigrate(&entity.User{}, &entity.Category{}, &entity.Location{}, &entity.Size{}, &entity.ShipmentContent{}, &entity.ExtraService{}, &entity.TransportationMode{}, &entity.TruckType{}, &entity.TruckModel{}, &entity.DeliveryTime{}, &entity.Driver{}, &entity.Order{}, &entity.Rating{}, &entity.Page{}, &entity.FAQ{}, &entity.OrderShipmentContent{}, &entity.OrderExtraService{}, &entity.OrderDriverPool{}, &entity.OrderDriverPool{}, &entity.Setting{}, &entity.PasswordReset{}, &entity.PhoneVerification{}, &entity.IdentityDocument{}, &entity.Balance{}, &entity.Offer{}, &entity.Device{}, &entity.City{}, &entity.OrderTimeline{}, &entity.RecurringOrder{}, &entity.OrderDropOff{}, &entity.Trip{}, &entity.TripStop{}, &entity.ServiceArea{}, &entity.Promotion{}, &entity.PromotionRedemption{}, &entity.Credit{}, &entity.Invoice{}, &entity.Ticket{}, &entity.TicketMessage{}, &entity.ChatChannel{}, &entity.ChatMember{}, &entity.ChatMessage{}, &entity.FlaggedMessage{}, &entity.ChatOutbox{}, &entity.OutboxEvent{}, &entity.WebhookSubscription{}, &entity.WebhookDelivery{}, &entity.Organization{}, &entity.OrganizationMember{}, &entity.APIKey{}, &entity.Address{}, &entity.Region{}, &entity.District{}, &entity.DriverCoverage{}, &entity.Hub{}, &entity.OrderLeg{}, &entity.HubMember{}, &entity.Scan{}); err != nil {
+		return err
+	}
+
+	if err := NewWebhookRepository(r.db).DropWebhookDeliveryResponseBodies(); err != nil {
+		return err
+	}
+
+	if err := NewRatingRepository(r.db).BackfillRatings(); err != nil {
+		return err
+	}
+
+	if err := NewRatingRepository(r.db).MigrateUniqueRatings(); err != nil {
+		return err
+	}
+
+	if err := NewOrderRepository(r.db).BackfillOrderDestinations(); err != nil {
+		return err
+	}
+
+	if err := NewPromotionRepository(r.db).BackfillPromotionRedemptionsCounts(); err != nil {
+		return err
+	}
+
+	return NewOrderRepository(r.db).BackfillOrderTrackingNumbers()
 }
 
 // SeedCategories seeds the categories into the database.
@@ -342,6 +416,24 @@ func (r *Repositories) SeedSettings() {
 		{Key: "rules_page_id", Value: "3"},
 		{Key: "shipment_contents_max_selections", Value: "3"},
 		{Key: "max_orders_per_trip", Value: "5"},
+		{Key: "return_window_hours", Value: "48"},
+		{Key: "preferred_driver_minutes", Value: "15"},
+		{Key: "scheduled_order_release_minutes", Value: "60"},
+		{Key: "recurring_order_lead_hours", Value: "24"},
+		{Key: "base_fare", Value: "10"},
+		{Key: "price_per_km", Value: "2"},
+		{Key: "surge_step", Value: "0.25"},
+		{Key: "surge_max_multiplier", Value: "2"},
+		{Key: "referral_credit_amount", Value: "20"},
+		{Key: "vat_rate", Value: "15"},
+		{Key: "commission_rate", Value: "10"},
+		{Key: "extra_service_fee", Value: "5"},
+		{Key: "seller_name", Value: "Jayeek"},
+		{Key: "seller_vat_number", Value: "300000000000003"},
+		{Key: "seller_address", Value: "Riyadh, Saudi Arabia"},
+		{Key: "chat_blocked_words", Value: "idiot,stupid,غبي,حمار,كلب"},
+		{Key: "masked_calling_numbers", Value: ""},
+		{Key: "max_bulk_orders", Value: "100"},
 	}
 
 	// Iterate through the list of transportation modes and insert each transportation mode into the database
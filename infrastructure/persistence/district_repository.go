@@ -0,0 +1,54 @@
+package persistence
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+)
+
+// DistrictRepository implements the repository.DistrictRepository interface.
+type DistrictRepository struct {
+	db *gorm.DB
+}
+
+// NewDistrictRepository creates a new instance of the DistrictRepository.
+func NewDistrictRepository(db *gorm.DB) *DistrictRepository {
+	return &DistrictRepository{db: db}
+}
+
+// CountDistricts counts the districts, of a region or a city when their IDs aren't 0
+func (r *DistrictRepository) CountDistricts(regionID uint64, cityID uint64) (int64, error) {
+	var count int64
+	if err := r.filterDistricts(regionID, cityID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAllDistricts retrieves a page of the districts, of a region or a city when their IDs aren't 0
+func (r *DistrictRepository) GetAllDistricts(regionID uint64, cityID uint64, page int, perPage int) ([]entity.District, error) {
+	var districts []entity.District
+	if err := r.filterDistricts(regionID, cityID).Order("id asc").Limit(perPage).Offset((page - 1) * perPage).Find(&districts).Error; err != nil {
+		return nil, err
+	}
+	return districts, nil
+}
+
+// GetDistrictByID retrieves a district by its ID
+func (r *DistrictRepository) GetDistrictByID(id uint64) (*entity.District, error) {
+	var district entity.District
+	if err := r.db.Debug().Where("id = ?", id).Take(&district).Error; err != nil {
+		return nil, err
+	}
+	return &district, nil
+}
+
+func (r *DistrictRepository) filterDistricts(regionID uint64, cityID uint64) *gorm.DB {
+	db := r.db.Debug().Model(&entity.District{})
+	if regionID != 0 {
+		db = db.Where("region_id = ?", regionID)
+	}
+	if cityID != 0 {
+		db = db.Where("city_id = ?", cityID)
+	}
+	return db
+}
@@ -0,0 +1,50 @@
+package persistence
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+)
+
+// DriverCoverageRepository implements the repository.DriverCoverageRepository interface
+type DriverCoverageRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewDriverCoverageRepository creates a new instance of the DriverCoverageRepository
+func NewDriverCoverageRepository(db *gorm.DB) *DriverCoverageRepository {
+	return &DriverCoverageRepository{db: db}
+}
+
+// GetAllDriverCoveragesByDriverID retrieves the cities and regions a driver serves
+func (r *DriverCoverageRepository) GetAllDriverCoveragesByDriverID(driverID uint64) ([]entity.DriverCoverage, error) {
+	var coverages []entity.DriverCoverage
+	if err := r.db.Debug().Where("driver_id = ?", driverID).Order("id asc").Preload("City").Preload("Region").Find(&coverages).Error; err != nil {
+		return nil, err
+	}
+	return coverages, nil
+}
+
+// ReplaceDriverCoveragesByDriverID replaces the cities and regions a driver serves
+func (r *DriverCoverageRepository) ReplaceDriverCoveragesByDriverID(driverID uint64, coverages []entity.DriverCoverage) ([]entity.DriverCoverage, error) {
+	if err := r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("driver_id = ?", driverID).Delete(&entity.DriverCoverage{}).Error; err != nil {
+			return err
+		}
+
+		if len(coverages) == 0 {
+			return nil
+		}
+
+		for i := range coverages {
+			coverages[i].ID = 0
+			coverages[i].DriverID = driverID
+		}
+
+		return tx.Omit("City", "Region").Create(&coverages).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	return r.GetAllDriverCoveragesByDriverID(driverID)
+}
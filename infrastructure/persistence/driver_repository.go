@@ -5,6 +5,7 @@ import (
 
 	"github.com/OmarBader7/web-service-jayeek/domain/entity"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // DriverRepository implements the repository.DriverRepository interface
@@ -98,6 +99,13 @@ func (r *DriverRepository) GetDriverByID(id uint64) (*entity.Driver, error) {
 	return &driver, nil
 }
 
+// LockDriverByID locks the driver's row until the end of the transaction, serializing the requests
+// that check and change the driver's workload
+func (r *DriverRepository) LockDriverByID(id uint64) error {
+	var driver entity.Driver
+	return r.db.Debug().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", id).Take(&driver).Error
+}
+
 func (r *DriverRepository) GetDriverByUserID(userID uint64) (*entity.Driver, error) {
 	// Driver struct to store the retrieved driver data
 	var driver entity.Driver
@@ -110,20 +118,35 @@ func (r *DriverRepository) GetDriverByUserID(userID uint64) (*entity.Driver, err
 	return &driver, nil
 }
 
+// CountDriversByUserLocationID counts the drivers serving a location, see driversServingLocation
 func (r *DriverRepository) CountDriversByUserLocationID(userLocationID uint64) (int64, error) {
 	var count int64
 	if err := r.db.Debug().Model(&entity.Driver{}).Joins("JOIN users ON drivers.user_id = users.id").
-		Where("users.location_id = ?", userLocationID).Count(&count).Error; err != nil {
+		Scopes(driversServingLocation(userLocationID)).Count(&count).Error; err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
+// GetDriversByUserLocationID retrieves the drivers serving a location, see driversServingLocation
 func (r *DriverRepository) GetDriversByUserLocationID(userLocationID uint64, page int, perPage int) ([]entity.Driver, error) {
 	var drivers []entity.Driver
 	if err := r.db.Debug().Model(&entity.Driver{}).Preload("User").Preload("User.Location").Preload("TransportationMode").Joins("JOIN users ON drivers.user_id = users.id").
-		Where("users.location_id = ?", userLocationID).Limit(perPage).Offset((page - 1) * perPage).Find(&drivers).Error; err != nil {
+		Scopes(driversServingLocation(userLocationID)).Order("drivers.id asc").Limit(perPage).Offset((page - 1) * perPage).Find(&drivers).Error; err != nil {
 		return nil, err
 	}
 	return drivers, nil
 }
+
+// driversServingLocation keeps the drivers covering the city or the region of a location. The
+// drivers who didn't declare the cities and regions they serve are kept when they live there.
+func driversServingLocation(locationID uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(`(EXISTS (
+			SELECT 1 FROM driver_coverages
+			JOIN locations ON locations.id = ?
+			LEFT JOIN cities ON cities.id = locations.city_id
+			WHERE driver_coverages.driver_id = drivers.id AND (driver_coverages.city_id = locations.city_id OR driver_coverages.region_id = cities.region_id)
+		) OR (NOT EXISTS (SELECT 1 FROM driver_coverages WHERE driver_coverages.driver_id = drivers.id) AND users.location_id = ?))`, locationID, locationID)
+	}
+}
@@ -0,0 +1,35 @@
+package persistence
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// uniqueViolationCode is the code of the errors PostgreSQL returns when a row breaks a unique index
+const uniqueViolationCode = "23505"
+
+// isUniqueViolation reports whether an error was returned because a row broke the unique index
+// of the given name
+func isUniqueViolation(err error, indexName string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode && pgErr.ConstraintName == indexName
+}
+
+// maxCodeAttempts is the number of random codes drawn for a record before giving up, when the ones
+// drawn are already taken
+const maxCodeAttempts = 5
+
+// retryTakenCode runs an attempt at saving a record with a random code, drawn by the attempt, until
+// the code isn't taken by another record. Each attempt runs in a savepoint when the repository is
+// part of a transaction, so a taken code doesn't abort it.
+func retryTakenCode(db *gorm.DB, indexName string, attempt func(tx *gorm.DB) error) error {
+	var err error
+	for i := 0; i < maxCodeAttempts; i++ {
+		if err = db.Transaction(attempt); !isUniqueViolation(err, indexName) {
+			return err
+		}
+	}
+	return err
+}
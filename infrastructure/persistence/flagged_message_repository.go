@@ -0,0 +1,70 @@
+package persistence
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+)
+
+// FlaggedMessageRepository implements the repository.FlaggedMessageRepository interface
+type FlaggedMessageRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewFlaggedMessageRepository creates a new instance of the FlaggedMessageRepository
+func NewFlaggedMessageRepository(db *gorm.DB) *FlaggedMessageRepository {
+	return &FlaggedMessageRepository{db: db}
+}
+
+// CreateFlaggedMessage creates a new flagged message in the database
+func (r *FlaggedMessageRepository) CreateFlaggedMessage(flaggedMessage *entity.FlaggedMessage) (*entity.FlaggedMessage, error) {
+	if err := r.db.Debug().Create(flaggedMessage).Error; err != nil {
+		return nil, err
+	}
+
+	return flaggedMessage, nil
+}
+
+// UpdateFlaggedMessageByID updates the flagged message
+func (r *FlaggedMessageRepository) UpdateFlaggedMessageByID(id uint64, flaggedMessage *entity.FlaggedMessage) (*entity.FlaggedMessage, error) {
+	if err := r.db.Debug().Model(&entity.FlaggedMessage{}).Where("id = ?", id).Select("*").Omit("id", "created_at").Updates(flaggedMessage).Error; err != nil {
+		return nil, err
+	}
+
+	return flaggedMessage, nil
+}
+
+// GetFlaggedMessageByID retrieves a flagged message by its ID
+func (r *FlaggedMessageRepository) GetFlaggedMessageByID(id uint64) (*entity.FlaggedMessage, error) {
+	var flaggedMessage entity.FlaggedMessage
+	if err := r.db.Debug().Where("id = ?", id).Take(&flaggedMessage).Error; err != nil {
+		return nil, err
+	}
+	return &flaggedMessage, nil
+}
+
+// GetAllFlaggedMessages retrieves a paginated list of the flagged messages, optionally filtered by status
+func (r *FlaggedMessageRepository) GetAllFlaggedMessages(status string, page int, perPage int) ([]entity.FlaggedMessage, error) {
+	var flaggedMessages []entity.FlaggedMessage
+	if err := r.filterByStatus(status).Order("created_at desc").Order("id desc").Limit(perPage).Offset((page - 1) * perPage).Find(&flaggedMessages).Error; err != nil {
+		return nil, err
+	}
+	return flaggedMessages, nil
+}
+
+// CountFlaggedMessages counts the flagged messages, optionally filtered by status
+func (r *FlaggedMessageRepository) CountFlaggedMessages(status string) (int64, error) {
+	var count int64
+	if err := r.filterByStatus(status).Model(&entity.FlaggedMessage{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *FlaggedMessageRepository) filterByStatus(status string) *gorm.DB {
+	db := r.db.Debug()
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	return db
+}
@@ -0,0 +1,239 @@
+package persistence
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GeocodingRepository implements the repository.GeocodingRepository interface
+type GeocodingRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewGeocodingRepository creates a new instance of the GeocodingRepository
+func NewGeocodingRepository(db *gorm.DB) *GeocodingRepository {
+	return &GeocodingRepository{db: db}
+}
+
+// SaveRegion creates the region or replaces the one with the same ID, along with its boundary
+func (r *GeocodingRepository) SaveRegion(region *entity.Region) error {
+	return r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name_ar", "name_en", "latitude", "longitude", "updated_at"}),
+		}).Create(region).Error; err != nil {
+			return err
+		}
+
+		if err := syncIDSequence(tx, "regions"); err != nil {
+			return err
+		}
+
+		return saveArea(tx, "regions", region.ID, region.Boundary)
+	})
+}
+
+// SaveCity creates the city or replaces the one with the same ID, along with its boundary
+func (r *GeocodingRepository) SaveCity(city *entity.City) error {
+	return r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"region_id", "name_ar", "name_en", "latitude", "longitude", "updated_at"}),
+		}).Create(city).Error; err != nil {
+			return err
+		}
+
+		if err := syncIDSequence(tx, "cities"); err != nil {
+			return err
+		}
+
+		return saveArea(tx, "cities", city.ID, city.Boundary)
+	})
+}
+
+// SaveDistrict creates the district or replaces the one with the same ID, along with its boundary
+func (r *GeocodingRepository) SaveDistrict(district *entity.District) error {
+	return r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"city_id", "region_id", "name_ar", "name_en", "latitude", "longitude", "updated_at"}),
+		}).Create(district).Error; err != nil {
+			return err
+		}
+
+		if err := syncIDSequence(tx, "districts"); err != nil {
+			return err
+		}
+
+		return saveArea(tx, "districts", district.ID, district.Boundary)
+	})
+}
+
+// SaveLocation creates the location or replaces the one with the same ID
+func (r *GeocodingRepository) SaveLocation(location *entity.Location) error {
+	return r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "latitude", "longitude", "city_id", "district_id", "updated_at"}),
+		}).Create(location).Error; err != nil {
+			return err
+		}
+
+		return syncIDSequence(tx, "locations")
+	})
+}
+
+// SearchGeoPlaces retrieves the regions, cities and districts whose Arabic or English name contains
+// the query. The names starting with it come first, then the cities, the districts and the regions.
+func (r *GeocodingRepository) SearchGeoPlaces(query string, limit int) ([]entity.GeoPlace, error) {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(query)
+
+	var places []entity.GeoPlace
+	if err := r.db.Debug().Raw(`
+		SELECT type, id, name_ar, name_en, region_id, city_id, district_id, latitude, longitude FROM (
+			SELECT 'city' AS type, id, name_ar, name_en, region_id, id AS city_id, 0 AS district_id, COALESCE(latitude, 0) AS latitude, COALESCE(longitude, 0) AS longitude, 1 AS level
+			FROM cities WHERE name_ar ILIKE @pattern OR name_en ILIKE @pattern
+			UNION ALL
+			SELECT 'district', id, name_ar, name_en, region_id, city_id, id, COALESCE(latitude, 0), COALESCE(longitude, 0), 2
+			FROM districts WHERE name_ar ILIKE @pattern OR name_en ILIKE @pattern
+			UNION ALL
+			SELECT 'region', id, name_ar, name_en, id, 0, 0, COALESCE(latitude, 0), COALESCE(longitude, 0), 3
+			FROM regions WHERE name_ar ILIKE @pattern OR name_en ILIKE @pattern
+		) places
+		ORDER BY (name_ar ILIKE @prefix OR name_en ILIKE @prefix) DESC, level ASC, LENGTH(name_en) ASC, id ASC
+		LIMIT @limit
+	`, map[string]interface{}{
+		"pattern": "%" + escaped + "%",
+		"prefix":  escaped + "%",
+		"limit":   limit,
+	}).Scan(&places).Error; err != nil {
+		return nil, err
+	}
+	return places, nil
+}
+
+// GetRegionByID retrieves a region by its ID
+func (r *GeocodingRepository) GetRegionByID(id uint64) (*entity.Region, error) {
+	var region entity.Region
+	if err := r.db.Debug().Where("id = ?", id).Take(&region).Error; err != nil {
+		return nil, err
+	}
+	return &region, nil
+}
+
+// GetRegionByCoordinates retrieves the region covering the given point
+func (r *GeocodingRepository) GetRegionByCoordinates(longitude float64, latitude float64) (*entity.Region, error) {
+	var region entity.Region
+	if err := r.db.Debug().Where("ST_Covers(area, ST_MakePoint(?, ?)::geography)", longitude, latitude).Order("ST_Area(area) ASC").Take(&region).Error; err != nil {
+		return nil, err
+	}
+	return &region, nil
+}
+
+// GetCityByCoordinates retrieves the city covering the given point
+func (r *GeocodingRepository) GetCityByCoordinates(longitude float64, latitude float64) (*entity.City, error) {
+	var city entity.City
+	if err := r.db.Debug().Where("ST_Covers(area, ST_MakePoint(?, ?)::geography)", longitude, latitude).Order("ST_Area(area) ASC").Take(&city).Error; err != nil {
+		return nil, err
+	}
+	return &city, nil
+}
+
+// GetDistrictByCoordinates retrieves the district covering the given point
+func (r *GeocodingRepository) GetDistrictByCoordinates(longitude float64, latitude float64) (*entity.District, error) {
+	var district entity.District
+	if err := r.db.Debug().Where("ST_Covers(area, ST_MakePoint(?, ?)::geography)", longitude, latitude).Order("ST_Area(area) ASC").Take(&district).Error; err != nil {
+		return nil, err
+	}
+	return &district, nil
+}
+
+// FillOrderCities sets the cities and regions of the orders, and of the recurring orders, placed
+// before they were known, from the districts, cities and regions covering their pickup and
+// destination points.
+func (r *GeocodingRepository) FillOrderCities() error {
+	return r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		for _, table := range []string{"orders", "recurring_orders"} {
+			for _, point := range []struct {
+				prefix    string
+				longitude string
+				latitude  string
+			}{
+				{"", "longitude", "latitude"},
+				{"destination_", "destination_longitude", "destination_latitude"},
+			} {
+				if err := tx.Exec(fmt.Sprintf(`
+					UPDATE %[4]s SET %[1]scity_id = COALESCE(district.city_id, city.id), %[1]sregion_id = COALESCE(district.region_id, city.region_id, region.id)
+					FROM %[4]s AS located
+					LEFT JOIN LATERAL (
+						SELECT districts.city_id, districts.region_id FROM districts
+						WHERE ST_Covers(districts.area, ST_MakePoint(located.%[2]s, located.%[3]s)::geography) ORDER BY ST_Area(districts.area) LIMIT 1
+					) district ON true
+					LEFT JOIN LATERAL (
+						SELECT cities.id, cities.region_id FROM cities
+						WHERE ST_Covers(cities.area, ST_MakePoint(located.%[2]s, located.%[3]s)::geography) ORDER BY ST_Area(cities.area) LIMIT 1
+					) city ON true
+					LEFT JOIN LATERAL (
+						SELECT regions.id FROM regions
+						WHERE ST_Covers(regions.area, ST_MakePoint(located.%[2]s, located.%[3]s)::geography) ORDER BY ST_Area(regions.area) LIMIT 1
+					) region ON true
+					WHERE %[4]s.id = located.id AND located.%[1]scity_id IS NULL AND located.%[3]s IS NOT NULL
+						AND COALESCE(district.city_id, city.id, region.id) IS NOT NULL
+				`, point.prefix, point.longitude, point.latitude, table)).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// FillLocationCities sets the cities and districts of the locations imported or seeded without
+// them, from the district or the city covering their point.
+func (r *GeocodingRepository) FillLocationCities() error {
+	return r.db.Debug().Exec(`
+		UPDATE locations SET district_id = COALESCE(locations.district_id, district.id), city_id = COALESCE(located_district.city_id, district.city_id, city.id)
+		FROM locations AS located
+		LEFT JOIN districts AS located_district ON located_district.id = located.district_id
+		LEFT JOIN LATERAL (
+			SELECT districts.id, districts.city_id FROM districts
+			WHERE ST_Covers(districts.area, ST_MakePoint(located.longitude, located.latitude)::geography) ORDER BY ST_Area(districts.area) LIMIT 1
+		) district ON true
+		LEFT JOIN LATERAL (
+			SELECT cities.id FROM cities
+			WHERE ST_Covers(cities.area, ST_MakePoint(located.longitude, located.latitude)::geography) ORDER BY ST_Area(cities.area) LIMIT 1
+		) city ON true
+		WHERE locations.id = located.id AND located.city_id IS NULL
+			AND COALESCE(located_district.city_id, district.city_id, city.id) IS NOT NULL
+	`).Error
+}
+
+// syncIDSequence moves the ID sequence of a table past the IDs imported with the rows, so the rows
+// created afterwards don't reuse them
+func syncIDSequence(tx *gorm.DB, table string) error {
+	return tx.Exec(fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), GREATEST((SELECT MAX(id) FROM %[1]s), 1))", table)).Error
+}
+
+// saveArea stores the boundary of a region, a city or a district, and derives its centroid from
+// the boundary when the dataset doesn't give one
+func saveArea(tx *gorm.DB, table string, id uint64, boundary datatypes.JSON) error {
+	if len(boundary) == 0 {
+		return nil
+	}
+
+	if err := tx.Exec(fmt.Sprintf("UPDATE %s SET area = ST_Multi(ST_GeomFromGeoJSON(?))::geography WHERE id = ?", table), string(boundary), id).Error; err != nil {
+		return err
+	}
+
+	return tx.Exec(fmt.Sprintf(`
+		UPDATE %s SET latitude = ST_Y(ST_PointOnSurface(area::geometry)), longitude = ST_X(ST_PointOnSurface(area::geometry))
+		WHERE id = ? AND (latitude IS NULL OR latitude = 0) AND (longitude IS NULL OR longitude = 0)
+	`, table), id).Error
+}
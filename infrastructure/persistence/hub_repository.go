@@ -0,0 +1,113 @@
+package persistence
+
+import (
+	"fmt"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HubRepository implements the repository.HubRepository interface.
+type HubRepository struct {
+	db *gorm.DB
+}
+
+// NewHubRepository creates a new instance of the HubRepository.
+func NewHubRepository(db *gorm.DB) *HubRepository {
+	return &HubRepository{db: db}
+}
+
+// CreateHub creates a new hub in the database
+func (r *HubRepository) CreateHub(hub *entity.Hub) (*entity.Hub, error) {
+	if err := r.db.Debug().Omit("City").Create(&hub).Error; err != nil {
+		return nil, err
+	}
+	return r.GetHubByID(hub.ID)
+}
+
+// UpdateHubByID updates the hub
+func (r *HubRepository) UpdateHubByID(id uint64, hub *entity.Hub) (*entity.Hub, error) {
+	if err := r.db.Debug().Model(&entity.Hub{}).Where("id = ?", id).Select("city_id", "name_ar", "name_en", "address", "latitude", "longitude", "is_active").Updates(hub).Error; err != nil {
+		return nil, err
+	}
+	return r.GetHubByID(id)
+}
+
+// CountHubs counts the hubs, of a city when its ID isn't 0
+func (r *HubRepository) CountHubs(cityID uint64) (int64, error) {
+	var count int64
+	if err := r.filterHubs(cityID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAllHubs retrieves a page of the hubs, of a city when its ID isn't 0
+func (r *HubRepository) GetAllHubs(cityID uint64, page int, perPage int) ([]entity.Hub, error) {
+	var hubs []entity.Hub
+	if err := r.filterHubs(cityID).Preload("City").Order("id asc").Limit(perPage).Offset((page - 1) * perPage).Find(&hubs).Error; err != nil {
+		return nil, err
+	}
+	return hubs, nil
+}
+
+// GetHubByID retrieves a hub by its ID
+func (r *HubRepository) GetHubByID(id uint64) (*entity.Hub, error) {
+	var hub entity.Hub
+	if err := r.db.Debug().Preload("City").Where("id = ?", id).Take(&hub).Error; err != nil {
+		return nil, err
+	}
+	return &hub, nil
+}
+
+// GetNearestActiveHubByCityID retrieves the active hub of a city closest to the given point
+func (r *HubRepository) GetNearestActiveHubByCityID(cityID uint64, latitude float64, longitude float64) (*entity.Hub, error) {
+	var hub entity.Hub
+	if err := r.db.Debug().Where("city_id = ?", cityID).Where("is_active = ?", true).
+		Order(fmt.Sprintf("ST_Distance(ST_MakePoint(longitude, latitude)::geography, ST_MakePoint(%.6f, %.6f)::geography)", longitude, latitude)).
+		Take(&hub).Error; err != nil {
+		return nil, err
+	}
+	return &hub, nil
+}
+
+// CreateHubMember adds a user to the staff of a hub
+func (r *HubRepository) CreateHubMember(hubMember *entity.HubMember) (*entity.HubMember, error) {
+	if err := r.db.Debug().Omit(clause.Associations).Create(hubMember).Error; err != nil {
+		return nil, err
+	}
+
+	return hubMember, nil
+}
+
+// DeleteHubMemberByID removes a user from the staff of a hub
+func (r *HubRepository) DeleteHubMemberByID(id uint64) error {
+	return r.db.Debug().Where("id = ?", id).Delete(&entity.HubMember{}).Error
+}
+
+// GetHubMemberByHubIDAndUserID retrieves the membership of a user in the staff of a hub
+func (r *HubRepository) GetHubMemberByHubIDAndUserID(hubID uint64, userID uint64) (*entity.HubMember, error) {
+	var hubMember entity.HubMember
+	if err := r.db.Debug().Where("hub_id = ?", hubID).Where("user_id = ?", userID).Preload("User").Preload("User.Location").Take(&hubMember).Error; err != nil {
+		return nil, err
+	}
+	return &hubMember, nil
+}
+
+// GetAllHubMembersByHubID retrieves the staff of a hub
+func (r *HubRepository) GetAllHubMembersByHubID(hubID uint64) ([]entity.HubMember, error) {
+	var hubMembers []entity.HubMember
+	if err := r.db.Debug().Where("hub_id = ?", hubID).Order("id asc").Preload("User").Preload("User.Location").Find(&hubMembers).Error; err != nil {
+		return nil, err
+	}
+	return hubMembers, nil
+}
+
+func (r *HubRepository) filterHubs(cityID uint64) *gorm.DB {
+	db := r.db.Debug().Model(&entity.Hub{})
+	if cityID != 0 {
+		db = db.Where("city_id = ?", cityID)
+	}
+	return db
+}
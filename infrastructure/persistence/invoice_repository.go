@@ -0,0 +1,72 @@
+package persistence
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+	"gorm.io/gorm"
+)
+
+// invoiceOrderIndex is the unique index allowing a single invoice for each order
+const invoiceOrderIndex = "idx_invoices_order_id"
+
+// InvoiceRepository implements the repository.InvoiceRepository interface
+type InvoiceRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewInvoiceRepository creates a new instance of the InvoiceRepository
+func NewInvoiceRepository(db *gorm.DB) *InvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+// CreateInvoice assigns the next invoice number and creates the invoice in the database.
+// repository.ErrConflict is returned when the order was invoiced meanwhile, no number is used then.
+func (r *InvoiceRepository) CreateInvoice(invoice *entity.Invoice) (*entity.Invoice, error) {
+	err := r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		// Invoice numbers must be sequential without gaps, so concurrent invoices are issued one at a time
+		if err := tx.Exec("LOCK TABLE invoices IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+
+		var lastSequence uint64
+		if err := tx.Model(&entity.Invoice{}).Select("COALESCE(MAX(sequence), 0)").Row().Scan(&lastSequence); err != nil {
+			return err
+		}
+
+		invoice.Sequence = lastSequence + 1
+		invoice.Number = entity.NewInvoiceNumber(invoice.Sequence)
+
+		if err := tx.Create(invoice).Error; err != nil {
+			if isUniqueViolation(err, invoiceOrderIndex) {
+				return repository.ErrConflict
+			}
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// UpdateInvoiceByID updates the invoice
+func (r *InvoiceRepository) UpdateInvoiceByID(id uint64, invoice *entity.Invoice) (*entity.Invoice, error) {
+	if err := r.db.Debug().Model(&entity.Invoice{}).Where("id = ?", id).Updates(invoice).Error; err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// GetInvoiceByOrderID retrieves the invoice of an order
+func (r *InvoiceRepository) GetInvoiceByOrderID(orderID uint64) (*entity.Invoice, error) {
+	var invoice entity.Invoice
+	if err := r.db.Debug().Where("order_id = ?", orderID).Take(&invoice).Error; err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
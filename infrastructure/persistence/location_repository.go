@@ -18,22 +18,39 @@ func NewLocationRepository(db *gorm.DB) *LocationRepository {
 	return &LocationRepository{db: db}
 }
 
-func (r *LocationRepository) CountLocations() (int64, error) {
+// CountLocations counts the locations, of a region, a city or a district when their IDs aren't 0
+func (r *LocationRepository) CountLocations(regionID uint64, cityID uint64, districtID uint64) (int64, error) {
 	var count int64
-	if err := r.db.Debug().Model(&entity.Location{}).Count(&count).Error; err != nil {
+	if err := r.filterLocations(regionID, cityID, districtID).Count(&count).Error; err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
-func (r *LocationRepository) GetAllLocations(page int, perPage int) ([]entity.Location, error) {
+// GetAllLocations retrieves a page of the locations, of a region, a city or a district when their IDs
+// aren't 0
+func (r *LocationRepository) GetAllLocations(regionID uint64, cityID uint64, districtID uint64, page int, perPage int) ([]entity.Location, error) {
 	var locations []entity.Location
-	if err := r.db.Debug().Model(&entity.Location{}).Limit(perPage).Offset((page - 1) * perPage).Find(&locations).Error; err != nil {
+	if err := r.filterLocations(regionID, cityID, districtID).Order("locations.id asc").Limit(perPage).Offset((page - 1) * perPage).Find(&locations).Error; err != nil {
 		return nil, err
 	}
 	return locations, nil
 }
 
+func (r *LocationRepository) filterLocations(regionID uint64, cityID uint64, districtID uint64) *gorm.DB {
+	db := r.db.Debug().Model(&entity.Location{})
+	if regionID != 0 {
+		db = db.Where("locations.city_id IN (SELECT id FROM cities WHERE region_id = ?)", regionID)
+	}
+	if cityID != 0 {
+		db = db.Where("locations.city_id = ?", cityID)
+	}
+	if districtID != 0 {
+		db = db.Where("locations.district_id = ?", districtID)
+	}
+	return db
+}
+
 // GetLocationByID retrieves a location by its ID
 func (r *LocationRepository) GetLocationByID(id uint64) (*entity.Location, error) {
 	// Location struct to store the retrieved location data
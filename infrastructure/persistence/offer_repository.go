@@ -2,7 +2,9 @@ package persistence
 
 import (
 	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // OfferRepository implements the repository.OfferRepository interface
@@ -37,6 +39,20 @@ func (r *OfferRepository) UpdateOfferByID(id uint64, offer *entity.Offer) (*enti
 	return offer, nil
 }
 
+// UpdateOfferByIDAndStatus updates the offer only if it's still in the given status, as a compare
+// and set. repository.ErrConflict is returned when another request changed the status first.
+func (r *OfferRepository) UpdateOfferByIDAndStatus(id uint64, status entity.OfferStatus, offer *entity.Offer) (*entity.Offer, error) {
+	result := r.db.Debug().Model(&entity.Offer{}).Where("id = ?", id).Where("status = ?", status).Omit(clause.Associations).Updates(offer)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, repository.ErrConflict
+	}
+
+	return offer, nil
+}
+
 func (r *OfferRepository) CountOffersByDriverIDAndStatus(driverID uint64, status entity.OfferStatus) (int64, error) {
 	var count int64
 	if err := r.db.Debug().Table("offers").Where("driver_id = ?", driverID).Where("status = ?", status).Count(&count).Error; err != nil {
@@ -80,3 +96,21 @@ func (r *OfferRepository) GetAllOffersByStatusAndOrderID(status entity.OfferStat
 	}
 	return offers, nil
 }
+
+// CountOffersByDriverIDAndOrderLegID counts the offers a driver made on a leg of an intercity order
+func (r *OfferRepository) CountOffersByDriverIDAndOrderLegID(driverID uint64, orderLegID uint64) (int64, error) {
+	var count int64
+	if err := r.db.Debug().Table("offers").Where("driver_id = ?", driverID).Where("order_leg_id = ?", orderLegID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAllOffersByStatusAndOrderLegID retrieves the offers made on a leg of an intercity order
+func (r *OfferRepository) GetAllOffersByStatusAndOrderLegID(status entity.OfferStatus, orderLegID uint64) ([]entity.Offer, error) {
+	var offers []entity.Offer
+	if err := r.db.Debug().Where("status = ?", status).Where("order_leg_id = ?", orderLegID).Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Order").Preload("Order.Location").Preload("Order.User").Preload("Order.User.Location").Preload("Order.Driver").Preload("Order.Driver.User").Preload("Order.Driver.User.Location").Preload("Order.Driver.TransportationMode").Preload("Order.Category").Preload("Order.Size").Preload("Order.DeliveryTime").Preload("Order.ShipmentContents").Preload("Order.ExtraServices").Preload("Order.Destination").Find(&offers).Error; err != nil {
+		return nil, err
+	}
+	return offers, nil
+}
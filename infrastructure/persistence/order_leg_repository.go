@@ -0,0 +1,151 @@
+package persistence
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrderLegRepository implements the repository.OrderLegRepository interface
+type OrderLegRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewOrderLegRepository creates a new instance of the OrderLegRepository
+func NewOrderLegRepository(db *gorm.DB) *OrderLegRepository {
+	return &OrderLegRepository{db: db}
+}
+
+// orderLegsBySequence preloads the legs of an order in the order they are carried
+func orderLegsBySequence(db *gorm.DB) *gorm.DB {
+	return db.Order("sequence asc")
+}
+
+// UpdateOrderLegByIDAndStatus updates the leg only if it's still in the given status, as a compare
+// and set. repository.ErrConflict is returned when another request changed the status first.
+func (r *OrderLegRepository) UpdateOrderLegByIDAndStatus(id uint64, status entity.OrderLegStatus, orderLeg *entity.OrderLeg) (*entity.OrderLeg, error) {
+	result := r.db.Debug().Model(&entity.OrderLeg{}).Where("id = ?", id).Where("status = ?", status).Omit(clause.Associations).Updates(orderLeg)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, repository.ErrConflict
+	}
+
+	return orderLeg, nil
+}
+
+// CancelOrderLegsByOrderID cancels the legs of an order that weren't completed yet
+func (r *OrderLegRepository) CancelOrderLegsByOrderID(orderID uint64) error {
+	return r.db.Debug().Model(&entity.OrderLeg{}).Where("order_id = ?", orderID).
+		Where("status <> ?", entity.OrderLegCompletedStatus).
+		Update("status", entity.OrderLegCanceledStatus).Error
+}
+
+// UpdateLastMileOrderLegByOrderID moves the end of the last mile of an order to the given point,
+// unless it was already delivered
+func (r *OrderLegRepository) UpdateLastMileOrderLegByOrderID(orderID uint64, latitude float64, longitude float64) error {
+	return r.db.Debug().Model(&entity.OrderLeg{}).Where("order_id = ?", orderID).
+		Where("type = ?", entity.LastMileOrderLegType).
+		Where("status NOT IN (?)", []entity.OrderLegStatus{entity.OrderLegCompletedStatus, entity.OrderLegCanceledStatus}).
+		Updates(map[string]interface{}{"to_latitude": latitude, "to_longitude": longitude}).Error
+}
+
+// CountAvailableOrderLegsByDriverID counts the legs waiting for a driver that the driver can make
+// an offer on, see availableOrderLegs
+func (r *OrderLegRepository) CountAvailableOrderLegsByDriverID(driverID uint64) (int64, error) {
+	var count int64
+	if err := r.db.Debug().Model(&entity.OrderLeg{}).Scopes(availableOrderLegs(driverID)).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAllAvailableOrderLegsByDriverID retrieves the legs waiting for a driver that the driver can
+// make an offer on, see availableOrderLegs
+func (r *OrderLegRepository) GetAllAvailableOrderLegsByDriverID(driverID uint64, page int, perPage int) ([]entity.OrderLeg, error) {
+	var orderLegs []entity.OrderLeg
+	if err := r.db.Debug().Model(&entity.OrderLeg{}).Scopes(availableOrderLegs(driverID)).Select("order_legs.*").
+		Preload("FromHub").Preload("ToHub").Order("order_legs.created_at asc").Limit(perPage).Offset((page - 1) * perPage).Find(&orderLegs).Error; err != nil {
+		return nil, err
+	}
+	return orderLegs, nil
+}
+
+// GetAvailableOrderLegByIDAndDriverID retrieves a leg by its ID if the driver can make an offer on
+// it, see availableOrderLegs
+func (r *OrderLegRepository) GetAvailableOrderLegByIDAndDriverID(id uint64, driverID uint64) (*entity.OrderLeg, error) {
+	var orderLeg entity.OrderLeg
+	if err := r.db.Debug().Model(&entity.OrderLeg{}).Scopes(availableOrderLegs(driverID)).Select("order_legs.*").
+		Where("order_legs.id = ?", id).Preload("FromHub").Preload("ToHub").Take(&orderLeg).Error; err != nil {
+		return nil, err
+	}
+	return &orderLeg, nil
+}
+
+// CountOrderLegsByDriverIDExcludingStatus counts the legs assigned to a driver
+func (r *OrderLegRepository) CountOrderLegsByDriverIDExcludingStatus(driverID uint64, status []entity.OrderLegStatus) (int64, error) {
+	var count int64
+	if err := r.db.Debug().Model(&entity.OrderLeg{}).Where("driver_id = ?", driverID).Where("status NOT IN (?)", status).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAllOrderLegsByDriverIDExcludingStatus retrieves the legs assigned to a driver
+func (r *OrderLegRepository) GetAllOrderLegsByDriverIDExcludingStatus(driverID uint64, status []entity.OrderLegStatus, page int, perPage int) ([]entity.OrderLeg, error) {
+	var orderLegs []entity.OrderLeg
+	if err := r.db.Debug().Where("driver_id = ?", driverID).Where("status NOT IN (?)", status).
+		Preload("FromHub").Preload("ToHub").Order("created_at desc").Limit(perPage).Offset((page - 1) * perPage).Find(&orderLegs).Error; err != nil {
+		return nil, err
+	}
+	return orderLegs, nil
+}
+
+// GetAllOrderLegsByOrderID retrieves the legs of an order in the order they are carried
+func (r *OrderLegRepository) GetAllOrderLegsByOrderID(orderID uint64) ([]entity.OrderLeg, error) {
+	var orderLegs []entity.OrderLeg
+	if err := r.db.Debug().Where("order_id = ?", orderID).Preload("FromHub").Preload("ToHub").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").
+		Order("sequence asc").Find(&orderLegs).Error; err != nil {
+		return nil, err
+	}
+	return orderLegs, nil
+}
+
+// GetOrderLegByID retrieves a leg by its ID
+func (r *OrderLegRepository) GetOrderLegByID(id uint64) (*entity.OrderLeg, error) {
+	var orderLeg entity.OrderLeg
+	if err := r.db.Debug().Where("id = ?", id).Preload("FromHub").Preload("ToHub").Take(&orderLeg).Error; err != nil {
+		return nil, err
+	}
+	return &orderLeg, nil
+}
+
+// GetOrderLegByIDAndDriverID retrieves a leg assigned to a driver by its ID
+func (r *OrderLegRepository) GetOrderLegByIDAndDriverID(id uint64, driverID uint64) (*entity.OrderLeg, error) {
+	var orderLeg entity.OrderLeg
+	if err := r.db.Debug().Where("id = ?", id).Where("driver_id = ?", driverID).Preload("FromHub").Preload("ToHub").Take(&orderLeg).Error; err != nil {
+		return nil, err
+	}
+	return &orderLeg, nil
+}
+
+// availableOrderLegs keeps the legs waiting for a driver, of the orders released for dispatch,
+// that start in a city or a region the driver covers. The drivers who didn't declare the cities
+// and regions they serve are offered the legs starting around them.
+func availableOrderLegs(driverID uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Joins("JOIN orders ON orders.id = order_legs.order_id").
+			Joins("JOIN drivers ON drivers.id = ?", driverID).
+			Where("order_legs.status = ?", entity.OrderLegPendingStatus).
+			Where("orders.status NOT IN (?)", []entity.OrderStatus{entity.OrderScheduledStatus, entity.OrderCanceledStatus}).
+			Where(`(EXISTS (
+				SELECT 1 FROM driver_coverages
+				LEFT JOIN cities ON cities.id = order_legs.city_id
+				WHERE driver_coverages.driver_id = drivers.id AND (driver_coverages.city_id = order_legs.city_id OR driver_coverages.region_id = cities.region_id)
+			) OR (NOT EXISTS (SELECT 1 FROM driver_coverages WHERE driver_coverages.driver_id = drivers.id)
+				AND ST_DWithin(ST_MakePoint(drivers.longitude, drivers.latitude)::geography, ST_MakePoint(order_legs.from_longitude, order_legs.from_latitude)::geography, ?)))`, 50000)
+	}
+}
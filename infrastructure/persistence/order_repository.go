@@ -5,9 +5,14 @@ import (
 	"time"
 
 	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// orderTrackingNumberIndex is the unique index of the orders' tracking numbers
+const orderTrackingNumberIndex = "idx_orders_tracking_number"
+
 // OrderRepository implements the repository.OrderRepository interface
 type OrderRepository struct {
 	// db is a pointer to the GORM DB instance
@@ -19,22 +24,126 @@ func NewOrderRepository(db *gorm.DB) *OrderRepository {
 	return &OrderRepository{db: db}
 }
 
+// orderDropOffsBySequence preloads the drop-offs of an order in delivery order
+func orderDropOffsBySequence(db *gorm.DB) *gorm.DB {
+	return db.Order("sequence asc")
+}
+
 // CreateOrder creates a new order in the database
 func (r *OrderRepository) CreateOrder(order *entity.Order) (*entity.Order, error) {
-	if err := r.db.Debug().Model(&order).Create(&order).Error; err != nil {
+	// The order is given another tracking number when the one drawn is taken
+	err := retryTakenCode(r.db.Debug(), orderTrackingNumberIndex, func(tx *gorm.DB) error {
+		return tx.Model(&order).Create(&order).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Debug().Model(&order).Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Preload("City").Preload("DestinationCity").Preload("Legs", orderLegsBySequence).Preload("DropOffs", orderDropOffsBySequence).Take(&order).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.createOrderStatusTimeline(order.ID, order.Status); err != nil {
+		return nil, err
+	}
+
+	// Scheduled orders are dispatched by the scheduler shortly before their pickup window
+	if order.Status == entity.OrderScheduledStatus {
+		return order, nil
+	}
+
+	// The legs of an intercity order are offered to drivers one by one, the order itself isn't
+	if order.IsIntercity {
+		return order, nil
+	}
+
+	// An order offered to a preferred driver first is only dispatched to that driver
+	if order.PreferredDriverID != 0 {
+		orderDriverPool := entity.OrderDriverPool{OrderID: order.ID, DriverID: order.PreferredDriverID}
+		if err := r.db.Debug().Model(&orderDriverPool).Create(&orderDriverPool).Error; err != nil {
+			return nil, err
+		}
+
+		return order, nil
+	}
+
+	if err := r.dispatchOrder(order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// UpdateOrder updates the order
+func (r *OrderRepository) UpdateOrderByID(id uint64, order *entity.Order) (*entity.Order, error) {
+	var previousStatus entity.OrderStatus
+	if err := r.db.Debug().Table("orders").Select("status").Where("id = ?", id).Row().Scan(&previousStatus); err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Debug().Model(&order).Updates(order).Where("id = ?", id).Error; err != nil {
 		return nil, err
 	}
 
-	if err := r.db.Debug().Model(&order).Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Take(&order).Error; err != nil {
+	if order.Status != "" && order.Status != previousStatus {
+		if err := r.createOrderStatusTimeline(id, order.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// ReleaseOrderDriverPoolByID dispatches an order to every available driver nearby
+// that has not been offered the order yet
+func (r *OrderRepository) ReleaseOrderDriverPoolByID(id uint64) error {
+	var order entity.Order
+	if err := r.db.Debug().Table("orders").Where("id = ?", id).Take(&order).Error; err != nil {
+		return err
+	}
+
+	return r.dispatchOrder(&order)
+}
+
+// GetAllOrdersPreferredBefore retrieves the orders still waiting for an offer whose preferred
+// driver had them to themselves until before the given time
+func (r *OrderRepository) GetAllOrdersPreferredBefore(before time.Time) ([]entity.Order, error) {
+	var orders []entity.Order
+	if err := r.db.Debug().Where("status = ?", entity.OrderCreatedStatus).Where("preferred_until <= ?", before).Order("preferred_until asc").Find(&orders).Error; err != nil {
 		return nil, err
 	}
+	return orders, nil
+}
+
+// ReleasePreferredOrderByID dispatches an order offered to its preferred driver first to every
+// available driver nearby, and ends the turn of the preferred driver so it isn't released again
+func (r *OrderRepository) ReleasePreferredOrderByID(id uint64) error {
+	return r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Order{}).Where("id = ?", id).UpdateColumn("preferred_until", nil).Error; err != nil {
+			return err
+		}
+
+		return NewOrderRepository(tx).ReleaseOrderDriverPoolByID(id)
+	})
+}
+
+// dispatchOrder adds the available drivers serving the order's pickup point to its driver pool. The
+// drivers who declared the cities and regions they serve get the orders picked up there, the others
+// the orders picked up around them.
+func (r *OrderRepository) dispatchOrder(order *entity.Order) error {
+	if order.IsIntercity {
+		return nil
+	}
 
 	var drivers []entity.Driver
 	if err := r.db.Debug().Table("drivers").Select("drivers.*").
-		Where("ST_DWithin(ST_MakePoint(drivers.longitude, drivers.latitude)::geography, ST_MakePoint(?, ?)::geography, ?)", order.Longitude, order.Latitude, 50000).
+		Where(`(EXISTS (SELECT 1 FROM driver_coverages WHERE driver_coverages.driver_id = drivers.id AND (driver_coverages.city_id = ? OR driver_coverages.region_id = ?))
+			OR (NOT EXISTS (SELECT 1 FROM driver_coverages WHERE driver_coverages.driver_id = drivers.id)
+				AND ST_DWithin(ST_MakePoint(drivers.longitude, drivers.latitude)::geography, ST_MakePoint(?, ?)::geography, ?)))`, order.CityID, order.RegionID, order.Longitude, order.Latitude, 50000).
+		Where("drivers.id NOT IN (SELECT driver_id FROM order_driver_pools WHERE order_id = ?)", order.ID).
 		Order(fmt.Sprintf("ST_Distance(ST_MakePoint(drivers.longitude, drivers.latitude)::geography, ST_MakePoint(%.6f, %.6f)::geography)", order.Longitude, order.Latitude)).
 		Preload("User").Preload("User.Location").Find(&drivers).Error; err != nil {
-		return nil, err
+		return err
 	}
 
 	for _, driver := range drivers {
@@ -59,18 +168,60 @@ func (r *OrderRepository) CreateOrder(order *entity.Order) (*entity.Order, error
 		}
 	}
 
-	return order, nil
+	return nil
 }
 
-// UpdateOrder updates the order
-func (r *OrderRepository) UpdateOrderByID(id uint64, order *entity.Order) (*entity.Order, error) {
-	if err := r.db.Debug().Model(&order).Updates(order).Where("id = ?", id).Error; err != nil {
-		return nil, err
+// UpdateOrderByIDAndStatus updates the order only if it's still in the given status, as a compare
+// and set. repository.ErrConflict is returned when another request changed the status first.
+func (r *OrderRepository) UpdateOrderByIDAndStatus(id uint64, status entity.OrderStatus, order *entity.Order) (*entity.Order, error) {
+	result := r.db.Debug().Model(&entity.Order{}).Where("id = ?", id).Where("status = ?", status).Omit(clause.Associations).Updates(order)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, repository.ErrConflict
+	}
+
+	if order.Status != "" && order.Status != status {
+		if err := r.createOrderStatusTimeline(id, order.Status); err != nil {
+			return nil, err
+		}
 	}
 
 	return order, nil
 }
 
+// createOrderStatusTimeline records a status change in the order's timeline, and emits it as an
+// event for the webhooks of the sender
+func (r *OrderRepository) createOrderStatusTimeline(orderID uint64, status entity.OrderStatus) error {
+	orderTimeline := entity.OrderTimeline{
+		OrderID: orderID,
+		Event:   entity.OrderStatusChangedEvent,
+		Status:  status,
+	}
+
+	if err := r.db.Debug().Model(&orderTimeline).Create(&orderTimeline).Error; err != nil {
+		return err
+	}
+
+	// The order given to the updates may be partial, so the parties are read back
+	var payload entity.OrderEventPayload
+	if err := r.db.Debug().Table("orders").
+		Select("orders.id AS order_id, orders.user_id, COALESCE(orders.driver_id, 0) AS driver_id, COALESCE(drivers.user_id, 0) AS driver_user_id, COALESCE(orders.recipient_id, 0) AS recipient_id").
+		Joins("LEFT JOIN drivers ON drivers.id = orders.driver_id").
+		Where("orders.id = ?", orderID).Scan(&payload).Error; err != nil {
+		return err
+	}
+	payload.Status = status
+
+	event, err := entity.NewOrderEventFromPayload(entity.OrderStatusUpdatedEvent, &payload)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Debug().Create(event).Error
+}
+
 // UpdateOrderDriverPool updates the order driver pool
 func (r *OrderRepository) UpdateOrderDriverPoolByOrderIDAndDriverID(orderID uint64, driverID uint64, orderDriverPool *entity.OrderDriverPool) (*entity.OrderDriverPool, error) {
 	if err := r.db.Debug().Model(&orderDriverPool).Where("order_id = ?", orderID).Where("driver_id = ?", driverID).Updates(orderDriverPool).Error; err != nil {
@@ -106,7 +257,25 @@ func (r *OrderRepository) CountOrdersByDriverIDExcludingStatus(driverID uint64,
 
 func (r *OrderRepository) GetAllOrdersByUserIDExcludingStatus(userID uint64, status []entity.OrderStatus, page int, perPage int) ([]entity.Order, error) {
 	var orders []entity.Order
-	if err := r.db.Debug().Where("user_id = ?", userID).Where("status NOT IN (?)", status).Model(&entity.Order{}).Limit(perPage).Offset((page - 1) * perPage).Order("created_at desc").Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Find(&orders).Error; err != nil {
+	if err := r.db.Debug().Where("user_id = ?", userID).Where("status NOT IN (?)", status).Model(&entity.Order{}).Limit(perPage).Offset((page - 1) * perPage).Order("created_at desc").Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Preload("City").Preload("DestinationCity").Preload("Legs", orderLegsBySequence).Preload("DropOffs", orderDropOffsBySequence).Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// CountOrdersByOrganizationID counts the orders created for an organization
+func (r *OrderRepository) CountOrdersByOrganizationID(organizationID uint64) (int64, error) {
+	var count int64
+	if err := r.db.Debug().Where("organization_id = ?", organizationID).Model(&entity.Order{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAllOrdersByOrganizationID retrieves a paginated list of the orders created for an organization
+func (r *OrderRepository) GetAllOrdersByOrganizationID(organizationID uint64, page int, perPage int) ([]entity.Order, error) {
+	var orders []entity.Order
+	if err := r.db.Debug().Where("organization_id = ?", organizationID).Model(&entity.Order{}).Limit(perPage).Offset((page - 1) * perPage).Order("created_at desc").Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Preload("City").Preload("DestinationCity").Preload("Legs", orderLegsBySequence).Preload("DropOffs", orderDropOffsBySequence).Find(&orders).Error; err != nil {
 		return nil, err
 	}
 	return orders, nil
@@ -114,7 +283,7 @@ func (r *OrderRepository) GetAllOrdersByUserIDExcludingStatus(userID uint64, sta
 
 func (r *OrderRepository) GetAllOrdersByUserIDAndRecipientIDExcludingStatus(userID uint64, recipientID uint64, status []entity.OrderStatus, page int, perPage int) ([]entity.Order, error) {
 	var orders []entity.Order
-	if err := r.db.Debug().Where("(user_id = ? OR recipient_id = ?) AND status NOT IN (?)", userID, recipientID, status).Model(&entity.Order{}).Limit(perPage).Offset((page - 1) * perPage).Order("created_at desc").Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Find(&orders).Error; err != nil {
+	if err := r.db.Debug().Where("(user_id = ? OR recipient_id = ?) AND status NOT IN (?)", userID, recipientID, status).Model(&entity.Order{}).Limit(perPage).Offset((page - 1) * perPage).Order("created_at desc").Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Preload("City").Preload("DestinationCity").Preload("Legs", orderLegsBySequence).Preload("DropOffs", orderDropOffsBySequence).Find(&orders).Error; err != nil {
 		return nil, err
 	}
 	return orders, nil
@@ -122,7 +291,7 @@ func (r *OrderRepository) GetAllOrdersByUserIDAndRecipientIDExcludingStatus(user
 
 func (r *OrderRepository) GetAllOrdersByDriverIDExcludingStatus(driverID uint64, status []entity.OrderStatus, page int, perPage int) ([]entity.Order, error) {
 	var orders []entity.Order
-	if err := r.db.Debug().Where("driver_id = ?", driverID).Where("status NOT IN (?)", status).Model(&entity.Order{}).Limit(perPage).Offset((page - 1) * perPage).Order("created_at desc").Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Find(&orders).Error; err != nil {
+	if err := r.db.Debug().Where("driver_id = ?", driverID).Where("status NOT IN (?)", status).Model(&entity.Order{}).Limit(perPage).Offset((page - 1) * perPage).Order("created_at desc").Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Preload("City").Preload("DestinationCity").Preload("Legs", orderLegsBySequence).Preload("DropOffs", orderDropOffsBySequence).Find(&orders).Error; err != nil {
 		return nil, err
 	}
 	return orders, nil
@@ -130,7 +299,37 @@ func (r *OrderRepository) GetAllOrdersByDriverIDExcludingStatus(driverID uint64,
 
 func (r *OrderRepository) GetAllOrdersByRecipientPhoneNumber(recipientPhoneNumber string) ([]entity.Order, error) {
 	var orders []entity.Order
-	if err := r.db.Debug().Where("recipient_phone_number = ?", recipientPhoneNumber).Model(&entity.Order{}).Order("created_at desc").Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Find(&orders).Error; err != nil {
+	if err := r.db.Debug().Where("recipient_phone_number = ?", recipientPhoneNumber).Model(&entity.Order{}).Order("created_at desc").Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Preload("City").Preload("DestinationCity").Preload("Legs", orderLegsBySequence).Preload("DropOffs", orderDropOffsBySequence).Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetAllScheduledOrdersDueBefore retrieves the scheduled orders whose pickup window starts before the given time
+func (r *OrderRepository) GetAllScheduledOrdersDueBefore(before time.Time) ([]entity.Order, error) {
+	var orders []entity.Order
+	if err := r.db.Debug().Where("status = ?", entity.OrderScheduledStatus).Where("pickup_window_start <= ?", before).Order("pickup_window_start asc").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetAllOrdersDeliveredBefore retrieves the delivered orders that aren't completed yet and were
+// delivered before the given time. The orders delivered before the delivery time was tracked fall
+// back to their last update.
+func (r *OrderRepository) GetAllOrdersDeliveredBefore(before time.Time) ([]entity.Order, error) {
+	var orders []entity.Order
+	if err := r.db.Debug().Where("status = ?", entity.ShipmentDeliveredStatus).Where("COALESCE(delivered_at, updated_at) <= ?", before).Order("id asc").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetAllOrdersByStatusAfterID retrieves the orders in the given statuses with an ID greater than
+// afterID, so all of them can be walked through in batches
+func (r *OrderRepository) GetAllOrdersByStatusAfterID(status []entity.OrderStatus, afterID uint64, limit int) ([]entity.Order, error) {
+	var orders []entity.Order
+	if err := r.db.Debug().Where("status IN ?", status).Where("id > ?", afterID).Preload("Driver").Order("id asc").Limit(limit).Find(&orders).Error; err != nil {
 		return nil, err
 	}
 	return orders, nil
@@ -143,7 +342,6 @@ func (r *OrderRepository) CountDriverPoolsByDriverIDAndCategoryID(driverID uint6
 		Joins("JOIN drivers ON order_driver_pools.driver_id = drivers.id").
 		Joins("JOIN delivery_times ON orders.delivery_time_id = delivery_times.id").
 		Joins("JOIN locations AS source ON orders.location_id = source.id").
-		Joins("JOIN locations AS destination ON orders.destination_id = destination.id").
 		Where("order_driver_pools.driver_id = ?", driverID).
 		Where("order_driver_pools.status = ?", entity.PendingStatus).
 		Where("orders.category_id = ?", categoryID)
@@ -176,7 +374,6 @@ func (r *OrderRepository) GetAllDriverPoolsByDriverIDAndCategoryID(driverID uint
 		Joins("JOIN drivers ON order_driver_pools.driver_id = drivers.id").
 		Joins("JOIN delivery_times ON orders.delivery_time_id = delivery_times.id").
 		Joins("JOIN locations AS source ON orders.location_id = source.id").
-		Joins("JOIN locations AS destination ON orders.destination_id = destination.id").
 		Where("order_driver_pools.driver_id = ?", driverID).
 		Where("order_driver_pools.status = ?", entity.PendingStatus).
 		Where("orders.category_id = ?", categoryID)
@@ -185,8 +382,8 @@ func (r *OrderRepository) GetAllDriverPoolsByDriverIDAndCategoryID(driverID uint
 	if orderBy != nil && (*orderBy == "distance" || *orderBy == "arrival" || *orderBy == "created_at") {
 		switch *orderBy {
 		case "distance":
-			// Include sorting logic based on distance
-			db = db.Order("SQRT(POW(orders.latitude - destination.latitude, 2) + POW(orders.longitude - destination.longitude, 2)) ASC")
+			// Include sorting logic based on the road distance of the order, in meters
+			db = db.Order("COALESCE(orders.route_distance, ST_Distance(ST_MakePoint(orders.longitude, orders.latitude)::geography, ST_MakePoint(orders.destination_longitude, orders.destination_latitude)::geography)) ASC")
 		case "arrival":
 			// Include sorting logic based on expected arrival time
 			db = db.Order("(current_timestamp + (delivery_times.duration * interval '1 second')) ASC")
@@ -225,7 +422,7 @@ func (r *OrderRepository) GetAllDriverPoolsByDriverIDAndCategoryID(driverID uint
 		Preload("DeliveryTime").
 		Preload("ShipmentContents").
 		Preload("ExtraServices").
-		Preload("Destination")
+		Preload("Destination").Preload("City").Preload("DestinationCity").Preload("Legs", orderLegsBySequence).Preload("DropOffs", orderDropOffsBySequence)
 
 	var orders []entity.Order
 	err := db.Find(&orders).Error
@@ -241,7 +438,7 @@ func (r *OrderRepository) GetOrderByID(id uint64) (*entity.Order, error) {
 	// Order struct to store the retrieved order data
 	var order entity.Order
 	// Find the order by its ID and store the data in the order struct
-	if err := r.db.Debug().Where("id = ?", id).Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Take(&order).Error; err != nil {
+	if err := r.db.Debug().Where("id = ?", id).Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Preload("City").Preload("DestinationCity").Preload("Legs", orderLegsBySequence).Preload("DropOffs", orderDropOffsBySequence).Take(&order).Error; err != nil {
 		// If there's an error, return nil and the error
 		return nil, err
 	}
@@ -253,7 +450,7 @@ func (r *OrderRepository) GetOrderByIDAndUserID(id uint64, userID uint64) (*enti
 	// Order struct to store the retrieved order data
 	var order entity.Order
 	// Find the order by its ID and store the data in the order struct
-	if err := r.db.Debug().Where("id = ?", id).Where("user_id = ?", userID).Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Take(&order).Error; err != nil {
+	if err := r.db.Debug().Where("id = ?", id).Where("user_id = ?", userID).Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Preload("City").Preload("DestinationCity").Preload("Legs", orderLegsBySequence).Preload("DropOffs", orderDropOffsBySequence).Take(&order).Error; err != nil {
 		// If there's an error, return nil and the error
 		return nil, err
 	}
@@ -265,7 +462,7 @@ func (r *OrderRepository) GetOrderByIDAndRecipientID(id uint64, recipientID uint
 	// Order struct to store the retrieved order data
 	var order entity.Order
 	// Find the order by its ID and store the data in the order struct
-	if err := r.db.Debug().Where("id = ?", id).Where("recipient_id = ?", recipientID).Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Take(&order).Error; err != nil {
+	if err := r.db.Debug().Where("id = ?", id).Where("recipient_id = ?", recipientID).Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Preload("City").Preload("DestinationCity").Preload("Legs", orderLegsBySequence).Preload("DropOffs", orderDropOffsBySequence).Take(&order).Error; err != nil {
 		// If there's an error, return nil and the error
 		return nil, err
 	}
@@ -277,7 +474,7 @@ func (r *OrderRepository) GetOrderByIDAndDriverID(id uint64, driverID uint64) (*
 	// Order struct to store the retrieved order data
 	var order entity.Order
 	// Find the order by its ID and store the data in the order struct
-	if err := r.db.Debug().Where("id = ?", id).Where("driver_id = ?", driverID).Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Take(&order).Error; err != nil {
+	if err := r.db.Debug().Where("id = ?", id).Where("driver_id = ?", driverID).Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Preload("City").Preload("DestinationCity").Preload("Legs", orderLegsBySequence).Preload("DropOffs", orderDropOffsBySequence).Take(&order).Error; err != nil {
 		// If there's an error, return nil and the error
 		return nil, err
 	}
@@ -285,6 +482,30 @@ func (r *OrderRepository) GetOrderByIDAndDriverID(id uint64, driverID uint64) (*
 	return &order, nil
 }
 
+// GetOrderByTrackingNumber retrieves the order with the given tracking number
+func (r *OrderRepository) GetOrderByTrackingNumber(trackingNumber string) (*entity.Order, error) {
+	var order entity.Order
+	if err := r.db.Debug().Where("tracking_number = ?", trackingNumber).Preload("Location").Preload("User").Preload("User.Location").Preload("Driver").Preload("Driver.User").Preload("Driver.User.Location").Preload("Driver.TransportationMode").Preload("Recipient").Preload("Recipient.Location").Preload("Category").Preload("Size").Preload("DeliveryTime").Preload("ShipmentContents").Preload("ExtraServices").Preload("Destination").Preload("City").Preload("DestinationCity").Preload("Legs", orderLegsBySequence).Preload("DropOffs", orderDropOffsBySequence).Take(&order).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetOrderByShareToken retrieves the order shared through the tracking link with the given token
+func (r *OrderRepository) GetOrderByShareToken(shareToken string) (*entity.Order, error) {
+	var order entity.Order
+	if err := r.db.Debug().Where("share_token = ?", shareToken).Preload("Driver").Preload("Driver.User").Preload("Driver.TransportationMode").Preload("City").Preload("DestinationCity").Preload("Legs", orderLegsBySequence).Preload("DropOffs", orderDropOffsBySequence).Take(&order).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// UpdateOrderShareTokenByID replaces the token of the tracking link of an order, a nil token
+// revokes the link
+func (r *OrderRepository) UpdateOrderShareTokenByID(id uint64, shareToken *string) error {
+	return r.db.Debug().Model(&entity.Order{}).Where("id = ?", id).Update("share_token", shareToken).Error
+}
+
 func (r *OrderRepository) GetOrderDriverPoolByOrderIDAndDriverID(orderID uint64, driverID uint64) (*entity.OrderDriverPool, error) {
 	// Order driver pool struct to store the retrieved order driver pool data
 	var orderDriverPool entity.OrderDriverPool
@@ -295,3 +516,46 @@ func (r *OrderRepository) GetOrderDriverPoolByOrderIDAndDriverID(orderID uint64,
 	// return the order driver pool data and nil error
 	return &orderDriverPool, nil
 }
+
+// BackfillOrderDestinations copies the coordinates of the destination locations to the orders and
+// recurring orders placed before the destinations had their own coordinates.
+func (r *OrderRepository) BackfillOrderDestinations() error {
+	return r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		for _, table := range []string{"orders", "recurring_orders"} {
+			if err := tx.Exec(fmt.Sprintf(`
+				UPDATE %[1]s SET destination_latitude = locations.latitude, destination_longitude = locations.longitude
+				FROM locations
+				WHERE locations.id = %[1]s.destination_id AND %[1]s.destination_latitude IS NULL
+			`, table)).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// BackfillOrderTrackingNumbers gives a tracking number drawn by NewTrackingNumber to the orders
+// placed before they had one, drawing another one when it's taken.
+func (r *OrderRepository) BackfillOrderTrackingNumbers() error {
+	var orderIDs []uint64
+	if err := r.db.Debug().Model(&entity.Order{}).Where("tracking_number IS NULL").Order("id asc").Pluck("id", &orderIDs).Error; err != nil {
+		return err
+	}
+
+	for _, orderID := range orderIDs {
+		err := retryTakenCode(r.db.Debug(), orderTrackingNumberIndex, func(tx *gorm.DB) error {
+			trackingNumber, err := entity.NewTrackingNumber()
+			if err != nil {
+				return err
+			}
+
+			return tx.Model(&entity.Order{}).Where("id = ?", orderID).Where("tracking_number IS NULL").UpdateColumn("tracking_number", trackingNumber).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package persistence
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+)
+
+// OrderTimelineRepository implements the repository.OrderTimelineRepository interface
+type OrderTimelineRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewOrderTimelineRepository creates a new instance of the OrderTimelineRepository
+func NewOrderTimelineRepository(db *gorm.DB) *OrderTimelineRepository {
+	return &OrderTimelineRepository{db: db}
+}
+
+// CreateOrderTimeline creates a new order timeline entry in the database
+func (r *OrderTimelineRepository) CreateOrderTimeline(orderTimeline *entity.OrderTimeline) (*entity.OrderTimeline, error) {
+	if err := r.db.Debug().Model(&orderTimeline).Create(&orderTimeline).Error; err != nil {
+		return nil, err
+	}
+
+	return orderTimeline, nil
+}
+
+// GetAllOrderTimelinesByOrderID retrieves the timeline of an order ordered from oldest to newest
+func (r *OrderTimelineRepository) GetAllOrderTimelinesByOrderID(orderID uint64) ([]entity.OrderTimeline, error) {
+	var orderTimelines []entity.OrderTimeline
+	if err := r.db.Debug().Where("order_id = ?", orderID).Preload("Scan").Preload("Scan.Hub").Order("created_at asc").Order("id asc").Find(&orderTimelines).Error; err != nil {
+		return nil, err
+	}
+	return orderTimelines, nil
+}
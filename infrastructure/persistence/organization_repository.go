@@ -0,0 +1,117 @@
+package persistence
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrganizationRepository implements the repository.OrganizationRepository interface
+type OrganizationRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewOrganizationRepository creates a new instance of the OrganizationRepository
+func NewOrganizationRepository(db *gorm.DB) *OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+// CreateOrganization creates a new organization in the database along with its owner
+func (r *OrganizationRepository) CreateOrganization(organization *entity.Organization, ownerID uint64) (*entity.Organization, error) {
+	err := r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(organization).Error; err != nil {
+			return err
+		}
+
+		owner := entity.OrganizationMember{
+			OrganizationID: organization.ID,
+			UserID:         ownerID,
+			Role:           entity.OrganizationOwnerRole,
+		}
+
+		return tx.Omit(clause.Associations).Create(&owner).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return organization, nil
+}
+
+// UpdateOrganizationByID updates the organization
+func (r *OrganizationRepository) UpdateOrganizationByID(id uint64, organization *entity.Organization) (*entity.Organization, error) {
+	if err := r.db.Debug().Model(&entity.Organization{}).Where("id = ?", id).Select("name", "commercial_registration", "vat_number", "updated_at").Updates(organization).Error; err != nil {
+		return nil, err
+	}
+
+	return organization, nil
+}
+
+// GetOrganizationByID retrieves an organization by its ID
+func (r *OrganizationRepository) GetOrganizationByID(id uint64) (*entity.Organization, error) {
+	var organization entity.Organization
+	if err := r.db.Debug().Where("id = ?", id).Take(&organization).Error; err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+// GetAllOrganizationsByUserID retrieves the organizations a user is a member of
+func (r *OrganizationRepository) GetAllOrganizationsByUserID(userID uint64) ([]entity.Organization, error) {
+	var organizations []entity.Organization
+	if err := r.db.Debug().Where("id IN (SELECT organization_id FROM organization_members WHERE user_id = ?)", userID).Order("id asc").Find(&organizations).Error; err != nil {
+		return nil, err
+	}
+	return organizations, nil
+}
+
+// CreateOrganizationMember adds a user to an organization
+func (r *OrganizationRepository) CreateOrganizationMember(organizationMember *entity.OrganizationMember) (*entity.OrganizationMember, error) {
+	if err := r.db.Debug().Omit(clause.Associations).Create(organizationMember).Error; err != nil {
+		return nil, err
+	}
+
+	return organizationMember, nil
+}
+
+// UpdateOrganizationMemberByID updates the role of an organization member
+func (r *OrganizationRepository) UpdateOrganizationMemberByID(id uint64, organizationMember *entity.OrganizationMember) (*entity.OrganizationMember, error) {
+	if err := r.db.Debug().Model(&entity.OrganizationMember{}).Where("id = ?", id).Select("role").Updates(organizationMember).Error; err != nil {
+		return nil, err
+	}
+
+	return organizationMember, nil
+}
+
+// DeleteOrganizationMemberByID removes a user from an organization
+func (r *OrganizationRepository) DeleteOrganizationMemberByID(id uint64) error {
+	return r.db.Debug().Where("id = ?", id).Delete(&entity.OrganizationMember{}).Error
+}
+
+// GetOrganizationMemberByOrganizationIDAndUserID retrieves the membership of a user in an organization
+func (r *OrganizationRepository) GetOrganizationMemberByOrganizationIDAndUserID(organizationID uint64, userID uint64) (*entity.OrganizationMember, error) {
+	var organizationMember entity.OrganizationMember
+	if err := r.db.Debug().Where("organization_id = ?", organizationID).Where("user_id = ?", userID).Preload("Organization").Preload("User").Preload("User.Location").Take(&organizationMember).Error; err != nil {
+		return nil, err
+	}
+	return &organizationMember, nil
+}
+
+// GetAllOrganizationMembersByOrganizationID retrieves the members of an organization
+func (r *OrganizationRepository) GetAllOrganizationMembersByOrganizationID(organizationID uint64) ([]entity.OrganizationMember, error) {
+	var organizationMembers []entity.OrganizationMember
+	if err := r.db.Debug().Where("organization_id = ?", organizationID).Order("id asc").Preload("User").Preload("User.Location").Find(&organizationMembers).Error; err != nil {
+		return nil, err
+	}
+	return organizationMembers, nil
+}
+
+// CountOrganizationMembersByOrganizationIDAndRole counts the members of an organization with a role
+func (r *OrganizationRepository) CountOrganizationMembersByOrganizationIDAndRole(organizationID uint64, role entity.OrganizationRole) (int64, error) {
+	var count int64
+	if err := r.db.Debug().Model(&entity.OrganizationMember{}).Where("organization_id = ?", organizationID).Where("role = ?", role).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
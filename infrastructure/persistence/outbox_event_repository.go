@@ -0,0 +1,54 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OutboxEventRepository implements the repository.OutboxEventRepository interface
+type OutboxEventRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewOutboxEventRepository creates a new instance of the OutboxEventRepository
+func NewOutboxEventRepository(db *gorm.DB) *OutboxEventRepository {
+	return &OutboxEventRepository{db: db}
+}
+
+// CreateOutboxEvent stores a domain event to be dispatched
+func (r *OutboxEventRepository) CreateOutboxEvent(event *entity.OutboxEvent) (*entity.OutboxEvent, error) {
+	if err := r.db.Debug().Create(event).Error; err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// UpdateOutboxEventByID updates the delivery state of a domain event
+func (r *OutboxEventRepository) UpdateOutboxEventByID(id uint64, event *entity.OutboxEvent) (*entity.OutboxEvent, error) {
+	if err := r.db.Debug().Model(&entity.OutboxEvent{}).Where("id = ?", id).Select("status", "attempts", "handled_by", "last_error", "next_attempt_at", "dispatched_at").Updates(event).Error; err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// GetAllDueOutboxEvents retrieves the oldest pending event of each aggregate when it's due for
+// delivery, in the order they were emitted. The later events of an aggregate wait until the ones
+// before them are delivered or given up on. The events are locked until the end of the transaction,
+// the ones locked by another dispatcher are skipped.
+func (r *OutboxEventRepository) GetAllDueOutboxEvents(now time.Time, limit int) ([]entity.OutboxEvent, error) {
+	var events []entity.OutboxEvent
+	if err := r.db.Debug().Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ?", entity.OutboxEventPendingStatus).
+		Where("next_attempt_at <= ?", now).
+		Where("NOT EXISTS (SELECT 1 FROM outbox AS earlier WHERE earlier.aggregate_id = outbox.aggregate_id AND earlier.status = ? AND earlier.id < outbox.id)", entity.OutboxEventPendingStatus).
+		Order("id asc").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,155 @@
+package persistence
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+	"gorm.io/gorm"
+)
+
+// PromotionRepository implements the repository.PromotionRepository interface
+type PromotionRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewPromotionRepository creates a new instance of the PromotionRepository
+func NewPromotionRepository(db *gorm.DB) *PromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+// CreatePromotion creates a new promotion in the database
+func (r *PromotionRepository) CreatePromotion(promotion *entity.Promotion) (*entity.Promotion, error) {
+	promotion.Code = strings.ToUpper(promotion.Code)
+
+	if err := r.db.Debug().Model(&promotion).Create(&promotion).Error; err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+// UpdatePromotionByID updates the promotion
+func (r *PromotionRepository) UpdatePromotionByID(id uint64, promotion *entity.Promotion) (*entity.Promotion, error) {
+	if err := r.db.Debug().Model(&entity.Promotion{}).Where("id = ?", id).Select("*").Omit("id", "code", "redemptions_count", "created_at").Updates(promotion).Error; err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+// GetAllPromotions retrieves all the promotions
+func (r *PromotionRepository) GetAllPromotions() ([]entity.Promotion, error) {
+	var promotions []entity.Promotion
+	if err := r.db.Debug().Order("created_at desc").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// GetPromotionByID retrieves a promotion by its ID
+func (r *PromotionRepository) GetPromotionByID(id uint64) (*entity.Promotion, error) {
+	var promotion entity.Promotion
+	if err := r.db.Debug().Where("id = ?", id).Take(&promotion).Error; err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// GetPromotionByCode retrieves a promotion by its code, ignoring case
+func (r *PromotionRepository) GetPromotionByCode(code string) (*entity.Promotion, error) {
+	var promotion entity.Promotion
+	if err := r.db.Debug().Where("code = ?", strings.ToUpper(code)).Take(&promotion).Error; err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// RedeemPromotion records the use of a promotion on an order, within its usage limits. The count
+// of redemptions is only raised while under the global limit, which locks the promotion until the
+// end of the transaction, so the redemptions of the user are counted without racing another one.
+func (r *PromotionRepository) RedeemPromotion(promotionRedemption *entity.PromotionRedemption) (*entity.PromotionRedemption, error) {
+	result := r.db.Debug().Model(&entity.Promotion{}).
+		Where("id = ?", promotionRedemption.PromotionID).
+		Where("global_usage_limit IS NULL OR redemptions_count < global_usage_limit").
+		UpdateColumn("redemptions_count", gorm.Expr("redemptions_count + 1"))
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, repository.ErrPromotionUsageLimit
+	}
+
+	var promotion entity.Promotion
+	if err := r.db.Debug().Select("id", "per_user_usage_limit").Where("id = ?", promotionRedemption.PromotionID).Take(&promotion).Error; err != nil {
+		return nil, err
+	}
+
+	if promotion.PerUserUsageLimit != nil {
+		count, err := r.CountPromotionRedemptionsByPromotionIDAndUserID(promotionRedemption.PromotionID, promotionRedemption.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		if count >= *promotion.PerUserUsageLimit {
+			return nil, repository.ErrPromotionUserUsageLimit
+		}
+	}
+
+	if err := r.db.Debug().Model(&promotionRedemption).Create(&promotionRedemption).Error; err != nil {
+		return nil, err
+	}
+
+	return promotionRedemption, nil
+}
+
+// ReleasePromotionRedemptionByOrderID gives back the use of the promotion redeemed on an order, as
+// when the order is canceled. Orders without a redemption are left as they are.
+func (r *PromotionRepository) ReleasePromotionRedemptionByOrderID(orderID uint64) error {
+	var promotionRedemption entity.PromotionRedemption
+	err := r.db.Debug().Where("order_id = ?", orderID).Take(&promotionRedemption).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := r.db.Debug().Delete(&promotionRedemption).Error; err != nil {
+		return err
+	}
+
+	return r.db.Debug().Model(&entity.Promotion{}).
+		Where("id = ?", promotionRedemption.PromotionID).
+		UpdateColumn("redemptions_count", gorm.Expr("GREATEST(redemptions_count - 1, 0)")).Error
+}
+
+// CountPromotionRedemptionsByPromotionID counts the uses of a promotion
+func (r *PromotionRepository) CountPromotionRedemptionsByPromotionID(promotionID uint64) (int64, error) {
+	var count int64
+	if err := r.db.Debug().Model(&entity.PromotionRedemption{}).Where("promotion_id = ?", promotionID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountPromotionRedemptionsByPromotionIDAndUserID counts the uses of a promotion by a user
+func (r *PromotionRepository) CountPromotionRedemptionsByPromotionIDAndUserID(promotionID uint64, userID uint64) (int64, error) {
+	var count int64
+	if err := r.db.Debug().Model(&entity.PromotionRedemption{}).Where("promotion_id = ?", promotionID).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// BackfillPromotionRedemptionsCounts counts the redemptions of the promotions redeemed before their
+// count was kept
+func (r *PromotionRepository) BackfillPromotionRedemptionsCounts() error {
+	return r.db.Debug().Exec(`
+		UPDATE promotions SET redemptions_count = counts.count
+		FROM (SELECT promotion_id, COUNT(*) AS count FROM promotion_redemptions GROUP BY promotion_id) AS counts
+		WHERE counts.promotion_id = promotions.id AND promotions.redemptions_count = 0
+	`).Error
+}
@@ -2,9 +2,13 @@ package persistence
 
 import (
 	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
 	"gorm.io/gorm"
 )
 
+// ratingOrderIndex is the unique index allowing a single rating of each type on an order
+const ratingOrderIndex = "idx_ratings_order_id_type"
+
 // RatingRepository implements repository.RatingRepository
 // and handles CRUD operations for Rating entities
 type RatingRepository struct {
@@ -16,14 +20,168 @@ func NewRatingRepository(db *gorm.DB) *RatingRepository {
 	return &RatingRepository{db}
 }
 
-// CreateRating creates a new rating in the database
+// CreateRating creates a new rating in the database and refreshes the rating aggregates of the rated user.
+// repository.ErrConflict is returned when the order was already rated by the same party.
 func (r *RatingRepository) CreateRating(rating *entity.Rating) (*entity.Rating, error) {
-	if err := r.db.Debug().Model(&rating).Create(&rating).Error; err != nil {
+	err := r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("User").Create(rating).Error; err != nil {
+			if isUniqueViolation(err, ratingOrderIndex) {
+				return repository.ErrConflict
+			}
+			return err
+		}
+
+		return refreshRatingAggregates(tx, rating.RatedUserID, rating.Type)
+	})
+	if err != nil {
 		return nil, err
 	}
+
 	if err := r.db.Debug().Model(&rating).Take(&rating).Error; err != nil {
 		return nil, err
 	}
 
 	return rating, nil
 }
+
+// GetRatingByOrderIDAndType retrieves the rating of a type given on an order
+func (r *RatingRepository) GetRatingByOrderIDAndType(orderID uint64, ratingType entity.RatingType) (*entity.Rating, error) {
+	var rating entity.Rating
+	if err := r.db.Debug().Where("order_id = ?", orderID).Where("type = ?", ratingType).Take(&rating).Error; err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+// CountRatingsByRatedUserIDAndType counts the ratings of a type received by a user
+func (r *RatingRepository) CountRatingsByRatedUserIDAndType(ratedUserID uint64, ratingType entity.RatingType) (int64, error) {
+	var count int64
+	if err := r.db.Debug().Model(&entity.Rating{}).Where("rated_user_id = ?", ratedUserID).Where("type = ?", ratingType).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAllRatingsByRatedUserIDAndType retrieves the ratings of a type received by a user, newest first
+func (r *RatingRepository) GetAllRatingsByRatedUserIDAndType(ratedUserID uint64, ratingType entity.RatingType, page int, perPage int) ([]entity.Rating, error) {
+	var ratings []entity.Rating
+	if err := r.db.Debug().Where("rated_user_id = ?", ratedUserID).Where("type = ?", ratingType).Order("created_at desc").Order("id desc").Limit(perPage).Offset((page - 1) * perPage).Preload("User").Find(&ratings).Error; err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+// BackfillRatings links the ratings given before two-sided ratings existed, which are all the
+// senders' ratings of their drivers, to the rated drivers and materializes their aggregates.
+func (r *RatingRepository) BackfillRatings() error {
+	return r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		var ratedUserIDs []uint64
+		if err := tx.Raw(`
+			UPDATE ratings SET type = ?, rated_user_id = drivers.user_id
+			FROM orders JOIN drivers ON drivers.id = orders.driver_id
+			WHERE orders.id = ratings.order_id AND (ratings.type IS NULL OR ratings.type = '')
+			RETURNING ratings.rated_user_id
+		`, entity.DriverRatingType).Scan(&ratedUserIDs).Error; err != nil {
+			return err
+		}
+
+		refreshed := make(map[uint64]bool)
+		for _, ratedUserID := range ratedUserIDs {
+			if refreshed[ratedUserID] {
+				continue
+			}
+			refreshed[ratedUserID] = true
+
+			if err := refreshRatingAggregates(tx, ratedUserID, entity.DriverRatingType); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// MigrateUniqueRatings makes the index of the ratings on their order and type unique in the
+// databases created before it was. The ratings given again by the same party on an order are
+// dropped, the first one is kept.
+func (r *RatingRepository) MigrateUniqueRatings() error {
+	return r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		var unique bool
+		if err := tx.Raw(`
+			SELECT pg_index.indisunique FROM pg_index
+			JOIN pg_class ON pg_class.oid = pg_index.indexrelid
+			WHERE pg_class.relname = ?
+		`, ratingOrderIndex).Scan(&unique).Error; err != nil {
+			return err
+		}
+		if unique {
+			return nil
+		}
+
+		var duplicates []struct {
+			RatedUserID uint64
+			Type        entity.RatingType
+		}
+		if err := tx.Raw(`
+			DELETE FROM ratings USING ratings AS earlier
+			WHERE earlier.order_id = ratings.order_id AND earlier.type = ratings.type AND earlier.id < ratings.id
+			RETURNING ratings.rated_user_id, ratings.type
+		`).Scan(&duplicates).Error; err != nil {
+			return err
+		}
+
+		refreshed := make(map[uint64]map[entity.RatingType]bool)
+		for _, duplicate := range duplicates {
+			if refreshed[duplicate.RatedUserID][duplicate.Type] {
+				continue
+			}
+			if refreshed[duplicate.RatedUserID] == nil {
+				refreshed[duplicate.RatedUserID] = make(map[entity.RatingType]bool)
+			}
+			refreshed[duplicate.RatedUserID][duplicate.Type] = true
+
+			if err := refreshRatingAggregates(tx, duplicate.RatedUserID, duplicate.Type); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec("DROP INDEX IF EXISTS " + ratingOrderIndex).Error; err != nil {
+			return err
+		}
+
+		return tx.Migrator().CreateIndex(&entity.Rating{}, ratingOrderIndex)
+	})
+}
+
+// refreshRatingAggregates stores the average scores and the number of ratings of a type received by a user
+func refreshRatingAggregates(tx *gorm.DB, ratedUserID uint64, ratingType entity.RatingType) error {
+	if ratingType == entity.CustomerRatingType {
+		return tx.Exec(`
+			UPDATE users SET customer_ratings_avg_score = COALESCE(aggregates.score, 0), customer_reviews_count = aggregates.count
+			FROM (
+				SELECT LEAST(ROUND(AVG(score)::numeric, 1), 5) AS score, COUNT(id) AS count
+				FROM ratings WHERE rated_user_id = ? AND type = ?
+			) AS aggregates
+			WHERE users.id = ?
+		`, ratedUserID, ratingType, ratedUserID).Error
+	}
+
+	return tx.Exec(`
+		UPDATE users SET
+			ratings_avg_score = COALESCE(aggregates.score, 0),
+			fast_ratings_avg_score = COALESCE(aggregates.fast, 0),
+			experience_ratings_avg_score = COALESCE(aggregates.experience, 0),
+			recommended_ratings_avg_score = COALESCE(aggregates.recommended, 0),
+			reviews_count = aggregates.count
+		FROM (
+			SELECT
+				LEAST(ROUND(((AVG(fast_rating) + AVG(experience_rating) + AVG(recommended_rating)) / 3)::numeric, 1), 5) AS score,
+				LEAST(ROUND(AVG(fast_rating)::numeric, 1), 5) AS fast,
+				LEAST(ROUND(AVG(experience_rating)::numeric, 1), 5) AS experience,
+				LEAST(ROUND(AVG(recommended_rating)::numeric, 1), 5) AS recommended,
+				COUNT(id) AS count
+			FROM ratings WHERE rated_user_id = ? AND type = ?
+		) AS aggregates
+		WHERE users.id = ?
+	`, ratedUserID, ratingType, ratedUserID).Error
+}
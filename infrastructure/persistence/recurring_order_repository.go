@@ -0,0 +1,90 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+	"gorm.io/gorm"
+)
+
+// RecurringOrderRepository implements the repository.RecurringOrderRepository interface
+type RecurringOrderRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewRecurringOrderRepository creates a new instance of the RecurringOrderRepository
+func NewRecurringOrderRepository(db *gorm.DB) *RecurringOrderRepository {
+	return &RecurringOrderRepository{db: db}
+}
+
+// CreateRecurringOrder creates a new recurring order in the database
+func (r *RecurringOrderRepository) CreateRecurringOrder(recurringOrder *entity.RecurringOrder) (*entity.RecurringOrder, error) {
+	if err := r.db.Debug().Model(&recurringOrder).Create(&recurringOrder).Error; err != nil {
+		return nil, err
+	}
+
+	return recurringOrder, nil
+}
+
+// UpdateRecurringOrderByID updates the recurring order
+func (r *RecurringOrderRepository) UpdateRecurringOrderByID(id uint64, recurringOrder *entity.RecurringOrder) (*entity.RecurringOrder, error) {
+	if err := r.db.Debug().Model(&entity.RecurringOrder{}).Where("id = ?", id).Select("*").Omit("id", "created_at").Updates(recurringOrder).Error; err != nil {
+		return nil, err
+	}
+
+	return recurringOrder, nil
+}
+
+// UpdateRecurringOrderRunByIDAndNextRunAt moves the runs of an active recurring order only if its
+// next run is still the given one, as a compare and set that claims the run. Only the run times
+// are written, so a template canceled meanwhile stays canceled. repository.ErrConflict is returned
+// when the run was claimed first or the template was canceled.
+func (r *RecurringOrderRepository) UpdateRecurringOrderRunByIDAndNextRunAt(id uint64, nextRunAt time.Time, recurringOrder *entity.RecurringOrder) (*entity.RecurringOrder, error) {
+	result := r.db.Debug().Model(&entity.RecurringOrder{}).Where("id = ?", id).Where("next_run_at = ?", nextRunAt).Where("is_active = ?", true).Select("next_run_at", "last_run_at").Updates(recurringOrder)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, repository.ErrConflict
+	}
+
+	return recurringOrder, nil
+}
+
+// CountRecurringOrdersByUserID counts the recurring orders of a user
+func (r *RecurringOrderRepository) CountRecurringOrdersByUserID(userID uint64) (int64, error) {
+	var count int64
+	if err := r.db.Debug().Model(&entity.RecurringOrder{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAllRecurringOrdersByUserID retrieves the recurring orders of a user
+func (r *RecurringOrderRepository) GetAllRecurringOrdersByUserID(userID uint64, page int, perPage int) ([]entity.RecurringOrder, error) {
+	var recurringOrders []entity.RecurringOrder
+	if err := r.db.Debug().Where("user_id = ?", userID).Order("created_at desc").Offset((page - 1) * perPage).Limit(perPage).Find(&recurringOrders).Error; err != nil {
+		return nil, err
+	}
+	return recurringOrders, nil
+}
+
+// GetAllActiveRecurringOrdersDueBefore retrieves the active recurring orders whose next run is before the given time
+func (r *RecurringOrderRepository) GetAllActiveRecurringOrdersDueBefore(before time.Time) ([]entity.RecurringOrder, error) {
+	var recurringOrders []entity.RecurringOrder
+	if err := r.db.Debug().Where("is_active = ?", true).Where("next_run_at <= ?", before).Order("next_run_at asc").Find(&recurringOrders).Error; err != nil {
+		return nil, err
+	}
+	return recurringOrders, nil
+}
+
+// GetRecurringOrderByIDAndUserID retrieves a recurring order of a user by its ID
+func (r *RecurringOrderRepository) GetRecurringOrderByIDAndUserID(id uint64, userID uint64) (*entity.RecurringOrder, error) {
+	var recurringOrder entity.RecurringOrder
+	if err := r.db.Debug().Where("id = ?", id).Where("user_id = ?", userID).Take(&recurringOrder).Error; err != nil {
+		return nil, err
+	}
+	return &recurringOrder, nil
+}
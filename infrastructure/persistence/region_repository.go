@@ -0,0 +1,43 @@
+package persistence
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+)
+
+// RegionRepository implements the repository.RegionRepository interface.
+type RegionRepository struct {
+	db *gorm.DB
+}
+
+// NewRegionRepository creates a new instance of the RegionRepository.
+func NewRegionRepository(db *gorm.DB) *RegionRepository {
+	return &RegionRepository{db: db}
+}
+
+// CountRegions counts the regions
+func (r *RegionRepository) CountRegions() (int64, error) {
+	var count int64
+	if err := r.db.Debug().Model(&entity.Region{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAllRegions retrieves a page of the regions
+func (r *RegionRepository) GetAllRegions(page int, perPage int) ([]entity.Region, error) {
+	var regions []entity.Region
+	if err := r.db.Debug().Model(&entity.Region{}).Order("id asc").Limit(perPage).Offset((page - 1) * perPage).Find(&regions).Error; err != nil {
+		return nil, err
+	}
+	return regions, nil
+}
+
+// GetRegionByID retrieves a region by its ID
+func (r *RegionRepository) GetRegionByID(id uint64) (*entity.Region, error) {
+	var region entity.Region
+	if err := r.db.Debug().Where("id = ?", id).Take(&region).Error; err != nil {
+		return nil, err
+	}
+	return &region, nil
+}
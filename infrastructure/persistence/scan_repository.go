@@ -0,0 +1,40 @@
+package persistence
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ScanRepository implements the repository.ScanRepository interface
+type ScanRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewScanRepository creates a new instance of the ScanRepository
+func NewScanRepository(db *gorm.DB) *ScanRepository {
+	return &ScanRepository{db: db}
+}
+
+// CreateScan records a scan of a parcel label and adds it to the timeline of its order
+func (r *ScanRepository) CreateScan(scan *entity.Scan) (*entity.Scan, error) {
+	err := r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(scan).Error; err != nil {
+			return err
+		}
+
+		orderTimeline := entity.OrderTimeline{
+			OrderID: scan.OrderID,
+			Event:   entity.OrderParcelScannedEvent,
+			ScanID:  scan.ID,
+		}
+
+		return tx.Omit(clause.Associations).Create(&orderTimeline).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return scan, nil
+}
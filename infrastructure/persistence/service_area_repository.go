@@ -0,0 +1,125 @@
+package persistence
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+)
+
+// ServiceAreaRepository implements the repository.ServiceAreaRepository interface
+type ServiceAreaRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewServiceAreaRepository creates a new instance of the ServiceAreaRepository
+func NewServiceAreaRepository(db *gorm.DB) *ServiceAreaRepository {
+	return &ServiceAreaRepository{db: db}
+}
+
+// serviceAreaColumns selects the service area along with its boundary as GeoJSON
+const serviceAreaColumns = "service_areas.*, ST_AsGeoJSON(service_areas.area) AS boundary"
+
+// CreateServiceArea creates a new service area in the database
+func (r *ServiceAreaRepository) CreateServiceArea(serviceArea *entity.ServiceArea) (*entity.ServiceArea, error) {
+	err := r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&serviceArea).Create(&serviceArea).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec("UPDATE service_areas SET area = ST_GeomFromGeoJSON(?)::geography WHERE id = ?", string(serviceArea.Boundary), serviceArea.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetServiceAreaByID(serviceArea.ID)
+}
+
+// UpdateServiceAreaByID updates the service area
+func (r *ServiceAreaRepository) UpdateServiceAreaByID(id uint64, serviceArea *entity.ServiceArea) (*entity.ServiceArea, error) {
+	err := r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.ServiceArea{}).Where("id = ?", id).Select("name", "price_multiplier", "category_ids", "opens_at", "closes_at", "timezone", "is_active").Updates(serviceArea).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec("UPDATE service_areas SET area = ST_GeomFromGeoJSON(?)::geography WHERE id = ?", string(serviceArea.Boundary), id).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetServiceAreaByID(id)
+}
+
+// DeleteServiceAreaByID deletes the service area
+func (r *ServiceAreaRepository) DeleteServiceAreaByID(id uint64) error {
+	return r.db.Debug().Where("id = ?", id).Delete(&entity.ServiceArea{}).Error
+}
+
+// UpdateServiceAreaSurgeByID updates the surge multiplier of the service area
+func (r *ServiceAreaRepository) UpdateServiceAreaSurgeByID(id uint64, surgeMultiplier float64) error {
+	return r.db.Debug().Model(&entity.ServiceArea{}).Where("id = ?", id).Update("surge_multiplier", surgeMultiplier).Error
+}
+
+// CountOpenOrdersByServiceAreaID counts the orders picked up in the service area that are still waiting for a driver
+func (r *ServiceAreaRepository) CountOpenOrdersByServiceAreaID(id uint64) (int64, error) {
+	var count int64
+	if err := r.db.Debug().Model(&entity.Order{}).Where("service_area_id = ?", id).Where("status = ?", entity.OrderCreatedStatus).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountAvailableDriversByServiceAreaID counts the available drivers currently within the service area
+func (r *ServiceAreaRepository) CountAvailableDriversByServiceAreaID(id uint64) (int64, error) {
+	var count int64
+	if err := r.db.Debug().Table("drivers").
+		Joins("JOIN users ON drivers.user_id = users.id").
+		Joins("JOIN service_areas ON service_areas.id = ?", id).
+		Where("ST_Covers(service_areas.area, ST_MakePoint(drivers.longitude, drivers.latitude)::geography)").
+		Where("(users.settings::jsonb ->> 'is_available')::boolean = ?", true).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountActiveServiceAreas counts the service areas that are currently active
+func (r *ServiceAreaRepository) CountActiveServiceAreas() (int64, error) {
+	var count int64
+	if err := r.db.Debug().Model(&entity.ServiceArea{}).Where("is_active = ?", true).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAllServiceAreas retrieves all the service areas
+func (r *ServiceAreaRepository) GetAllServiceAreas() ([]entity.ServiceArea, error) {
+	var serviceAreas []entity.ServiceArea
+	if err := r.db.Debug().Select(serviceAreaColumns).Order("id asc").Find(&serviceAreas).Error; err != nil {
+		return nil, err
+	}
+	return serviceAreas, nil
+}
+
+// GetServiceAreaByID retrieves a service area by its ID
+func (r *ServiceAreaRepository) GetServiceAreaByID(id uint64) (*entity.ServiceArea, error) {
+	var serviceArea entity.ServiceArea
+	if err := r.db.Debug().Select(serviceAreaColumns).Where("id = ?", id).Take(&serviceArea).Error; err != nil {
+		return nil, err
+	}
+	return &serviceArea, nil
+}
+
+// GetActiveServiceAreaByCoordinates retrieves the smallest active service area covering the given point
+func (r *ServiceAreaRepository) GetActiveServiceAreaByCoordinates(latitude float64, longitude float64) (*entity.ServiceArea, error) {
+	var serviceArea entity.ServiceArea
+	if err := r.db.Debug().Select(serviceAreaColumns).
+		Where("is_active = ?", true).
+		Where("ST_Covers(area, ST_MakePoint(?, ?)::geography)", longitude, latitude).
+		Order("ST_Area(area) ASC").
+		Take(&serviceArea).Error; err != nil {
+		return nil, err
+	}
+	return &serviceArea, nil
+}
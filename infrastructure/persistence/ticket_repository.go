@@ -0,0 +1,162 @@
+package persistence
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+	"gorm.io/gorm"
+)
+
+// TicketRepository implements the repository.TicketRepository interface
+type TicketRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewTicketRepository creates a new instance of the TicketRepository
+func NewTicketRepository(db *gorm.DB) *TicketRepository {
+	return &TicketRepository{db: db}
+}
+
+// CreateTicket creates a new ticket in the database, along with its first message
+func (r *TicketRepository) CreateTicket(ticket *entity.Ticket) (*entity.Ticket, error) {
+	if err := r.db.Debug().Omit("User", "Messages.User").Create(ticket).Error; err != nil {
+		return nil, err
+	}
+
+	return r.GetTicketByID(ticket.ID)
+}
+
+// UpdateTicketByID updates the ticket
+func (r *TicketRepository) UpdateTicketByID(id uint64, ticket *entity.Ticket) (*entity.Ticket, error) {
+	if err := r.db.Debug().Model(&entity.Ticket{}).Where("id = ?", id).Select("*").Omit("id", "user_id", "order_id", "created_at", "User", "Messages").Updates(ticket).Error; err != nil {
+		return nil, err
+	}
+
+	return r.GetTicketByID(id)
+}
+
+// GetTicketByID retrieves a ticket by its ID, along with its message history
+func (r *TicketRepository) GetTicketByID(id uint64) (*entity.Ticket, error) {
+	var ticket entity.Ticket
+	if err := r.db.Debug().Preload("User").Preload("Messages", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at asc").Order("id asc")
+	}).Preload("Messages.User").Where("id = ?", id).Take(&ticket).Error; err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// GetAllTickets retrieves a paginated list of the tickets, optionally filtered by status
+func (r *TicketRepository) GetAllTickets(status string, page int, perPage int) ([]entity.Ticket, error) {
+	var tickets []entity.Ticket
+	if err := r.filterByStatus(status).Order("created_at desc").Order("id desc").Limit(perPage).Offset((page - 1) * perPage).Preload("User").Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// CountTickets counts the tickets, optionally filtered by status
+func (r *TicketRepository) CountTickets(status string) (int64, error) {
+	var count int64
+	if err := r.filterByStatus(status).Model(&entity.Ticket{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAllTicketsByUserID retrieves a paginated list of the tickets of a user
+func (r *TicketRepository) GetAllTicketsByUserID(userID uint64, page int, perPage int) ([]entity.Ticket, error) {
+	var tickets []entity.Ticket
+	if err := r.db.Debug().Where("user_id = ?", userID).Order("created_at desc").Order("id desc").Limit(perPage).Offset((page - 1) * perPage).Preload("User").Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// CountTicketsByUserID counts the tickets of a user
+func (r *TicketRepository) CountTicketsByUserID(userID uint64) (int64, error) {
+	var count int64
+	if err := r.db.Debug().Model(&entity.Ticket{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CreateTicketMessage adds a message to the history of a ticket
+func (r *TicketRepository) CreateTicketMessage(message *entity.TicketMessage) (*entity.TicketMessage, error) {
+	if err := r.db.Debug().Omit("User").Create(message).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Debug().Preload("User").Where("id = ?", message.ID).Take(message).Error; err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// ResolveTicket marks the ticket as resolved and records the refund credited to its user and the
+// adjustment of the driver's balance, if any, in a single transaction. The ticket is only resolved
+// once, so a refund can't be granted twice. The order is locked while its refunds are added up,
+// repository.ErrRefundExceedsAmount is returned when they would exceed its amount.
+func (r *TicketRepository) ResolveTicket(ticket *entity.Ticket, credit *entity.Credit, balance *entity.Balance) (*entity.Ticket, error) {
+	err := r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Ticket{}).
+			Where("id = ?", ticket.ID).
+			Where("status NOT IN ?", []entity.TicketStatus{entity.TicketResolvedStatus, entity.TicketClosedStatus}).
+			Updates(map[string]interface{}{
+				"status":            entity.TicketResolvedStatus,
+				"assignee_id":       ticket.AssigneeID,
+				"resolution":        ticket.Resolution,
+				"refund_amount":     ticket.RefundAmount,
+				"driver_adjustment": ticket.DriverAdjustment,
+				"resolved_at":       ticket.ResolvedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if credit != nil {
+			var order entity.Order
+			if err := tx.Raw("SELECT id, amount, discount FROM orders WHERE id = ? FOR UPDATE", credit.OrderID).Scan(&order).Error; err != nil {
+				return err
+			}
+
+			var refunded float64
+			if err := tx.Model(&entity.Credit{}).Select("COALESCE(SUM(amount), 0)").Where("order_id = ?", credit.OrderID).Where("reason = ?", entity.RefundCreditReason).Scan(&refunded).Error; err != nil {
+				return err
+			}
+
+			if credit.Amount > order.RefundableAmount(refunded) {
+				return repository.ErrRefundExceedsAmount
+			}
+
+			if err := tx.Create(credit).Error; err != nil {
+				return err
+			}
+		}
+
+		if balance != nil {
+			if err := tx.Omit("Driver", "Order").Create(balance).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetTicketByID(ticket.ID)
+}
+
+func (r *TicketRepository) filterByStatus(status string) *gorm.DB {
+	db := r.db.Debug()
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	return db
+}
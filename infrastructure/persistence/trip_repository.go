@@ -0,0 +1,157 @@
+package persistence
+
+import (
+	"errors"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+)
+
+// TripRepository implements the repository.TripRepository interface
+type TripRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewTripRepository creates a new instance of the TripRepository
+func NewTripRepository(db *gorm.DB) *TripRepository {
+	return &TripRepository{db: db}
+}
+
+// tripStopsBySequence preloads the stops of a trip in visiting order
+func tripStopsBySequence(db *gorm.DB) *gorm.DB {
+	return db.Order("sequence asc").Order("id asc")
+}
+
+// AddOrderToTrip appends the stops of an order to the driver's active trip, starting a new trip when needed
+func (r *TripRepository) AddOrderToTrip(driverID uint64, order *entity.Order) (*entity.Trip, error) {
+	var trip entity.Trip
+	err := r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("driver_id = ?", driverID).Where("status = ?", entity.TripActiveStatus).Take(&trip).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			trip = entity.Trip{DriverID: driverID, Status: entity.TripActiveStatus}
+			if err := tx.Create(&trip).Error; err != nil {
+				return err
+			}
+		}
+
+		var lastSequence uint64
+		if err := tx.Model(&entity.TripStop{}).Select("COALESCE(MAX(sequence), 0)").Where("trip_id = ?", trip.ID).Row().Scan(&lastSequence); err != nil {
+			return err
+		}
+
+		stops := entity.NewTripStops(order)
+		for i := range stops {
+			stops[i].TripID = trip.ID
+			stops[i].Sequence = lastSequence + uint64(i) + 1
+		}
+
+		return tx.Create(&stops).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetActiveTripByDriverID(driverID)
+}
+
+// RemoveOrderFromTrips removes the pending stops of an order from its trips. The trips left without
+// pending stops are completed, like they are once their last stop is done.
+func (r *TripRepository) RemoveOrderFromTrips(orderID uint64) error {
+	return r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		var tripIDs []uint64
+		if err := tx.Model(&entity.TripStop{}).Where("order_id = ?", orderID).Where("status = ?", entity.TripStopPendingStatus).Distinct().Pluck("trip_id", &tripIDs).Error; err != nil {
+			return err
+		}
+
+		if len(tripIDs) == 0 {
+			return nil
+		}
+
+		if err := tx.Where("order_id = ?", orderID).Where("status = ?", entity.TripStopPendingStatus).Delete(&entity.TripStop{}).Error; err != nil {
+			return err
+		}
+
+		return completeTripsWithoutPendingStops(tx, tripIDs)
+	})
+}
+
+// CompleteOrderTripStops marks the pending stops of the given types of an order as done, for the
+// orders moved along outside of their trip, and completes the trips left without pending stops
+func (r *TripRepository) CompleteOrderTripStops(orderID uint64, stopTypes []entity.TripStopType, completedAt time.Time) error {
+	return r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		var tripIDs []uint64
+		if err := tx.Model(&entity.TripStop{}).Where("order_id = ?", orderID).Where("type IN ?", stopTypes).Where("status = ?", entity.TripStopPendingStatus).Distinct().Pluck("trip_id", &tripIDs).Error; err != nil {
+			return err
+		}
+
+		if len(tripIDs) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&entity.TripStop{}).Where("order_id = ?", orderID).Where("type IN ?", stopTypes).Where("status = ?", entity.TripStopPendingStatus).Updates(map[string]interface{}{"status": entity.TripStopDoneStatus, "completed_at": completedAt}).Error; err != nil {
+			return err
+		}
+
+		return completeTripsWithoutPendingStops(tx, tripIDs)
+	})
+}
+
+// UpdateOrderDropOffTripStops moves the pending drop-off stops of an order to its new drop-off
+func (r *TripRepository) UpdateOrderDropOffTripStops(orderID uint64, latitude float64, longitude float64) error {
+	return r.db.Debug().Model(&entity.TripStop{}).
+		Where("order_id = ?", orderID).
+		Where("type = ?", entity.DropOffStopType).
+		Where("status = ?", entity.TripStopPendingStatus).
+		Updates(map[string]interface{}{"latitude": latitude, "longitude": longitude}).Error
+}
+
+// completeTripsWithoutPendingStops completes the active trips among the given ones that have no
+// pending stops left
+func completeTripsWithoutPendingStops(tx *gorm.DB, tripIDs []uint64) error {
+	return tx.Model(&entity.Trip{}).
+		Where("id IN ?", tripIDs).
+		Where("status = ?", entity.TripActiveStatus).
+		Where("NOT EXISTS (SELECT 1 FROM trip_stops WHERE trip_stops.trip_id = trips.id AND trip_stops.status = ?)", entity.TripStopPendingStatus).
+		Update("status", entity.TripCompletedStatus).Error
+}
+
+// UpdateTripByID updates the trip
+func (r *TripRepository) UpdateTripByID(id uint64, trip *entity.Trip) (*entity.Trip, error) {
+	if err := r.db.Debug().Model(&entity.Trip{}).Where("id = ?", id).Updates(map[string]interface{}{"status": trip.Status}).Error; err != nil {
+		return nil, err
+	}
+
+	return trip, nil
+}
+
+// UpdateTripStopByID updates the trip stop
+func (r *TripRepository) UpdateTripStopByID(id uint64, tripStop *entity.TripStop) (*entity.TripStop, error) {
+	if err := r.db.Debug().Model(&entity.TripStop{}).Where("id = ?", id).Updates(map[string]interface{}{"status": tripStop.Status, "sequence": tripStop.Sequence, "completed_at": tripStop.CompletedAt}).Error; err != nil {
+		return nil, err
+	}
+
+	return tripStop, nil
+}
+
+// GetActiveTripByDriverID retrieves the active trip of a driver along with its stops
+func (r *TripRepository) GetActiveTripByDriverID(driverID uint64) (*entity.Trip, error) {
+	var trip entity.Trip
+	if err := r.db.Debug().Where("driver_id = ?", driverID).Where("status = ?", entity.TripActiveStatus).Preload("Stops", tripStopsBySequence).Take(&trip).Error; err != nil {
+		return nil, err
+	}
+	return &trip, nil
+}
+
+// GetTripStopByIDAndTripID retrieves a stop of a trip by its ID
+func (r *TripRepository) GetTripStopByIDAndTripID(id uint64, tripID uint64) (*entity.TripStop, error) {
+	var tripStop entity.TripStop
+	if err := r.db.Debug().Where("id = ?", id).Where("trip_id = ?", tripID).Take(&tripStop).Error; err != nil {
+		return nil, err
+	}
+	return &tripStop, nil
+}
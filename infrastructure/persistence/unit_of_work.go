@@ -0,0 +1,87 @@
+package persistence
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+	"gorm.io/gorm"
+)
+
+// UnitOfWork implements the repository.UnitOfWork interface with GORM transactions
+type UnitOfWork struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+var _ repository.UnitOfWork = &UnitOfWork{}
+
+// NewUnitOfWork creates a new instance of the UnitOfWork
+func NewUnitOfWork(db *gorm.DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// Do runs fn in a transaction, the repositories it's given write through the transaction
+func (u *UnitOfWork) Do(fn func(tx repository.Transaction) error) error {
+	return u.db.Transaction(func(db *gorm.DB) error {
+		return fn(&transaction{db: db})
+	})
+}
+
+// transaction implements the repository.Transaction interface
+type transaction struct {
+	db *gorm.DB
+}
+
+func (t *transaction) Order() repository.OrderRepository {
+	return NewOrderRepository(t.db)
+}
+
+func (t *transaction) Offer() repository.OfferRepository {
+	return NewOfferRepository(t.db)
+}
+
+func (t *transaction) User() repository.UserRepository {
+	return NewUserRepository(t.db)
+}
+
+func (t *transaction) Driver() repository.DriverRepository {
+	return NewDriverRepository(t.db)
+}
+
+func (t *transaction) Balance() repository.BalanceRepository {
+	return NewBalanceRepository(t.db)
+}
+
+func (t *transaction) Credit() repository.CreditRepository {
+	return NewCreditRepository(t.db)
+}
+
+func (t *transaction) Promotion() repository.PromotionRepository {
+	return NewPromotionRepository(t.db)
+}
+
+func (t *transaction) Rating() repository.RatingRepository {
+	return NewRatingRepository(t.db)
+}
+
+func (t *transaction) Trip() repository.TripRepository {
+	return NewTripRepository(t.db)
+}
+
+func (t *transaction) OutboxEvent() repository.OutboxEventRepository {
+	return NewOutboxEventRepository(t.db)
+}
+
+func (t *transaction) OrderLeg() repository.OrderLegRepository {
+	return NewOrderLegRepository(t.db)
+}
+
+func (t *transaction) OrderTimeline() repository.OrderTimelineRepository {
+	return NewOrderTimelineRepository(t.db)
+}
+
+func (t *transaction) RecurringOrder() repository.RecurringOrderRepository {
+	return NewRecurringOrderRepository(t.db)
+}
+
+func (t *transaction) Webhook() repository.WebhookRepository {
+	return NewWebhookRepository(t.db)
+}
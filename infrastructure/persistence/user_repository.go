@@ -2,11 +2,13 @@ package persistence
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/OmarBader7/web-service-jayeek/domain/entity"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/security"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // UserRepository implements repository.UserRepository
@@ -20,9 +22,16 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{db}
 }
 
-// CreateUser creates a new user in the database
+// userReferralCodeIndex is the unique index of the users' referral codes
+const userReferralCodeIndex = "idx_users_referral_code"
+
+// CreateUser creates a new user in the database. The user is given another referral code when the
+// one drawn is taken.
 func (r *UserRepository) CreateUser(user *entity.User) (*entity.User, error) {
-	if err := r.db.Debug().Model(&user).Create(&user).Error; err != nil {
+	err := retryTakenCode(r.db.Debug(), userReferralCodeIndex, func(tx *gorm.DB) error {
+		return tx.Model(&user).Create(&user).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -59,6 +68,39 @@ func (r *UserRepository) UpdateUserByID(id uint64, user *entity.User) (*entity.U
 	return user, nil
 }
 
+// AssignUserReferralCodeByID gives a referral code to a user registered before referral codes
+// existed, drawing another one when it's taken, and returns the user's code
+func (r *UserRepository) AssignUserReferralCodeByID(id uint64) (string, error) {
+	err := retryTakenCode(r.db.Debug(), userReferralCodeIndex, func(tx *gorm.DB) error {
+		referralCode, err := entity.NewReferralCode()
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.User{}).Where("id = ?", id).Where("referral_code IS NULL").UpdateColumn("referral_code", referralCode).Error
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var user entity.User
+	if err := r.db.Debug().Select("id", "referral_code").Where("id = ?", id).Take(&user).Error; err != nil {
+		return "", err
+	}
+	if user.ReferralCode == nil {
+		return "", gorm.ErrRecordNotFound
+	}
+
+	return *user.ReferralCode, nil
+}
+
+// LockUserByID locks the user's row until the end of the transaction, serializing the requests
+// that check and change the user's credit
+func (r *UserRepository) LockUserByID(id uint64) error {
+	var user entity.User
+	return r.db.Debug().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", id).Take(&user).Error
+}
+
 // UserWithFieldExists checks if a user with the given field and value exists in the database
 func (r *UserRepository) UserWithFieldExists(field string, value string) (bool, error) {
 	var user entity.User
@@ -116,3 +158,12 @@ func (r *UserRepository) GetUserByPhone(phone string) (*entity.User, error) {
 	}
 	return &user, nil
 }
+
+// GetUserByReferralCode retrieves a user from the database by their referral code
+func (r *UserRepository) GetUserByReferralCode(referralCode string) (*entity.User, error) {
+	var user entity.User
+	if err := r.db.Debug().Model(&user).Where("referral_code = ?", strings.ToUpper(referralCode)).Preload("Location").Take(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
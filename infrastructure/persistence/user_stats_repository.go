@@ -0,0 +1,44 @@
+package persistence
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+)
+
+// UserStatsRepository implements the repository.UserStatsRepository interface
+type UserStatsRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewUserStatsRepository creates a new instance of the UserStatsRepository
+func NewUserStatsRepository(db *gorm.DB) *UserStatsRepository {
+	return &UserStatsRepository{db: db}
+}
+
+// GetUserStatsByUserID calculates the statistics of a user in a single query
+func (r *UserStatsRepository) GetUserStatsByUserID(userID uint64) (*entity.UserStats, error) {
+	var stats entity.UserStats
+	err := r.db.Debug().Raw(`
+		SELECT
+			EXISTS (SELECT 1 FROM drivers WHERE user_id = @user_id) AS is_driver,
+			(SELECT COUNT(*) FROM orders WHERE user_id = @user_id) AS purchases_count,
+			(SELECT COUNT(*) FROM orders JOIN drivers ON drivers.id = orders.driver_id WHERE drivers.user_id = @user_id) AS orders_count,
+			(SELECT COUNT(*) FROM offers JOIN drivers ON drivers.id = offers.driver_id WHERE drivers.user_id = @user_id AND offers.status = @pending)
+				+ (SELECT COUNT(*) FROM orders JOIN drivers ON drivers.id = orders.driver_id WHERE drivers.user_id = @user_id AND orders.status NOT IN @finished) AS in_progress_orders_count,
+			(SELECT COUNT(*) FROM orders JOIN drivers ON drivers.id = orders.driver_id WHERE drivers.user_id = @user_id AND orders.status IN @delivered) AS trips_count,
+			(SELECT COALESCE(SUM(balance), 0)::bigint FROM balances JOIN drivers ON drivers.id = balances.driver_id WHERE drivers.user_id = @user_id) AS balances_sum_balance,
+			(SELECT COALESCE(SUM(balance), 0)::bigint FROM balances JOIN drivers ON drivers.id = balances.driver_id WHERE drivers.user_id = @user_id
+				AND DATE_TRUNC('month', balances.created_at) = DATE_TRUNC('month', CURRENT_DATE)) AS monthly_revenue
+	`, map[string]interface{}{
+		"user_id":   userID,
+		"pending":   entity.OfferStatusPending,
+		"finished":  []entity.OrderStatus{entity.OrderCompletedStatus, entity.OrderCanceledStatus, entity.ShipmentReturnedStatus},
+		"delivered": []entity.OrderStatus{entity.ShipmentDeliveredStatus, entity.OrderCompletedStatus},
+	}).Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
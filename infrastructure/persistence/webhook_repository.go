@@ -0,0 +1,154 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookRepository implements the repository.WebhookRepository interface
+type WebhookRepository struct {
+	// db is a pointer to the GORM DB instance
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new instance of the WebhookRepository
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// CreateWebhookSubscription creates a new webhook subscription in the database
+func (r *WebhookRepository) CreateWebhookSubscription(webhookSubscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	if err := r.db.Debug().Create(webhookSubscription).Error; err != nil {
+		return nil, err
+	}
+
+	return webhookSubscription, nil
+}
+
+// UpdateWebhookSubscriptionByID updates the webhook subscription
+func (r *WebhookRepository) UpdateWebhookSubscriptionByID(id uint64, webhookSubscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	if err := r.db.Debug().Model(&entity.WebhookSubscription{}).Where("id = ?", id).Select("url", "event_types", "is_active", "updated_at").Updates(webhookSubscription).Error; err != nil {
+		return nil, err
+	}
+
+	return webhookSubscription, nil
+}
+
+// DeleteWebhookSubscriptionByID deletes the webhook subscription along with its deliveries
+func (r *WebhookRepository) DeleteWebhookSubscriptionByID(id uint64) error {
+	return r.db.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&entity.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", id).Delete(&entity.WebhookSubscription{}).Error
+	})
+}
+
+// GetWebhookSubscriptionByIDAndUserID retrieves a webhook subscription of a user by its ID
+func (r *WebhookRepository) GetWebhookSubscriptionByIDAndUserID(id uint64, userID uint64) (*entity.WebhookSubscription, error) {
+	var webhookSubscription entity.WebhookSubscription
+	if err := r.db.Debug().Where("id = ?", id).Where("user_id = ?", userID).Take(&webhookSubscription).Error; err != nil {
+		return nil, err
+	}
+	return &webhookSubscription, nil
+}
+
+// GetAllWebhookSubscriptionsByUserID retrieves the webhook subscriptions of a user
+func (r *WebhookRepository) GetAllWebhookSubscriptionsByUserID(userID uint64) ([]entity.WebhookSubscription, error) {
+	var webhookSubscriptions []entity.WebhookSubscription
+	if err := r.db.Debug().Where("user_id = ?", userID).Order("id asc").Find(&webhookSubscriptions).Error; err != nil {
+		return nil, err
+	}
+	return webhookSubscriptions, nil
+}
+
+// GetAllActiveWebhookSubscriptionsByUserID retrieves the active webhook subscriptions of a user
+func (r *WebhookRepository) GetAllActiveWebhookSubscriptionsByUserID(userID uint64) ([]entity.WebhookSubscription, error) {
+	var webhookSubscriptions []entity.WebhookSubscription
+	if err := r.db.Debug().Where("user_id = ?", userID).Where("is_active = ?", true).Order("id asc").Find(&webhookSubscriptions).Error; err != nil {
+		return nil, err
+	}
+	return webhookSubscriptions, nil
+}
+
+// CreateWebhookDeliveries stores the deliveries of an event. A delivery of the same event to the
+// same subscription is only stored once, so an event handled twice isn't delivered twice.
+func (r *WebhookRepository) CreateWebhookDeliveries(webhookDeliveries []entity.WebhookDelivery) error {
+	if len(webhookDeliveries) == 0 {
+		return nil
+	}
+
+	return r.db.Debug().Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&webhookDeliveries).Error
+}
+
+// UpdateWebhookDeliveryByID updates the webhook delivery
+func (r *WebhookRepository) UpdateWebhookDeliveryByID(id uint64, webhookDelivery *entity.WebhookDelivery) (*entity.WebhookDelivery, error) {
+	if err := r.db.Debug().Model(&entity.WebhookDelivery{}).Where("id = ?", id).Select("status", "attempts", "response_status", "last_error", "next_attempt_at", "delivered_at").Updates(webhookDelivery).Error; err != nil {
+		return nil, err
+	}
+
+	return webhookDelivery, nil
+}
+
+// GetWebhookDeliveryByID retrieves a webhook delivery by its ID
+func (r *WebhookRepository) GetWebhookDeliveryByID(id uint64) (*entity.WebhookDelivery, error) {
+	var webhookDelivery entity.WebhookDelivery
+	if err := r.db.Debug().Where("id = ?", id).Preload("Subscription").Take(&webhookDelivery).Error; err != nil {
+		return nil, err
+	}
+	return &webhookDelivery, nil
+}
+
+// GetAllDueWebhookDeliveries retrieves the pending webhook deliveries due for an attempt, in the
+// order they were made. The deliveries are locked until the end of the transaction, the ones
+// locked by another instance are skipped.
+func (r *WebhookRepository) GetAllDueWebhookDeliveries(now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	var webhookDeliveries []entity.WebhookDelivery
+	if err := r.db.Debug().Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Where("status = ?", entity.WebhookDeliveryPendingStatus).Where("next_attempt_at <= ?", now).Order("id asc").Limit(limit).Preload("Subscription").Find(&webhookDeliveries).Error; err != nil {
+		return nil, err
+	}
+	return webhookDeliveries, nil
+}
+
+// GetAllWebhookDeliveries retrieves a paginated list of the webhook deliveries, optionally filtered
+// by subscription and status
+func (r *WebhookRepository) GetAllWebhookDeliveries(subscriptionID uint64, status string, page int, perPage int) ([]entity.WebhookDelivery, error) {
+	var webhookDeliveries []entity.WebhookDelivery
+	if err := r.filterDeliveries(subscriptionID, status).Order("created_at desc").Order("id desc").Limit(perPage).Offset((page - 1) * perPage).Preload("Subscription").Find(&webhookDeliveries).Error; err != nil {
+		return nil, err
+	}
+	return webhookDeliveries, nil
+}
+
+// CountWebhookDeliveries counts the webhook deliveries, optionally filtered by subscription and status
+func (r *WebhookRepository) CountWebhookDeliveries(subscriptionID uint64, status string) (int64, error) {
+	var count int64
+	if err := r.filterDeliveries(subscriptionID, status).Model(&entity.WebhookDelivery{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *WebhookRepository) filterDeliveries(subscriptionID uint64, status string) *gorm.DB {
+	db := r.db.Debug()
+	if subscriptionID != 0 {
+		db = db.Where("subscription_id = ?", subscriptionID)
+	}
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	return db
+}
+
+// DropWebhookDeliveryResponseBodies drops the answers of the endpoints kept by the deliveries made
+// before only their status was recorded.
+func (r *WebhookRepository) DropWebhookDeliveryResponseBodies() error {
+	if !r.db.Migrator().HasColumn(&entity.WebhookDelivery{}, "response_body") {
+		return nil
+	}
+	return r.db.Migrator().DropColumn(&entity.WebhookDelivery{}, "response_body")
+}
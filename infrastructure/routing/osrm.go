@@ -0,0 +1,95 @@
+package routing
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OSRMService requests routes from an OSRM-compatible HTTP server.
+type OSRMService struct {
+	BaseURL  string
+	Profile  string
+	Client   *http.Client
+	Fallback RoutingServiceInterface
+}
+
+// Ensure that OSRMService implements RoutingServiceInterface.
+var _ RoutingServiceInterface = &OSRMService{}
+
+// NewOSRMService creates and returns a new instance of OSRMService.
+func NewOSRMService(baseURL string, fallback RoutingServiceInterface) *OSRMService {
+	return &OSRMService{
+		BaseURL:  strings.TrimRight(baseURL, "/"),
+		Profile:  "driving",
+		Client:   &http.Client{Timeout: 5 * time.Second},
+		Fallback: fallback,
+	}
+}
+
+// osrmRouteResponse represents the parts of an OSRM route response that are used.
+type osrmRouteResponse struct {
+	Code   string `json:"code"`
+	Routes []struct {
+		Distance float64 `json:"distance"`
+		Duration float64 `json:"duration"`
+		Geometry string  `json:"geometry"`
+	} `json:"routes"`
+}
+
+// GetRoute requests the road route through the waypoints, using the fallback when the server can't be reached.
+func (s *OSRMService) GetRoute(waypoints []Waypoint) (*Route, error) {
+	if len(waypoints) < 2 {
+		return nil, ErrNotEnoughWaypoints
+	}
+
+	route, err := s.fetchRoute(waypoints)
+	if err != nil {
+		if s.Fallback == nil {
+			return nil, err
+		}
+
+		log.Println("Error fetching OSRM route, falling back: ", err)
+		return s.Fallback.GetRoute(waypoints)
+	}
+
+	return route, nil
+}
+
+func (s *OSRMService) fetchRoute(waypoints []Waypoint) (*Route, error) {
+	// OSRM expects the coordinates as longitude,latitude pairs
+	coordinates := make([]string, len(waypoints))
+	for i, waypoint := range waypoints {
+		coordinates[i] = fmt.Sprintf("%.6f,%.6f", waypoint.Longitude, waypoint.Latitude)
+	}
+
+	url := fmt.Sprintf("%s/route/v1/%s/%s?overview=full&geometries=polyline", s.BaseURL, s.Profile, strings.Join(coordinates, ";"))
+
+	resp, err := s.Client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OSRM responded with status %d", resp.StatusCode)
+	}
+
+	var routeResponse osrmRouteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&routeResponse); err != nil {
+		return nil, err
+	}
+
+	if routeResponse.Code != "Ok" || len(routeResponse.Routes) == 0 {
+		return nil, fmt.Errorf("OSRM found no route: %s", routeResponse.Code)
+	}
+
+	return &Route{
+		Distance: routeResponse.Routes[0].Distance,
+		Duration: routeResponse.Routes[0].Duration,
+		Polyline: routeResponse.Routes[0].Geometry,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package routing
+
+import (
+	"errors"
+
+	"github.com/OmarBader7/web-service-jayeek/pkg/geoutil"
+)
+
+// ErrNotEnoughWaypoints is returned when a route is requested with less than two waypoints.
+var ErrNotEnoughWaypoints = errors.New("a route needs at least two waypoints")
+
+// RoutingServiceInterface defines the methods that a routing service should implement.
+type RoutingServiceInterface interface {
+	GetRoute(waypoints []Waypoint) (*Route, error)
+}
+
+// Waypoint represents a point a route goes through.
+type Waypoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Route represents the road route between a list of waypoints.
+type Route struct {
+	Distance float64 `json:"distance"` // Distance in meters
+	Duration float64 `json:"duration"` // Duration in seconds
+	Polyline string  `json:"polyline"` // Encoded polyline of the route geometry
+}
+
+// NewRoutingService returns an OSRM routing service falling back to straight-line estimates,
+// or only the straight-line estimates when no OSRM server is configured.
+func NewRoutingService(osrmURL string) RoutingServiceInterface {
+	fallback := NewHaversineService(DefaultAverageSpeedKmh)
+	if osrmURL == "" {
+		return fallback
+	}
+	return NewOSRMService(osrmURL, fallback)
+}
+
+// DefaultAverageSpeedKmh is the average road speed used to estimate durations without a routing backend.
+const DefaultAverageSpeedKmh = 50.0
+
+// roadDetourFactor approximates how much longer a road route is compared to a straight line.
+const roadDetourFactor = 1.3
+
+// HaversineService estimates routes from straight-line distances.
+type HaversineService struct {
+	AverageSpeedKmh float64
+}
+
+// Ensure that HaversineService implements RoutingServiceInterface.
+var _ RoutingServiceInterface = &HaversineService{}
+
+// NewHaversineService creates and returns a new instance of HaversineService.
+func NewHaversineService(averageSpeedKmh float64) *HaversineService {
+	return &HaversineService{AverageSpeedKmh: averageSpeedKmh}
+}
+
+// GetRoute estimates the route through the waypoints in straight lines.
+func (s *HaversineService) GetRoute(waypoints []Waypoint) (*Route, error) {
+	if len(waypoints) < 2 {
+		return nil, ErrNotEnoughWaypoints
+	}
+
+	var distanceKm float64
+	points := make([][2]float64, len(waypoints))
+	for i, waypoint := range waypoints {
+		points[i] = [2]float64{waypoint.Latitude, waypoint.Longitude}
+		if i > 0 {
+			distanceKm += geoutil.CalculateDistance(waypoints[i-1].Latitude, waypoints[i-1].Longitude, waypoint.Latitude, waypoint.Longitude)
+		}
+	}
+
+	distanceKm *= roadDetourFactor
+
+	return &Route{
+		Distance: distanceKm * 1000,
+		Duration: distanceKm / s.AverageSpeedKmh * 3600,
+		Polyline: geoutil.EncodePolyline(points),
+	}, nil
+}
@@ -0,0 +1,321 @@
+package scheduler
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// SchedulerServiceInterface defines the methods that a scheduler service should implement.
+type SchedulerServiceInterface interface {
+	Start(interval time.Duration)
+	ReleaseScheduledOrders(now time.Time) error
+	GenerateRecurringOrders(now time.Time) error
+	CompleteDeliveredOrders(now time.Time) error
+	ReleasePreferredOrders(now time.Time) error
+}
+
+// OrderPreparerInterface prepares the orders generated by the scheduler the same way as the
+// orders placed by the senders, before they're created.
+type OrderPreparerInterface interface {
+	PrepareRecurringOrder(order *entity.Order) error
+}
+
+// SchedulerService represents the scheduler service implementation.
+type SchedulerService struct {
+	UnitOfWorkApp     application.UnitOfWorkApplicationInterface
+	OrderApp          application.OrderApplicationInterface
+	RecurringOrderApp application.RecurringOrderApplicationInterface
+	OrderPreparer     OrderPreparerInterface
+	SettingApp        application.SettingApplicationInterface
+}
+
+// Ensure that SchedulerService implements SchedulerServiceInterface.
+var _ SchedulerServiceInterface = &SchedulerService{}
+
+// NewSchedulerService creates and returns a new instance of SchedulerService.
+func NewSchedulerService(unitOfWorkApp application.UnitOfWorkApplicationInterface, orderApp application.OrderApplicationInterface, recurringOrderApp application.RecurringOrderApplicationInterface, orderPreparer OrderPreparerInterface, settingApp application.SettingApplicationInterface) *SchedulerService {
+	return &SchedulerService{
+		UnitOfWorkApp:     unitOfWorkApp,
+		OrderApp:          orderApp,
+		RecurringOrderApp: recurringOrderApp,
+		OrderPreparer:     orderPreparer,
+		SettingApp:        settingApp,
+	}
+}
+
+// Start runs the scheduler jobs every interval until the process exits.
+func (s *SchedulerService) Start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if err := s.GenerateRecurringOrders(now); err != nil {
+			log.Println("Error generating recurring orders: ", err)
+		}
+
+		if err := s.ReleaseScheduledOrders(now); err != nil {
+			log.Println("Error releasing scheduled orders: ", err)
+		}
+
+		if err := s.CompleteDeliveredOrders(now); err != nil {
+			log.Println("Error completing delivered orders: ", err)
+		}
+
+		if err := s.ReleasePreferredOrders(now); err != nil {
+			log.Println("Error releasing preferred orders: ", err)
+		}
+	}
+}
+
+// ReleaseScheduledOrders dispatches the scheduled orders whose pickup window is about to start.
+func (s *SchedulerService) ReleaseScheduledOrders(now time.Time) error {
+	releaseBefore, err := s.getScheduledOrderRelease()
+	if err != nil {
+		return err
+	}
+
+	orders, err := s.OrderApp.GetAllScheduledOrdersDueBefore(now.Add(releaseBefore))
+	if err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		// An order released meanwhile by another instance, or canceled, is left as it is
+		_, err := s.OrderApp.UpdateOrderByIDAndStatus(order.ID, entity.OrderScheduledStatus, &entity.Order{Status: entity.OrderCreatedStatus})
+		if errors.Is(err, repository.ErrConflict) {
+			continue
+		}
+		if err != nil {
+			log.Println("Error releasing scheduled order: ", err)
+			continue
+		}
+
+		if err := s.OrderApp.ReleaseOrderDriverPoolByID(order.ID); err != nil {
+			log.Println("Error dispatching scheduled order: ", err)
+		}
+	}
+
+	return nil
+}
+
+// GenerateRecurringOrders creates the orders of the recurring order templates that are due within the lead time.
+func (s *SchedulerService) GenerateRecurringOrders(now time.Time) error {
+	leadTime, err := s.getRecurringOrderLead()
+	if err != nil {
+		return err
+	}
+
+	recurringOrders, err := s.RecurringOrderApp.GetAllActiveRecurringOrdersDueBefore(now.Add(leadTime))
+	if err != nil {
+		return err
+	}
+
+	for _, recurringOrder := range recurringOrders {
+		recurringOrder := recurringOrder
+
+		// Occurrences that were missed while the scheduler was down are skipped. An occurrence
+		// that can't be prepared is tried again until its pickup time is over.
+		pickupAt := recurringOrder.NextRunAt
+		run := entity.RecurringOrder{LastRunAt: recurringOrder.LastRunAt}
+
+		var order *entity.Order
+		if pickupAt.After(now) {
+			if order, err = s.prepareRecurringOrder(&recurringOrder, pickupAt); err != nil {
+				log.Println("Error preparing recurring order: ", err)
+				continue
+			}
+			run.LastRunAt = &pickupAt
+		}
+
+		run.NextRunAt, err = recurringOrder.NextOccurrence(maxTime(pickupAt, now))
+		if err != nil {
+			log.Println("Error computing next recurring order occurrence: ", err)
+			continue
+		}
+
+		// Moving the next run claims the occurrence, so only one instance creates its order
+		err := s.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+			if _, err := tx.RecurringOrder().UpdateRecurringOrderRunByIDAndNextRunAt(recurringOrder.ID, pickupAt, &run); err != nil {
+				return err
+			}
+
+			if order == nil {
+				return nil
+			}
+
+			_, err := tx.Order().CreateOrder(order)
+			return err
+		})
+		if err != nil && !errors.Is(err, repository.ErrConflict) {
+			log.Println("Error creating recurring order: ", err)
+		}
+	}
+
+	return nil
+}
+
+// ReleasePreferredOrders dispatches to every driver nearby the orders offered to a preferred driver
+// first, such as returns offered to the driver of the original order, once their turn is over.
+func (s *SchedulerService) ReleasePreferredOrders(now time.Time) error {
+	orders, err := s.OrderApp.GetAllOrdersPreferredBefore(now)
+	if err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		if err := s.OrderApp.ReleasePreferredOrderByID(order.ID); err != nil {
+			log.Println("Error releasing preferred order: ", err)
+		}
+	}
+
+	return nil
+}
+
+// CompleteDeliveredOrders completes the delivered orders whose return window is over, and credits
+// the referrals of the senders completing their first order.
+func (s *SchedulerService) CompleteDeliveredOrders(now time.Time) error {
+	returnWindow, err := s.getReturnWindow()
+	if err != nil {
+		return err
+	}
+
+	orders, err := s.OrderApp.GetAllOrdersDeliveredBefore(now.Add(-returnWindow))
+	if err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		order := order
+
+		// The order only completes from shipment_delivered, an order completed meanwhile by another
+		// instance is rolled back
+		err := s.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+			if _, err := tx.Order().UpdateOrderByIDAndStatus(order.ID, entity.ShipmentDeliveredStatus, &entity.Order{Status: entity.OrderCompletedStatus}); err != nil {
+				return err
+			}
+
+			return s.grantReferralCredits(tx, &order)
+		})
+		if err != nil && !errors.Is(err, repository.ErrConflict) {
+			log.Println("Error completing delivered order: ", err)
+		}
+	}
+
+	return nil
+}
+
+// grantReferralCredits credits both the referrer and the referee once the referee's first order
+// is completed. The referee is locked, so two of their orders completing at once credit them once.
+func (s *SchedulerService) grantReferralCredits(tx repository.Transaction, order *entity.Order) error {
+	if err := tx.User().LockUserByID(order.UserID); err != nil {
+		return err
+	}
+
+	customer, err := tx.User().GetUserByID(order.UserID)
+	if err != nil {
+		return err
+	}
+
+	if customer.ReferredByID == 0 {
+		return nil
+	}
+
+	count, err := tx.Credit().CountCreditsByUserIDAndReason(customer.ID, entity.RefereeCreditReason)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	amount, err := s.getReferralCreditAmount()
+	if err != nil || amount <= 0 {
+		return err
+	}
+
+	credits := []entity.Credit{
+		{UserID: customer.ID, OrderID: order.ID, Amount: amount, Reason: entity.RefereeCreditReason},
+		{UserID: customer.ReferredByID, OrderID: order.ID, Amount: amount, Reason: entity.ReferrerCreditReason},
+	}
+
+	for _, credit := range credits {
+		if _, err := tx.Credit().CreateCredit(&credit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// prepareRecurringOrder prepares the order generated by a recurring order template for a pickup time
+func (s *SchedulerService) prepareRecurringOrder(recurringOrder *entity.RecurringOrder, pickupAt time.Time) (*entity.Order, error) {
+	order, err := recurringOrder.NewOrder(pickupAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.OrderPreparer.PrepareRecurringOrder(order); err != nil {
+		return nil, err
+	}
+
+	order.Status = entity.OrderScheduledStatus
+	return order, nil
+}
+
+func (s *SchedulerService) getScheduledOrderRelease() (time.Duration, error) {
+	releaseMinutesStr, err := s.SettingApp.GetSettingByKey("scheduled_order_release_minutes")
+	if err != nil {
+		return 0, err
+	}
+	releaseMinutes, err := strconv.ParseInt(releaseMinutesStr, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(releaseMinutes) * time.Minute, nil
+}
+
+func (s *SchedulerService) getRecurringOrderLead() (time.Duration, error) {
+	leadHoursStr, err := s.SettingApp.GetSettingByKey("recurring_order_lead_hours")
+	if err != nil {
+		return 0, err
+	}
+	leadHours, err := strconv.ParseInt(leadHoursStr, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(leadHours) * time.Hour, nil
+}
+
+func (s *SchedulerService) getReturnWindow() (time.Duration, error) {
+	returnWindowHoursStr, err := s.SettingApp.GetSettingByKey("return_window_hours")
+	if err != nil {
+		return 0, err
+	}
+	returnWindowHours, err := strconv.ParseInt(returnWindowHoursStr, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(returnWindowHours) * time.Hour, nil
+}
+
+func (s *SchedulerService) getReferralCreditAmount() (float64, error) {
+	amountStr, err := s.SettingApp.GetSettingByKey("referral_credit_amount")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(amountStr, 64)
+}
+
+func maxTime(a time.Time, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
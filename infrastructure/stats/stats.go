@@ -0,0 +1,94 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/go-redis/redis/v9"
+)
+
+// cacheTTL is how long the calculated statistics of a user are served from the cache.
+const cacheTTL = time.Minute
+
+var ctx = context.Background()
+
+// StatsServiceInterface defines the methods that a stats service should implement.
+type StatsServiceInterface interface {
+	GetUserStats(userID uint64) (*entity.UserStats, error)
+	InvalidateUserStats(userIDs ...uint64) error
+	HandleOrderEvent(event *entity.OutboxEvent) error
+}
+
+// StatsService calculates user statistics and caches them in Redis.
+type StatsService struct {
+	RedisClient  *redis.Client
+	UserStatsApp application.UserStatsApplicationInterface
+}
+
+// Ensure that StatsService implements StatsServiceInterface.
+var _ StatsServiceInterface = &StatsService{}
+
+// NewStatsService creates and returns a new instance of StatsService.
+func NewStatsService(redisClient *redis.Client, userStatsApp application.UserStatsApplicationInterface) *StatsService {
+	return &StatsService{
+		RedisClient:  redisClient,
+		UserStatsApp: userStatsApp,
+	}
+}
+
+// GetUserStats returns the statistics of a user from the cache, calculating them when they're missing.
+func (s *StatsService) GetUserStats(userID uint64) (*entity.UserStats, error) {
+	key := cacheKey(userID)
+
+	if cached, err := s.RedisClient.Get(ctx, key).Bytes(); err == nil {
+		var stats entity.UserStats
+		if err := json.Unmarshal(cached, &stats); err == nil {
+			return &stats, nil
+		}
+	}
+
+	stats, err := s.UserStatsApp.GetUserStatsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A failure to cache the statistics only costs a recalculation on the next request
+	if encoded, err := json.Marshal(stats); err == nil {
+		s.RedisClient.Set(ctx, key, encoded, cacheTTL)
+	}
+
+	return stats, nil
+}
+
+// InvalidateUserStats drops the cached statistics of users, so they're recalculated on the next request.
+func (s *StatsService) InvalidateUserStats(userIDs ...uint64) error {
+	var keys []string
+	for _, userID := range userIDs {
+		if userID != 0 {
+			keys = append(keys, cacheKey(userID))
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return s.RedisClient.Del(ctx, keys...).Err()
+}
+
+// HandleOrderEvent invalidates the statistics of the users of an order whose status changed.
+func (s *StatsService) HandleOrderEvent(event *entity.OutboxEvent) error {
+	payload, err := event.OrderPayload()
+	if err != nil {
+		return err
+	}
+
+	return s.InvalidateUserStats(payload.UserID, payload.DriverUserID, payload.RecipientID)
+}
+
+func cacheKey(userID uint64) string {
+	return fmt.Sprintf("user-stats:%d", userID)
+}
@@ -0,0 +1,119 @@
+package surge
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+)
+
+// SurgeServiceInterface defines the methods that a surge service should implement.
+type SurgeServiceInterface interface {
+	Start(interval time.Duration)
+	UpdateSurgeMultipliers() error
+}
+
+// SurgeService represents the surge service implementation.
+type SurgeService struct {
+	ServiceAreaApp application.ServiceAreaApplicationInterface
+	SettingApp     application.SettingApplicationInterface
+}
+
+// Ensure that SurgeService implements SurgeServiceInterface.
+var _ SurgeServiceInterface = &SurgeService{}
+
+// NewSurgeService creates and returns a new instance of SurgeService.
+func NewSurgeService(serviceAreaApp application.ServiceAreaApplicationInterface, settingApp application.SettingApplicationInterface) *SurgeService {
+	return &SurgeService{
+		ServiceAreaApp: serviceAreaApp,
+		SettingApp:     settingApp,
+	}
+}
+
+// Start recalculates the surge multipliers every interval until the process exits.
+func (s *SurgeService) Start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.UpdateSurgeMultipliers(); err != nil {
+			log.Println("Error updating surge multipliers: ", err)
+		}
+	}
+}
+
+// UpdateSurgeMultipliers compares the open orders against the available drivers of every
+// active service area and stores the resulting surge multiplier.
+func (s *SurgeService) UpdateSurgeMultipliers() error {
+	step, err := s.getFloatSetting("surge_step")
+	if err != nil {
+		return err
+	}
+
+	maxMultiplier, err := s.getFloatSetting("surge_max_multiplier")
+	if err != nil {
+		return err
+	}
+
+	serviceAreas, err := s.ServiceAreaApp.GetAllServiceAreas()
+	if err != nil {
+		return err
+	}
+
+	for _, serviceArea := range serviceAreas {
+		if !serviceArea.IsActive {
+			continue
+		}
+
+		openOrders, err := s.ServiceAreaApp.CountOpenOrdersByServiceAreaID(serviceArea.ID)
+		if err != nil {
+			log.Println("Error counting open orders: ", err)
+			continue
+		}
+
+		availableDrivers, err := s.ServiceAreaApp.CountAvailableDriversByServiceAreaID(serviceArea.ID)
+		if err != nil {
+			log.Println("Error counting available drivers: ", err)
+			continue
+		}
+
+		multiplier := CalculateMultiplier(openOrders, availableDrivers, step, maxMultiplier)
+		if multiplier == serviceArea.SurgeMultiplier {
+			continue
+		}
+
+		if err := s.ServiceAreaApp.UpdateServiceAreaSurgeByID(serviceArea.ID, multiplier); err != nil {
+			log.Println("Error updating surge multiplier: ", err)
+		}
+	}
+
+	return nil
+}
+
+// CalculateMultiplier returns the surge multiplier for a demand and supply. Every open order
+// beyond one per available driver raises the multiplier by step, up to maxMultiplier.
+func CalculateMultiplier(openOrders int64, availableDrivers int64, step float64, maxMultiplier float64) float64 {
+	if openOrders <= availableDrivers {
+		return 1
+	}
+
+	ratio := float64(openOrders) / math.Max(float64(availableDrivers), 1)
+	multiplier := 1 + (ratio-1)*step
+
+	if maxMultiplier >= 1 && multiplier > maxMultiplier {
+		multiplier = maxMultiplier
+	}
+
+	// Multipliers are stored with two decimals
+	return math.Round(multiplier*100) / 100
+}
+
+func (s *SurgeService) getFloatSetting(key string) (float64, error) {
+	valueStr, err := s.SettingApp.GetSettingByKey(key)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(valueStr, 64)
+}
@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrURLNotAllowed is returned for the endpoints that don't use https or resolve to an address
+// that isn't public, such as the loopback, the private networks or the cloud metadata service.
+var ErrURLNotAllowed = errors.New("webhook URL must use https and resolve to a public address")
+
+// nonPublicNetworks are the ranges reserved for special uses that net.IP doesn't report
+var nonPublicNetworks = func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",     // this network
+		"100.64.0.0/10", // carrier-grade NAT
+		"192.0.0.0/24",  // IETF protocol assignments
+		"198.18.0.0/15", // benchmarking
+		"240.0.0.0/4",   // reserved
+		"64:ff9b::/96",  // IPv4/IPv6 translation
+	} {
+		_, network, _ := net.ParseCIDR(cidr)
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+// ValidateURL makes sure an endpoint uses https and every address its host resolves to is public.
+// The addresses are checked again when a delivery connects, since the host can resolve differently.
+func ValidateURL(rawURL string) error {
+	endpoint, err := url.Parse(rawURL)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Hostname() == "" {
+		return ErrURLNotAllowed
+	}
+
+	ips, err := net.LookupIP(endpoint.Hostname())
+	if err != nil || len(ips) == 0 {
+		return ErrURLNotAllowed
+	}
+
+	for _, ip := range ips {
+		if !isPublicIP(ip) {
+			return ErrURLNotAllowed
+		}
+	}
+
+	return nil
+}
+
+// isPublicIP reports whether an address can be reached on the internet
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newHTTPClient returns the client posting the deliveries. It only connects to public addresses,
+// checked once the host is resolved, doesn't follow redirects and doesn't go through a proxy.
+func newHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return ErrURLNotAllowed
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, address)
+			},
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
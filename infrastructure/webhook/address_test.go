@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"net"
+	"testing"
+)
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want error
+	}{
+		{name: "public https", url: "https://8.8.8.8/hooks", want: nil},
+		{name: "http", url: "http://8.8.8.8/hooks", want: ErrURLNotAllowed},
+		{name: "no host", url: "https:///hooks", want: ErrURLNotAllowed},
+		{name: "loopback", url: "https://127.0.0.1/hooks", want: ErrURLNotAllowed},
+		{name: "localhost", url: "https://localhost/hooks", want: ErrURLNotAllowed},
+		{name: "private network", url: "https://10.0.0.5/hooks", want: ErrURLNotAllowed},
+		{name: "metadata service", url: "https://169.254.169.254/latest/meta-data", want: ErrURLNotAllowed},
+		{name: "carrier-grade NAT", url: "https://100.64.0.1/hooks", want: ErrURLNotAllowed},
+		{name: "IPv6 loopback", url: "https://[::1]/hooks", want: ErrURLNotAllowed},
+		{name: "IPv6 unique local", url: "https://[fd00::1]/hooks", want: ErrURLNotAllowed},
+		{name: "IPv4-mapped loopback", url: "https://[::ffff:127.0.0.1]/hooks", want: ErrURLNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidateURL(tt.url); got != tt.want {
+				t.Errorf("ValidateURL(%q) = %v, want %v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPClientRefusesNonPublicAddresses(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	_, err = newHTTPClient().Get("http://" + listener.Addr().String())
+	if err == nil {
+		t.Fatal("the client connected to a loopback address")
+	}
+}
@@ -0,0 +1,223 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+)
+
+// batchSize is the number of due deliveries attempted in each round.
+const batchSize = 100
+
+// maxAttempts is the number of times a delivery is attempted before it's marked as failed.
+const maxAttempts = 10
+
+// maxBackoff caps the delay between the attempts of a failing delivery.
+const maxBackoff = 6 * time.Hour
+
+// SignatureHeader carries the signature of a delivery, "t=<unix time>,v1=<hex HMAC-SHA256>". The
+// HMAC is computed with the subscription's secret over the time, a dot and the request body, so
+// the endpoint can check both the sender and the age of a delivery.
+const SignatureHeader = "X-Jayeek-Signature"
+
+// WebhookServiceInterface defines the methods that a webhook service should implement.
+type WebhookServiceInterface interface {
+	Start(interval time.Duration)
+	DeliverDueWebhooks(now time.Time) error
+	HandleEvent(event *entity.OutboxEvent) error
+}
+
+// WebhookService delivers the events of the business customers' orders to their webhook subscriptions.
+type WebhookService struct {
+	UnitOfWorkApp application.UnitOfWorkApplicationInterface
+	WebhookApp    application.WebhookApplicationInterface
+	HTTPClient    *http.Client
+}
+
+// Ensure that WebhookService implements WebhookServiceInterface.
+var _ WebhookServiceInterface = &WebhookService{}
+
+// NewWebhookService creates and returns a new instance of WebhookService.
+func NewWebhookService(unitOfWorkApp application.UnitOfWorkApplicationInterface, webhookApp application.WebhookApplicationInterface) *WebhookService {
+	return &WebhookService{
+		UnitOfWorkApp: unitOfWorkApp,
+		WebhookApp:    webhookApp,
+		HTTPClient:    newHTTPClient(),
+	}
+}
+
+// Start attempts the due deliveries every interval until the process exits.
+func (s *WebhookService) Start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if err := s.DeliverDueWebhooks(now); err != nil {
+			log.Println("Error delivering webhooks: ", err)
+		}
+	}
+}
+
+// HandleEvent queues the deliveries of an event to the active subscriptions of the order's sender
+// that selected it. A delivery is only queued once per subscription, so the event can be handled again.
+func (s *WebhookService) HandleEvent(event *entity.OutboxEvent) error {
+	userID, err := event.UserID()
+	if err != nil {
+		return err
+	}
+	if userID == 0 {
+		return nil
+	}
+
+	subscriptions, err := s.WebhookApp.GetAllActiveWebhookSubscriptionsByUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []entity.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !subscription.Selects(event.Type) {
+			continue
+		}
+
+		deliveries = append(deliveries, entity.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        event.Payload,
+			Status:         entity.WebhookDeliveryPendingStatus,
+		})
+	}
+
+	return s.WebhookApp.CreateWebhookDeliveries(deliveries)
+}
+
+// DeliverDueWebhooks attempts the deliveries due for an attempt. The deliveries are locked while
+// they're attempted, so each one is attempted by a single instance. An endpoint that failed isn't
+// attempted again in the same round.
+func (s *WebhookService) DeliverDueWebhooks(now time.Time) error {
+	return s.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		deliveries, err := tx.Webhook().GetAllDueWebhookDeliveries(now, batchSize)
+		if err != nil {
+			return err
+		}
+
+		failing := make(map[uint64]bool)
+
+		for _, delivery := range deliveries {
+			delivery := delivery
+
+			if failing[delivery.SubscriptionID] {
+				continue
+			}
+
+			if err := s.attempt(&delivery, now); err != nil {
+				failing[delivery.SubscriptionID] = true
+				log.Printf("Error delivering webhook %d to %s (attempt %d): %v", delivery.ID, delivery.Subscription.URL, delivery.Attempts, err)
+			}
+
+			if _, err := tx.Webhook().UpdateWebhookDeliveryByID(delivery.ID, &delivery); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// attempt delivers a delivery once and records the outcome in it. A failed delivery is attempted
+// again after a backoff, until it's given up on.
+func (s *WebhookService) attempt(delivery *entity.WebhookDelivery, now time.Time) error {
+	delivery.Attempts++
+
+	if err := s.deliver(delivery, now); err != nil {
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = now.Add(backoff(delivery.Attempts))
+		if delivery.Attempts >= maxAttempts {
+			delivery.Status = entity.WebhookDeliveryFailedStatus
+		}
+		return err
+	}
+
+	deliveredAt := time.Now()
+	delivery.Status = entity.WebhookDeliverySucceededStatus
+	delivery.LastError = ""
+	delivery.DeliveredAt = &deliveredAt
+	return nil
+}
+
+// deliver posts a delivery to its endpoint, recording the status of the response in the delivery.
+// Only the status is kept, the endpoint's answer is never read back to its owner.
+func (s *WebhookService) deliver(delivery *entity.WebhookDelivery, now time.Time) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"id":         delivery.ID,
+		"event_id":   delivery.EventID,
+		"type":       delivery.EventType,
+		"created_at": delivery.CreatedAt,
+		"data":       delivery.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, delivery.Subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Jayeek-Webhooks/1.0")
+	request.Header.Set("X-Jayeek-Event", string(delivery.EventType))
+	request.Header.Set("X-Jayeek-Delivery", strconv.FormatUint(delivery.ID, 10))
+	request.Header.Set(SignatureHeader, Sign(delivery.Subscription.Secret, now, body))
+
+	response, err := s.HTTPClient.Do(request)
+	if err != nil {
+		delivery.ResponseStatus = 0
+		return err
+	}
+	defer response.Body.Close()
+
+	delivery.ResponseStatus = response.StatusCode
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("endpoint answered with status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign returns the signature header of a body sent at the given time.
+func Sign(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// backoff returns the delay before the next attempt of a delivery that failed the given number of
+// times, doubling from 30 seconds up to six hours.
+func backoff(attempts int) time.Duration {
+	delay := 30 * time.Second
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
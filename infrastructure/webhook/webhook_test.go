@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"gorm.io/datatypes"
+)
+
+func TestSign(t *testing.T) {
+	at := time.Unix(1709287200, 0)
+	body := []byte(`{"id":1}`)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1709287200." + string(body)))
+	want := "t=1709287200,v1=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign("secret", at, body); got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+
+	if Sign("other", at, body) == want {
+		t.Error("Sign() with another secret gave the same signature")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 2, want: time.Minute},
+		{attempts: 3, want: 2 * time.Minute},
+		{attempts: 9, want: 128 * time.Minute},
+		{attempts: 10, want: 256 * time.Minute},
+		{attempts: 11, want: maxBackoff},
+		{attempts: 50, want: maxBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.attempts), func(t *testing.T) {
+			if got := backoff(tt.attempts); got != tt.want {
+				t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttempt(t *testing.T) {
+	now := time.Unix(1709287200, 0)
+
+	tests := []struct {
+		name          string
+		status        int
+		attempts      int
+		wantStatus    entity.WebhookDeliveryStatus
+		wantAttempts  int
+		wantNext      time.Time
+		wantDelivered bool
+		wantErr       bool
+	}{
+		{name: "delivered", status: http.StatusNoContent, wantStatus: entity.WebhookDeliverySucceededStatus, wantAttempts: 1, wantDelivered: true},
+		{name: "first failure", status: http.StatusInternalServerError, wantStatus: entity.WebhookDeliveryPendingStatus, wantAttempts: 1, wantNext: now.Add(30 * time.Second), wantErr: true},
+		{name: "third failure", status: http.StatusBadGateway, attempts: 2, wantStatus: entity.WebhookDeliveryPendingStatus, wantAttempts: 3, wantNext: now.Add(2 * time.Minute), wantErr: true},
+		{name: "last failure", status: http.StatusNotFound, attempts: maxAttempts - 1, wantStatus: entity.WebhookDeliveryFailedStatus, wantAttempts: maxAttempts, wantNext: now.Add(backoff(maxAttempts)), wantErr: true},
+		{name: "redirect", status: http.StatusFound, wantStatus: entity.WebhookDeliveryPendingStatus, wantAttempts: 1, wantNext: now.Add(30 * time.Second), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request *http.Request
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				request = r
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			// The test server listens on the loopback, which the service's own client refuses
+			service := &WebhookService{HTTPClient: &http.Client{
+				CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+			}}
+
+			delivery := entity.WebhookDelivery{
+				ID:           7,
+				EventID:      3,
+				EventType:    entity.OrderDeliveredEvent,
+				Payload:      datatypes.JSON(`{"order_id":42}`),
+				Status:       entity.WebhookDeliveryPendingStatus,
+				Attempts:     tt.attempts,
+				Subscription: entity.WebhookSubscription{URL: server.URL + "/hooks", Secret: "secret"},
+			}
+
+			err := service.attempt(&delivery, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("attempt() error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if request == nil {
+				t.Fatal("attempt() didn't reach the endpoint")
+			}
+			if got := request.Header.Get(SignatureHeader); got != Sign("secret", now, body) {
+				t.Errorf("%s = %q, want the signature of the body", SignatureHeader, got)
+			}
+			if got := request.Header.Get("X-Jayeek-Event"); got != string(entity.OrderDeliveredEvent) {
+				t.Errorf("X-Jayeek-Event = %q, want %q", got, entity.OrderDeliveredEvent)
+			}
+			if !strings.Contains(string(body), `"data":{"order_id":42}`) {
+				t.Errorf("body %s doesn't carry the payload", body)
+			}
+
+			if delivery.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", delivery.Status, tt.wantStatus)
+			}
+			if delivery.Attempts != tt.wantAttempts {
+				t.Errorf("Attempts = %d, want %d", delivery.Attempts, tt.wantAttempts)
+			}
+			if delivery.ResponseStatus != tt.status {
+				t.Errorf("ResponseStatus = %d, want %d", delivery.ResponseStatus, tt.status)
+			}
+			if tt.wantErr && !delivery.NextAttemptAt.Equal(tt.wantNext) {
+				t.Errorf("NextAttemptAt = %v, want %v", delivery.NextAttemptAt, tt.wantNext)
+			}
+			if tt.wantErr && delivery.LastError == "" {
+				t.Error("LastError is empty after a failure")
+			}
+			if (delivery.DeliveredAt != nil) != tt.wantDelivered {
+				t.Errorf("DeliveredAt = %v, want delivered %v", delivery.DeliveredAt, tt.wantDelivered)
+			}
+		})
+	}
+}
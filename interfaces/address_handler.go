@@ -0,0 +1,240 @@
+package interfaces
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/pkg/language"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Addresses holds the address book-related application interfaces
+type Addresses struct {
+	AuthService  auth.AuthServiceInterface
+	TokenService auth.TokenInterface
+	UserApp      application.UserApplicationInterface
+	LocationApp  application.LocationApplicationInterface
+	AddressApp   application.AddressApplicationInterface
+}
+
+// NewAddresses creates and returns a new instance of Addresses.
+func NewAddresses(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, userApp application.UserApplicationInterface, locationApp application.LocationApplicationInterface, addressApp application.AddressApplicationInterface) *Addresses {
+	return &Addresses{
+		AuthService:  authService,
+		TokenService: tokenService,
+		UserApp:      userApp,
+		LocationApp:  locationApp,
+		AddressApp:   addressApp,
+	}
+}
+
+// GetAllAddresses retrieves the saved addresses of the authenticated user, the default one first
+func (a *Addresses) GetAllAddresses(ctx *gin.Context) {
+	user, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	addresses, err := a.AddressApp.GetAllAddressesByUserID(user.ID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if len(addresses) <= 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No addresses found."))
+		return
+	}
+
+	var addressPublicData []interface{}
+	for _, address := range addresses {
+		addressPublicData = append(addressPublicData, address.PublicData(language.GetLanguage(ctx)))
+	}
+
+	response.SendOK(ctx, addressPublicData, "")
+}
+
+// GetAddressByID retrieves a saved address of the authenticated user
+func (a *Addresses) GetAddressByID(ctx *gin.Context) {
+	user, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	address, ok := a.getAddress(ctx, user)
+	if !ok {
+		return
+	}
+
+	response.SendOK(ctx, address.PublicData(language.GetLanguage(ctx)), "")
+}
+
+// CreateAddress saves an address in the authenticated user's address book
+func (a *Addresses) CreateAddress(ctx *gin.Context) {
+	var address entity.Address
+
+	// Bind the JSON body of the request to the Address struct
+	if err := ctx.ShouldBindJSON(&address); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	user, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	address.ID = 0
+	address.UserID = user.ID
+
+	if !a.prepareAddress(ctx, &address) {
+		return
+	}
+
+	createdAddress, err := a.AddressApp.CreateAddress(&address)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendCreated(ctx, createdAddress.PublicData(language.GetLanguage(ctx)), "")
+}
+
+// UpdateAddressByID updates a saved address of the authenticated user
+func (a *Addresses) UpdateAddressByID(ctx *gin.Context) {
+	var input entity.Address
+
+	// Bind the JSON body of the request to the Address struct
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	user, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	address, ok := a.getAddress(ctx, user)
+	if !ok {
+		return
+	}
+
+	input.ID = address.ID
+	input.UserID = user.ID
+	input.CreatedAt = address.CreatedAt
+	input.UpdatedAt = time.Now()
+
+	// The default address is replaced by making another one the default, not unset
+	if address.IsDefault {
+		input.IsDefault = true
+	}
+
+	if !a.prepareAddress(ctx, &input) {
+		return
+	}
+
+	updatedAddress, err := a.AddressApp.UpdateAddressByID(address.ID, &input)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, updatedAddress.PublicData(language.GetLanguage(ctx)), "")
+}
+
+// DeleteAddressByID deletes a saved address of the authenticated user. The orders it was picked
+// for keep their copy of it.
+func (a *Addresses) DeleteAddressByID(ctx *gin.Context) {
+	user, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	address, ok := a.getAddress(ctx, user)
+	if !ok {
+		return
+	}
+
+	if err := a.AddressApp.DeleteAddressByID(address.ID); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, nil, ginI18n.MustGetMessage("Address deleted successfully."))
+}
+
+// prepareAddress validates an address and sets the location nearest to its coordinates
+func (a *Addresses) prepareAddress(ctx *gin.Context, address *entity.Address) bool {
+	validationErrors, _ := validator.ValidateExcept(ctx, address, "UserID", "LocationID", "Location")
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return false
+	}
+
+	location, err := a.LocationApp.GetLocationByCoordinates(address.Longitude, address.Latitude, nil)
+	if err != nil {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Location not found."))
+		return false
+	}
+
+	address.LocationID = location.ID
+	address.Location = entity.Location{}
+
+	return true
+}
+
+// getAddress retrieves the address of the authenticated user named in the URL
+func (a *Addresses) getAddress(ctx *gin.Context, user *entity.User) (*entity.Address, bool) {
+	// Parse the address ID from the URL parameter.
+	addressID, err := strconv.ParseUint(ctx.Param("address_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid address ID."))
+		return nil, false
+	}
+
+	address, err := a.AddressApp.GetAddressByIDAndUserID(addressID, user.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.SendNotFound(ctx, ginI18n.MustGetMessage("Address not found."))
+			return nil, false
+		}
+		response.SendInternalServerError(ctx, err.Error())
+		return nil, false
+	}
+
+	return address, true
+}
+
+func (a *Addresses) authenticate(ctx *gin.Context) (*entity.User, bool) {
+	// Extract the token metadata from the request
+	metadata, err := a.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := a.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Get the user from the user application service
+	user, err := a.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	return user, true
+}
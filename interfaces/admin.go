@@ -0,0 +1,42 @@
+package interfaces
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// requireAdmin returns the authenticated user if they're an admin, sending an error response
+// otherwise. The handlers only open to the admins check their users with it.
+func requireAdmin(ctx *gin.Context, tokenService auth.TokenInterface, authService auth.AuthServiceInterface, userApp application.UserApplicationInterface) (*entity.User, bool) {
+	// Extract the token metadata from the request
+	metadata, err := tokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := authService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Get the user from the user application service
+	user, err := userApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	if !user.IsAdmin() {
+		response.SendForbidden(ctx, ginI18n.MustGetMessage("Forbidden"))
+		return nil, false
+	}
+
+	return user, true
+}
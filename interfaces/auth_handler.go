@@ -8,8 +8,9 @@ import (
 
 	"github.com/OmarBader7/web-service-jayeek/application"
 	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
-	"github.com/OmarBader7/web-service-jayeek/infrastructure/chat"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/stats"
 	"github.com/OmarBader7/web-service-jayeek/pkg/language"
 	"github.com/OmarBader7/web-service-jayeek/pkg/response"
 	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
@@ -21,24 +22,26 @@ import (
 type Auth struct {
 	AuthService          auth.AuthServiceInterface
 	TokenService         auth.TokenInterface
-	ChatService          chat.ChatServiceInterface
+	UnitOfWorkApp        application.UnitOfWorkApplicationInterface
 	UserApp              application.UserApplicationInterface
 	LocationApp          application.LocationApplicationInterface
 	OrderApp             application.OrderApplicationInterface
 	PasswordResetApp     application.PasswordResetApplicationInterface
 	PhoneVerificationApp application.PhoneVerificationApplicationInterface
+	StatsService         stats.StatsServiceInterface
 }
 
-func NewAuth(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, chatService chat.ChatServiceInterface, userApp application.UserApplicationInterface, locationApp application.LocationApplicationInterface, orderApp application.OrderApplicationInterface, passwordReset application.PasswordResetApplicationInterface, phoneVerification application.PhoneVerificationApplicationInterface) *Auth {
+func NewAuth(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, unitOfWorkApp application.UnitOfWorkApplicationInterface, userApp application.UserApplicationInterface, locationApp application.LocationApplicationInterface, orderApp application.OrderApplicationInterface, passwordReset application.PasswordResetApplicationInterface, phoneVerification application.PhoneVerificationApplicationInterface, statsService stats.StatsServiceInterface) *Auth {
 	return &Auth{
 		AuthService:          authService,
 		TokenService:         tokenService,
-		ChatService:          chatService,
+		UnitOfWorkApp:        unitOfWorkApp,
 		UserApp:              userApp,
 		LocationApp:          locationApp,
 		OrderApp:             orderApp,
 		PasswordResetApp:     passwordReset,
 		PhoneVerificationApp: phoneVerification,
+		StatsService:         statsService,
 	}
 }
 
@@ -65,6 +68,16 @@ func (a *Auth) Register(c *gin.Context) {
 		return
 	}
 
+	// Link the user to the referrer whose code was entered.
+	if user.ReferredByCode != nil && *user.ReferredByCode != "" {
+		referrer, err := a.UserApp.GetUserByReferralCode(*user.ReferredByCode)
+		if err != nil {
+			response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Invalid referral code."))
+			return
+		}
+		user.ReferredByID = referrer.ID
+	}
+
 	// Create a new user.
 	user.Role = "user"
 
@@ -95,23 +108,28 @@ func (a *Auth) Register(c *gin.Context) {
 		return
 	}
 
-	for _, order := range orders {
-		order.RecipientID = user.ID
+	// The orders sent to the new user are linked to them, joining the chats of the ongoing ones
+	err = a.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		for _, order := range orders {
+			order := order
+			order.RecipientID = user.ID
 
-		_, err = a.OrderApp.UpdateOrderByID(order.ID, &order)
-		if err != nil {
-			response.SendInternalServerError(c, err.Error())
-			return
-		}
-
-		if order.Status == entity.OrderAcceptedStatus || order.Status == entity.ShipmentPickedUpStatus {
-			err = a.ChatService.AddMember(fmt.Sprintf("order-%d", order.ID), fmt.Sprintf("client-%d", user.ID))
+			if _, err := tx.Order().UpdateOrderByID(order.ID, &order); err != nil {
+				return err
+			}
 
-			if err != nil {
-				response.SendInternalServerError(c, ginI18n.MustGetMessage("Failed to add members from the chat channel."))
-				return
+			if order.Status == entity.OrderAcceptedStatus || order.Status == entity.ShipmentPickedUpStatus {
+				if err := emitOrderEvent(tx, entity.OrderRecipientLinkedEvent, &order, order.Driver.UserID); err != nil {
+					return err
+				}
 			}
 		}
+
+		return nil
+	})
+	if err != nil {
+		response.SendInternalServerError(c, err.Error())
+		return
 	}
 
 	userData := make(map[string]interface{})
@@ -159,6 +177,13 @@ func (a *Auth) Login(c *gin.Context) {
 		return
 	}
 
+	stats, err := a.StatsService.GetUserStats(u.ID)
+	if err != nil {
+		response.SendInternalServerError(c, err.Error())
+		return
+	}
+	u.SetStats(stats)
+
 	userData := make(map[string]interface{})
 	userData["access_token"] = ts.AccessToken
 	userData["refresh_token"] = ts.RefreshToken
@@ -255,6 +280,13 @@ func (a *Auth) Refresh(c *gin.Context) {
 		return
 	}
 
+	stats, err := a.StatsService.GetUserStats(user.ID)
+	if err != nil {
+		response.SendInternalServerError(c, err.Error())
+		return
+	}
+	user.SetStats(stats)
+
 	userData := make(map[string]interface{})
 	userData["access_token"] = ts.AccessToken
 	userData["refresh_token"] = ts.RefreshToken
@@ -400,6 +432,13 @@ func (a *Auth) Reset(c *gin.Context) {
 		return
 	}
 
+	stats, err := a.StatsService.GetUserStats(updatedUser.ID)
+	if err != nil {
+		response.SendInternalServerError(c, err.Error())
+		return
+	}
+	updatedUser.SetStats(stats)
+
 	userData := make(map[string]interface{})
 	userData["access_token"] = ts.AccessToken
 	userData["refresh_token"] = ts.RefreshToken
@@ -2,6 +2,7 @@
 package interfaces
 
 import (
+	"github.com/OmarBader7/web-service-jayeek/application"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
 	"github.com/OmarBader7/web-service-jayeek/pkg/response"
 	ginI18n "github.com/gin-contrib/i18n"
@@ -11,6 +12,10 @@ import (
 // AuthMiddleware is a gin middleware that handles the authorization of incoming requests.
 func AuthMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		// API keys are only accepted by the routes using APIKeyAuthMiddleware, the key is dropped
+		// so the handlers authenticate the request by its token.
+		ctx.Request.Header.Del(auth.APIKeyHeader)
+
 		// Check if the request has a valid token.
 		err := auth.TokenValid(ctx.Request)
 		if err != nil {
@@ -23,3 +28,24 @@ func AuthMiddleware() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// APIKeyAuthMiddleware is a gin middleware that authorizes the requests made with an organization's
+// API key, or with a valid token. It's used by the order routes the organizations' systems call.
+func APIKeyAuthMiddleware(apiKeyApp application.APIKeyApplicationInterface) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := auth.ExtractAPIKey(ctx.Request)
+		if key == "" {
+			AuthMiddleware()(ctx)
+			return
+		}
+
+		// The key must exist and must not have been revoked
+		if _, err := apiKeyApp.GetActiveAPIKeyByKeyHash(auth.HashAPIKey(key)); err != nil {
+			response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,123 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
+)
+
+// fakeAPIKeyApp finds the unrevoked keys among its keys, by their hash
+type fakeAPIKeyApp struct {
+	application.APIKeyApplicationInterface
+	apiKeys map[string]entity.APIKey
+}
+
+func (a *fakeAPIKeyApp) GetActiveAPIKeyByKeyHash(keyHash string) (*entity.APIKey, error) {
+	apiKey, ok := a.apiKeys[keyHash]
+	if !ok || apiKey.RevokedAt != nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &apiKey, nil
+}
+
+// newTestRouter returns a router translating the messages like the service does
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ginI18n.Localize(ginI18n.WithBundle(&ginI18n.BundleCfg{
+		RootPath:         "../resources",
+		AcceptLanguage:   []language.Tag{language.Arabic, language.English},
+		DefaultLanguage:  language.English,
+		UnmarshalFunc:    json.Unmarshal,
+		FormatBundleFile: "json",
+	})))
+	return router
+}
+
+func TestAuthMiddlewares(t *testing.T) {
+	t.Setenv("ACCESS_SECRET", "test-access-secret")
+	t.Setenv("REFRESH_SECRET", "test-refresh-secret")
+
+	tokenDetails, err := auth.NewToken().CreateToken(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	activeKey, _, activeKeyHash, _ := auth.GenerateAPIKey()
+	revokedKey, _, revokedKeyHash, _ := auth.GenerateAPIKey()
+	madeUpKey, _, _, _ := auth.GenerateAPIKey()
+
+	revokedAt := time.Now()
+	apiKeyApp := &fakeAPIKeyApp{apiKeys: map[string]entity.APIKey{
+		activeKeyHash:  {ID: 1, KeyHash: activeKeyHash},
+		revokedKeyHash: {ID: 2, KeyHash: revokedKeyHash, RevokedAt: &revokedAt},
+	}}
+
+	router := newTestRouter()
+	ok := func(ctx *gin.Context) { ctx.Status(http.StatusOK) }
+	router.GET("/session", AuthMiddleware(), ok)
+	router.GET("/api-key", APIKeyAuthMiddleware(apiKeyApp), ok)
+
+	tests := []struct {
+		name   string
+		path   string
+		apiKey string
+		token  string
+		want   int
+	}{
+		{name: "no credentials", path: "/api-key", want: http.StatusUnauthorized},
+		{name: "made-up key", path: "/api-key", apiKey: madeUpKey, want: http.StatusUnauthorized},
+		{name: "revoked key", path: "/api-key", apiKey: revokedKey, want: http.StatusUnauthorized},
+		{name: "active key", path: "/api-key", apiKey: activeKey, want: http.StatusOK},
+		{name: "token on an API key route", path: "/api-key", token: tokenDetails.AccessToken, want: http.StatusOK},
+		{name: "invalid token", path: "/session", token: "invalid", want: http.StatusUnauthorized},
+		{name: "token", path: "/session", token: tokenDetails.AccessToken, want: http.StatusOK},
+		{name: "active key on a session route", path: "/session", apiKey: activeKey, want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.apiKey != "" {
+				req.Header.Set(auth.APIKeyHeader, tt.apiKey)
+			}
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("got status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	t.Run("session routes drop the key", func(t *testing.T) {
+		router := newTestRouter()
+		var apiKey string
+		router.GET("/session", AuthMiddleware(), func(ctx *gin.Context) {
+			apiKey = auth.ExtractAPIKey(ctx.Request)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/session", nil)
+		req.Header.Set(auth.APIKeyHeader, activeKey)
+		req.Header.Set("Authorization", "Bearer "+tokenDetails.AccessToken)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		if apiKey != "" {
+			t.Errorf("the handler got the API key %q", apiKey)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package interfaces
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strconv"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/calling"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Calls struct {
+	AuthService    auth.AuthServiceInterface
+	TokenService   auth.TokenInterface
+	CallingService calling.CallingServiceInterface
+	UserApp        application.UserApplicationInterface
+	OrderApp       application.OrderApplicationInterface
+	DriverApp      application.DriverApplicationInterface
+	// WebhookSecret authenticates the telephony provider, forwarding is disabled when it's empty
+	WebhookSecret string
+}
+
+// NewCalls creates and returns a new instance of Calls.
+func NewCalls(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, callingService calling.CallingServiceInterface, userApp application.UserApplicationInterface, orderApp application.OrderApplicationInterface, driverApp application.DriverApplicationInterface, webhookSecret string) *Calls {
+	return &Calls{
+		AuthService:    authService,
+		TokenService:   tokenService,
+		CallingService: callingService,
+		UserApp:        userApp,
+		OrderApp:       orderApp,
+		DriverApp:      driverApp,
+		WebhookSecret:  webhookSecret,
+	}
+}
+
+// GetOrderCallNumber returns the masked number the sender dials to reach the driver of an order,
+// or the driver dials to reach the sender, while the order is being delivered.
+func (c *Calls) GetOrderCallNumber(ctx *gin.Context) {
+	// Extract the token metadata from the request
+	metadata, err := c.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := c.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Get the user from the user application service
+	user, err := c.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Parse the order ID from the URL parameter.
+	orderID, err := strconv.ParseUint(ctx.Param("order_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid order ID."))
+		return
+	}
+
+	order, err := c.OrderApp.GetOrderByID(orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.SendNotFound(ctx, ginI18n.MustGetMessage("Order not found."))
+			return
+		}
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	var calleePhone string
+	if order.UserID == user.ID {
+		calleePhone = order.Driver.User.Phone
+	} else if driver, err := c.DriverApp.GetDriverByUserID(user.ID); err == nil && order.DriverID != 0 && driver.ID == order.DriverID {
+		calleePhone = order.User.Phone
+	} else {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Order not found."))
+		return
+	}
+
+	if order.DriverID == 0 || !isCallableStatus(order.Status) {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Calls are only available while the order is being delivered."))
+		return
+	}
+
+	number, err := c.CallingService.GetMaskedNumber(user.Phone, calleePhone)
+	if err != nil {
+		if errors.Is(err, calling.ErrNotConfigured) || errors.Is(err, calling.ErrNoNumberAvailable) {
+			response.SendNotFound(ctx, ginI18n.MustGetMessage("Calling isn't available right now."))
+			return
+		}
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	// Prepare the response data.
+	data := make(map[string]interface{})
+	data["number"] = number
+
+	response.SendOK(ctx, data, "")
+}
+
+// ForwardCall handles the telephony provider's webhook, returning the phone number a call to a
+// masked number is forwarded to.
+func (c *Calls) ForwardCall(ctx *gin.Context) {
+	if c.WebhookSecret == "" {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Calling isn't available right now."))
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(ctx.GetHeader("X-Webhook-Secret")), []byte(c.WebhookSecret)) != 1 {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	var input struct {
+		From string `json:"from" validate:"required"`
+		To   string `json:"to" validate:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &input)
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	phone, err := c.CallingService.ResolveCall(input.From, input.To)
+	if err != nil {
+		if errors.Is(err, calling.ErrSessionNotFound) {
+			response.SendNotFound(ctx, ginI18n.MustGetMessage("Call session not found."))
+			return
+		}
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	// Prepare the response data.
+	data := make(map[string]interface{})
+	data["forward_to"] = phone
+
+	response.SendOK(ctx, data, "")
+}
+
+// isCallableStatus reports whether the sender and the driver of an order in the given status can call each other
+func isCallableStatus(status entity.OrderStatus) bool {
+	switch status {
+	case entity.OrderAcceptedStatus, entity.PickupInProgressStatus, entity.ShipmentPickedUpStatus, entity.InTransitStatus,
+		entity.AtDestinationCityStatus, entity.OutForDeliveryStatus, entity.DeliveryAttemptedStatus, entity.DeliveryRescheduledStatus:
+		return true
+	}
+	return false
+}
@@ -4,25 +4,39 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/OmarBader7/web-service-jayeek/application"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/chat"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/moderation"
 	"github.com/OmarBader7/web-service-jayeek/pkg/response"
 	ginI18n "github.com/gin-contrib/i18n"
 	"github.com/gin-gonic/gin"
 )
 
 type Chat struct {
-	AuthService  auth.AuthServiceInterface
-	TokenService auth.TokenInterface
-	ChatService  chat.ChatServiceInterface
+	AuthService       auth.AuthServiceInterface
+	TokenService      auth.TokenInterface
+	ChatService       chat.ChatServiceInterface
+	ModerationService moderation.ModerationServiceInterface
+	UserApp           application.UserApplicationInterface
+	FlaggedMessageApp application.FlaggedMessageApplicationInterface
+	// MessagingService is nil when the conversations are hosted by a third party such as Stream
+	MessagingService chat.MessagingServiceInterface
+	// WebhookVerifier is nil when the chat provider doesn't send webhooks
+	WebhookVerifier chat.WebhookVerifierInterface
 }
 
 // NewChat creates and returns a new instance of Chat.
-func NewChat(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, chatService chat.ChatServiceInterface) *Chat {
+func NewChat(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, chatService chat.ChatServiceInterface, messagingService chat.MessagingServiceInterface, webhookVerifier chat.WebhookVerifierInterface, moderationService moderation.ModerationServiceInterface, userApp application.UserApplicationInterface, flaggedMessageApp application.FlaggedMessageApplicationInterface) *Chat {
 	return &Chat{
-		AuthService:  authService,
-		TokenService: tokenService,
-		ChatService:  chatService,
+		AuthService:       authService,
+		TokenService:      tokenService,
+		ChatService:       chatService,
+		ModerationService: moderationService,
+		UserApp:           userApp,
+		FlaggedMessageApp: flaggedMessageApp,
+		MessagingService:  messagingService,
+		WebhookVerifier:   webhookVerifier,
 	}
 }
 
@@ -0,0 +1,267 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/chat"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// chatMessagesPerPage is the number of messages of a channel returned at once
+const chatMessagesPerPage = 50
+
+// socketPingInterval is how often the real-time socket is checked for a dead connection
+const socketPingInterval = 30 * time.Second
+
+// The mobile apps connect from no particular origin, the chat token authenticates the socket
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// GetChatChannels retrieves the chat channels of the authenticated user
+func (c *Chat) GetChatChannels(ctx *gin.Context) {
+	chatUserID, ok := c.getChatUserID(ctx)
+	if !ok {
+		return
+	}
+
+	channels, err := c.MessagingService.GetChannels(chatUserID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if len(channels) <= 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No chat channels found."))
+		return
+	}
+
+	var channelPublicData []interface{}
+	for _, channel := range channels {
+		channelPublicData = append(channelPublicData, channel.PublicData())
+	}
+
+	response.SendOK(ctx, channelPublicData, "")
+}
+
+// GetChatMessages retrieves the latest messages of a chat channel, newest first. Older messages
+// are fetched with the before_id query parameter.
+func (c *Chat) GetChatMessages(ctx *gin.Context) {
+	chatUserID, ok := c.getChatUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var beforeID uint64
+	if beforeIDStr := ctx.Query("before_id"); beforeIDStr != "" {
+		var err error
+		if beforeID, err = strconv.ParseUint(beforeIDStr, 10, 64); err != nil {
+			response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid message ID."))
+			return
+		}
+	}
+
+	messages, err := c.MessagingService.GetMessages(ctx.Param("channel_id"), chatUserID, beforeID, chatMessagesPerPage)
+	if err != nil {
+		sendChatError(ctx, err)
+		return
+	}
+
+	var messagePublicData []interface{}
+	for _, message := range messages {
+		messagePublicData = append(messagePublicData, message.PublicData())
+	}
+
+	response.SendOK(ctx, messagePublicData, "")
+}
+
+// SendChatMessage sends a message to a chat channel, delivering it to the connected members
+func (c *Chat) SendChatMessage(ctx *gin.Context) {
+	var input struct {
+		Text string `json:"text" validate:"required,max=5000"`
+	}
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	chatUserID, ok := c.getChatUserID(ctx)
+	if !ok {
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &input)
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	// Contact details are masked before the message reaches the other members
+	result, err := c.ModerationService.Moderate(input.Text)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	message, err := c.MessagingService.SendMessage(ctx.Param("channel_id"), chatUserID, result.Text)
+	if err != nil {
+		sendChatError(ctx, err)
+		return
+	}
+
+	if result.Flagged() {
+		c.flagMessage(chat.LocalProvider, message.ChannelID, chatUserID, strconv.FormatUint(message.ID, 10), input.Text, result)
+	}
+
+	response.SendCreated(ctx, message.PublicData(), "")
+}
+
+// MarkChatChannelRead records that the user has read the messages of a chat channel up to the given one
+func (c *Chat) MarkChatChannelRead(ctx *gin.Context) {
+	var input struct {
+		MessageID uint64 `json:"message_id" validate:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	chatUserID, ok := c.getChatUserID(ctx)
+	if !ok {
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &input)
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	if err := c.MessagingService.MarkRead(ctx.Param("channel_id"), chatUserID, input.MessageID); err != nil {
+		sendChatError(ctx, err)
+		return
+	}
+
+	response.SendOK(ctx, nil, "")
+}
+
+// ConnectChatSocket upgrades the request to the real-time socket the events of the user's chat
+// channels are delivered over. The socket is authenticated with a chat token, passed in the token
+// query parameter since browsers can't set headers on sockets.
+func (c *Chat) ConnectChatSocket(ctx *gin.Context) {
+	if c.MessagingService == nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Not available with the configured chat provider."))
+		return
+	}
+
+	chatUserID, err := c.MessagingService.Authenticate(ctx.Query("token"))
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	events, unsubscribe, err := c.MessagingService.Subscribe(chatUserID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+	defer unsubscribe()
+
+	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		// The upgrader has already responded
+		return
+	}
+	defer conn.Close()
+
+	// Messages are sent over the API, reading only handles the control frames and notices when the
+	// connection is closed
+	closed := make(chan struct{})
+	conn.SetReadDeadline(time.Now().Add(2 * socketPingInterval))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * socketPingInterval))
+	})
+
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(socketPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// getChatUserID returns the chat user ID of the authenticated user, sending an error response
+// otherwise. Users chat as clients unless the as query parameter is set to driver.
+func (c *Chat) getChatUserID(ctx *gin.Context) (string, bool) {
+	if c.MessagingService == nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Not available with the configured chat provider."))
+		return "", false
+	}
+
+	// Extract the token metadata from the request
+	metadata, err := c.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return "", false
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := c.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return "", false
+	}
+
+	side := ctx.DefaultQuery("as", "client")
+	if side != "client" && side != "driver" {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid chat side."))
+		return "", false
+	}
+
+	return fmt.Sprintf("%s-%d", side, userID), true
+}
+
+// sendChatError sends the response matching an error of the messaging service
+func sendChatError(ctx *gin.Context, err error) {
+	if errors.Is(err, chat.ErrNotMember) {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Chat channel not found."))
+		return
+	}
+
+	log.Println("Error handling chat request: ", err)
+	response.SendInternalServerError(ctx, err.Error())
+}
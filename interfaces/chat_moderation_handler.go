@@ -0,0 +1,213 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/chat"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/moderation"
+	"github.com/OmarBader7/web-service-jayeek/pkg/pagination"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ModerateStreamMessage handles the before message send webhook of Stream. The message is returned
+// with its contact details masked, and flagged for review when needed.
+func (c *Chat) ModerateStreamMessage(ctx *gin.Context) {
+	if c.WebhookVerifier == nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Not available with the configured chat provider."))
+		return
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	if !c.WebhookVerifier.VerifyWebhook(body, []byte(ctx.GetHeader("X-Signature"))) {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// The message is decoded loosely so its other fields are returned to Stream untouched
+	var payload struct {
+		ChannelID string                 `json:"channel_id"`
+		Message   map[string]interface{} `json:"message"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Message == nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	text, _ := payload.Message["text"].(string)
+	if text == "" {
+		ctx.JSON(http.StatusOK, gin.H{"message": payload.Message})
+		return
+	}
+
+	result, err := c.ModerationService.Moderate(text)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if result.Flagged() {
+		payload.Message["text"] = result.Text
+
+		var userID string
+		if user, ok := payload.Message["user"].(map[string]interface{}); ok {
+			userID, _ = user["id"].(string)
+		}
+
+		channelID := payload.ChannelID
+		if channelID == "" {
+			channelID, _ = payload.Message["cid"].(string)
+		}
+
+		messageID, _ := payload.Message["id"].(string)
+
+		c.flagMessage(chat.StreamProvider, channelID, userID, messageID, text, result)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": payload.Message})
+}
+
+// GetAllFlaggedMessages retrieves a paginated list of the flagged chat messages, optionally
+// filtered by status. Only admins can review them.
+func (c *Chat) GetAllFlaggedMessages(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, c.TokenService, c.AuthService, c.UserApp); !ok {
+		return
+	}
+
+	// Get the desired page number from the query parameters.
+	page := pagination.GetPage(ctx)
+
+	// Set the number of items per page.
+	perPage := 30
+
+	status := ctx.Query("status")
+
+	count, err := c.FlaggedMessageApp.CountFlaggedMessages(status)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	flaggedMessages, err := c.FlaggedMessageApp.GetAllFlaggedMessages(status, page, perPage)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if page == 1 && len(flaggedMessages) <= 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No flagged messages found."))
+		return
+	}
+
+	// Check if the page is valid
+	if page <= 0 || (len(flaggedMessages) <= 0 && page*perPage > int(count)) {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Page not found."))
+		return
+	}
+
+	var flaggedMessagePublicData []interface{}
+	for _, flaggedMessage := range flaggedMessages {
+		flaggedMessagePublicData = append(flaggedMessagePublicData, flaggedMessage.PublicData())
+	}
+
+	// Prepare the response data.
+	data := make(map[string]interface{})
+	data["data"] = flaggedMessagePublicData
+	data["current_page"] = page
+	if page*perPage < int(count) {
+		data["next_page"] = page + 1
+	}
+	data["total"] = count
+
+	response.SendOK(ctx, data, "")
+}
+
+// ReviewFlaggedMessageByID records an admin's decision on a flagged chat message
+func (c *Chat) ReviewFlaggedMessageByID(ctx *gin.Context) {
+	var input struct {
+		Status entity.FlaggedMessageStatus `json:"status" validate:"required,oneof=dismissed confirmed"`
+	}
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	admin, ok := requireAdmin(ctx, c.TokenService, c.AuthService, c.UserApp)
+	if !ok {
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &input)
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	// Parse the flagged message ID from the URL parameter.
+	flaggedMessageID, err := strconv.ParseUint(ctx.Param("flagged_message_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid flagged message ID."))
+		return
+	}
+
+	if _, err := c.FlaggedMessageApp.GetFlaggedMessageByID(flaggedMessageID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.SendNotFound(ctx, ginI18n.MustGetMessage("Flagged message not found."))
+			return
+		}
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	now := time.Now()
+	flaggedMessage, err := c.FlaggedMessageApp.UpdateFlaggedMessageByID(flaggedMessageID, &entity.FlaggedMessage{
+		Status:       input.Status,
+		ReviewedByID: &admin.ID,
+		ReviewedAt:   &now,
+	})
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, flaggedMessage.PublicData(), "")
+}
+
+// flagMessage holds a moderated message for admin review. Failing to record it doesn't stop the
+// message, which has already been masked.
+func (c *Chat) flagMessage(provider string, channelID string, userID string, messageID string, text string, result *moderation.Result) {
+	reasons, _ := json.Marshal(result.Reasons)
+	words, _ := json.Marshal(result.Words)
+
+	_, err := c.FlaggedMessageApp.CreateFlaggedMessage(&entity.FlaggedMessage{
+		Provider:   provider,
+		ChannelID:  channelID,
+		UserID:     userID,
+		MessageID:  messageID,
+		Text:       text,
+		MaskedText: result.Text,
+		Reasons:    datatypes.JSON(reasons),
+		Words:      datatypes.JSON(words),
+		Status:     entity.FlaggedMessagePendingStatus,
+	})
+	if err != nil {
+		log.Println("Error flagging chat message: ", err)
+	}
+}
@@ -26,18 +26,23 @@ func NewCities(authService auth.AuthServiceInterface, tokenService auth.TokenInt
 	}
 }
 
-// GetAllCities retrieves a paginated list of all cities.
+// GetAllCities retrieves a paginated list of all cities, of the region given in the query parameters.
 func (c *Cities) GetAllCities(ctx *gin.Context) {
 	page := pagination.GetPage(ctx)
 	perPage := 30
 		
-	count, err := c.CityApp.CountCities()
+	regionID, ok := getQueryID(ctx, "region_id", "Invalid region ID.")
+	if !ok {
+		return
+	}
+
+	count, err := c.CityApp.CountCities(regionID)
 	if err != nil {
 		response.SendInternalServerError(ctx, err.Error())
 		return
 	}
 
-	cities, err := c.CityApp.GetAllCities(page, perPage)
+	cities, err := c.CityApp.GetAllCities(regionID, page, perPage)
 	if err != nil {
 		response.SendInternalServerError(ctx, err.Error())
 		return
@@ -0,0 +1,99 @@
+package interfaces
+
+import (
+	"strconv"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/pkg/pagination"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// Districts holds the district-related application interfaces
+type Districts struct {
+	AuthService  auth.AuthServiceInterface
+	TokenService auth.TokenInterface
+	DistrictApp  application.DistrictApplicationInterface
+}
+
+// NewDistricts returns a new instance of Districts
+func NewDistricts(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, districtApp application.DistrictApplicationInterface) *Districts {
+	return &Districts{
+		AuthService:  authService,
+		TokenService: tokenService,
+		DistrictApp:  districtApp,
+	}
+}
+
+// GetAllDistricts retrieves a paginated list of all districts, of the region or the city given in
+// the query parameters.
+func (d *Districts) GetAllDistricts(ctx *gin.Context) {
+	page := pagination.GetPage(ctx)
+	perPage := 30
+
+	regionID, ok := getQueryID(ctx, "region_id", "Invalid region ID.")
+	if !ok {
+		return
+	}
+
+	cityID, ok := getQueryID(ctx, "city_id", "Invalid city ID.")
+	if !ok {
+		return
+	}
+
+	count, err := d.DistrictApp.CountDistricts(regionID, cityID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	districts, err := d.DistrictApp.GetAllDistricts(regionID, cityID, page, perPage)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if page <= 1 && len(districts) == 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No districts found."))
+		return
+	}
+
+	if page <= 0 || (len(districts) == 0 && page*perPage > int(count)) {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Page not found."))
+		return
+	}
+
+	var districtPublicData []interface{}
+	for _, district := range districts {
+		districtPublicData = append(districtPublicData, district.PublicData())
+	}
+
+	data := make(map[string]interface{})
+	data["data"] = districtPublicData
+	data["current_page"] = page
+	if page*perPage < int(count) {
+		data["next_page"] = page + 1
+	}
+	data["total"] = count
+
+	response.SendOK(ctx, data, "")
+}
+
+// GetDistrictByID retrieves a single district by ID.
+func (d *Districts) GetDistrictByID(ctx *gin.Context) {
+	districtID, err := strconv.ParseUint(ctx.Param("district_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid district ID."))
+		return
+	}
+
+	district, err := d.DistrictApp.GetDistrictByID(districtID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("District not found."))
+		return
+	}
+
+	response.SendOK(ctx, district.PublicData(), "")
+}
@@ -0,0 +1,129 @@
+package interfaces
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// GetDriverCoverage retrieves the cities and regions the authenticated driver serves
+func (d *Drivers) GetDriverCoverage(ctx *gin.Context) {
+	driver, ok := d.authenticateDriver(ctx)
+	if !ok {
+		return
+	}
+
+	coverages, err := d.DriverCoverageApp.GetAllDriverCoveragesByDriverID(driver.ID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	d.sendDriverCoverage(ctx, coverages)
+}
+
+// UpdateDriverCoverage replaces the cities and regions the authenticated driver serves. A driver
+// covering none of them is offered the orders picked up around them.
+func (d *Drivers) UpdateDriverCoverage(ctx *gin.Context) {
+	var input struct {
+		CityIDs   []uint64 `json:"city_ids" validate:"max=100"`
+		RegionIDs []uint64 `json:"region_ids" validate:"max=50"`
+	}
+
+	// Bind the JSON body of the request to the input struct
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	driver, ok := d.authenticateDriver(ctx)
+	if !ok {
+		return
+	}
+
+	if validationErrors, _ := validator.ValidateExcept(ctx, &input); validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	var coverages []entity.DriverCoverage
+	coveredCities := make(map[uint64]bool)
+	coveredRegions := make(map[uint64]bool)
+
+	for _, cityID := range input.CityIDs {
+		if _, err := d.CityApp.GetCityByID(cityID); err != nil {
+			response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("City not found."))
+			return
+		}
+
+		if !coveredCities[cityID] {
+			coveredCities[cityID] = true
+			coverages = append(coverages, entity.DriverCoverage{CityID: cityID})
+		}
+	}
+
+	for _, regionID := range input.RegionIDs {
+		if _, err := d.RegionApp.GetRegionByID(regionID); err != nil {
+			response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Region not found."))
+			return
+		}
+
+		if !coveredRegions[regionID] {
+			coveredRegions[regionID] = true
+			coverages = append(coverages, entity.DriverCoverage{RegionID: regionID})
+		}
+	}
+
+	updatedCoverages, err := d.DriverCoverageApp.ReplaceDriverCoveragesByDriverID(driver.ID, coverages)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	d.sendDriverCoverage(ctx, updatedCoverages)
+}
+
+// sendDriverCoverage sends the cities and regions a driver serves
+func (d *Drivers) sendDriverCoverage(ctx *gin.Context, coverages []entity.DriverCoverage) {
+	coveragePublicData := make([]interface{}, 0, len(coverages))
+	for _, coverage := range coverages {
+		coveragePublicData = append(coveragePublicData, coverage.PublicData())
+	}
+
+	response.SendOK(ctx, coveragePublicData, "")
+}
+
+// authenticateDriver retrieves the driver profile of the authenticated user
+func (d *Drivers) authenticateDriver(ctx *gin.Context) (*entity.Driver, bool) {
+	// Extract the token metadata from the request
+	metadata, err := d.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := d.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Get the user from the user application service
+	user, err := d.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Get the driver from the driver application service
+	driver, err := d.DriverApp.GetDriverByUserID(user.ID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Driver not found."))
+		return nil, false
+	}
+
+	return driver, true
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/OmarBader7/web-service-jayeek/application"
 	"github.com/OmarBader7/web-service-jayeek/domain/entity"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/stats"
 	"github.com/OmarBader7/web-service-jayeek/pkg/language"
 	"github.com/OmarBader7/web-service-jayeek/pkg/pagination"
 	"github.com/OmarBader7/web-service-jayeek/pkg/response"
@@ -23,10 +24,15 @@ type Drivers struct {
 	UserApp               application.UserApplicationInterface
 	TransportationModeApp application.TransportationModeApplicationInterface
 	IdentityDocumentApp   application.IdentityDocumentApplicationInterface
+	RatingApp             application.RatingApplicationInterface
+	CityApp               application.CityApplicationInterface
+	RegionApp             application.RegionApplicationInterface
+	DriverCoverageApp     application.DriverCoverageApplicationInterface
+	StatsService          stats.StatsServiceInterface
 }
 
 // NewDrivers returns a new instance of Drivers
-func NewDrivers(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, driverApp application.DriverApplicationInterface, userApp application.UserApplicationInterface, transportationModeApp application.TransportationModeApplicationInterface, identityDocumentApp application.IdentityDocumentApplicationInterface) *Drivers {
+func NewDrivers(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, driverApp application.DriverApplicationInterface, userApp application.UserApplicationInterface, transportationModeApp application.TransportationModeApplicationInterface, identityDocumentApp application.IdentityDocumentApplicationInterface, ratingApp application.RatingApplicationInterface, cityApp application.CityApplicationInterface, regionApp application.RegionApplicationInterface, driverCoverageApp application.DriverCoverageApplicationInterface, statsService stats.StatsServiceInterface) *Drivers {
 	return &Drivers{
 		AuthService:           authService,
 		TokenService:          tokenService,
@@ -34,6 +40,11 @@ func NewDrivers(authService auth.AuthServiceInterface, tokenService auth.TokenIn
 		UserApp:               userApp,
 		TransportationModeApp: transportationModeApp,
 		IdentityDocumentApp:   identityDocumentApp,
+		RatingApp:             ratingApp,
+		CityApp:               cityApp,
+		RegionApp:             regionApp,
+		DriverCoverageApp:     driverCoverageApp,
+		StatsService:          statsService,
 	}
 }
 
@@ -117,7 +128,7 @@ func (d *Drivers) CreateDriver(c *gin.Context) {
 		return
 	}
 
-	if user.IsDriver {
+	if _, err := d.DriverApp.GetDriverByUserID(user.ID); err == nil {
 		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("You have already registered as a driver."))
 		return
 	}
@@ -284,10 +295,83 @@ func (d *Drivers) GetDriverByID(ctx *gin.Context) {
 		return
 	}
 
+	stats, err := d.StatsService.GetUserStats(driver.UserID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+	driver.User.SetStats(stats)
+
 	// Send the driver as a response.
 	response.SendOK(ctx, driver.PublicData(language.GetLanguage(ctx)), "")
 }
 
+// GetDriverReviewsByID retrieves a paginated list of the reviews a driver received from senders.
+func (d *Drivers) GetDriverReviewsByID(ctx *gin.Context) {
+	// Parse the driver ID from the URL parameter.
+	driverID, err := strconv.ParseUint(ctx.Param("driver_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid driver ID."))
+		return
+	}
+
+	// Get the desired page number from the query parameters.
+	page := pagination.GetPage(ctx)
+
+	// Set the number of items per page.
+	perPage := 30
+
+	// Get the driver from the driver application service.
+	driver, err := d.DriverApp.GetDriverByID(driverID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Driver not found."))
+		return
+	}
+
+	count, err := d.RatingApp.CountRatingsByRatedUserIDAndType(driver.UserID, entity.DriverRatingType)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	ratings, err := d.RatingApp.GetAllRatingsByRatedUserIDAndType(driver.UserID, entity.DriverRatingType, page, perPage)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if page <= 1 && len(ratings) <= 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No reviews found."))
+		return
+	}
+
+	// Check if the page is valid
+	if page <= 0 || (len(ratings) <= 0 && page*perPage > int(count)) {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Page not found."))
+		return
+	}
+
+	var ratingPublicData []interface{}
+
+	for _, rating := range ratings {
+		ratingPublicData = append(ratingPublicData, rating.PublicData())
+	}
+
+	// Build response data
+	data := make(map[string]interface{})
+	data["data"] = ratingPublicData
+	data["current_page"] = page
+	if page*perPage < int(count) {
+		data["next_page"] = page + 1
+	}
+	data["total"] = count
+	data["ratings_avg_score"] = driver.User.RatingsAvgScore
+
+	response.SendOK(ctx, data, "")
+}
+
+// GetDriversByUserLocationID retrieves a paginated list of the drivers serving a location, the ones
+// covering its city or its region, and the ones living there who didn't declare what they cover.
 func (d *Drivers) GetDriversByUserLocationID(ctx *gin.Context) {
 	// Parse the driver ID from the URL parameter.
 	userLocationID, err := strconv.ParseUint(ctx.Param("location_id"), 10, 64)
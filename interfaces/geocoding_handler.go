@@ -0,0 +1,113 @@
+package interfaces
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/geocoding"
+	"github.com/OmarBader7/web-service-jayeek/pkg/language"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// Geocoding holds the geocoding service searching and resolving addresses
+type Geocoding struct {
+	GeocodingService geocoding.GeocodingServiceInterface
+}
+
+// NewGeocoding creates and returns a new instance of Geocoding.
+func NewGeocoding(geocodingService geocoding.GeocodingServiceInterface) *Geocoding {
+	return &Geocoding{
+		GeocodingService: geocodingService,
+	}
+}
+
+// Search suggests the regions, cities and districts whose Arabic or English name contains the query,
+// named in the language of the request.
+func (g *Geocoding) Search(ctx *gin.Context) {
+	query := strings.TrimSpace(ctx.Query("q"))
+	if utf8.RuneCountInString(query) < 2 {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("The search query must be at least 2 characters long."))
+		return
+	}
+
+	limit := 10
+	if ctx.Query("limit") != "" {
+		parsedLimit, err := strconv.Atoi(ctx.Query("limit"))
+		if err != nil || parsedLimit <= 0 {
+			response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid limit."))
+			return
+		}
+		limit = parsedLimit
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	places, err := g.GeocodingService.Search(query, limit)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if len(places) <= 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No places found."))
+		return
+	}
+
+	var placePublicData []interface{}
+	for _, place := range places {
+		placePublicData = append(placePublicData, place.PublicData(language.GetLanguage(ctx)))
+	}
+
+	response.SendOK(ctx, placePublicData, "")
+}
+
+// Reverse resolves the district, the city and the region of the given coordinates.
+func (g *Geocoding) Reverse(ctx *gin.Context) {
+	// Parse the latitude from the URL parameter.
+	latitude, err := strconv.ParseFloat(ctx.Param("latitude"), 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		// Send an error response if the latitude is invalid.
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid latitude."))
+		return
+	}
+
+	// Parse the longitude from the URL parameter.
+	longitude, err := strconv.ParseFloat(ctx.Param("longitude"), 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		// Send an error response if the longitude is invalid.
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid longitude."))
+		return
+	}
+
+	locality, err := g.GeocodingService.Reverse(latitude, longitude)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if locality.Region == nil && locality.City == nil && locality.District == nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("No place found at these coordinates."))
+		return
+	}
+
+	// Build response data
+	data := make(map[string]interface{})
+	data["region"] = nil
+	if locality.Region != nil {
+		data["region"] = locality.Region.PublicData()
+	}
+	data["city"] = nil
+	if locality.City != nil {
+		data["city"] = locality.City.PublicData()
+	}
+	data["district"] = nil
+	if locality.District != nil {
+		data["district"] = locality.District.PublicData()
+	}
+
+	response.SendOK(ctx, data, "")
+}
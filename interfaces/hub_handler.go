@@ -0,0 +1,314 @@
+package interfaces
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/pkg/language"
+	"github.com/OmarBader7/web-service-jayeek/pkg/pagination"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Hubs holds the hub-related application interfaces
+type Hubs struct {
+	AuthService  auth.AuthServiceInterface
+	TokenService auth.TokenInterface
+	HubApp       application.HubApplicationInterface
+	CityApp      application.CityApplicationInterface
+	UserApp      application.UserApplicationInterface
+}
+
+// NewHubs returns a new instance of Hubs
+func NewHubs(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, hubApp application.HubApplicationInterface, cityApp application.CityApplicationInterface, userApp application.UserApplicationInterface) *Hubs {
+	return &Hubs{
+		AuthService:  authService,
+		TokenService: tokenService,
+		HubApp:       hubApp,
+		CityApp:      cityApp,
+		UserApp:      userApp,
+	}
+}
+
+// GetAllHubs retrieves a paginated list of all hubs, of the city given in the query parameters.
+func (h *Hubs) GetAllHubs(ctx *gin.Context) {
+	page := pagination.GetPage(ctx)
+	perPage := 30
+
+	cityID, ok := getQueryID(ctx, "city_id", "Invalid city ID.")
+	if !ok {
+		return
+	}
+
+	count, err := h.HubApp.CountHubs(cityID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	hubs, err := h.HubApp.GetAllHubs(cityID, page, perPage)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if page <= 1 && len(hubs) == 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No hubs found."))
+		return
+	}
+
+	if page <= 0 || (len(hubs) == 0 && page*perPage > int(count)) {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Page not found."))
+		return
+	}
+
+	var hubPublicData []interface{}
+	for _, hub := range hubs {
+		hubPublicData = append(hubPublicData, hub.PublicData())
+	}
+
+	data := make(map[string]interface{})
+	data["data"] = hubPublicData
+	data["current_page"] = page
+	if page*perPage < int(count) {
+		data["next_page"] = page + 1
+	}
+	data["total"] = count
+
+	response.SendOK(ctx, data, "")
+}
+
+// GetHubByID retrieves a single hub by ID.
+func (h *Hubs) GetHubByID(ctx *gin.Context) {
+	hubID, err := strconv.ParseUint(ctx.Param("hub_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid hub ID."))
+		return
+	}
+
+	hub, err := h.HubApp.GetHubByID(hubID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Hub not found."))
+		return
+	}
+
+	response.SendOK(ctx, hub.PublicData(), "")
+}
+
+// CreateHub handles the creation of a new hub
+func (h *Hubs) CreateHub(ctx *gin.Context) {
+	var hub entity.Hub
+
+	// Bind the JSON body of the request to the Hub struct
+	if err := ctx.ShouldBindJSON(&hub); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	if _, ok := requireAdmin(ctx, h.TokenService, h.AuthService, h.UserApp); !ok {
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &hub, "City")
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	if _, err := h.CityApp.GetCityByID(hub.CityID); err != nil {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("City not found."))
+		return
+	}
+
+	hub.ID = 0
+
+	createdHub, err := h.HubApp.CreateHub(&hub)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, createdHub.PublicData(), "")
+}
+
+// UpdateHubByID updates a hub by its ID. A hub that is no longer active isn't given the legs of
+// the new orders, the legs already planned through it are kept.
+func (h *Hubs) UpdateHubByID(ctx *gin.Context) {
+	var hub entity.Hub
+
+	// Bind the JSON body of the request to the Hub struct
+	if err := ctx.ShouldBindJSON(&hub); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	if _, ok := requireAdmin(ctx, h.TokenService, h.AuthService, h.UserApp); !ok {
+		return
+	}
+
+	// Parse the hub ID from the URL parameter.
+	hubID, err := strconv.ParseUint(ctx.Param("hub_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid hub ID."))
+		return
+	}
+
+	if _, err := h.HubApp.GetHubByID(hubID); err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Hub not found."))
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &hub, "City")
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	if _, err := h.CityApp.GetCityByID(hub.CityID); err != nil {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("City not found."))
+		return
+	}
+
+	updatedHub, err := h.HubApp.UpdateHubByID(hubID, &hub)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, updatedHub.PublicData(), "")
+}
+
+// GetAllHubMembers lists the staff of a hub
+func (h *Hubs) GetAllHubMembers(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, h.TokenService, h.AuthService, h.UserApp); !ok {
+		return
+	}
+
+	hub, ok := h.getHub(ctx)
+	if !ok {
+		return
+	}
+
+	hubMembers, err := h.HubApp.GetAllHubMembersByHubID(hub.ID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	var hubMemberPublicData []interface{}
+	for _, hubMember := range hubMembers {
+		hubMemberPublicData = append(hubMemberPublicData, hubMember.PublicData(language.GetLanguage(ctx)))
+	}
+
+	response.SendOK(ctx, hubMemberPublicData, "")
+}
+
+// AddHubMember adds a registered user to the staff of a hub by their phone number, letting them
+// scan the parcels at the hub
+func (h *Hubs) AddHubMember(ctx *gin.Context) {
+	var input struct {
+		Phone string `json:"phone" validate:"required,e164"`
+	}
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	if _, ok := requireAdmin(ctx, h.TokenService, h.AuthService, h.UserApp); !ok {
+		return
+	}
+
+	hub, ok := h.getHub(ctx)
+	if !ok {
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &input)
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	user, err := h.UserApp.GetUserByPhone(input.Phone)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("User not found."))
+		return
+	}
+
+	if _, err := h.HubApp.GetHubMemberByHubIDAndUserID(hub.ID, user.ID); err == nil {
+		response.SendConflict(ctx, ginI18n.MustGetMessage("The user is already a member of the hub staff."))
+		return
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	hubMember := entity.HubMember{
+		HubID:  hub.ID,
+		UserID: user.ID,
+	}
+
+	if _, err := h.HubApp.CreateHubMember(&hubMember); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	hubMember.User = *user
+
+	response.SendCreated(ctx, hubMember.PublicData(language.GetLanguage(ctx)), "")
+}
+
+// DeleteHubMemberByUserID removes a user from the staff of a hub
+func (h *Hubs) DeleteHubMemberByUserID(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, h.TokenService, h.AuthService, h.UserApp); !ok {
+		return
+	}
+
+	hub, ok := h.getHub(ctx)
+	if !ok {
+		return
+	}
+
+	userID, err := strconv.ParseUint(ctx.Param("user_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid user ID."))
+		return
+	}
+
+	hubMember, err := h.HubApp.GetHubMemberByHubIDAndUserID(hub.ID, userID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Hub member not found."))
+		return
+	}
+
+	if err := h.HubApp.DeleteHubMemberByID(hubMember.ID); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, nil, ginI18n.MustGetMessage("The user was removed from the hub staff."))
+}
+
+// getHub retrieves the hub given in the URL, sending an error response when it isn't found
+func (h *Hubs) getHub(ctx *gin.Context) (*entity.Hub, bool) {
+	hubID, err := strconv.ParseUint(ctx.Param("hub_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid hub ID."))
+		return nil, false
+	}
+
+	hub, err := h.HubApp.GetHubByID(hubID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Hub not found."))
+		return nil, false
+	}
+
+	return hub, true
+}
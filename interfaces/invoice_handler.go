@@ -0,0 +1,182 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/invoice"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/upload"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// invoiceDirectory is where the generated invoices are stored
+const invoiceDirectory = "uploads/invoices"
+
+// GetOrderInvoiceByID sends the tax invoice of a completed order to its sender or driver.
+func (o *Orders) GetOrderInvoiceByID(ctx *gin.Context) {
+	// Extract the token metadata from the request
+	metadata, err := o.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := o.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Get the user from the user application service
+	user, err := o.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Parse the order ID from the URL parameter.
+	orderID, err := strconv.ParseUint(ctx.Param("order_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid order ID."))
+		return
+	}
+
+	// The invoice is available to the sender and to the driver of the order
+	order, err := o.OrderApp.GetOrderByIDAndUserID(orderID, user.ID)
+	if err != nil {
+		driver, driverErr := o.DriverApp.GetDriverByUserID(user.ID)
+		if driverErr == nil {
+			order, err = o.OrderApp.GetOrderByIDAndDriverID(orderID, driver.ID)
+		}
+	}
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Order not found."))
+		return
+	}
+
+	if order.Status != entity.ShipmentDeliveredStatus && order.Status != entity.OrderCompletedStatus {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Invoices are only available for completed orders."))
+		return
+	}
+
+	orderInvoice, err := o.issueInvoice(order)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	ctx.FileAttachment(orderInvoice.FilePath, fmt.Sprintf("%s.pdf", orderInvoice.Number))
+}
+
+// issueInvoice returns the invoice of a completed order, issuing it with the next invoice number
+// and storing its PDF when needed.
+func (o *Orders) issueInvoice(order *entity.Order) (*entity.Invoice, error) {
+	orderInvoice, err := o.InvoiceApp.GetInvoiceByOrderID(order.ID)
+	if err != nil {
+		orderInvoice, err = o.newInvoice(order)
+		if err != nil {
+			return nil, err
+		}
+
+		// The invoice issued meanwhile, as by the delivery of the order, is used instead
+		orderInvoice, err = o.InvoiceApp.CreateInvoice(orderInvoice)
+		if errors.Is(err, repository.ErrConflict) {
+			orderInvoice, err = o.InvoiceApp.GetInvoiceByOrderID(order.ID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The stored copy is generated again if it's missing
+	if orderInvoice.FilePath != "" {
+		if _, err := os.Stat(orderInvoice.FilePath); err == nil {
+			return orderInvoice, nil
+		}
+	}
+
+	seller := invoice.Seller{}
+	if seller.Name, err = o.SettingApp.GetSettingByKey("seller_name"); err != nil {
+		return nil, err
+	}
+	if seller.VATNumber, err = o.SettingApp.GetSettingByKey("seller_vat_number"); err != nil {
+		return nil, err
+	}
+	if seller.Address, err = o.SettingApp.GetSettingByKey("seller_address"); err != nil {
+		return nil, err
+	}
+
+	pdf, err := o.InvoiceService.GeneratePDF(orderInvoice, order, seller)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stored invoices get unguessable names since the uploads directory is served publicly
+	filename := fmt.Sprintf("%s.pdf", uuid.NewString())
+	if _, err := upload.SaveFile(pdf, invoiceDirectory, filename); err != nil {
+		return nil, err
+	}
+
+	orderInvoice.FilePath = filepath.Join(invoiceDirectory, filename)
+
+	return o.InvoiceApp.UpdateInvoiceByID(orderInvoice.ID, orderInvoice)
+}
+
+// newInvoice calculates the amount breakdown of the invoice of an order
+func (o *Orders) newInvoice(order *entity.Order) (*entity.Invoice, error) {
+	vatRate, err := o.getFloatSetting("vat_rate")
+	if err != nil {
+		return nil, err
+	}
+
+	commissionRate, err := o.getFloatSetting("commission_rate")
+	if err != nil {
+		return nil, err
+	}
+
+	extraServiceFee, err := o.getFloatSetting("extra_service_fee")
+	if err != nil {
+		return nil, err
+	}
+
+	currency, err := o.SettingApp.GetSettingByKey("currency_symbol")
+	if err != nil {
+		return nil, err
+	}
+
+	orderInvoice := &entity.Invoice{
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		DriverID:       order.DriverID,
+		VATRate:        vatRate,
+		CommissionRate: commissionRate,
+		Currency:       currency,
+		IssuedAt:       time.Now(),
+	}
+
+	if order.Amount != nil {
+		orderInvoice.Fare = *order.Amount
+	}
+
+	if order.ExtraServices != nil {
+		orderInvoice.Extras = extraServiceFee * float64(len(*order.ExtraServices))
+	}
+
+	if order.Discount != nil {
+		orderInvoice.Discount = *order.Discount
+	}
+
+	orderInvoice.CalculateAmounts()
+
+	return orderInvoice, nil
+}
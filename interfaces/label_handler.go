@@ -0,0 +1,73 @@
+package interfaces
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// GetOrderLabelByID sends the printable label of an order to its sender and to the drivers carrying it.
+func (o *Orders) GetOrderLabelByID(ctx *gin.Context) {
+	// Extract the token metadata from the request
+	metadata, err := o.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := o.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Get the user from the user application service
+	user, err := o.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Parse the order ID from the URL parameter.
+	orderID, err := strconv.ParseUint(ctx.Param("order_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid order ID."))
+		return
+	}
+
+	// The label is available to the sender and to the drivers of the order or of its legs
+	order, err := o.OrderApp.GetOrderByIDAndUserID(orderID, user.ID)
+	if err != nil {
+		if driver, driverErr := o.DriverApp.GetDriverByUserID(user.ID); driverErr == nil {
+			if order, err = o.OrderApp.GetOrderByID(orderID); err == nil {
+				if _, ok := driverOrderLeg(order, driver.ID); !ok {
+					order = nil
+				}
+			}
+		}
+	}
+	if order == nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Order not found."))
+		return
+	}
+
+	if order.Status == entity.OrderCanceledStatus {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Labels aren't available for canceled orders."))
+		return
+	}
+
+	pdf, err := o.LabelService.GeneratePDF(order)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%s.pdf", order.TrackingNumber)))
+	ctx.Data(http.StatusOK, "application/pdf", pdf)
+}
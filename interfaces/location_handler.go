@@ -28,7 +28,8 @@ func NewLocations(authService auth.AuthServiceInterface, tokenService auth.Token
 	}
 }
 
-// GetAllLocations retrieves a paginated list of all locations.
+// GetAllLocations retrieves a paginated list of all locations, of the region, the city or the
+// district given in the query parameters.
 func (l *Locations) GetAllLocations(ctx *gin.Context) {
 	// Get the desired page number from the query parameters.
 	page := pagination.GetPage(ctx)
@@ -36,15 +37,30 @@ func (l *Locations) GetAllLocations(ctx *gin.Context) {
 	// Set the number of items per page.
 	perPage := 30
 
+	regionID, ok := getQueryID(ctx, "region_id", "Invalid region ID.")
+	if !ok {
+		return
+	}
+
+	cityID, ok := getQueryID(ctx, "city_id", "Invalid city ID.")
+	if !ok {
+		return
+	}
+
+	districtID, ok := getQueryID(ctx, "district_id", "Invalid district ID.")
+	if !ok {
+		return
+	}
+
 	// Get the location count from the location application service.
-	count, err := l.LocationApp.CountLocations()
+	count, err := l.LocationApp.CountLocations(regionID, cityID, districtID)
 	if err != nil {
 		response.SendInternalServerError(ctx, err.Error())
 		return
 	}
 
 	// Get the locations from the location application service.
-	locations, err := l.LocationApp.GetAllLocations(page, perPage)
+	locations, err := l.LocationApp.GetAllLocations(regionID, cityID, districtID, page, perPage)
 	if err != nil {
 		response.SendInternalServerError(ctx, err.Error())
 		return
@@ -1,13 +1,14 @@
 package interfaces
 
 import (
-	"fmt"
+	"errors"
+	"io"
 	"strconv"
 
 	"github.com/OmarBader7/web-service-jayeek/application"
 	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
-	"github.com/OmarBader7/web-service-jayeek/infrastructure/chat"
 	"github.com/OmarBader7/web-service-jayeek/pkg/language"
 	"github.com/OmarBader7/web-service-jayeek/pkg/pagination"
 	"github.com/OmarBader7/web-service-jayeek/pkg/response"
@@ -17,25 +18,31 @@ import (
 
 // Offers holds the offer-related application interfaces
 type Offers struct {
-	AuthService  auth.AuthServiceInterface
-	TokenService auth.TokenInterface
-	ChatService  chat.ChatServiceInterface
-	UserApp      application.UserApplicationInterface
-	OfferApp     application.OfferApplicationInterface
-	OrderApp     application.OrderApplicationInterface
-	DriverApp    application.DriverApplicationInterface
+	AuthService   auth.AuthServiceInterface
+	TokenService  auth.TokenInterface
+	UnitOfWorkApp application.UnitOfWorkApplicationInterface
+	UserApp       application.UserApplicationInterface
+	OfferApp      application.OfferApplicationInterface
+	OrderApp      application.OrderApplicationInterface
+	DriverApp     application.DriverApplicationInterface
+	TripApp       application.TripApplicationInterface
+	PromotionApp  application.PromotionApplicationInterface
+	OrderLegApp   application.OrderLegApplicationInterface
 }
 
 // NewOffers returns a new instance of Offers
-func NewOffers(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, chatService chat.ChatServiceInterface, userApp application.UserApplicationInterface, offerApp application.OfferApplicationInterface, orderApp application.OrderApplicationInterface, driverApp application.DriverApplicationInterface) *Offers {
+func NewOffers(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, unitOfWorkApp application.UnitOfWorkApplicationInterface, userApp application.UserApplicationInterface, offerApp application.OfferApplicationInterface, orderApp application.OrderApplicationInterface, driverApp application.DriverApplicationInterface, tripApp application.TripApplicationInterface, promotionApp application.PromotionApplicationInterface, orderLegApp application.OrderLegApplicationInterface) *Offers {
 	return &Offers{
-		AuthService:  authService,
-		TokenService: tokenService,
-		ChatService:  chatService,
-		UserApp:      userApp,
-		OfferApp:     offerApp,
-		OrderApp:     orderApp,
-		DriverApp:    driverApp,
+		AuthService:   authService,
+		TokenService:  tokenService,
+		UnitOfWorkApp: unitOfWorkApp,
+		UserApp:       userApp,
+		OfferApp:      offerApp,
+		OrderApp:      orderApp,
+		DriverApp:     driverApp,
+		TripApp:       tripApp,
+		PromotionApp:  promotionApp,
+		OrderLegApp:   orderLegApp,
 	}
 }
 
@@ -113,6 +120,17 @@ func (o *Offers) GetAllOffers(ctx *gin.Context) {
 }
 
 func (o *Offers) AcceptOfferByID(ctx *gin.Context) {
+	var body struct {
+		PromoCode *string `json:"promo_code"`
+		UseCredit bool    `json:"use_credit"`
+	}
+
+	// The request body is optional and only carries a promo code and whether to spend the credit
+	if err := ctx.ShouldBindJSON(&body); err != nil && err != io.EOF {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
 	// Extract the token metadata from the request
 	metadata, err := o.TokenService.ExtractTokenMetadata(ctx.Request)
 	if err != nil {
@@ -162,67 +180,137 @@ func (o *Offers) AcceptOfferByID(ctx *gin.Context) {
 		return
 	}
 
-	offer.Status = entity.OfferStatusAccepted
-
-	if _, err := o.OfferApp.UpdateOfferByID(offer.ID, offer); err != nil {
-		response.SendInternalServerError(ctx, err.Error())
+	// The offers on the legs of an intercity order only assign the driver of their leg
+	if offer.OrderLegID != 0 {
+		o.acceptOrderLegOffer(ctx, offer, driver, order)
 		return
 	}
 
+	// A promo code given on acceptance replaces the one given at order creation
+	var promotion *entity.Promotion
+	if body.PromoCode != nil && *body.PromoCode != "" {
+		if promotion, err = findPromotion(o.PromotionApp, *body.PromoCode, user.ID, order.CategoryID); err != nil {
+			sendRequestError(ctx, err)
+			return
+		}
+	} else if order.PromotionID != 0 {
+		storedPromotion, err := o.PromotionApp.GetPromotionByID(order.PromotionID)
+		if err != nil {
+			response.SendInternalServerError(ctx, err.Error())
+			return
+		}
+
+		if promotion, err = findPromotion(o.PromotionApp, storedPromotion.Code, user.ID, order.CategoryID); err != nil {
+			sendRequestError(ctx, err)
+			return
+		}
+	}
+
 	order.DriverID = driver.ID
 	order.Amount = &offer.Amount
 	order.Status = entity.OrderAcceptedStatus
 
-	_, err = o.OrderApp.UpdateOrderByID(order.ID, order)
-	if err != nil {
-		response.SendInternalServerError(ctx, err.Error())
-		return
+	if promotion != nil {
+		discount := promotion.CalculateDiscount(offer.Amount)
+		order.PromotionID = promotion.ID
+		order.Discount = &discount
 	}
 
-	offers, err := o.OfferApp.GetAllOffersByStatusAndOrderID(entity.OfferStatusPending, order.ID)
-	if err != nil {
-		response.SendInternalServerError(ctx, err.Error())
-		return
+	// Get the recipient from the user application service
+	if recipient, err := o.UserApp.GetUserByPhone(order.RecipientPhoneNumber); err == nil {
+		order.RecipientID = recipient.ID
 	}
 
-	for _, offer := range offers {
-		offer.Status = entity.OfferStatusDeclined
-		if _, err := o.OfferApp.UpdateOfferByID(offer.ID, &offer); err != nil {
-			response.SendInternalServerError(ctx, err.Error())
-			return
+	// The offers, the order and the trip are updated together with the event of the acceptance. The
+	// order is only accepted from order_created and the offer from pending, so of two requests
+	// accepting at once the second one finds them changed and is rolled back.
+	err = o.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		offer.Status = entity.OfferStatusAccepted
+
+		if _, err := tx.Offer().UpdateOfferByIDAndStatus(offer.ID, entity.OfferStatusPending, offer); err != nil {
+			return err
 		}
-	}
 
-	err = o.ChatService.CreateChannel(fmt.Sprintf("order-%d", order.ID), fmt.Sprintf("client-%d", user.ID))
+		if err := emitOfferEvent(tx, entity.OfferAcceptedEvent, offer, order.UserID); err != nil {
+			return err
+		}
 
-	if err != nil {
-		response.SendInternalServerError(ctx, ginI18n.MustGetMessage("Failed to create chat channel."))
-		return
-	}
+		// The sender is locked while their credit is summed, so it isn't spent twice by two
+		// orders accepted at once
+		if body.UseCredit {
+			if err := tx.User().LockUserByID(user.ID); err != nil {
+				return err
+			}
+
+			credit, err := tx.Credit().SumCreditsByUserID(user.ID)
+			if err != nil {
+				return err
+			}
+
+			if spent := order.CreditToSpend(credit); spent > 0 {
+				spending := entity.Credit{UserID: user.ID, OrderID: order.ID, Amount: -spent, Reason: entity.OrderCreditReason}
+				if _, err := tx.Credit().CreateCredit(&spending); err != nil {
+					return err
+				}
+				order.CreditUsed = &spent
+			}
+		}
 
-	err = o.ChatService.AddMember(fmt.Sprintf("order-%d", order.ID), fmt.Sprintf("driver-%d", driver.User.ID))
+		if _, err := tx.Order().UpdateOrderByIDAndStatus(order.ID, entity.OrderCreatedStatus, order); err != nil {
+			return err
+		}
 
-	if err != nil {
-		response.SendInternalServerError(ctx, ginI18n.MustGetMessage("Failed to add members to the chat channel."))
-		return
-	}
+		if promotion != nil {
+			redemption := entity.PromotionRedemption{
+				PromotionID: promotion.ID,
+				UserID:      user.ID,
+				OrderID:     order.ID,
+				Discount:    *order.Discount,
+			}
+
+			if _, err := tx.Promotion().RedeemPromotion(&redemption); err != nil {
+				return err
+			}
+		}
 
-	// Get the recipient from the user application service
-	if recipient, err := o.UserApp.GetUserByPhone(order.RecipientPhoneNumber); err == nil {
-		order.RecipientID = recipient.ID
+		// The accepted order joins the stops of the driver's current trip
+		if _, err := tx.Trip().AddOrderToTrip(driver.ID, order); err != nil {
+			return err
+		}
 
-		_, err = o.OrderApp.UpdateOrderByID(order.ID, order)
+		pendingOffers, err := tx.Offer().GetAllOffersByStatusAndOrderID(entity.OfferStatusPending, order.ID)
 		if err != nil {
-			response.SendInternalServerError(ctx, err.Error())
-			return
+			return err
 		}
 
-		err = o.ChatService.AddMember(fmt.Sprintf("order-%d", order.ID), fmt.Sprintf("client-%d", recipient.ID))
+		for _, pendingOffer := range pendingOffers {
+			pendingOffer.Status = entity.OfferStatusDeclined
+			if _, err := tx.Offer().UpdateOfferByID(pendingOffer.ID, &pendingOffer); err != nil {
+				return err
+			}
 
-		if err != nil {
-			response.SendInternalServerError(ctx, ginI18n.MustGetMessage("Failed to remove members from the chat channel."))
-			return
+			if err := emitOfferEvent(tx, entity.OfferDeclinedEvent, &pendingOffer, order.UserID); err != nil {
+				return err
+			}
 		}
+
+		return emitOrderEvent(tx, entity.OrderAcceptedEvent, order, driver.UserID)
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		response.SendConflict(ctx, ginI18n.MustGetMessage("The order was already accepted."))
+		return
+	}
+	if errors.Is(err, repository.ErrPromotionUsageLimit) {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("The promo code has reached its usage limit."))
+		return
+	}
+	if errors.Is(err, repository.ErrPromotionUserUsageLimit) {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("You have already used this promo code."))
+		return
+	}
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
 	}
 
 	response.SendOK(ctx, offer.PublicData(language.GetLanguage(ctx)), "")
@@ -266,7 +354,95 @@ func (o *Offers) DeclineOfferByID(ctx *gin.Context) {
 
 	offer.Status = entity.OfferStatusDeclined
 
-	if _, err := o.OfferApp.UpdateOfferByID(offer.ID, offer); err != nil {
+	// The offer is declined together with its event
+	err = o.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		if _, err := tx.Offer().UpdateOfferByID(offer.ID, offer); err != nil {
+			return err
+		}
+
+		return emitOfferEvent(tx, entity.OfferDeclinedEvent, offer, user.ID)
+	})
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, offer.PublicData(language.GetLanguage(ctx)), "")
+}
+
+// acceptOrderLegOffer accepts an offer on a leg of an intercity order, assigning the driver to the
+// leg and declining the other offers on it. The order is accepted with its first mile, and its
+// amount is the sum of the amounts of its accepted legs.
+func (o *Offers) acceptOrderLegOffer(ctx *gin.Context, offer *entity.Offer, driver *entity.Driver, order *entity.Order) {
+	leg, err := o.OrderLegApp.GetOrderLegByID(offer.OrderLegID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Order leg not found."))
+		return
+	}
+
+	// The leg is only accepted from leg_pending and the offer from pending, so of two requests
+	// accepting at once the second one finds them changed and is rolled back
+	err = o.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		offer.Status = entity.OfferStatusAccepted
+
+		if _, err := tx.Offer().UpdateOfferByIDAndStatus(offer.ID, entity.OfferStatusPending, offer); err != nil {
+			return err
+		}
+
+		if err := emitOfferEvent(tx, entity.OfferAcceptedEvent, offer, order.UserID); err != nil {
+			return err
+		}
+
+		acceptedLeg := entity.OrderLeg{
+			DriverID: driver.ID,
+			Amount:   &offer.Amount,
+			Status:   entity.OrderLegAcceptedStatus,
+		}
+
+		if _, err := tx.OrderLeg().UpdateOrderLegByIDAndStatus(leg.ID, entity.OrderLegPendingStatus, &acceptedLeg); err != nil {
+			return err
+		}
+
+		pendingOffers, err := tx.Offer().GetAllOffersByStatusAndOrderLegID(entity.OfferStatusPending, leg.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, pendingOffer := range pendingOffers {
+			pendingOffer.Status = entity.OfferStatusDeclined
+			if _, err := tx.Offer().UpdateOfferByID(pendingOffer.ID, &pendingOffer); err != nil {
+				return err
+			}
+
+			if err := emitOfferEvent(tx, entity.OfferDeclinedEvent, &pendingOffer, order.UserID); err != nil {
+				return err
+			}
+		}
+
+		update := entity.Order{}
+		if leg.Type == entity.FirstMileOrderLegType {
+			update.DriverID = driver.ID
+		}
+
+		if err := updateOrderFromLegs(tx, order, &update); err != nil {
+			return err
+		}
+
+		if leg.Type != entity.FirstMileOrderLegType {
+			return nil
+		}
+
+		order.DriverID = driver.ID
+		order.Status = update.Status
+		order.Amount = update.Amount
+
+		return emitOrderEvent(tx, entity.OrderAcceptedEvent, order, driver.UserID)
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		response.SendConflict(ctx, ginI18n.MustGetMessage("The order leg was already accepted."))
+		return
+	}
+	if err != nil {
 		response.SendInternalServerError(ctx, err.Error())
 		return
 	}
@@ -0,0 +1,245 @@
+package interfaces
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+	"github.com/OmarBader7/web-service-jayeek/pkg/language"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// recipientAddressStatuses are the statuses of the orders whose recipient can still pick where
+// they're delivered, before the shipment is out for delivery
+var recipientAddressStatuses = []entity.OrderStatus{
+	entity.OrderCreatedStatus,
+	entity.OrderScheduledStatus,
+	entity.OrderAcceptedStatus,
+	entity.PickupInProgressStatus,
+	entity.ShipmentPickedUpStatus,
+	entity.InTransitStatus,
+	entity.AtDestinationCityStatus,
+}
+
+// applyOrderAddresses fills the pickup and destination of an order from the saved addresses of the
+// user it names, keeping a copy of them on the order. A requestError is returned when an
+// address isn't one of the user's.
+func (d *Orders) applyOrderAddresses(userID uint64, order *entity.Order) error {
+	// The copies of the addresses are only taken from the address book
+	order.PickupPlace = nil
+	order.DestinationPlace = nil
+
+	if order.PickupAddressID != 0 {
+		address, err := d.getOrderAddress(order.PickupAddressID, userID, "Pickup address not found.")
+		if err != nil {
+			return err
+		}
+
+		order.Latitude = address.Latitude
+		order.Longitude = address.Longitude
+		order.PickupPlace = address.Place()
+	}
+
+	if order.DestinationAddressID != 0 {
+		address, err := d.getOrderAddress(order.DestinationAddressID, userID, "Destination address not found.")
+		if err != nil {
+			return err
+		}
+
+		order.DestinationID = address.LocationID
+		order.DestinationLatitude = address.Latitude
+		order.DestinationLongitude = address.Longitude
+		order.DestinationAddress = address.Line()
+		order.DestinationPlace = address.Place()
+	}
+
+	return nil
+}
+
+// getOrderAddress retrieves a saved address of a user for an order, returning a requestError with
+// the given message when it isn't found
+func (d *Orders) getOrderAddress(addressID uint64, userID uint64, notFoundMessage string) (*entity.Address, error) {
+	address, err := d.AddressApp.GetAddressByIDAndUserID(addressID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, newRequestError(notFoundMessage)
+		}
+		return nil, err
+	}
+
+	return address, nil
+}
+
+// UpdateOrderRecipientAddressByID lets the recipient of an order pick one of their saved addresses
+// as its drop-off, until the shipment is out for delivery. The route of the order is estimated
+// again, its amount is left as agreed.
+func (d *Orders) UpdateOrderRecipientAddressByID(c *gin.Context) {
+	var input struct {
+		AddressID uint64 `json:"address_id" validate:"required,numeric"`
+	}
+
+	// Bind the JSON body of the request to the input struct
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.SendBadRequest(c, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	// Extract the token metadata from the request
+	metadata, err := d.TokenService.ExtractTokenMetadata(c.Request)
+	if err != nil {
+		response.SendUnauthorized(c, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := d.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(c, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Get the user from the user application service
+	user, err := d.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(c, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	if validationErrors, _ := validator.ValidateExcept(c, &input); validationErrors != nil {
+		response.SendUnprocessableEntity(c, validationErrors, "")
+		return
+	}
+
+	// Parse the order ID from the URL parameter.
+	orderID, err := strconv.ParseUint(c.Param("order_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(c, ginI18n.MustGetMessage("Invalid order ID."))
+		return
+	}
+
+	// Get the order from the order application service.
+	order, err := d.OrderApp.GetOrderByIDAndRecipientID(orderID, user.ID)
+	if err != nil {
+		response.SendNotFound(c, ginI18n.MustGetMessage("Order not found."))
+		return
+	}
+
+	changeable := false
+	for _, status := range recipientAddressStatuses {
+		if order.Status == status {
+			changeable = true
+			break
+		}
+	}
+
+	if !changeable {
+		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("The drop-off address of this order can no longer be changed."))
+		return
+	}
+
+	// Orders with several drop-offs are delivered to the points their sender gave
+	if len(order.DropOffs) > 0 {
+		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("The drop-off address of an order with several drop-offs can't be changed."))
+		return
+	}
+
+	address, err := d.getOrderAddress(input.AddressID, user.ID, "Address not found.")
+	if err != nil {
+		sendRequestError(c, err)
+		return
+	}
+
+	order.DestinationID = address.LocationID
+	order.DestinationLatitude = address.Latitude
+	order.DestinationLongitude = address.Longitude
+	order.DestinationAddress = address.Line()
+	order.DestinationAddressID = address.ID
+	order.DestinationPlace = address.Place()
+
+	destinationCityID := order.DestinationCityID
+	if err := d.locateOrder(order); err != nil {
+		response.SendInternalServerError(c, err.Error())
+		return
+	}
+
+	// The line haul of an intercity order already heads to the hub of its destination city
+	if order.IsIntercity && order.DestinationCityID != destinationCityID {
+		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("The drop-off address of an intercity order must stay in its destination city."))
+		return
+	}
+
+	// The new drop-off must be delivered within the service areas, the pickup was checked already
+	count, err := d.ServiceAreaApp.CountActiveServiceAreas()
+	if err != nil {
+		response.SendInternalServerError(c, err.Error())
+		return
+	}
+
+	if count > 0 {
+		if err := d.checkDestinationCoverage(order); err != nil {
+			sendRequestError(c, err)
+			return
+		}
+	}
+
+	route, err := d.getOrderRoute(order)
+	if err != nil {
+		response.SendInternalServerError(c, err.Error())
+		return
+	}
+
+	err = d.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		// Only the destination and the route are changed, as long as the order kept its status
+		_, err := tx.Order().UpdateOrderByIDAndStatus(order.ID, order.Status, &entity.Order{
+			DestinationID:        order.DestinationID,
+			DestinationLatitude:  order.DestinationLatitude,
+			DestinationLongitude: order.DestinationLongitude,
+			DestinationAddress:   order.DestinationAddress,
+			DestinationAddressID: order.DestinationAddressID,
+			DestinationPlace:     order.DestinationPlace,
+			DestinationCityID:    order.DestinationCityID,
+			DestinationRegionID:  order.DestinationRegionID,
+			RouteDistance:        &route.Distance,
+			RouteDuration:        &route.Duration,
+			RoutePolyline:        &route.Polyline,
+		})
+		if err != nil {
+			return err
+		}
+
+		// The driver's trip heads to the new drop-off
+		if err := tx.Trip().UpdateOrderDropOffTripStops(order.ID, order.DestinationLatitude, order.DestinationLongitude); err != nil {
+			return err
+		}
+
+		if order.IsIntercity {
+			return tx.OrderLeg().UpdateLastMileOrderLegByOrderID(order.ID, order.DestinationLatitude, order.DestinationLongitude)
+		}
+
+		return nil
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		response.SendConflict(c, ginI18n.MustGetMessage("The order was updated in the meantime, please try again."))
+		return
+	}
+	if err != nil {
+		response.SendInternalServerError(c, err.Error())
+		return
+	}
+
+	// Get the updated order from the order application service.
+	updatedOrder, err := d.OrderApp.GetOrderByIDAndRecipientID(order.ID, user.ID)
+	if err != nil {
+		response.SendInternalServerError(c, err.Error())
+		return
+	}
+
+	updatedOrder.IsReceiver = true
+
+	response.SendOK(c, updatedOrder.PublicData(language.GetLanguage(c)), ginI18n.MustGetMessage("Drop-off address updated successfully."))
+}
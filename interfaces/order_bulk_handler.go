@@ -0,0 +1,281 @@
+package interfaces
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/pkg/language"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// bulkOrderRow is an order of a bulk request, or the reason it couldn't be read
+type bulkOrderRow struct {
+	order *entity.Order
+	err   string
+}
+
+// bulkOrderResult reports the outcome of an order of a bulk request. Rows are numbered from 1, the
+// header of a CSV file isn't counted.
+type bulkOrderResult struct {
+	Row     int         `json:"row"`
+	Success bool        `json:"success"`
+	Order   interface{} `json:"order,omitempty"`
+	Message string      `json:"message,omitempty"`
+	Errors  interface{} `json:"errors,omitempty"`
+}
+
+// orderColumns maps the JSON names of the order fields, which are also the CSV columns, to their types
+var orderColumns = func() map[string]reflect.Type {
+	columns := make(map[string]reflect.Type)
+	orderType := reflect.TypeOf(entity.Order{})
+	for i := 0; i < orderType.NumField(); i++ {
+		field := orderType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			columns[name] = field.Type
+		}
+	}
+	return columns
+}()
+
+// BulkCreateOrders creates orders for an organization at once, from a JSON body {"orders": [...]}
+// or from the rows of a CSV file uploaded as "file", whose columns are the fields of an order. Each
+// order goes through the checks of CreateOrder, the valid ones are created and the others are
+// reported by row.
+func (d *Orders) BulkCreateOrders(c *gin.Context) {
+	// Extract the token metadata from the request
+	metadata, err := d.TokenService.ExtractTokenMetadata(c.Request)
+	if err != nil {
+		response.SendUnauthorized(c, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := d.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(c, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Get the user from the user application service
+	user, err := d.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(c, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Requests made with an API key create the orders of its organization, the others name it
+	organizationID := metadata.OrganizationID
+	if organizationID == 0 {
+		organizationID, err = strconv.ParseUint(c.Query("organization_id"), 10, 64)
+		if err != nil {
+			response.SendBadRequest(c, ginI18n.MustGetMessage("Invalid organization ID."))
+			return
+		}
+	}
+
+	if _, err := d.OrganizationApp.GetOrganizationMemberByOrganizationIDAndUserID(organizationID, user.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.SendNotFound(c, ginI18n.MustGetMessage("Organization not found."))
+			return
+		}
+		response.SendInternalServerError(c, err.Error())
+		return
+	}
+
+	var rows []bulkOrderRow
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			response.SendBadRequest(c, ginI18n.MustGetMessage("Invalid request body"))
+			return
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			response.SendBadRequest(c, ginI18n.MustGetMessage("Invalid request body"))
+			return
+		}
+		defer reader.Close()
+
+		var ok bool
+		if rows, ok = readOrdersCSV(c, reader); !ok {
+			return
+		}
+	} else {
+		var body struct {
+			Orders []json.RawMessage `json:"orders"`
+		}
+
+		if err := c.ShouldBindJSON(&body); err != nil {
+			response.SendBadRequest(c, ginI18n.MustGetMessage("Invalid request body"))
+			return
+		}
+
+		for _, raw := range body.Orders {
+			var order entity.Order
+			if err := json.Unmarshal(raw, &order); err != nil {
+				rows = append(rows, bulkOrderRow{err: ginI18n.MustGetMessage("Invalid order.")})
+				continue
+			}
+			rows = append(rows, bulkOrderRow{order: &order})
+		}
+	}
+
+	maxBulkOrders, err := d.getMaxBulkOrders()
+	if err != nil {
+		response.SendInternalServerError(c, err.Error())
+		return
+	}
+
+	if len(rows) == 0 {
+		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("No orders given."))
+		return
+	}
+
+	if len(rows) > maxBulkOrders {
+		response.SendUnprocessableEntity(c, map[string]interface{}{"max_orders": maxBulkOrders}, ginI18n.MustGetMessage("Too many orders in a single request."))
+		return
+	}
+
+	results := make([]bulkOrderResult, len(rows))
+	created := 0
+
+	for i, row := range rows {
+		results[i].Row = i + 1
+
+		if row.order == nil {
+			results[i].Message = row.err
+			continue
+		}
+
+		createdOrder, err := d.createOrder(c, user, organizationID, row.order)
+		if err != nil {
+			var reqErr *requestError
+			if errors.As(err, &reqErr) {
+				results[i].Message = reqErr.message
+				results[i].Errors = reqErr.errors
+			} else {
+				results[i].Message = err.Error()
+			}
+			continue
+		}
+
+		results[i].Success = true
+		results[i].Order = createdOrder.PublicData(language.GetLanguage(c))
+		created++
+	}
+
+	// Prepare the response data.
+	data := make(map[string]interface{})
+	data["created"] = created
+	data["failed"] = len(rows) - created
+	data["results"] = results
+
+	response.SendOK(c, data, "")
+}
+
+// readOrdersCSV reads the orders of a CSV file. The cells of the text and time fields are taken
+// as is, the others are read as JSON, such as 3, true or [1,2]. It sends an error response and
+// returns false when the file itself can't be read.
+func readOrdersCSV(c *gin.Context, reader io.Reader) ([]bulkOrderRow, bool) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Invalid CSV file."))
+		return nil, false
+	}
+
+	for i, column := range header {
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		if _, ok := orderColumns[column]; !ok {
+			response.SendUnprocessableEntity(c, map[string]interface{}{"column": column}, ginI18n.MustGetMessage("Unknown CSV column."))
+			return nil, false
+		}
+		header[i] = column
+	}
+
+	var rows []bulkOrderRow
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Invalid CSV file."))
+				return nil, false
+			}
+			rows = append(rows, bulkOrderRow{err: ginI18n.MustGetMessage("Invalid order.")})
+			continue
+		}
+
+		order, err := orderFromCSVRecord(header, record)
+		if err != nil {
+			rows = append(rows, bulkOrderRow{err: ginI18n.MustGetMessage("Invalid order.")})
+			continue
+		}
+		rows = append(rows, bulkOrderRow{order: order})
+	}
+
+	return rows, true
+}
+
+// orderFromCSVRecord decodes an order from the cells of a CSV record
+func orderFromCSVRecord(header []string, record []string) (*entity.Order, error) {
+	values := make(map[string]json.RawMessage)
+	for i, column := range header {
+		cell := strings.TrimSpace(record[i])
+		if cell == "" {
+			continue
+		}
+
+		fieldType := orderColumns[column]
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() == reflect.String || fieldType == reflect.TypeOf(time.Time{}) {
+			text, err := json.Marshal(cell)
+			if err != nil {
+				return nil, err
+			}
+			values[column] = text
+		} else {
+			values[column] = json.RawMessage(cell)
+		}
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	var order entity.Order
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (o *Orders) getMaxBulkOrders() (int, error) {
+	maxBulkOrdersStr, err := o.SettingApp.GetSettingByKey("max_bulk_orders")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(maxBulkOrdersStr)
+}
@@ -1,13 +1,21 @@
 package interfaces
 
 import (
-	"fmt"
+	"errors"
+	"log"
+	"math"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/OmarBader7/web-service-jayeek/application"
 	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
-	"github.com/OmarBader7/web-service-jayeek/infrastructure/chat"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/geocoding"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/invoice"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/label"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/routing"
 	"github.com/OmarBader7/web-service-jayeek/pkg/geoutil"
 	"github.com/OmarBader7/web-service-jayeek/pkg/language"
 	"github.com/OmarBader7/web-service-jayeek/pkg/pagination"
@@ -21,7 +29,11 @@ import (
 type Orders struct {
 	AuthService        auth.AuthServiceInterface
 	TokenService       auth.TokenInterface
-	ChatService        chat.ChatServiceInterface
+	UnitOfWorkApp      application.UnitOfWorkApplicationInterface
+	RoutingService     routing.RoutingServiceInterface
+	InvoiceService     invoice.InvoiceServiceInterface
+	LabelService       label.LabelServiceInterface
+	GeocodingService   geocoding.GeocodingServiceInterface
 	OrderApp           application.OrderApplicationInterface
 	UserApp            application.UserApplicationInterface
 	CategoryApp        application.CategoryApplicationInterface
@@ -37,14 +49,29 @@ type Orders struct {
 	SettingApp         application.SettingApplicationInterface
 	OfferApp           application.OfferApplicationInterface
 	RatingApp          application.RatingApplicationInterface
+	OrderTimelineApp   application.OrderTimelineApplicationInterface
+	RecurringOrderApp  application.RecurringOrderApplicationInterface
+	TripApp            application.TripApplicationInterface
+	ServiceAreaApp     application.ServiceAreaApplicationInterface
+	PromotionApp       application.PromotionApplicationInterface
+	CreditApp          application.CreditApplicationInterface
+	InvoiceApp         application.InvoiceApplicationInterface
+	OrganizationApp    application.OrganizationApplicationInterface
+	AddressApp         application.AddressApplicationInterface
+	HubApp             application.HubApplicationInterface
+	OrderLegApp        application.OrderLegApplicationInterface
 }
 
 // NewOrders returns a new instance of Orders
-func NewOrders(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, chatService chat.ChatServiceInterface, orderApp application.OrderApplicationInterface, userApp application.UserApplicationInterface, categoryApp application.CategoryApplicationInterface, locationApp application.LocationApplicationInterface, driverApp application.DriverApplicationInterface, sizeApp application.SizeApplicationInterface, truckTypeApp application.TruckTypeApplicationInterface, truckModelApp application.TruckModelApplicationInterface, deliveryTimeApp application.DeliveryTimeApplicationInterface, shipmentContentApp application.ShipmentContentApplicationInterface, extraServiceApp application.ExtraServiceApplicationInterface, balanceApp application.BalanceApplicationInterface, settingApp application.SettingApplicationInterface, offerApp application.OfferApplicationInterface, ratingApp application.RatingApplicationInterface) *Orders {
+func NewOrders(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, unitOfWorkApp application.UnitOfWorkApplicationInterface, routingService routing.RoutingServiceInterface, invoiceService invoice.InvoiceServiceInterface, labelService label.LabelServiceInterface, geocodingService geocoding.GeocodingServiceInterface, orderApp application.OrderApplicationInterface, userApp application.UserApplicationInterface, categoryApp application.CategoryApplicationInterface, locationApp application.LocationApplicationInterface, driverApp application.DriverApplicationInterface, sizeApp application.SizeApplicationInterface, truckTypeApp application.TruckTypeApplicationInterface, truckModelApp application.TruckModelApplicationInterface, deliveryTimeApp application.DeliveryTimeApplicationInterface, shipmentContentApp application.ShipmentContentApplicationInterface, extraServiceApp application.ExtraServiceApplicationInterface, balanceApp application.BalanceApplicationInterface, settingApp application.SettingApplicationInterface, offerApp application.OfferApplicationInterface, ratingApp application.RatingApplicationInterface, orderTimelineApp application.OrderTimelineApplicationInterface, recurringOrderApp application.RecurringOrderApplicationInterface, tripApp application.TripApplicationInterface, serviceAreaApp application.ServiceAreaApplicationInterface, promotionApp application.PromotionApplicationInterface, creditApp application.CreditApplicationInterface, invoiceApp application.InvoiceApplicationInterface, organizationApp application.OrganizationApplicationInterface, addressApp application.AddressApplicationInterface, hubApp application.HubApplicationInterface, orderLegApp application.OrderLegApplicationInterface) *Orders {
 	return &Orders{
 		AuthService:        authService,
 		TokenService:       tokenService,
-		ChatService:        chatService,
+		UnitOfWorkApp:      unitOfWorkApp,
+		RoutingService:     routingService,
+		InvoiceService:     invoiceService,
+		LabelService:       labelService,
+		GeocodingService:   geocodingService,
 		OrderApp:           orderApp,
 		UserApp:            userApp,
 		CategoryApp:        categoryApp,
@@ -60,6 +87,17 @@ func NewOrders(authService auth.AuthServiceInterface, tokenService auth.TokenInt
 		SettingApp:         settingApp,
 		OfferApp:           offerApp,
 		RatingApp:          ratingApp,
+		OrderTimelineApp:   orderTimelineApp,
+		RecurringOrderApp:  recurringOrderApp,
+		TripApp:            tripApp,
+		ServiceAreaApp:     serviceAreaApp,
+		PromotionApp:       promotionApp,
+		CreditApp:          creditApp,
+		InvoiceApp:         invoiceApp,
+		OrganizationApp:    organizationApp,
+		AddressApp:         addressApp,
+		HubApp:             hubApp,
+		OrderLegApp:        orderLegApp,
 	}
 }
 
@@ -94,35 +132,312 @@ func (d *Orders) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	createdOrder, err := d.createOrder(c, user, 0, &order)
+	if err != nil {
+		sendRequestError(c, err)
+		return
+	}
+
+	response.SendOK(c, createdOrder.PublicData(language.GetLanguage(c)), "")
+}
+
+// createOrder validates an order of the user and creates it, for an organization when its ID is
+// given. A requestError is returned when the order isn't valid.
+func (d *Orders) createOrder(c *gin.Context, user *entity.User, organizationID uint64, order *entity.Order) (*entity.Order, error) {
+	// Saved addresses stand in for the coordinates and the destination they point to
+	if err := d.applyOrderAddresses(user.ID, order); err != nil {
+		return nil, err
+	}
+
 	// Validate all fields of the order struct except for UserID, LocationID, Amount, Status, Location, User, Driver, Recipient, Category, Size, TruckType, TruckModel, DeliveryTime, Destination, and ShipmentContents
-	validationErrors, _ := validator.ValidateExcept(c, &order, "UserID", "LocationID", "Amount", "Status", "Location", "User", "Driver", "Recipient", "Category", "Size", "TruckType", "TruckModel", "DeliveryTime", "Destination", "ShipmentContents", "ExtraServices")
+	validationErrors, _ := validator.ValidateExcept(c, order, "UserID", "LocationID", "Amount", "Status", "Location", "User", "Driver", "Recipient", "Category", "Size", "TruckType", "TruckModel", "DeliveryTime", "Destination", "ShipmentContents", "ExtraServices")
+	if validationErrors != nil {
+		return nil, &requestError{errors: validationErrors}
+	}
+
+	if err := d.prepareOrder(order); err != nil {
+		return nil, err
+	}
+
+	// Set the UserID and Status for the order struct
+	order.UserID = user.ID
+	order.Status = entity.OrderCreatedStatus
+
+	// Return and dispatch details are only set by the return flow
+	order.ReturnOfOrderID = 0
+	order.ReturnReason = nil
+	order.PreferredDriverID = 0
+	order.PreferredUntil = nil
+	order.DeliveredAt = nil
+	order.RecurringOrderID = 0
+	order.OrganizationID = organizationID
+
+	// The zone and its surge are only set by the coverage check, which skips them until service
+	// areas are set up
+	order.ServiceAreaID = 0
+	order.SurgeMultiplier = nil
+
+	// The promo code is redeemed once an offer is accepted and the amount is known
+	order.PromotionID = 0
+	order.Discount = nil
+	order.CreditUsed = nil
+	if order.PromoCode != nil && *order.PromoCode != "" {
+		promotion, err := findPromotion(d.PromotionApp, *order.PromoCode, user.ID, order.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		order.PromotionID = promotion.ID
+	}
+
+	// Intercity orders are carried hub to hub, by a driver per leg
+	if err := d.planOrderLegs(order); err != nil {
+		return nil, err
+	}
+
+	// Drop-offs are visited in the order they were given
+	for i := range order.DropOffs {
+		order.DropOffs[i].ID = 0
+		order.DropOffs[i].OrderID = 0
+		order.DropOffs[i].Sequence = uint64(i + 1)
+	}
+
+	// Orders with a pickup window far enough in the future are held back from dispatch
+	if err := d.scheduleOrder(order); err != nil {
+		return nil, err
+	}
+
+	// Orders can only be picked up and delivered within the service areas
+	pickupAt := time.Now()
+	if order.PickupWindowStart != nil {
+		pickupAt = *order.PickupWindowStart
+	}
+
+	if err := d.checkCoverage(order, pickupAt); err != nil {
+		return nil, err
+	}
+
+	// Estimate the road route from the pickup point through every drop-off
+	route, err := d.getOrderRoute(order)
+	if err != nil {
+		return nil, err
+	}
+
+	order.RouteDistance = &route.Distance
+	order.RouteDuration = &route.Duration
+	order.RoutePolyline = &route.Polyline
+
+	// Create the new order
+	return d.OrderApp.CreateOrder(order)
+}
+
+// scheduleOrder validates the pickup window of an order and marks it as scheduled when
+// the window starts after the release lead time.
+func (d *Orders) scheduleOrder(order *entity.Order) error {
+	if order.PickupWindowStart == nil {
+		order.PickupWindowEnd = nil
+		return nil
+	}
+
+	now := time.Now()
+	if !order.PickupWindowStart.After(now) {
+		return newRequestError("The pickup window must start in the future.")
+	}
+
+	if order.PickupWindowEnd == nil || !order.PickupWindowEnd.After(*order.PickupWindowStart) {
+		return newRequestError("The pickup window must end after it starts.")
+	}
+
+	releaseBefore, err := d.getScheduledOrderRelease()
+	if err != nil {
+		return err
+	}
+
+	if order.PickupWindowStart.Sub(now) > releaseBefore {
+		order.Status = entity.OrderScheduledStatus
+	}
+
+	return nil
+}
+
+// QuoteOrder estimates the road distance, duration and route of an order before it is created
+func (d *Orders) QuoteOrder(c *gin.Context) {
+	var order entity.Order
+
+	// Bind the JSON body of the request to the Order struct
+	if err := c.ShouldBindJSON(&order); err != nil {
+		response.SendBadRequest(c, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	// Only the pickup point and the drop-off points are needed to quote an order
+	validationErrors, _ := validator.ValidatePartial(c, &order, "Latitude", "Longitude", "DestinationID", "DestinationLatitude", "DestinationLongitude", "DropOffs")
 	if validationErrors != nil {
 		response.SendUnprocessableEntity(c, validationErrors, "")
 		return
 	}
 
-	// Get the category by its ID
-	category, err := d.CategoryApp.GetCategoryByID(order.CategoryID)
+	if err := d.resolveOrderDestination(&order); err != nil {
+		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Destination not found."))
+		return
+	}
+
+	route, err := d.getOrderRoute(&order)
 	if err != nil {
-		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Category not found."))
+		response.SendInternalServerError(c, err.Error())
 		return
 	}
 
-	// Get the destination by its ID
-	destination, err := d.LocationApp.GetLocationByID(order.DestinationID)
+	baseFare, err := d.getFloatSetting("base_fare")
 	if err != nil {
-		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Destination not found."))
+		response.SendInternalServerError(c, err.Error())
+		return
+	}
+
+	pricePerKm, err := d.getFloatSetting("price_per_km")
+	if err != nil {
+		response.SendInternalServerError(c, err.Error())
 		return
 	}
 
+	quote := entity.OrderQuote{
+		Distance:        route.Distance,
+		Duration:        route.Duration,
+		Polyline:        route.Polyline,
+		PriceMultiplier: 1,
+		SurgeMultiplier: 1,
+	}
+
+	// Zones carry their own pricing and the surge of their current demand
+	if serviceArea, err := d.ServiceAreaApp.GetActiveServiceAreaByCoordinates(order.Latitude, order.Longitude); err == nil {
+		quote.ServiceAreaID = serviceArea.ID
+		quote.PriceMultiplier = serviceArea.PriceMultiplier
+		quote.SurgeMultiplier = serviceArea.SurgeMultiplier
+	}
+
+	amount := (baseFare + pricePerKm*route.Distance/1000) * quote.PriceMultiplier * quote.SurgeMultiplier
+	quote.Amount = math.Round(amount*100) / 100
+
+	response.SendOK(c, quote, "")
+}
+
+// getOrderRoute returns the road route from the pickup point of an order through its drop-offs,
+// or to its destination when it has no drop-offs
+func (d *Orders) getOrderRoute(order *entity.Order) (*routing.Route, error) {
+	waypoints := []routing.Waypoint{{Latitude: order.Latitude, Longitude: order.Longitude}}
+
+	if len(order.DropOffs) == 0 {
+		waypoints = append(waypoints, routing.Waypoint{Latitude: order.DestinationLatitude, Longitude: order.DestinationLongitude})
+	}
+
+	for _, dropOff := range order.DropOffs {
+		waypoints = append(waypoints, routing.Waypoint{Latitude: dropOff.Latitude, Longitude: dropOff.Longitude})
+	}
+
+	return d.RoutingService.GetRoute(waypoints)
+}
+
+// getDriverRoute returns the road route from the assigned driver to their next stop for an order,
+// or nil when the order is not on its way
+func (d *Orders) getDriverRoute(order *entity.Order) (*routing.Route, error) {
+	if order.DriverID == 0 || order.Driver.ID == 0 {
+		return nil, nil
+	}
+
+	from := routing.Waypoint{Latitude: order.Driver.Latitude, Longitude: order.Driver.Longitude}
+
+	switch order.Status {
+	case entity.OrderAcceptedStatus, entity.PickupInProgressStatus:
+		return d.RoutingService.GetRoute([]routing.Waypoint{from, {Latitude: order.Latitude, Longitude: order.Longitude}})
+	case entity.ShipmentPickedUpStatus, entity.InTransitStatus, entity.AtDestinationCityStatus, entity.OutForDeliveryStatus, entity.DeliveryRescheduledStatus:
+		to := routing.Waypoint{Latitude: order.DestinationLatitude, Longitude: order.DestinationLongitude}
+		if len(order.DropOffs) > 0 {
+			to = routing.Waypoint{Latitude: order.DropOffs[0].Latitude, Longitude: order.DropOffs[0].Longitude}
+		}
+		return d.RoutingService.GetRoute([]routing.Waypoint{from, to})
+	}
+
+	return nil, nil
+}
+
+// checkCoverage makes sure the pickup point and every drop-off of an order are within an active
+// service area that allows its category and is open at pickup time. A requestError is returned
+// when they are not. Coverage is not restricted until service areas are set up.
+func (d *Orders) checkCoverage(order *entity.Order, pickupAt time.Time) error {
+	count, err := d.ServiceAreaApp.CountActiveServiceAreas()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	serviceArea, err := d.ServiceAreaApp.GetActiveServiceAreaByCoordinates(order.Latitude, order.Longitude)
+	if err != nil {
+		return newRequestError("The pickup location is outside our service area.")
+	}
+
+	if !serviceArea.AllowsCategory(order.CategoryID) {
+		return newRequestError("This category is not available in the pickup area.")
+	}
+
+	if !serviceArea.IsOpenAt(pickupAt) {
+		return newRequestError("The pickup area is closed at the requested time.")
+	}
+
+	if err := d.checkDestinationCoverage(order); err != nil {
+		return err
+	}
+
+	// The surge at creation time is kept so drivers know the order was placed during high demand
+	order.ServiceAreaID = serviceArea.ID
+	order.SurgeMultiplier = &serviceArea.SurgeMultiplier
+
+	return nil
+}
+
+// checkDestinationCoverage makes sure the destination or every drop-off of an order is within an
+// active service area. A requestError is returned when one is not.
+func (d *Orders) checkDestinationCoverage(order *entity.Order) error {
+	var destinations [][2]float64
+	if len(order.DropOffs) == 0 {
+		destinations = append(destinations, [2]float64{order.DestinationLatitude, order.DestinationLongitude})
+	}
+
+	for _, dropOff := range order.DropOffs {
+		destinations = append(destinations, [2]float64{dropOff.Latitude, dropOff.Longitude})
+	}
+
+	for _, destination := range destinations {
+		if _, err := d.ServiceAreaApp.GetActiveServiceAreaByCoordinates(destination[0], destination[1]); err != nil {
+			return newRequestError("The destination is outside our service area.")
+		}
+	}
+
+	return nil
+}
+
+// prepareOrder looks up the taxonomies referenced by an order and resolves its pickup location.
+// A requestError is returned when one of them can't be found.
+func (d *Orders) prepareOrder(order *entity.Order) error {
+	// Get the category by its ID
+	category, err := d.CategoryApp.GetCategoryByID(order.CategoryID)
+	if err != nil {
+		return newRequestError("Category not found.")
+	}
+
+	// Resolve the destination from its coordinates, or from the location it names
+	if err := d.resolveOrderDestination(order); err != nil {
+		return newRequestError("Destination not found.")
+	}
+
 	var driver *entity.Driver
 
 	if order.DriverID != 0 {
 		// Get the driver by its ID
 		driver, err = d.DriverApp.GetDriverByID(order.DriverID)
 		if err != nil {
-			response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Driver not found."))
-			return
+			return newRequestError("Driver not found.")
 		}
 	}
 
@@ -134,49 +449,47 @@ func (d *Orders) CreateOrder(c *gin.Context) {
 		// Get the truck type by its ID
 		truckType, err = d.TruckTypeApp.GetTruckTypeByID(order.TruckTypeID)
 		if err != nil {
-			response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Truck type not found."))
-			return
+			return newRequestError("Truck type not found.")
 		}
 
 		// Get the truck type by its ID
 		truckModel, err = d.TruckModelApp.GetTruckModelByID(order.TruckModelID)
 		if err != nil {
-			response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Truck model not found."))
-			return
+			return newRequestError("Truck model not found.")
 		}
 	} else {
 		// Get the size by its ID
 		size, err = d.SizeApp.GetSizeByID(order.SizeID)
 		if err != nil {
-			response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Size not found."))
-			return
+			return newRequestError("Size not found.")
 		}
 	}
 
 	// Get the delivery time by its ID
 	deliveryTime, err := d.DeliveryTimeApp.GetDeliveryTimeByID(order.DeliveryTimeID)
 	if err != nil {
-		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Delivery time not found."))
-		return
+		return newRequestError("Delivery time not found.")
 	}
 
 	shipmentContents := make([]entity.ShipmentContent, len(order.ShipmentContentIDs))
 	for i, shipmentContentID := range order.ShipmentContentIDs {
 		shipmentContent, err := d.ShipmentContentApp.GetShipmentContentByID(shipmentContentID)
 		if err != nil {
-			response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Shipment content not found."))
-			return
+			return newRequestError("Shipment content not found.")
 		}
 		shipmentContents[i] = *shipmentContent
 	}
 	order.ShipmentContents = shipmentContents
 
+	if order.ExtraServiceIDs == nil {
+		order.ExtraServiceIDs = &[]uint64{}
+	}
+
 	extraServices := make([]entity.ExtraService, len(*order.ExtraServiceIDs))
 	for i, extraServiceID := range *order.ExtraServiceIDs {
 		extraService, err := d.ExtraServiceApp.GetExtraServiceByID(extraServiceID)
 		if err != nil {
-			response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Extra service not found."))
-			return
+			return newRequestError("Extra service not found.")
 		}
 		extraServices[i] = *extraService
 	}
@@ -184,13 +497,11 @@ func (d *Orders) CreateOrder(c *gin.Context) {
 
 	location, err := d.LocationApp.GetLocationByCoordinates(order.Longitude, order.Latitude, nil)
 	if err != nil {
-		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Location not found."))
-		return
+		return newRequestError("Location not found.")
 	}
 
-	// Set the LocationID, UserID, DriverID, SizeID, DeliveryTimeID, DestinationID and Status for the order struct
+	// Set the LocationID, DriverID, SizeID and DeliveryTimeID for the order struct
 	order.LocationID = location.ID
-	order.UserID = user.ID
 	if order.DriverID != 0 {
 		order.DriverID = driver.ID
 	}
@@ -209,17 +520,56 @@ func (d *Orders) CreateOrder(c *gin.Context) {
 	}
 
 	order.DeliveryTimeID = deliveryTime.ID
+
+	if err := d.locateOrder(order); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resolveOrderDestination completes the destination of an order. Orders with destination
+// coordinates are delivered to them and get the location nearest to them, the others are
+// delivered to the location they name.
+func (d *Orders) resolveOrderDestination(order *entity.Order) error {
+	if order.DestinationLatitude == 0 && order.DestinationLongitude == 0 {
+		destination, err := d.LocationApp.GetLocationByID(order.DestinationID)
+		if err != nil {
+			return err
+		}
+
+		order.DestinationLatitude = destination.Latitude
+		order.DestinationLongitude = destination.Longitude
+		return nil
+	}
+
+	destination, err := d.LocationApp.GetLocationByCoordinates(order.DestinationLongitude, order.DestinationLatitude, nil)
+	if err != nil {
+		return err
+	}
+
 	order.DestinationID = destination.ID
-	order.Status = entity.OrderCreatedStatus
+	return nil
+}
 
-	// Create the new order
-	createdOrder, err := d.OrderApp.CreateOrder(&order)
+// locateOrder sets the cities and regions of the pickup and the destination of an order. They're
+// left unset where the geocoding dataset doesn't cover the point.
+func (d *Orders) locateOrder(order *entity.Order) error {
+	pickup, err := d.GeocodingService.Reverse(order.Latitude, order.Longitude)
 	if err != nil {
-		response.SendInternalServerError(c, err.Error())
-		return
+		return err
 	}
 
-	response.SendOK(c, createdOrder.PublicData(language.GetLanguage(c)), "")
+	destination, err := d.GeocodingService.Reverse(order.DestinationLatitude, order.DestinationLongitude)
+	if err != nil {
+		return err
+	}
+
+	order.CityID = pickup.CityID()
+	order.RegionID = pickup.RegionID()
+	order.DestinationCityID = destination.CityID()
+	order.DestinationRegionID = destination.RegionID()
+	return nil
 }
 
 // GetAllOrders retrieves a paginated list of all orders.
@@ -537,57 +887,94 @@ func (o *Orders) AcceptDriverPoolByID(ctx *gin.Context) {
 		return
 	}
 
-	// Get the order from the order application service.
-	orderDriverPool, err := o.OrderApp.GetOrderDriverPoolByOrderIDAndDriverID(order.ID, driver.ID)
-	if err != nil {
+	// Get the order driver pool from the order application service.
+	if _, err := o.OrderApp.GetOrderDriverPoolByOrderIDAndDriverID(order.ID, driver.ID); err != nil {
 		response.SendNotFound(ctx, ginI18n.MustGetMessage("Order driver pool not found."))
 		return
 	}
 
-	offersCount, _ := o.OfferApp.CountOffersByDriverIDAndStatus(driver.ID, entity.OfferStatusPending)
-
-	statusesToExclude := []entity.OrderStatus{
-		entity.OrderCompletedStatus,
-		entity.OrderCanceledStatus,
-		entity.ShipmentReturnedStatus,
-	}
-
-	ordersCount, _ := o.OrderApp.CountOrdersByDriverIDExcludingStatus(driver.ID, statusesToExclude)
-
 	maxOrdersPerTrip, err := o.getMaxOrdersPerTrip()
 	if err != nil {
 		response.SendInternalServerError(ctx, err.Error())
-	}
-
-	if offersCount+ordersCount >= maxOrdersPerTrip {
-		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Max order limit reached."))
 		return
 	}
 
+	// The preferred driver of an order is offered it regardless of their current position
 	dist := geoutil.CalculateDistance(driver.Latitude, driver.Longitude, order.Latitude, order.Longitude)
-	if dist > 10.0 {
+	if order.PreferredDriverID != driver.ID && dist > 10.0 {
 		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Driver out of range."))
 		return
 	}
 
-	orderDriverPool.Status = entity.AcceptedStatus
+	// The driver's row is locked while their workload is counted and the offer created, so two
+	// requests of the same driver can't both pass the limit
+	limitReached := false
+	err = o.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		if err := tx.Driver().LockDriverByID(driver.ID); err != nil {
+			return err
+		}
 
-	if _, err := o.OrderApp.UpdateOrderDriverPoolByOrderIDAndDriverID(order.ID, driver.ID, orderDriverPool); err != nil {
-		response.SendInternalServerError(ctx, err.Error())
-		return
-	}
+		// The pool is read again under the lock, a concurrent request may have accepted it
+		orderDriverPool, err := tx.Order().GetOrderDriverPoolByOrderIDAndDriverID(order.ID, driver.ID)
+		if err != nil {
+			return err
+		}
+		if orderDriverPool.Status == entity.AcceptedStatus {
+			return repository.ErrConflict
+		}
 
-	offer.DriverID = driver.ID
-	offer.OrderID = order.ID
-	offer.Status = entity.OfferStatusPending
+		offersCount, err := tx.Offer().CountOffersByDriverIDAndStatus(driver.ID, entity.OfferStatusPending)
+		if err != nil {
+			return err
+		}
+
+		statusesToExclude := []entity.OrderStatus{
+			entity.OrderCompletedStatus,
+			entity.OrderCanceledStatus,
+			entity.ShipmentReturnedStatus,
+		}
+
+		ordersCount, err := tx.Order().CountOrdersByDriverIDExcludingStatus(driver.ID, statusesToExclude)
+		if err != nil {
+			return err
+		}
+
+		if offersCount+ordersCount >= maxOrdersPerTrip {
+			limitReached = true
+			return nil
+		}
 
-	// Create the new offer
-	_, err = o.OfferApp.CreateOffer(&offer)
+		orderDriverPool.Status = entity.AcceptedStatus
+
+		if _, err := tx.Order().UpdateOrderDriverPoolByOrderIDAndDriverID(order.ID, driver.ID, orderDriverPool); err != nil {
+			return err
+		}
+
+		offer.DriverID = driver.ID
+		offer.OrderID = order.ID
+		offer.Status = entity.OfferStatusPending
+
+		// Create the new offer
+		if _, err := tx.Offer().CreateOffer(&offer); err != nil {
+			return err
+		}
+
+		return emitOfferEvent(tx, entity.OfferCreatedEvent, &offer, order.UserID)
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		response.SendConflict(ctx, ginI18n.MustGetMessage("You already sent an offer for this order."))
+		return
+	}
 	if err != nil {
 		response.SendInternalServerError(ctx, err.Error())
 		return
 	}
 
+	if limitReached {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Max order limit reached."))
+		return
+	}
+
 	response.SendOK(ctx, order.PublicData(language.GetLanguage(ctx)), "")
 }
 
@@ -648,9 +1035,25 @@ func (o *Orders) DeclineDriverPoolByID(ctx *gin.Context) {
 		return
 	}
 
+	// Once the preferred driver declines, the order is open to every driver nearby
+	if order.PreferredDriverID == driver.ID && order.Status == entity.OrderCreatedStatus {
+		if err := o.OrderApp.ReleasePreferredOrderByID(order.ID); err != nil {
+			response.SendInternalServerError(ctx, err.Error())
+			return
+		}
+	}
+
 	response.SendOK(ctx, nil, "")
 }
 
+// cancelableStatuses are the statuses of the orders their sender can still cancel, before the
+// shipment is on its way
+var cancelableStatuses = []entity.OrderStatus{
+	entity.OrderCreatedStatus,
+	entity.OrderScheduledStatus,
+	entity.OrderAcceptedStatus,
+}
+
 func (o *Orders) CancelOrderByID(ctx *gin.Context) {
 	// Extract the token metadata from the request
 	metadata, err := o.TokenService.ExtractTokenMetadata(ctx.Request)
@@ -687,34 +1090,57 @@ func (o *Orders) CancelOrderByID(ctx *gin.Context) {
 		return
 	}
 
-	order.Status = entity.OrderCanceledStatus
+	// Orders can only be canceled until they're picked up
+	status := order.Status
+	cancelable := false
+	for _, cancelableStatus := range cancelableStatuses {
+		if status == cancelableStatus {
+			cancelable = true
+			break
+		}
+	}
 
-	updatedOrder, err := o.OrderApp.UpdateOrderByID(order.ID, order)
-	if err != nil {
-		response.SendInternalServerError(ctx, err.Error())
+	if !cancelable {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("This order can no longer be canceled."))
 		return
 	}
 
-	if order.RecipientID != 0 {
-		// Get the recipient from the user application service
-		recipient, err := o.UserApp.GetUserByID(order.RecipientID)
-		if err != nil {
-			response.SendNotFound(ctx, ginI18n.MustGetMessage("Recipient not found."))
-			return
+	order.Status = entity.OrderCanceledStatus
+
+	var updatedOrder *entity.Order
+	err = o.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		var err error
+		if updatedOrder, err = tx.Order().UpdateOrderByIDAndStatus(order.ID, status, order); err != nil {
+			return err
 		}
 
-		err = o.ChatService.RemoveMember(fmt.Sprintf("order-%d", order.ID), fmt.Sprintf("client-%d", recipient.ID))
+		// A canceled order no longer has stops to visit
+		if err := tx.Trip().RemoveOrderFromTrips(order.ID); err != nil {
+			return err
+		}
 
-		if err != nil {
-			response.SendInternalServerError(ctx, ginI18n.MustGetMessage("Failed to remove members from the chat channel."))
-			return
+		// The legs of an intercity order that weren't carried yet are canceled with it
+		if err := tx.OrderLeg().CancelOrderLegsByOrderID(order.ID); err != nil {
+			return err
 		}
-	}
 
-	err = o.ChatService.RemoveMember(fmt.Sprintf("order-%d", order.ID), fmt.Sprintf("client-%d", userID))
+		// The credit and the promotion spent on the order go back to the sender
+		if err := tx.Credit().DeleteCreditsByOrderIDAndReason(order.ID, entity.OrderCreditReason); err != nil {
+			return err
+		}
+
+		if err := tx.Promotion().ReleasePromotionRedemptionByOrderID(order.ID); err != nil {
+			return err
+		}
 
+		return emitOrderEvent(tx, entity.OrderCanceledEvent, order, order.Driver.UserID)
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		response.SendConflict(ctx, ginI18n.MustGetMessage("The order was changed meanwhile."))
+		return
+	}
 	if err != nil {
-		response.SendInternalServerError(ctx, ginI18n.MustGetMessage("Failed to remove members from the chat channel."))
+		response.SendInternalServerError(ctx, err.Error())
 		return
 	}
 
@@ -764,56 +1190,51 @@ func (o *Orders) DeliverOrderByID(ctx *gin.Context) {
 		return
 	}
 
-	order.Status = entity.ShipmentDeliveredStatus
-
-	updatedOrder, err := o.OrderApp.UpdateOrderByID(order.ID, order)
-	if err != nil {
-		response.SendInternalServerError(ctx, err.Error())
+	if order.IsIntercity {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Intercity orders are picked up and delivered leg by leg."))
 		return
 	}
 
-	var balance entity.Balance
+	deliveredAt := time.Now()
 
-	balance.OrderID = updatedOrder.ID
-	balance.DriverID = updatedOrder.Driver.ID
-
-	// Create the new balance
-	_, err = o.BalanceApp.CreateBalance(&balance)
-	if err != nil {
-		response.SendInternalServerError(ctx, err.Error())
-		return
-	}
+	order.Status = entity.ShipmentDeliveredStatus
+	order.DeliveredAt = &deliveredAt
+
+	// The order and the driver's balance are written together with the event of the delivery
+	var updatedOrder *entity.Order
+	err = o.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		var err error
+		if updatedOrder, err = tx.Order().UpdateOrderByID(order.ID, order); err != nil {
+			return err
+		}
 
-	err = o.ChatService.RemoveMember(fmt.Sprintf("order-%d", order.ID), fmt.Sprintf("driver-%d", driver.User.ID))
+		var balance entity.Balance
 
-	if err != nil {
-		response.SendInternalServerError(ctx, ginI18n.MustGetMessage("Failed to remove members from the chat channel."))
-		return
-	}
+		balance.OrderID = updatedOrder.ID
+		balance.DriverID = updatedOrder.Driver.ID
 
-	if order.RecipientID != 0 {
-		// Get the recipient from the user application service
-		recipient, err := o.UserApp.GetUserByID(order.RecipientID)
-		if err != nil {
-			response.SendNotFound(ctx, ginI18n.MustGetMessage("Recipient not found."))
-			return
+		// Create the new balance
+		if _, err := tx.Balance().CreateBalance(&balance); err != nil {
+			return err
 		}
 
-		err = o.ChatService.RemoveMember(fmt.Sprintf("order-%d", order.ID), fmt.Sprintf("client-%d", recipient.ID))
-
-		if err != nil {
-			response.SendInternalServerError(ctx, ginI18n.MustGetMessage("Failed to remove members from the chat channel."))
-			return
+		// The stops of the order in the driver's trip are done with it
+		if err := tx.Trip().CompleteOrderTripStops(order.ID, []entity.TripStopType{entity.PickupStopType, entity.DropOffStopType}, deliveredAt); err != nil {
+			return err
 		}
-	}
-
-	err = o.ChatService.RemoveMember(fmt.Sprintf("order-%d", order.ID), fmt.Sprintf("driver-%d", driver.User.ID))
 
+		return emitOrderEvent(tx, entity.OrderDeliveredEvent, order, driver.UserID)
+	})
 	if err != nil {
-		response.SendInternalServerError(ctx, ginI18n.MustGetMessage("Failed to remove members from the chat channel."))
+		response.SendInternalServerError(ctx, err.Error())
 		return
 	}
 
+	// The invoice is issued with the delivery, it's generated again on request if this fails
+	if _, err := o.issueInvoice(updatedOrder); err != nil {
+		log.Println("Error issuing invoice: ", err)
+	}
+
 	response.SendOK(ctx, updatedOrder.PublicData(language.GetLanguage(ctx)), "")
 }
 
@@ -860,9 +1281,23 @@ func (o *Orders) PickupOrderByID(ctx *gin.Context) {
 		return
 	}
 
+	if order.IsIntercity {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Intercity orders are picked up and delivered leg by leg."))
+		return
+	}
+
 	order.Status = entity.ShipmentPickedUpStatus
 
-	updatedOrder, err := o.OrderApp.UpdateOrderByID(order.ID, order)
+	// The pickup stop of the order in the driver's trip is done with it
+	var updatedOrder *entity.Order
+	err = o.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		var err error
+		if updatedOrder, err = tx.Order().UpdateOrderByID(order.ID, order); err != nil {
+			return err
+		}
+
+		return tx.Trip().CompleteOrderTripStops(order.ID, []entity.TripStopType{entity.PickupStopType}, time.Now())
+	})
 	if err != nil {
 		response.SendInternalServerError(ctx, err.Error())
 		return
@@ -887,11 +1322,16 @@ func (l *Orders) GetOrderByID(ctx *gin.Context) {
 		return
 	}
 
+	// Estimate how long the driver needs to reach their next stop for this order
+	if route, err := l.getDriverRoute(order); err == nil && route != nil {
+		order.DriverETA = &route.Duration
+	}
+
 	// Send the order as a response.
 	response.SendOK(ctx, order.PublicData(language.GetLanguage(ctx)).(*entity.OrderPublicData), "")
 }
 
-// RateOrderByID handles the creation of a new order
+// RateOrderByID lets the sender rate the driver of a delivered order
 func (d *Orders) RateOrderByID(c *gin.Context) {
 	var rating entity.Rating
 
@@ -936,35 +1376,167 @@ func (d *Orders) RateOrderByID(c *gin.Context) {
 		return
 	}
 
-	validationErrors, _ := validator.ValidateExcept(c, &rating, "OrderID", "UserID")
+	validationErrors, _ := validator.ValidateExcept(c, &rating, "OrderID", "UserID", "User")
 	if validationErrors != nil {
 		response.SendUnprocessableEntity(c, validationErrors, "")
 		return
 	}
 
+	// The driver is rated on every criterion
+	if rating.FastRating == nil || rating.ExperienceRating == nil || rating.RecommendedRating == nil {
+		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("All rating criteria are required."))
+		return
+	}
+
+	if !d.canRateOrder(c, order, entity.DriverRatingType) {
+		return
+	}
+
+	rating.ID = 0
 	rating.OrderID = order.ID
 	rating.UserID = user.ID
+	rating.RatedUserID = order.Driver.UserID
+	rating.Type = entity.DriverRatingType
+	rating.CalculateScore()
+
+	// Create the new rating along with the event of the sender leaving the order
+	err = d.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		if _, err := tx.Rating().CreateRating(&rating); err != nil {
+			return err
+		}
 
-	// Create the new order
-	_, err = d.RatingApp.CreateRating(&rating)
+		return emitOrderEvent(tx, entity.OrderDriverRatedEvent, order, order.Driver.UserID)
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		response.SendConflict(c, ginI18n.MustGetMessage("You have already rated this order."))
+		return
+	}
 	if err != nil {
 		response.SendInternalServerError(c, err.Error())
 		return
 	}
 
-	err = d.ChatService.RemoveMember(fmt.Sprintf("order-%d", order.ID), fmt.Sprintf("client-%d", userID))
+	response.SendOK(c, order.PublicData(language.GetLanguage(c)), "")
+}
+
+// RateCustomerByID lets the driver rate the sender of a delivered order
+func (d *Orders) RateCustomerByID(c *gin.Context) {
+	var rating entity.Rating
 
+	// Parse the order ID from the URL parameter.
+	orderID, err := strconv.ParseUint(c.Param("order_id"), 10, 64)
 	if err != nil {
-		response.SendInternalServerError(c, ginI18n.MustGetMessage("Failed to remove members from the chat channel."))
+		response.SendBadRequest(c, ginI18n.MustGetMessage("Invalid order ID."))
 		return
 	}
 
-	response.SendOK(c, order.PublicData(language.GetLanguage(c)), "")
+	// Bind the JSON body of the request to the Rating struct
+	if err := c.ShouldBindJSON(&rating); err != nil {
+		response.SendBadRequest(c, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	// Extract the token metadata from the request
+	metadata, err := d.TokenService.ExtractTokenMetadata(c.Request)
+	if err != nil {
+		response.SendUnauthorized(c, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := d.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(c, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Get the user from the user application service
+	user, err := d.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(c, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Get the driver from the driver application service
+	driver, err := d.DriverApp.GetDriverByUserID(user.ID)
+	if err != nil {
+		response.SendNotFound(c, ginI18n.MustGetMessage("Driver not found."))
+		return
+	}
+
+	// Get the order from the order application service.
+	order, err := d.OrderApp.GetOrderByIDAndDriverID(orderID, driver.ID)
+	if err != nil {
+		response.SendNotFound(c, ginI18n.MustGetMessage("Order not found."))
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(c, &rating, "OrderID", "UserID", "User")
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(c, validationErrors, "")
+		return
+	}
+
+	// Customers are rated with a single score
+	if rating.Score == nil {
+		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("The rating score is required."))
+		return
+	}
+
+	if !d.canRateOrder(c, order, entity.CustomerRatingType) {
+		return
+	}
+
+	rating.ID = 0
+	rating.OrderID = order.ID
+	rating.UserID = user.ID
+	rating.RatedUserID = order.UserID
+	rating.Type = entity.CustomerRatingType
+	rating.FastRating = nil
+	rating.ExperienceRating = nil
+	rating.RecommendedRating = nil
+
+	// Create the new rating
+	createdRating, err := d.RatingApp.CreateRating(&rating)
+	if errors.Is(err, repository.ErrConflict) {
+		response.SendConflict(c, ginI18n.MustGetMessage("You have already rated this order."))
+		return
+	}
+	if err != nil {
+		response.SendInternalServerError(c, err.Error())
+		return
+	}
+
+	response.SendOK(c, createdRating.PublicData(), "")
 }
 
-// ReturnOrderByID handles the creation of a new order
+// canRateOrder makes sure an order was delivered and hasn't been rated by the same party yet,
+// sending an error response otherwise.
+func (d *Orders) canRateOrder(c *gin.Context, order *entity.Order, ratingType entity.RatingType) bool {
+	if order.Status != entity.ShipmentDeliveredStatus && order.Status != entity.OrderCompletedStatus {
+		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Orders can only be rated after they are delivered."))
+		return false
+	}
+
+	if _, err := d.RatingApp.GetRatingByOrderIDAndType(order.ID, ratingType); err == nil {
+		response.SendConflict(c, ginI18n.MustGetMessage("You have already rated this order."))
+		return false
+	}
+
+	return true
+}
+
+// ReturnOrderByID lets the recipient of a delivered order send it back to its sender within the
+// return window. The return is built from the original order, only its pickup point and reason
+// are taken from the request.
 func (d *Orders) ReturnOrderByID(c *gin.Context) {
-	var newOrder entity.Order
+	var input struct {
+		ReturnReason       string  `json:"return_reason"`
+		Latitude           float64 `json:"latitude"`
+		Longitude          float64 `json:"longitude"`
+		PickupAddressID    uint64  `json:"pickup_address_id"`
+		OfferToDriverFirst bool    `json:"offer_to_driver_first"`
+	}
 
 	// Parse the order ID from the URL parameter.
 	orderID, err := strconv.ParseUint(c.Param("order_id"), 10, 64)
@@ -973,8 +1545,8 @@ func (d *Orders) ReturnOrderByID(c *gin.Context) {
 		return
 	}
 
-	// Bind the JSON body of the request to the Order struct
-	if err := c.ShouldBindJSON(&newOrder); err != nil {
+	// Bind the JSON body of the request to the input struct
+	if err := c.ShouldBindJSON(&input); err != nil {
 		response.SendBadRequest(c, ginI18n.MustGetMessage("Invalid request body"))
 		return
 	}
@@ -1007,127 +1579,252 @@ func (d *Orders) ReturnOrderByID(c *gin.Context) {
 		return
 	}
 
+	returnReason := strings.TrimSpace(input.ReturnReason)
+	if returnReason == "" {
+		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Return reason is required."))
+		return
+	}
+
+	// Only delivered orders can be returned, which also rules out orders that were already returned
+	if order.Status != entity.ShipmentDeliveredStatus && order.Status != entity.OrderCompletedStatus {
+		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Only delivered orders can be returned."))
+		return
+	}
+
+	returnWindow, err := d.getReturnWindow()
+	if err != nil {
+		response.SendInternalServerError(c, err.Error())
+		return
+	}
+
+	// Orders delivered before the delivery time was tracked fall back to their last update
+	deliveredAt := order.UpdatedAt
+	if order.DeliveredAt != nil {
+		deliveredAt = *order.DeliveredAt
+	}
+
+	if time.Since(deliveredAt) > returnWindow {
+		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("The return window for this order has expired."))
+		return
+	}
+
+	// The return carries the same shipment back to where the original order was picked up, from
+	// where the recipient is or one of their saved addresses
+	shipmentContentIDs := make([]uint64, len(order.ShipmentContents))
+	for i, shipmentContent := range order.ShipmentContents {
+		shipmentContentIDs[i] = shipmentContent.ID
+	}
+
+	extraServiceIDs := []uint64{}
+	if order.ExtraServices != nil {
+		for _, extraService := range *order.ExtraServices {
+			extraServiceIDs = append(extraServiceIDs, extraService.ID)
+		}
+	}
+
+	newOrder := entity.Order{
+		UserID:               user.ID,
+		CategoryID:           order.CategoryID,
+		SizeID:               order.SizeID,
+		TruckTypeID:          order.TruckTypeID,
+		TruckModelID:         order.TruckModelID,
+		DeliveryTimeID:       order.DeliveryTimeID,
+		ShipmentContentIDs:   shipmentContentIDs,
+		ExtraServiceIDs:      &extraServiceIDs,
+		Quantity:             order.Quantity,
+		RecipientPhoneNumber: order.User.Phone,
+		Latitude:             input.Latitude,
+		Longitude:            input.Longitude,
+		PickupAddressID:      input.PickupAddressID,
+		DestinationLatitude:  order.Latitude,
+		DestinationLongitude: order.Longitude,
+		IsIntercity:          order.IsIntercity,
+		Status:               entity.OrderCreatedStatus,
+		ReturnOfOrderID:      order.ID,
+		ReturnReason:         &returnReason,
+	}
+
+	if err := d.applyOrderAddresses(user.ID, &newOrder); err != nil {
+		sendRequestError(c, err)
+		return
+	}
+	newOrder.DestinationPlace = order.PickupPlace
+
 	if validationErrors, _ := validator.ValidatePartial(c, &newOrder, "Longitude", "Latitude"); validationErrors != nil {
 		response.SendUnprocessableEntity(c, validationErrors, "")
 		return
 	}
 
-	// Get the category by its ID
-	category, err := d.CategoryApp.GetCategoryByID(order.CategoryID)
-	if err != nil {
-		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Category not found."))
+	if err := d.prepareOrder(&newOrder); err != nil {
+		sendRequestError(c, err)
+		return
+	}
+
+	// The return of an intercity order goes back through the hubs
+	if err := d.planOrderLegs(&newOrder); err != nil {
+		sendRequestError(c, err)
+		return
+	}
+
+	if err := d.checkCoverage(&newOrder, time.Now()); err != nil {
+		sendRequestError(c, err)
 		return
 	}
 
-	// Get the destination by its ID
-	destination, err := d.LocationApp.GetLocationByID(order.LocationID)
+	route, err := d.getOrderRoute(&newOrder)
 	if err != nil {
-		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Destination not found."))
+		response.SendInternalServerError(c, err.Error())
 		return
 	}
 
-	var size *entity.Size
-	var truckType *entity.TruckType
-	var truckModel *entity.TruckModel
+	newOrder.RouteDistance = &route.Distance
+	newOrder.RouteDuration = &route.Duration
+	newOrder.RoutePolyline = &route.Polyline
 
-	if category.IsTruck != nil && *category.IsTruck {
-		// Get the truck type by its ID
-		truckType, err = d.TruckTypeApp.GetTruckTypeByID(order.TruckTypeID)
+	// Offer the return to the driver who delivered the original order first if requested. It's
+	// open to every driver nearby once they decline it or their turn is over.
+	if input.OfferToDriverFirst && order.DriverID != 0 {
+		preferredDriverWindow, err := d.getPreferredDriverWindow()
 		if err != nil {
-			response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Truck type not found."))
+			response.SendInternalServerError(c, err.Error())
 			return
 		}
 
-		// Get the truck type by its ID
-		truckModel, err = d.TruckModelApp.GetTruckModelByID(order.TruckModelID)
-		if err != nil {
-			response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Truck model not found."))
-			return
+		preferredUntil := time.Now().Add(preferredDriverWindow)
+		newOrder.PreferredDriverID = order.DriverID
+		newOrder.PreferredUntil = &preferredUntil
+	}
+
+	var createdOrder *entity.Order
+	err = d.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		// Only the first of concurrent returns of the order goes through
+		returnedOrder := entity.Order{Status: entity.ShipmentReturnedStatus}
+		if _, err := tx.Order().UpdateOrderByIDAndStatus(order.ID, order.Status, &returnedOrder); err != nil {
+			return err
 		}
-	} else {
-		// Get the size by its ID
-		size, err = d.SizeApp.GetSizeByID(order.SizeID)
+
+		createdOrder, err = tx.Order().CreateOrder(&newOrder)
 		if err != nil {
-			response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Size not found."))
-			return
+			return err
+		}
+
+		// Show the return in the timelines of both orders
+		orderTimelines := []entity.OrderTimeline{
+			{OrderID: order.ID, Event: entity.OrderReturnRequestedEvent, RelatedOrderID: createdOrder.ID, Note: &returnReason},
+			{OrderID: createdOrder.ID, Event: entity.OrderReturnCreatedEvent, RelatedOrderID: order.ID, Note: &returnReason},
+		}
+
+		for _, orderTimeline := range orderTimelines {
+			if _, err := tx.OrderTimeline().CreateOrderTimeline(&orderTimeline); err != nil {
+				return err
+			}
 		}
+
+		order.Status = entity.ShipmentReturnedStatus
+		return emitOrderEvent(tx, entity.OrderReturnedEvent, order, order.Driver.UserID)
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		response.SendConflict(c, ginI18n.MustGetMessage("The order was already returned."))
+		return
+	}
+	if err != nil {
+		response.SendInternalServerError(c, err.Error())
+		return
 	}
 
-	// Get the delivery time by its ID
-	deliveryTime, err := d.DeliveryTimeApp.GetDeliveryTimeByID(order.DeliveryTimeID)
+	response.SendOK(c, createdOrder.PublicData(language.GetLanguage(c)), "")
+}
+
+// GetOrderTimelineByID retrieves the timeline of an order for its sender, recipient or driver.
+func (o *Orders) GetOrderTimelineByID(ctx *gin.Context) {
+	// Extract the token metadata from the request
+	metadata, err := o.TokenService.ExtractTokenMetadata(ctx.Request)
 	if err != nil {
-		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Delivery time not found."))
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
 		return
 	}
 
-	shipmentContents := make([]entity.ShipmentContent, len(order.ShipmentContents))
-	for i, shipmentContent := range order.ShipmentContents {
-		shipmentContent, err := d.ShipmentContentApp.GetShipmentContentByID(shipmentContent.ID)
-		if err != nil {
-			response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Shipment content not found."))
-			return
-		}
-		shipmentContents[i] = *shipmentContent
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := o.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
 	}
-	newOrder.ShipmentContents = shipmentContents
 
-	extraServices := make([]entity.ExtraService, len(*order.ExtraServices))
-	for i, extraService := range *order.ExtraServices {
-		extraService, err := d.ExtraServiceApp.GetExtraServiceByID(extraService.ID)
-		if err != nil {
-			response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Extra service not found."))
-			return
-		}
-		extraServices[i] = *extraService
+	// Get the user from the user application service
+	user, err := o.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
 	}
-	newOrder.ExtraServices = &extraServices
 
-	location, err := d.LocationApp.GetLocationByCoordinates(newOrder.Longitude, newOrder.Latitude, nil)
+	// Parse the order ID from the URL parameter.
+	orderID, err := strconv.ParseUint(ctx.Param("order_id"), 10, 64)
 	if err != nil {
-		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Location not found."))
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid order ID."))
 		return
 	}
 
-	// Set the LocationID, UserID, DriverID, SizeID, DeliveryTimeID, DestinationID and Status for the order struct
-	newOrder.CategoryID = category.ID
-	newOrder.LocationID = location.ID
-	newOrder.UserID = user.ID
+	// Get the order from the order application service.
+	order, err := o.OrderApp.GetOrderByID(orderID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Order not found."))
+		return
+	}
 
-	if category.IsTruck != nil && *category.IsTruck {
-		if order.TruckTypeID != 0 {
-			newOrder.TruckTypeID = truckType.ID
-		}
-		if order.TruckModelID != 0 {
-			newOrder.TruckModelID = truckModel.ID
-		}
-	} else {
-		if order.SizeID != 0 {
-			newOrder.SizeID = size.ID
-		}
+	isDriver := false
+	if driver, err := o.DriverApp.GetDriverByUserID(user.ID); err == nil {
+		isDriver = order.DriverID != 0 && order.DriverID == driver.ID
 	}
 
-	newOrder.DeliveryTimeID = deliveryTime.ID
-	newOrder.DestinationID = destination.ID
-	newOrder.Quantity = order.Quantity
-	newOrder.RecipientPhoneNumber = order.User.Phone
-	newOrder.Status = entity.OrderCreatedStatus
+	if order.UserID != user.ID && order.RecipientID != user.ID && !isDriver {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Order not found."))
+		return
+	}
 
-	// Create the new order
-	createdOrder, err := d.OrderApp.CreateOrder(&newOrder)
+	// Get the order timeline from the order timeline application service.
+	orderTimelines, err := o.OrderTimelineApp.GetAllOrderTimelinesByOrderID(order.ID)
 	if err != nil {
-		response.SendInternalServerError(c, err.Error())
+		response.SendInternalServerError(ctx, err.Error())
 		return
 	}
 
-	order.Status = entity.ShipmentReturnedStatus
+	orderTimelinePublicData := make([]interface{}, 0, len(orderTimelines))
+
+	for _, orderTimeline := range orderTimelines {
+		orderTimelinePublicData = append(orderTimelinePublicData, orderTimeline.PublicData())
+	}
+
+	// Build response data
+	data := make(map[string]interface{})
+	data["data"] = orderTimelinePublicData
 
-	_, err = d.OrderApp.UpdateOrderByID(order.ID, order)
+	// Send the order timeline as a response.
+	response.SendOK(ctx, data, "")
+}
+
+func (o *Orders) getReturnWindow() (time.Duration, error) {
+	returnWindowHoursStr, err := o.SettingApp.GetSettingByKey("return_window_hours")
 	if err != nil {
-		response.SendInternalServerError(c, err.Error())
-		return
+		return 0, err
+	}
+	returnWindowHours, err := strconv.ParseInt(returnWindowHoursStr, 10, 64)
+	if err != nil {
+		return 0, err
 	}
+	return time.Duration(returnWindowHours) * time.Hour, nil
+}
 
-	response.SendOK(c, createdOrder.PublicData(language.GetLanguage(c)), "")
+func (o *Orders) getPreferredDriverWindow() (time.Duration, error) {
+	preferredDriverMinutesStr, err := o.SettingApp.GetSettingByKey("preferred_driver_minutes")
+	if err != nil {
+		return 0, err
+	}
+	preferredDriverMinutes, err := strconv.ParseInt(preferredDriverMinutesStr, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(preferredDriverMinutes) * time.Minute, nil
 }
 
 func (o *Orders) getMaxOrdersPerTrip() (int64, error) {
@@ -1141,3 +1838,46 @@ func (o *Orders) getMaxOrdersPerTrip() (int64, error) {
 	}
 	return maxOrdersPerTrip, nil
 }
+
+func (o *Orders) getScheduledOrderRelease() (time.Duration, error) {
+	releaseMinutesStr, err := o.SettingApp.GetSettingByKey("scheduled_order_release_minutes")
+	if err != nil {
+		return 0, err
+	}
+	releaseMinutes, err := strconv.ParseInt(releaseMinutesStr, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(releaseMinutes) * time.Minute, nil
+}
+
+func (o *Orders) getFloatSetting(key string) (float64, error) {
+	valueStr, err := o.SettingApp.GetSettingByKey(key)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(valueStr, 64)
+}
+
+// emitOrderEvent writes an order event to the outbox in the transaction of the change it describes
+func emitOrderEvent(tx repository.Transaction, eventType entity.EventType, order *entity.Order, driverUserID uint64) error {
+	event, err := entity.NewOrderEvent(eventType, order, driverUserID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.OutboxEvent().CreateOutboxEvent(event)
+	return err
+}
+
+// emitOfferEvent records an event about an offer made on an order of the given sender in the
+// outbox of the transaction
+func emitOfferEvent(tx repository.Transaction, eventType entity.EventType, offer *entity.Offer, userID uint64) error {
+	event, err := entity.NewOfferEvent(eventType, offer, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.OutboxEvent().CreateOutboxEvent(event)
+	return err
+}
@@ -0,0 +1,524 @@
+package interfaces
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+	"github.com/OmarBader7/web-service-jayeek/pkg/language"
+	"github.com/OmarBader7/web-service-jayeek/pkg/pagination"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// planOrderLegs splits an intercity order into a first mile to the hub of its pickup city, a line
+// haul to the hub of its destination city and a last mile to the recipient. The hubs closest to
+// the pickup point and the destination are used. A requestError is returned when the order
+// can't be carried hub to hub.
+func (d *Orders) planOrderLegs(order *entity.Order) error {
+	order.Legs = nil
+	if !order.IsIntercity {
+		return nil
+	}
+
+	if len(order.DropOffs) > 0 {
+		return newRequestError("Intercity orders can't have drop-offs.")
+	}
+
+	// Promotions are redeemed with the amount of a single driver's offer
+	if order.PromotionID != 0 {
+		return newRequestError("Promo codes can't be used on intercity orders.")
+	}
+
+	if order.CityID == 0 || order.DestinationCityID == 0 || order.CityID == order.DestinationCityID {
+		return newRequestError("Intercity orders must be delivered to another city.")
+	}
+
+	originHub, err := d.HubApp.GetNearestActiveHubByCityID(order.CityID, order.Latitude, order.Longitude)
+	if err != nil {
+		return newRequestError("There is no hub in the pickup city.")
+	}
+
+	destinationHub, err := d.HubApp.GetNearestActiveHubByCityID(order.DestinationCityID, order.DestinationLatitude, order.DestinationLongitude)
+	if err != nil {
+		return newRequestError("There is no hub in the destination city.")
+	}
+
+	order.Legs = []entity.OrderLeg{
+		{
+			Sequence:      1,
+			Type:          entity.FirstMileOrderLegType,
+			CityID:        order.CityID,
+			ToHubID:       originHub.ID,
+			FromLatitude:  order.Latitude,
+			FromLongitude: order.Longitude,
+			ToLatitude:    originHub.Latitude,
+			ToLongitude:   originHub.Longitude,
+			Status:        entity.OrderLegPendingStatus,
+		},
+		{
+			Sequence:      2,
+			Type:          entity.LineHaulOrderLegType,
+			CityID:        order.CityID,
+			FromHubID:     originHub.ID,
+			ToHubID:       destinationHub.ID,
+			FromLatitude:  originHub.Latitude,
+			FromLongitude: originHub.Longitude,
+			ToLatitude:    destinationHub.Latitude,
+			ToLongitude:   destinationHub.Longitude,
+			Status:        entity.OrderLegPendingStatus,
+		},
+		{
+			Sequence:      3,
+			Type:          entity.LastMileOrderLegType,
+			CityID:        order.DestinationCityID,
+			FromHubID:     destinationHub.ID,
+			FromLatitude:  destinationHub.Latitude,
+			FromLongitude: destinationHub.Longitude,
+			ToLatitude:    order.DestinationLatitude,
+			ToLongitude:   order.DestinationLongitude,
+			Status:        entity.OrderLegPendingStatus,
+		},
+	}
+
+	return nil
+}
+
+// updateOrderFromLegs writes the changes of an intercity order along with the status derived from
+// its legs and the sum of the amounts of the accepted ones, read back in the transaction. The order
+// is only updated from the status it was read in, repository.ErrConflict is returned otherwise.
+func updateOrderFromLegs(tx repository.Transaction, order *entity.Order, update *entity.Order) error {
+	legs, err := tx.OrderLeg().GetAllOrderLegsByOrderID(order.ID)
+	if err != nil {
+		return err
+	}
+
+	var amount float64
+	for _, leg := range legs {
+		if leg.Amount != nil && leg.Status != entity.OrderLegCanceledStatus {
+			amount += *leg.Amount
+		}
+	}
+
+	update.Status = entity.OrderStatusFromLegs(legs)
+	update.Amount = &amount
+
+	_, err = tx.Order().UpdateOrderByIDAndStatus(order.ID, order.Status, update)
+	return err
+}
+
+// GetOrderLegsByID retrieves the legs of an intercity order of the sender or the recipient, along
+// with their hubs and drivers.
+func (o *Orders) GetOrderLegsByID(ctx *gin.Context) {
+	// Extract the token metadata from the request
+	metadata, err := o.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := o.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Get the user from the user application service
+	user, err := o.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Parse the order ID from the URL parameter.
+	orderID, err := strconv.ParseUint(ctx.Param("order_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid order ID."))
+		return
+	}
+
+	// Get the order from the order application service.
+	order, err := o.OrderApp.GetOrderByIDAndUserID(orderID, user.ID)
+	if err != nil {
+		order, err = o.OrderApp.GetOrderByIDAndRecipientID(orderID, user.ID)
+	}
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Order not found."))
+		return
+	}
+
+	legs, err := o.OrderLegApp.GetAllOrderLegsByOrderID(order.ID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if len(legs) == 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No order legs found."))
+		return
+	}
+
+	var legPublicData []interface{}
+	for _, leg := range legs {
+		legPublicData = append(legPublicData, leg.PublicData(language.GetLanguage(ctx)))
+	}
+
+	response.SendOK(ctx, legPublicData, "")
+}
+
+// GetAllAvailableOrderLegs retrieves a paginated list of the legs waiting for a driver that start
+// in the cities and regions the authenticated driver covers.
+func (o *Orders) GetAllAvailableOrderLegs(ctx *gin.Context) {
+	page := pagination.GetPage(ctx)
+	perPage := 30
+
+	driver, ok := o.authenticateDriver(ctx)
+	if !ok {
+		return
+	}
+
+	count, err := o.OrderLegApp.CountAvailableOrderLegsByDriverID(driver.ID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	legs, err := o.OrderLegApp.GetAllAvailableOrderLegsByDriverID(driver.ID, page, perPage)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	o.sendOrderLegs(ctx, legs, count, page, perPage)
+}
+
+// GetAllOrderLegs retrieves a paginated list of the legs the authenticated driver still has to carry.
+func (o *Orders) GetAllOrderLegs(ctx *gin.Context) {
+	page := pagination.GetPage(ctx)
+	perPage := 30
+
+	driver, ok := o.authenticateDriver(ctx)
+	if !ok {
+		return
+	}
+
+	statusesToExclude := []entity.OrderLegStatus{
+		entity.OrderLegCompletedStatus,
+		entity.OrderLegCanceledStatus,
+	}
+
+	count, err := o.OrderLegApp.CountOrderLegsByDriverIDExcludingStatus(driver.ID, statusesToExclude)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	legs, err := o.OrderLegApp.GetAllOrderLegsByDriverIDExcludingStatus(driver.ID, statusesToExclude, page, perPage)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	o.sendOrderLegs(ctx, legs, count, page, perPage)
+}
+
+// sendOrderLegs sends a page of legs as a response
+func (o *Orders) sendOrderLegs(ctx *gin.Context, legs []entity.OrderLeg, count int64, page int, perPage int) {
+	if page <= 1 && len(legs) == 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No order legs found."))
+		return
+	}
+
+	if page <= 0 || (len(legs) == 0 && page*perPage > int(count)) {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Page not found."))
+		return
+	}
+
+	var legPublicData []interface{}
+	for _, leg := range legs {
+		legPublicData = append(legPublicData, leg.PublicData(language.GetLanguage(ctx)))
+	}
+
+	data := make(map[string]interface{})
+	data["data"] = legPublicData
+	data["current_page"] = page
+	if page*perPage < int(count) {
+		data["next_page"] = page + 1
+	}
+	data["total"] = count
+
+	response.SendOK(ctx, data, "")
+}
+
+// CreateOrderLegOffer sends the sender an offer of the authenticated driver to carry a leg of an
+// intercity order. The sender accepts it like the offers on the other orders.
+func (o *Orders) CreateOrderLegOffer(ctx *gin.Context) {
+	var offer entity.Offer
+
+	// Bind the JSON body of the request to the Offer struct
+	if err := ctx.ShouldBindJSON(&offer); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	driver, ok := o.authenticateDriver(ctx)
+	if !ok {
+		return
+	}
+
+	// Validate all fields of the offer struct except for DriverID, OrderID, Status, Driver, and Order
+	validationErrors, _ := validator.ValidateExcept(ctx, &offer, "DriverID", "OrderID", "Status", "Driver", "Order")
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	// Parse the leg ID from the URL parameter.
+	legID, err := strconv.ParseUint(ctx.Param("leg_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid order leg ID."))
+		return
+	}
+
+	leg, err := o.OrderLegApp.GetAvailableOrderLegByIDAndDriverID(legID, driver.ID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Order leg not found."))
+		return
+	}
+
+	order, err := o.OrderApp.GetOrderByID(leg.OrderID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Order not found."))
+		return
+	}
+
+	// The driver's row is locked while their offers on the leg are counted, so two requests of the
+	// same driver can't both make one
+	err = o.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		if err := tx.Driver().LockDriverByID(driver.ID); err != nil {
+			return err
+		}
+
+		offersCount, err := tx.Offer().CountOffersByDriverIDAndOrderLegID(driver.ID, leg.ID)
+		if err != nil {
+			return err
+		}
+		if offersCount > 0 {
+			return repository.ErrConflict
+		}
+
+		offer.ID = 0
+		offer.DriverID = driver.ID
+		offer.OrderID = order.ID
+		offer.OrderLegID = leg.ID
+		offer.Status = entity.OfferStatusPending
+
+		// Create the new offer
+		if _, err := tx.Offer().CreateOffer(&offer); err != nil {
+			return err
+		}
+
+		return emitOfferEvent(tx, entity.OfferCreatedEvent, &offer, order.UserID)
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		response.SendConflict(ctx, ginI18n.MustGetMessage("You already sent an offer for this leg."))
+		return
+	}
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, leg.PublicData(language.GetLanguage(ctx)), "")
+}
+
+// PickupOrderLegByID marks a leg of the authenticated driver as picked up. The parcel must have
+// been brought to the start of the leg by the previous one, the driver becomes the driver of the
+// order until the next leg picks it up.
+func (o *Orders) PickupOrderLegByID(ctx *gin.Context) {
+	driver, leg, order, ok := o.findDriverOrderLeg(ctx)
+	if !ok {
+		return
+	}
+
+	if leg.Status != entity.OrderLegAcceptedStatus {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("This leg can't be picked up."))
+		return
+	}
+
+	legs, err := o.OrderLegApp.GetAllOrderLegsByOrderID(order.ID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	for _, previousLeg := range legs {
+		if previousLeg.Sequence < leg.Sequence && previousLeg.Status != entity.OrderLegCompletedStatus {
+			response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("The parcel hasn't reached the start of this leg yet."))
+			return
+		}
+	}
+
+	pickedUpAt := time.Now()
+	leg.Status = entity.OrderLegPickedUpStatus
+	leg.PickedUpAt = &pickedUpAt
+
+	err = o.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		if _, err := tx.OrderLeg().UpdateOrderLegByIDAndStatus(leg.ID, entity.OrderLegAcceptedStatus, &entity.OrderLeg{Status: leg.Status, PickedUpAt: leg.PickedUpAt}); err != nil {
+			return err
+		}
+
+		return updateOrderFromLegs(tx, order, &entity.Order{DriverID: driver.ID})
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		response.SendConflict(ctx, ginI18n.MustGetMessage("The order leg was changed meanwhile."))
+		return
+	}
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, leg.PublicData(language.GetLanguage(ctx)), "")
+}
+
+// CompleteOrderLegByID marks a leg of the authenticated driver as completed, once the parcel was
+// handed over at the hub it ends at or delivered to the recipient for the last mile. The driver's
+// balance is credited for the leg.
+func (o *Orders) CompleteOrderLegByID(ctx *gin.Context) {
+	driver, leg, order, ok := o.findDriverOrderLeg(ctx)
+	if !ok {
+		return
+	}
+
+	if leg.Status != entity.OrderLegPickedUpStatus {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("This leg can't be completed."))
+		return
+	}
+
+	completedAt := time.Now()
+	leg.Status = entity.OrderLegCompletedStatus
+	leg.CompletedAt = &completedAt
+
+	// The last mile delivers the order
+	delivered := leg.Type == entity.LastMileOrderLegType
+
+	err := o.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		if _, err := tx.OrderLeg().UpdateOrderLegByIDAndStatus(leg.ID, entity.OrderLegPickedUpStatus, &entity.OrderLeg{Status: leg.Status, CompletedAt: leg.CompletedAt}); err != nil {
+			return err
+		}
+
+		update := entity.Order{}
+		if delivered {
+			update.DeliveredAt = &completedAt
+		}
+
+		if err := updateOrderFromLegs(tx, order, &update); err != nil {
+			return err
+		}
+
+		balance := entity.Balance{OrderID: order.ID, DriverID: driver.ID}
+		if _, err := tx.Balance().CreateBalance(&balance); err != nil {
+			return err
+		}
+
+		if !delivered {
+			return nil
+		}
+
+		order.Status = update.Status
+		order.DeliveredAt = update.DeliveredAt
+		order.Amount = update.Amount
+
+		return emitOrderEvent(tx, entity.OrderDeliveredEvent, order, driver.UserID)
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		response.SendConflict(ctx, ginI18n.MustGetMessage("The order leg was changed meanwhile."))
+		return
+	}
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	// The invoice is issued with the delivery, it's generated again on request if this fails
+	if delivered {
+		if deliveredOrder, err := o.OrderApp.GetOrderByID(order.ID); err != nil {
+			log.Println("Error issuing invoice: ", err)
+		} else if _, err := o.issueInvoice(deliveredOrder); err != nil {
+			log.Println("Error issuing invoice: ", err)
+		}
+	}
+
+	response.SendOK(ctx, leg.PublicData(language.GetLanguage(ctx)), "")
+}
+
+// findDriverOrderLeg finds the leg given in the URL parameters among the ones of the authenticated
+// driver, along with its order. It sends an error response and returns false when it can't be found.
+func (o *Orders) findDriverOrderLeg(ctx *gin.Context) (*entity.Driver, *entity.OrderLeg, *entity.Order, bool) {
+	driver, ok := o.authenticateDriver(ctx)
+	if !ok {
+		return nil, nil, nil, false
+	}
+
+	// Parse the leg ID from the URL parameter.
+	legID, err := strconv.ParseUint(ctx.Param("leg_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid order leg ID."))
+		return nil, nil, nil, false
+	}
+
+	leg, err := o.OrderLegApp.GetOrderLegByIDAndDriverID(legID, driver.ID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Order leg not found."))
+		return nil, nil, nil, false
+	}
+
+	order, err := o.OrderApp.GetOrderByID(leg.OrderID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Order not found."))
+		return nil, nil, nil, false
+	}
+
+	return driver, leg, order, true
+}
+
+// authenticateDriver returns the driver of the authenticated user. It sends an error response and
+// returns false when the user isn't a driver.
+func (o *Orders) authenticateDriver(ctx *gin.Context) (*entity.Driver, bool) {
+	// Extract the token metadata from the request
+	metadata, err := o.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := o.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Get the user from the user application service
+	user, err := o.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Get the driver from the driver application service
+	driver, err := o.DriverApp.GetDriverByUserID(user.ID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Driver not found."))
+		return nil, false
+	}
+
+	return driver, true
+}
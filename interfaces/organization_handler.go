@@ -0,0 +1,586 @@
+package interfaces
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/pkg/language"
+	"github.com/OmarBader7/web-service-jayeek/pkg/pagination"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Organizations struct {
+	AuthService     auth.AuthServiceInterface
+	TokenService    auth.TokenInterface
+	UserApp         application.UserApplicationInterface
+	OrganizationApp application.OrganizationApplicationInterface
+	APIKeyApp       application.APIKeyApplicationInterface
+	OrderApp        application.OrderApplicationInterface
+}
+
+// NewOrganizations creates and returns a new instance of Organizations.
+func NewOrganizations(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, userApp application.UserApplicationInterface, organizationApp application.OrganizationApplicationInterface, apiKeyApp application.APIKeyApplicationInterface, orderApp application.OrderApplicationInterface) *Organizations {
+	return &Organizations{
+		AuthService:     authService,
+		TokenService:    tokenService,
+		UserApp:         userApp,
+		OrganizationApp: organizationApp,
+		APIKeyApp:       apiKeyApp,
+		OrderApp:        orderApp,
+	}
+}
+
+// CreateOrganization creates a business organization owned by the authenticated user
+func (o *Organizations) CreateOrganization(ctx *gin.Context) {
+	var organization entity.Organization
+
+	if err := ctx.ShouldBindJSON(&organization); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	user, _, ok := o.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &organization)
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	organization.ID = 0
+
+	if _, err := o.OrganizationApp.CreateOrganization(&organization, user.ID); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendCreated(ctx, organization.PublicData(), "")
+}
+
+// GetAllOrganizations retrieves the organizations the authenticated user is a member of
+func (o *Organizations) GetAllOrganizations(ctx *gin.Context) {
+	user, _, ok := o.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	organizations, err := o.OrganizationApp.GetAllOrganizationsByUserID(user.ID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if len(organizations) <= 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No organizations found."))
+		return
+	}
+
+	var organizationPublicData []interface{}
+	for _, organization := range organizations {
+		organizationPublicData = append(organizationPublicData, organization.PublicData())
+	}
+
+	response.SendOK(ctx, organizationPublicData, "")
+}
+
+// GetOrganizationByID retrieves an organization of the authenticated user along with their role
+func (o *Organizations) GetOrganizationByID(ctx *gin.Context) {
+	membership, ok := o.getMembership(ctx)
+	if !ok {
+		return
+	}
+
+	// Prepare the response data.
+	data := make(map[string]interface{})
+	data["organization"] = membership.Organization.PublicData()
+	data["role"] = membership.Role
+
+	response.SendOK(ctx, data, "")
+}
+
+// UpdateOrganizationByID updates the details of an organization. Only its owners can update it.
+func (o *Organizations) UpdateOrganizationByID(ctx *gin.Context) {
+	var input entity.Organization
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	membership, ok := o.getMembership(ctx)
+	if !ok {
+		return
+	}
+
+	if membership.Role != entity.OrganizationOwnerRole {
+		response.SendForbidden(ctx, ginI18n.MustGetMessage("Forbidden"))
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &input)
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	organization := membership.Organization
+	organization.Name = input.Name
+	organization.CommercialRegistration = input.CommercialRegistration
+	organization.VATNumber = input.VATNumber
+	organization.UpdatedAt = time.Now()
+
+	if _, err := o.OrganizationApp.UpdateOrganizationByID(organization.ID, &organization); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, organization.PublicData(), "")
+}
+
+// GetAllOrganizationMembers retrieves the members of an organization of the authenticated user
+func (o *Organizations) GetAllOrganizationMembers(ctx *gin.Context) {
+	membership, ok := o.getMembership(ctx)
+	if !ok {
+		return
+	}
+
+	organizationMembers, err := o.OrganizationApp.GetAllOrganizationMembersByOrganizationID(membership.OrganizationID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	var organizationMemberPublicData []interface{}
+	for _, organizationMember := range organizationMembers {
+		organizationMemberPublicData = append(organizationMemberPublicData, organizationMember.PublicData(language.GetLanguage(ctx)))
+	}
+
+	response.SendOK(ctx, organizationMemberPublicData, "")
+}
+
+// AddOrganizationMember adds a registered user to an organization by their phone number. Owners
+// and admins add members, only owners add other owners.
+func (o *Organizations) AddOrganizationMember(ctx *gin.Context) {
+	var input struct {
+		Phone string                  `json:"phone" validate:"required,e164"`
+		Role  entity.OrganizationRole `json:"role" validate:"required,oneof=owner admin member"`
+	}
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	membership, ok := o.getMembership(ctx)
+	if !ok {
+		return
+	}
+
+	if !membership.CanManage() || (input.Role == entity.OrganizationOwnerRole && membership.Role != entity.OrganizationOwnerRole) {
+		response.SendForbidden(ctx, ginI18n.MustGetMessage("Forbidden"))
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &input)
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	user, err := o.UserApp.GetUserByPhone(input.Phone)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("User not found."))
+		return
+	}
+
+	if _, err := o.OrganizationApp.GetOrganizationMemberByOrganizationIDAndUserID(membership.OrganizationID, user.ID); err == nil {
+		response.SendConflict(ctx, ginI18n.MustGetMessage("The user is already a member of the organization."))
+		return
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	organizationMember := entity.OrganizationMember{
+		OrganizationID: membership.OrganizationID,
+		UserID:         user.ID,
+		Role:           input.Role,
+	}
+
+	if _, err := o.OrganizationApp.CreateOrganizationMember(&organizationMember); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	organizationMember.User = *user
+
+	response.SendCreated(ctx, organizationMember.PublicData(language.GetLanguage(ctx)), "")
+}
+
+// UpdateOrganizationMemberByID changes the role of a member of an organization
+func (o *Organizations) UpdateOrganizationMemberByID(ctx *gin.Context) {
+	var input struct {
+		Role entity.OrganizationRole `json:"role" validate:"required,oneof=owner admin member"`
+	}
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	membership, ok := o.getMembership(ctx)
+	if !ok {
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &input)
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	organizationMember, ok := o.getMember(ctx, membership)
+	if !ok {
+		return
+	}
+
+	if input.Role == entity.OrganizationOwnerRole && membership.Role != entity.OrganizationOwnerRole {
+		response.SendForbidden(ctx, ginI18n.MustGetMessage("Forbidden"))
+		return
+	}
+
+	if organizationMember.Role == entity.OrganizationOwnerRole && input.Role != entity.OrganizationOwnerRole && !o.hasOtherOwner(ctx, membership.OrganizationID) {
+		return
+	}
+
+	organizationMember.Role = input.Role
+
+	if _, err := o.OrganizationApp.UpdateOrganizationMemberByID(organizationMember.ID, organizationMember); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, organizationMember.PublicData(language.GetLanguage(ctx)), "")
+}
+
+// RemoveOrganizationMemberByID removes a member from an organization and revokes the API keys
+// they created for it
+func (o *Organizations) RemoveOrganizationMemberByID(ctx *gin.Context) {
+	membership, ok := o.getMembership(ctx)
+	if !ok {
+		return
+	}
+
+	organizationMember, ok := o.getMember(ctx, membership)
+	if !ok {
+		return
+	}
+
+	if organizationMember.Role == entity.OrganizationOwnerRole && !o.hasOtherOwner(ctx, membership.OrganizationID) {
+		return
+	}
+
+	if err := o.APIKeyApp.RevokeAPIKeysByOrganizationIDAndUserID(membership.OrganizationID, organizationMember.UserID, time.Now()); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if err := o.OrganizationApp.DeleteOrganizationMemberByID(organizationMember.ID); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, nil, ginI18n.MustGetMessage("Member removed successfully."))
+}
+
+// GetAllAPIKeys retrieves the API keys of an organization
+func (o *Organizations) GetAllAPIKeys(ctx *gin.Context) {
+	membership, ok := o.getMembership(ctx)
+	if !ok {
+		return
+	}
+
+	if !membership.CanManage() {
+		response.SendForbidden(ctx, ginI18n.MustGetMessage("Forbidden"))
+		return
+	}
+
+	apiKeys, err := o.APIKeyApp.GetAllAPIKeysByOrganizationID(membership.OrganizationID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if len(apiKeys) <= 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No API keys found."))
+		return
+	}
+
+	var apiKeyPublicData []interface{}
+	for _, apiKey := range apiKeys {
+		apiKeyPublicData = append(apiKeyPublicData, apiKey.PublicData())
+	}
+
+	response.SendOK(ctx, apiKeyPublicData, "")
+}
+
+// CreateAPIKey creates an API key acting as the authenticated member. The key is only returned here.
+func (o *Organizations) CreateAPIKey(ctx *gin.Context) {
+	var input struct {
+		Name string `json:"name" validate:"required,max=255"`
+	}
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	membership, ok := o.getMembership(ctx)
+	if !ok {
+		return
+	}
+
+	if !membership.CanManage() {
+		response.SendForbidden(ctx, ginI18n.MustGetMessage("Forbidden"))
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &input)
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	key, prefix, keyHash, err := auth.GenerateAPIKey()
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	apiKey := entity.APIKey{
+		OrganizationID: membership.OrganizationID,
+		UserID:         membership.UserID,
+		Name:           input.Name,
+		Prefix:         prefix,
+		KeyHash:        keyHash,
+	}
+
+	if _, err := o.APIKeyApp.CreateAPIKey(&apiKey); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	// Prepare the response data.
+	data := make(map[string]interface{})
+	data["api_key"] = apiKey.PublicData()
+	data["key"] = key
+
+	response.SendCreated(ctx, data, "")
+}
+
+// RevokeAPIKeyByID revokes an API key of an organization
+func (o *Organizations) RevokeAPIKeyByID(ctx *gin.Context) {
+	membership, ok := o.getMembership(ctx)
+	if !ok {
+		return
+	}
+
+	if !membership.CanManage() {
+		response.SendForbidden(ctx, ginI18n.MustGetMessage("Forbidden"))
+		return
+	}
+
+	// Parse the API key ID from the URL parameter.
+	apiKeyID, err := strconv.ParseUint(ctx.Param("api_key_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid API key ID."))
+		return
+	}
+
+	if _, err := o.APIKeyApp.GetAPIKeyByIDAndOrganizationID(apiKeyID, membership.OrganizationID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.SendNotFound(ctx, ginI18n.MustGetMessage("API key not found."))
+			return
+		}
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if err := o.APIKeyApp.RevokeAPIKeyByID(apiKeyID, time.Now()); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, nil, ginI18n.MustGetMessage("API key revoked successfully."))
+}
+
+// GetAllOrganizationOrders retrieves a paginated list of the orders created for an organization
+func (o *Organizations) GetAllOrganizationOrders(ctx *gin.Context) {
+	membership, ok := o.getMembership(ctx)
+	if !ok {
+		return
+	}
+
+	// Get the desired page number from the query parameters.
+	page := pagination.GetPage(ctx)
+
+	// Set the number of items per page.
+	perPage := 30
+
+	count, err := o.OrderApp.CountOrdersByOrganizationID(membership.OrganizationID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	orders, err := o.OrderApp.GetAllOrdersByOrganizationID(membership.OrganizationID, page, perPage)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if page == 1 && len(orders) <= 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No orders found."))
+		return
+	}
+
+	// Check if the page is valid
+	if page <= 0 || (len(orders) <= 0 && page*perPage > int(count)) {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Page not found."))
+		return
+	}
+
+	var orderPublicData []interface{}
+	for _, order := range orders {
+		orderPublicData = append(orderPublicData, order.PublicData(language.GetLanguage(ctx)))
+	}
+
+	// Prepare the response data.
+	data := make(map[string]interface{})
+	data["data"] = orderPublicData
+	data["current_page"] = page
+	if page*perPage < int(count) {
+		data["next_page"] = page + 1
+	}
+	data["total"] = count
+
+	response.SendOK(ctx, data, "")
+}
+
+// getMembership returns the authenticated user's membership in the organization of the URL
+// parameter. A request made with an API key only reaches the key's organization.
+func (o *Organizations) getMembership(ctx *gin.Context) (*entity.OrganizationMember, bool) {
+	user, metadata, ok := o.authenticate(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	// Parse the organization ID from the URL parameter.
+	organizationID, err := strconv.ParseUint(ctx.Param("organization_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid organization ID."))
+		return nil, false
+	}
+
+	if metadata.OrganizationID != 0 && metadata.OrganizationID != organizationID {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Organization not found."))
+		return nil, false
+	}
+
+	membership, err := o.OrganizationApp.GetOrganizationMemberByOrganizationIDAndUserID(organizationID, user.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.SendNotFound(ctx, ginI18n.MustGetMessage("Organization not found."))
+			return nil, false
+		}
+		response.SendInternalServerError(ctx, err.Error())
+		return nil, false
+	}
+
+	return membership, true
+}
+
+// getMember returns the member of the URL parameter, if the authenticated member can manage them.
+// Admins can't manage the owners.
+func (o *Organizations) getMember(ctx *gin.Context, membership *entity.OrganizationMember) (*entity.OrganizationMember, bool) {
+	if !membership.CanManage() {
+		response.SendForbidden(ctx, ginI18n.MustGetMessage("Forbidden"))
+		return nil, false
+	}
+
+	// Parse the user ID from the URL parameter.
+	userID, err := strconv.ParseUint(ctx.Param("user_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid user ID."))
+		return nil, false
+	}
+
+	organizationMember, err := o.OrganizationApp.GetOrganizationMemberByOrganizationIDAndUserID(membership.OrganizationID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.SendNotFound(ctx, ginI18n.MustGetMessage("Member not found."))
+			return nil, false
+		}
+		response.SendInternalServerError(ctx, err.Error())
+		return nil, false
+	}
+
+	if organizationMember.Role == entity.OrganizationOwnerRole && membership.Role != entity.OrganizationOwnerRole {
+		response.SendForbidden(ctx, ginI18n.MustGetMessage("Forbidden"))
+		return nil, false
+	}
+
+	return organizationMember, true
+}
+
+// hasOtherOwner makes sure an organization keeps an owner when one of them is demoted or removed
+func (o *Organizations) hasOtherOwner(ctx *gin.Context, organizationID uint64) bool {
+	count, err := o.OrganizationApp.CountOrganizationMembersByOrganizationIDAndRole(organizationID, entity.OrganizationOwnerRole)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return false
+	}
+
+	if count <= 1 {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("An organization must keep at least one owner."))
+		return false
+	}
+
+	return true
+}
+
+func (o *Organizations) authenticate(ctx *gin.Context) (*entity.User, *auth.AccessDetails, bool) {
+	// Extract the token metadata from the request
+	metadata, err := o.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, nil, false
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := o.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, nil, false
+	}
+
+	// Get the user from the user application service
+	user, err := o.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, nil, false
+	}
+
+	return user, metadata, true
+}
@@ -0,0 +1,166 @@
+package interfaces
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// Promotions holds the promotion-related application interfaces
+type Promotions struct {
+	AuthService  auth.AuthServiceInterface
+	TokenService auth.TokenInterface
+	PromotionApp application.PromotionApplicationInterface
+	UserApp      application.UserApplicationInterface
+}
+
+// NewPromotions returns a new instance of Promotions
+func NewPromotions(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, promotionApp application.PromotionApplicationInterface, userApp application.UserApplicationInterface) *Promotions {
+	return &Promotions{
+		AuthService:  authService,
+		TokenService: tokenService,
+		PromotionApp: promotionApp,
+		UserApp:      userApp,
+	}
+}
+
+// GetAllPromotions retrieves all the promotions
+func (p *Promotions) GetAllPromotions(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, p.TokenService, p.AuthService, p.UserApp); !ok {
+		return
+	}
+
+	promotions, err := p.PromotionApp.GetAllPromotions()
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	var promotionPublicData []interface{}
+	for _, promotion := range promotions {
+		promotionPublicData = append(promotionPublicData, promotion.PublicData())
+	}
+
+	response.SendOK(ctx, promotionPublicData, "")
+}
+
+// CreatePromotion handles the creation of a new promotion
+func (p *Promotions) CreatePromotion(ctx *gin.Context) {
+	var promotion entity.Promotion
+
+	// Bind the JSON body of the request to the Promotion struct
+	if err := ctx.ShouldBindJSON(&promotion); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	if _, ok := requireAdmin(ctx, p.TokenService, p.AuthService, p.UserApp); !ok {
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &promotion)
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	if promotion.DiscountType == entity.PercentageDiscountType && promotion.DiscountValue > 100 {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Percentage discounts can't exceed 100."))
+		return
+	}
+
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.EndsAt.After(*promotion.StartsAt) {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Invalid promotion validity window."))
+		return
+	}
+
+	if _, err := p.PromotionApp.GetPromotionByCode(promotion.Code); err == nil {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("The promo code already exists."))
+		return
+	}
+
+	promotion.ID = 0
+	promotion.IsActive = true
+
+	createdPromotion, err := p.PromotionApp.CreatePromotion(&promotion)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, createdPromotion.PublicData(), "")
+}
+
+// DeactivatePromotionByID stops a promotion from being applied to new orders
+func (p *Promotions) DeactivatePromotionByID(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, p.TokenService, p.AuthService, p.UserApp); !ok {
+		return
+	}
+
+	// Parse the promotion ID from the URL parameter.
+	promotionID, err := strconv.ParseUint(ctx.Param("promotion_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid promotion ID."))
+		return
+	}
+
+	promotion, err := p.PromotionApp.GetPromotionByID(promotionID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Promotion not found."))
+		return
+	}
+
+	// Discounts already recorded against orders are kept
+	promotion.IsActive = false
+
+	updatedPromotion, err := p.PromotionApp.UpdatePromotionByID(promotion.ID, promotion)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, updatedPromotion.PublicData(), "")
+}
+
+// findPromotion looks up a promo code and makes sure the user can apply it to an order of the
+// category, returning a requestError otherwise.
+func findPromotion(promotionApp application.PromotionApplicationInterface, code string, userID uint64, categoryID uint64) (*entity.Promotion, error) {
+	promotion, err := promotionApp.GetPromotionByCode(code)
+	if err != nil {
+		return nil, newRequestError("Invalid promo code.")
+	}
+
+	if !promotion.IsValidAt(time.Now()) {
+		return nil, newRequestError("The promo code has expired.")
+	}
+
+	if !promotion.AllowsCategory(categoryID) {
+		return nil, newRequestError("The promo code doesn't apply to this category.")
+	}
+
+	// The limits are checked again when the promotion is redeemed, this spares the user an order
+	// that can't get the discount
+	if promotion.GlobalUsageLimit != nil && promotion.RedemptionsCount >= *promotion.GlobalUsageLimit {
+		return nil, newRequestError("The promo code has reached its usage limit.")
+	}
+
+	if promotion.PerUserUsageLimit != nil {
+		count, err := promotionApp.CountPromotionRedemptionsByPromotionIDAndUserID(promotion.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		if count >= *promotion.PerUserUsageLimit {
+			return nil, newRequestError("You have already used this promo code.")
+		}
+	}
+
+	return promotion, nil
+}
@@ -0,0 +1,273 @@
+package interfaces
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/pkg/pagination"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// CreateRecurringOrder handles the creation of a new recurring order template
+func (d *Orders) CreateRecurringOrder(c *gin.Context) {
+	var order entity.Order
+	var recurringOrder entity.RecurringOrder
+
+	// Bind the JSON body of the request to both the Order and the RecurringOrder structs
+	if err := c.ShouldBindBodyWith(&order, binding.JSON); err != nil {
+		response.SendBadRequest(c, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	if err := c.ShouldBindBodyWith(&recurringOrder, binding.JSON); err != nil {
+		response.SendBadRequest(c, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	// Extract the token metadata from the request
+	metadata, err := d.TokenService.ExtractTokenMetadata(c.Request)
+	if err != nil {
+		response.SendUnauthorized(c, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := d.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(c, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Get the user from the user application service
+	user, err := d.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(c, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Validate the order details the same way as a one-off order
+	validationErrors, _ := validator.ValidateExcept(c, &order, "UserID", "LocationID", "Amount", "Status", "Location", "User", "Driver", "Recipient", "Category", "Size", "TruckType", "TruckModel", "DeliveryTime", "Destination", "ShipmentContents", "ExtraServices")
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(c, validationErrors, "")
+		return
+	}
+
+	// Validate the schedule of the recurring order
+	validationErrors, _ = validator.ValidateExcept(c, &recurringOrder, "UserID", "LocationID", "CategoryID", "DeliveryTimeID", "DestinationID", "Quantity")
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(c, validationErrors, "")
+		return
+	}
+
+	if recurringOrder.Frequency == entity.WeeklyFrequency && recurringOrder.Weekday == nil {
+		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Weekly recurring orders require a weekday."))
+		return
+	}
+
+	if err := d.prepareOrder(&order); err != nil {
+		sendRequestError(c, err)
+		return
+	}
+
+	// Intercity templates must have hubs to go through, like their orders
+	order.PromotionID = 0
+	if err := d.planOrderLegs(&order); err != nil {
+		sendRequestError(c, err)
+		return
+	}
+
+	order.UserID = user.ID
+
+	if err := entity.NewRecurringOrderFromOrder(&order, &recurringOrder); err != nil {
+		response.SendInternalServerError(c, err.Error())
+		return
+	}
+
+	if recurringOrder.PickupWindowMinutes == 0 {
+		recurringOrder.PickupWindowMinutes = 60
+	}
+
+	if recurringOrder.Timezone == "" {
+		recurringOrder.Timezone = "Asia/Riyadh"
+	}
+
+	nextRunAt, err := recurringOrder.NextOccurrence(time.Now())
+	if err != nil {
+		response.SendUnprocessableEntity(c, nil, ginI18n.MustGetMessage("Invalid recurring order schedule."))
+		return
+	}
+
+	// The first pickup must be within the service areas and their operating hours
+	if err := d.checkCoverage(&order, nextRunAt); err != nil {
+		sendRequestError(c, err)
+		return
+	}
+
+	recurringOrder.ID = 0
+	recurringOrder.IsActive = true
+	recurringOrder.NextRunAt = nextRunAt
+	recurringOrder.LastRunAt = nil
+
+	createdRecurringOrder, err := d.RecurringOrderApp.CreateRecurringOrder(&recurringOrder)
+	if err != nil {
+		response.SendInternalServerError(c, err.Error())
+		return
+	}
+
+	response.SendOK(c, createdRecurringOrder.PublicData(), "")
+}
+
+// PrepareRecurringOrder prepares an order generated by the scheduler from a recurring order
+// template the same way as the orders placed by the senders. The pickup must still be covered at
+// its own time, and the route is estimated.
+func (d *Orders) PrepareRecurringOrder(order *entity.Order) error {
+	if err := d.prepareOrder(order); err != nil {
+		return err
+	}
+
+	if err := d.planOrderLegs(order); err != nil {
+		return err
+	}
+
+	pickupAt := time.Now()
+	if order.PickupWindowStart != nil {
+		pickupAt = *order.PickupWindowStart
+	}
+
+	if err := d.checkCoverage(order, pickupAt); err != nil {
+		return err
+	}
+
+	route, err := d.getOrderRoute(order)
+	if err != nil {
+		return err
+	}
+
+	order.RouteDistance = &route.Distance
+	order.RouteDuration = &route.Duration
+	order.RoutePolyline = &route.Polyline
+	return nil
+}
+
+// GetAllRecurringOrders retrieves a paginated list of the user's recurring orders.
+func (o *Orders) GetAllRecurringOrders(ctx *gin.Context) {
+	// Get the desired page number from the query parameters.
+	page := pagination.GetPage(ctx)
+
+	// Set the number of items per page.
+	perPage := 30
+
+	// Extract the token metadata from the request
+	metadata, err := o.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := o.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Get the user from the user application service
+	user, err := o.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	count, err := o.RecurringOrderApp.CountRecurringOrdersByUserID(user.ID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	recurringOrders, err := o.RecurringOrderApp.GetAllRecurringOrdersByUserID(user.ID, page, perPage)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if page <= 1 && len(recurringOrders) <= 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No recurring orders found."))
+		return
+	}
+
+	// Check if the page is valid
+	if page <= 0 || (len(recurringOrders) <= 0 && page*perPage > int(count)) {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Page not found."))
+		return
+	}
+
+	var recurringOrderPublicData []interface{}
+
+	for _, recurringOrder := range recurringOrders {
+		recurringOrderPublicData = append(recurringOrderPublicData, recurringOrder.PublicData())
+	}
+
+	// Build response data
+	data := make(map[string]interface{})
+	data["data"] = recurringOrderPublicData
+	data["current_page"] = page
+	if page*perPage < int(count) {
+		data["next_page"] = page + 1
+	}
+	data["total"] = count
+
+	response.SendOK(ctx, data, "")
+}
+
+// CancelRecurringOrderByID stops a recurring order from generating new orders.
+func (o *Orders) CancelRecurringOrderByID(ctx *gin.Context) {
+	// Extract the token metadata from the request
+	metadata, err := o.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := o.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Get the user from the user application service
+	user, err := o.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Parse the recurring order ID from the URL parameter.
+	recurringOrderID, err := strconv.ParseUint(ctx.Param("recurring_order_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid recurring order ID."))
+		return
+	}
+
+	recurringOrder, err := o.RecurringOrderApp.GetRecurringOrderByIDAndUserID(recurringOrderID, user.ID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Recurring order not found."))
+		return
+	}
+
+	// Orders already generated by the template are kept and can be canceled individually
+	recurringOrder.IsActive = false
+
+	updatedRecurringOrder, err := o.RecurringOrderApp.UpdateRecurringOrderByID(recurringOrder.ID, recurringOrder)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, updatedRecurringOrder.PublicData(), "")
+}
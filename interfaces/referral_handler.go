@@ -0,0 +1,73 @@
+package interfaces
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// Referrals holds the referral-related application interfaces
+type Referrals struct {
+	AuthService  auth.AuthServiceInterface
+	TokenService auth.TokenInterface
+	UserApp      application.UserApplicationInterface
+	CreditApp    application.CreditApplicationInterface
+}
+
+// NewReferrals returns a new instance of Referrals
+func NewReferrals(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, userApp application.UserApplicationInterface, creditApp application.CreditApplicationInterface) *Referrals {
+	return &Referrals{
+		AuthService:  authService,
+		TokenService: tokenService,
+		UserApp:      userApp,
+		CreditApp:    creditApp,
+	}
+}
+
+// GetReferral retrieves the user's referral code and the credit they have left to spend
+func (r *Referrals) GetReferral(ctx *gin.Context) {
+	// Extract the token metadata from the request
+	metadata, err := r.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := r.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Get the user from the user application service
+	user, err := r.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Users registered before referrals existed get their code on first use
+	if user.ReferralCode == nil {
+		referralCode, err := r.UserApp.AssignUserReferralCodeByID(user.ID)
+		if err != nil {
+			response.SendInternalServerError(ctx, err.Error())
+			return
+		}
+		user.ReferralCode = &referralCode
+	}
+
+	credit, err := r.CreditApp.SumCreditsByUserID(user.ID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	data := make(map[string]interface{})
+	data["referral_code"] = *user.ReferralCode
+	data["credit"] = credit
+
+	response.SendOK(ctx, data, "")
+}
@@ -0,0 +1,104 @@
+package interfaces
+
+import (
+	"strconv"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/pkg/pagination"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// Regions holds the region-related application interfaces
+type Regions struct {
+	AuthService  auth.AuthServiceInterface
+	TokenService auth.TokenInterface
+	RegionApp    application.RegionApplicationInterface
+}
+
+// NewRegions returns a new instance of Regions
+func NewRegions(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, regionApp application.RegionApplicationInterface) *Regions {
+	return &Regions{
+		AuthService:  authService,
+		TokenService: tokenService,
+		RegionApp:    regionApp,
+	}
+}
+
+// GetAllRegions retrieves a paginated list of all regions.
+func (r *Regions) GetAllRegions(ctx *gin.Context) {
+	page := pagination.GetPage(ctx)
+	perPage := 30
+
+	count, err := r.RegionApp.CountRegions()
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	regions, err := r.RegionApp.GetAllRegions(page, perPage)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if page <= 1 && len(regions) == 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No regions found."))
+		return
+	}
+
+	if page <= 0 || (len(regions) == 0 && page*perPage > int(count)) {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Page not found."))
+		return
+	}
+
+	var regionPublicData []interface{}
+	for _, region := range regions {
+		regionPublicData = append(regionPublicData, region.PublicData())
+	}
+
+	data := make(map[string]interface{})
+	data["data"] = regionPublicData
+	data["current_page"] = page
+	if page*perPage < int(count) {
+		data["next_page"] = page + 1
+	}
+	data["total"] = count
+
+	response.SendOK(ctx, data, "")
+}
+
+// GetRegionByID retrieves a single region by ID.
+func (r *Regions) GetRegionByID(ctx *gin.Context) {
+	regionID, err := strconv.ParseUint(ctx.Param("region_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid region ID."))
+		return
+	}
+
+	region, err := r.RegionApp.GetRegionByID(regionID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Region not found."))
+		return
+	}
+
+	response.SendOK(ctx, region.PublicData(), "")
+}
+
+// getQueryID parses the optional ID a listing is filtered by, 0 when the query parameter isn't set.
+// It sends the given message and returns false when the ID is invalid.
+func getQueryID(ctx *gin.Context, key string, invalidMessage string) (uint64, bool) {
+	if ctx.Query(key) == "" {
+		return 0, true
+	}
+
+	id, err := strconv.ParseUint(ctx.Query(key), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage(invalidMessage))
+		return 0, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,37 @@
+package interfaces
+
+import (
+	"errors"
+
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// requestError is an error the client can correct, such as an invalid field or a reference to a
+// record that doesn't exist. It's sent as 422 Unprocessable Entity with its validation errors.
+type requestError struct {
+	message string
+	errors  interface{}
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+// newRequestError returns a requestError with the translation of the message
+func newRequestError(message string) error {
+	return &requestError{message: ginI18n.MustGetMessage(message)}
+}
+
+// sendRequestError sends the response matching an error of a handler helper, 422 for the errors
+// of the request and 500 for the others
+func sendRequestError(ctx *gin.Context, err error) {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		response.SendUnprocessableEntity(ctx, reqErr.errors, reqErr.message)
+		return
+	}
+
+	response.SendInternalServerError(ctx, err.Error())
+}
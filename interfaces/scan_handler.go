@@ -0,0 +1,160 @@
+package interfaces
+
+import (
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// Scans holds the application interfaces used to record the scans of the parcel labels
+type Scans struct {
+	AuthService  auth.AuthServiceInterface
+	TokenService auth.TokenInterface
+	ScanApp      application.ScanApplicationInterface
+	OrderApp     application.OrderApplicationInterface
+	HubApp       application.HubApplicationInterface
+	DriverApp    application.DriverApplicationInterface
+	UserApp      application.UserApplicationInterface
+}
+
+// NewScans returns a new instance of Scans
+func NewScans(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, scanApp application.ScanApplicationInterface, orderApp application.OrderApplicationInterface, hubApp application.HubApplicationInterface, driverApp application.DriverApplicationInterface, userApp application.UserApplicationInterface) *Scans {
+	return &Scans{
+		AuthService:  authService,
+		TokenService: tokenService,
+		ScanApp:      scanApp,
+		OrderApp:     orderApp,
+		HubApp:       hubApp,
+		DriverApp:    driverApp,
+		UserApp:      userApp,
+	}
+}
+
+// CreateScan records the scan of a parcel label, by its tracking number, and adds it to the
+// timeline of the order. The parcels are scanned by the drivers carrying them, by the staff of the
+// hub they go through and by the admins.
+func (s *Scans) CreateScan(ctx *gin.Context) {
+	var scan entity.Scan
+
+	// Bind the JSON body of the request to the Scan struct
+	if err := ctx.ShouldBindJSON(&scan); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	// Extract the token metadata from the request
+	metadata, err := s.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := s.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	// Get the user from the user application service
+	user, err := s.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &scan, "Hub")
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	// The parcels are scanned at a hub when they arrive and when they're loaded for the line haul
+	if (scan.Type == entity.ArrivedAtHubScanType || scan.Type == entity.LoadedScanType) && scan.HubID == 0 {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("A hub is required for this scan."))
+		return
+	}
+
+	order, err := s.OrderApp.GetOrderByTrackingNumber(scan.TrackingNumber)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Order not found."))
+		return
+	}
+
+	if order.Status == entity.OrderCanceledStatus || order.Status == entity.OrderScheduledStatus {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("This order can't be scanned."))
+		return
+	}
+
+	var hub *entity.Hub
+	if scan.HubID != 0 {
+		if hub, err = s.HubApp.GetHubByID(scan.HubID); err != nil {
+			response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Hub not found."))
+			return
+		}
+	}
+
+	allowed := user.IsAdmin()
+
+	// The drivers scan the parcels they carry, the scan is recorded against their leg
+	if driver, err := s.DriverApp.GetDriverByUserID(user.ID); err == nil {
+		if orderLeg, ok := driverOrderLeg(order, driver.ID); ok {
+			allowed = true
+			if orderLeg != nil {
+				scan.OrderLegID = orderLeg.ID
+			}
+		}
+	}
+
+	// The hub staff scan the parcels at their hub
+	if !allowed && hub != nil {
+		if _, err := s.HubApp.GetHubMemberByHubIDAndUserID(hub.ID, user.ID); err == nil {
+			allowed = true
+		}
+	}
+
+	if !allowed {
+		response.SendForbidden(ctx, ginI18n.MustGetMessage("Forbidden"))
+		return
+	}
+
+	scan.ID = 0
+	scan.OrderID = order.ID
+	scan.UserID = user.ID
+
+	createdScan, err := s.ScanApp.CreateScan(&scan)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if hub != nil {
+		createdScan.Hub = *hub
+	}
+
+	response.SendCreated(ctx, createdScan.PublicData(), "")
+}
+
+// driverOrderLeg reports whether the driver carries the order, or one of its legs. The leg of an
+// intercity order is returned too, the one still in progress before the ones the driver completed.
+func driverOrderLeg(order *entity.Order, driverID uint64) (*entity.OrderLeg, bool) {
+	var driverLeg *entity.OrderLeg
+	for i := range order.Legs {
+		leg := &order.Legs[i]
+		if leg.DriverID != driverID || leg.Status == entity.OrderLegCanceledStatus {
+			continue
+		}
+		if driverLeg == nil || driverLeg.Status == entity.OrderLegCompletedStatus {
+			driverLeg = leg
+		}
+	}
+	if driverLeg != nil {
+		return driverLeg, true
+	}
+
+	return nil, order.DriverID != 0 && order.DriverID == driverID
+}
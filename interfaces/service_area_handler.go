@@ -0,0 +1,179 @@
+package interfaces
+
+import (
+	"strconv"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceAreas holds the service area-related application interfaces
+type ServiceAreas struct {
+	AuthService    auth.AuthServiceInterface
+	TokenService   auth.TokenInterface
+	ServiceAreaApp application.ServiceAreaApplicationInterface
+	UserApp        application.UserApplicationInterface
+}
+
+// NewServiceAreas returns a new instance of ServiceAreas
+func NewServiceAreas(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, serviceAreaApp application.ServiceAreaApplicationInterface, userApp application.UserApplicationInterface) *ServiceAreas {
+	return &ServiceAreas{
+		AuthService:    authService,
+		TokenService:   tokenService,
+		ServiceAreaApp: serviceAreaApp,
+		UserApp:        userApp,
+	}
+}
+
+// GetAllServiceAreas retrieves all the service areas
+func (s *ServiceAreas) GetAllServiceAreas(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, s.TokenService, s.AuthService, s.UserApp); !ok {
+		return
+	}
+
+	serviceAreas, err := s.ServiceAreaApp.GetAllServiceAreas()
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	var serviceAreaPublicData []interface{}
+	for _, serviceArea := range serviceAreas {
+		serviceAreaPublicData = append(serviceAreaPublicData, serviceArea.PublicData())
+	}
+
+	response.SendOK(ctx, serviceAreaPublicData, "")
+}
+
+// GetServiceAreaByID retrieves a service area by its ID
+func (s *ServiceAreas) GetServiceAreaByID(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, s.TokenService, s.AuthService, s.UserApp); !ok {
+		return
+	}
+
+	// Parse the service area ID from the URL parameter.
+	serviceAreaID, err := strconv.ParseUint(ctx.Param("service_area_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid service area ID."))
+		return
+	}
+
+	serviceArea, err := s.ServiceAreaApp.GetServiceAreaByID(serviceAreaID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Service area not found."))
+		return
+	}
+
+	response.SendOK(ctx, serviceArea.PublicData(), "")
+}
+
+// CreateServiceArea handles the creation of a new service area
+func (s *ServiceAreas) CreateServiceArea(ctx *gin.Context) {
+	var serviceArea entity.ServiceArea
+
+	// Bind the JSON body of the request to the ServiceArea struct
+	if err := ctx.ShouldBindJSON(&serviceArea); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	if _, ok := requireAdmin(ctx, s.TokenService, s.AuthService, s.UserApp); !ok {
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &serviceArea, "Area")
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	if serviceArea.PriceMultiplier == 0 {
+		serviceArea.PriceMultiplier = 1
+	}
+
+	serviceArea.ID = 0
+
+	createdServiceArea, err := s.ServiceAreaApp.CreateServiceArea(&serviceArea)
+	if err != nil {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Invalid service area boundary."))
+		return
+	}
+
+	response.SendOK(ctx, createdServiceArea.PublicData(), "")
+}
+
+// UpdateServiceAreaByID updates a service area by its ID
+func (s *ServiceAreas) UpdateServiceAreaByID(ctx *gin.Context) {
+	var serviceArea entity.ServiceArea
+
+	// Bind the JSON body of the request to the ServiceArea struct
+	if err := ctx.ShouldBindJSON(&serviceArea); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	if _, ok := requireAdmin(ctx, s.TokenService, s.AuthService, s.UserApp); !ok {
+		return
+	}
+
+	// Parse the service area ID from the URL parameter.
+	serviceAreaID, err := strconv.ParseUint(ctx.Param("service_area_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid service area ID."))
+		return
+	}
+
+	if _, err := s.ServiceAreaApp.GetServiceAreaByID(serviceAreaID); err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Service area not found."))
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &serviceArea, "Area")
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	if serviceArea.PriceMultiplier == 0 {
+		serviceArea.PriceMultiplier = 1
+	}
+
+	updatedServiceArea, err := s.ServiceAreaApp.UpdateServiceAreaByID(serviceAreaID, &serviceArea)
+	if err != nil {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Invalid service area boundary."))
+		return
+	}
+
+	response.SendOK(ctx, updatedServiceArea.PublicData(), "")
+}
+
+// DeleteServiceAreaByID deletes a service area by its ID
+func (s *ServiceAreas) DeleteServiceAreaByID(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, s.TokenService, s.AuthService, s.UserApp); !ok {
+		return
+	}
+
+	// Parse the service area ID from the URL parameter.
+	serviceAreaID, err := strconv.ParseUint(ctx.Param("service_area_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid service area ID."))
+		return
+	}
+
+	if _, err := s.ServiceAreaApp.GetServiceAreaByID(serviceAreaID); err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Service area not found."))
+		return
+	}
+
+	if err := s.ServiceAreaApp.DeleteServiceAreaByID(serviceAreaID); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, nil, ginI18n.MustGetMessage("Service area deleted."))
+}
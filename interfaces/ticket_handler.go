@@ -0,0 +1,507 @@
+package interfaces
+
+import (
+	"errors"
+	"mime/multipart"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/pkg/pagination"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/upload"
+	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// maxAttachmentSize is the largest file that can be attached to a ticket message
+const maxAttachmentSize = 10 << 20
+
+// attachmentExtensions are the file types that can be attached to a ticket message
+var attachmentExtensions = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".pdf": true}
+
+// Tickets holds the support ticket-related application interfaces
+type Tickets struct {
+	AuthService  auth.AuthServiceInterface
+	TokenService auth.TokenInterface
+	TicketApp    application.TicketApplicationInterface
+	UserApp      application.UserApplicationInterface
+	OrderApp     application.OrderApplicationInterface
+	DriverApp    application.DriverApplicationInterface
+	CreditApp    application.CreditApplicationInterface
+}
+
+// NewTickets returns a new instance of Tickets
+func NewTickets(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, ticketApp application.TicketApplicationInterface, userApp application.UserApplicationInterface, orderApp application.OrderApplicationInterface, driverApp application.DriverApplicationInterface, creditApp application.CreditApplicationInterface) *Tickets {
+	return &Tickets{
+		AuthService:  authService,
+		TokenService: tokenService,
+		TicketApp:    ticketApp,
+		UserApp:      userApp,
+		OrderApp:     orderApp,
+		DriverApp:    driverApp,
+		CreditApp:    creditApp,
+	}
+}
+
+// GetAllTickets retrieves a paginated list of the authenticated user's tickets. Admins get the
+// tickets of all users, optionally filtered by status.
+func (t *Tickets) GetAllTickets(ctx *gin.Context) {
+	user, ok := t.getUser(ctx)
+	if !ok {
+		return
+	}
+
+	// Get the desired page number from the query parameters.
+	page := pagination.GetPage(ctx)
+
+	// Set the number of items per page.
+	perPage := 30
+
+	var tickets []entity.Ticket
+	var count int64
+	var err error
+
+	if user.IsAdmin() {
+		status := ctx.Query("status")
+
+		if count, err = t.TicketApp.CountTickets(status); err == nil {
+			tickets, err = t.TicketApp.GetAllTickets(status, page, perPage)
+		}
+	} else {
+		if count, err = t.TicketApp.CountTicketsByUserID(user.ID); err == nil {
+			tickets, err = t.TicketApp.GetAllTicketsByUserID(user.ID, page, perPage)
+		}
+	}
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if page <= 1 && len(tickets) <= 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No tickets found."))
+		return
+	}
+
+	// Check if the page is valid
+	if page <= 0 || (len(tickets) <= 0 && page*perPage > int(count)) {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Page not found."))
+		return
+	}
+
+	var ticketPublicData []interface{}
+
+	for _, ticket := range tickets {
+		ticketPublicData = append(ticketPublicData, ticket.PublicData())
+	}
+
+	// Build response data
+	data := make(map[string]interface{})
+	data["data"] = ticketPublicData
+	data["current_page"] = page
+	if page*perPage < int(count) {
+		data["next_page"] = page + 1
+	}
+	data["total"] = count
+
+	response.SendOK(ctx, data, "")
+}
+
+// CreateTicket opens a new support ticket, optionally disputing one of the user's orders. The
+// request is a multipart form so the first message can carry an attachment.
+func (t *Tickets) CreateTicket(ctx *gin.Context) {
+	user, ok := t.getUser(ctx)
+	if !ok {
+		return
+	}
+
+	ticket := entity.Ticket{
+		UserID:   user.ID,
+		Category: entity.TicketCategory(ctx.PostForm("category")),
+		Subject:  strings.TrimSpace(ctx.PostForm("subject")),
+		Status:   entity.TicketOpenStatus,
+	}
+
+	if orderIDStr := ctx.PostForm("order_id"); orderIDStr != "" {
+		orderID, err := strconv.ParseUint(orderIDStr, 10, 64)
+		if err != nil {
+			response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid order ID."))
+			return
+		}
+		ticket.OrderID = &orderID
+	}
+
+	message := entity.TicketMessage{
+		UserID: user.ID,
+		Body:   strings.TrimSpace(ctx.PostForm("body")),
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &ticket, "User")
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	validationErrors, _ = validator.ValidateExcept(ctx, &message, "User")
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	// Disputed orders must have been sent or delivered by the user
+	if ticket.OrderID != nil {
+		_, err := t.OrderApp.GetOrderByIDAndUserID(*ticket.OrderID, user.ID)
+		if err != nil {
+			driver, driverErr := t.DriverApp.GetDriverByUserID(user.ID)
+			if driverErr == nil {
+				_, err = t.OrderApp.GetOrderByIDAndDriverID(*ticket.OrderID, driver.ID)
+			}
+		}
+		if err != nil {
+			response.SendNotFound(ctx, ginI18n.MustGetMessage("Order not found."))
+			return
+		}
+	}
+
+	if !t.attach(ctx, &message) {
+		return
+	}
+
+	ticket.Messages = []entity.TicketMessage{message}
+
+	createdTicket, err := t.TicketApp.CreateTicket(&ticket)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendCreated(ctx, createdTicket.PublicData(), "")
+}
+
+// GetTicketByID retrieves a ticket with its message history
+func (t *Tickets) GetTicketByID(ctx *gin.Context) {
+	user, ok := t.getUser(ctx)
+	if !ok {
+		return
+	}
+
+	ticket, ok := t.findTicket(ctx, user)
+	if !ok {
+		return
+	}
+
+	response.SendOK(ctx, ticket.PublicData(), "")
+}
+
+// CreateTicketMessage adds a message, with an optional attachment, to the history of a ticket.
+func (t *Tickets) CreateTicketMessage(ctx *gin.Context) {
+	user, ok := t.getUser(ctx)
+	if !ok {
+		return
+	}
+
+	ticket, ok := t.findTicket(ctx, user)
+	if !ok {
+		return
+	}
+
+	if ticket.IsFinished() {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("The ticket is no longer open."))
+		return
+	}
+
+	message := entity.TicketMessage{
+		TicketID: ticket.ID,
+		UserID:   user.ID,
+		Body:     strings.TrimSpace(ctx.PostForm("body")),
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &message, "User")
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	if !t.attach(ctx, &message) {
+		return
+	}
+
+	createdMessage, err := t.TicketApp.CreateTicketMessage(&message)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendCreated(ctx, createdMessage.PublicData(), "")
+}
+
+// CloseTicketByID lets the user close a ticket that no longer needs a resolution
+func (t *Tickets) CloseTicketByID(ctx *gin.Context) {
+	user, ok := t.getUser(ctx)
+	if !ok {
+		return
+	}
+
+	ticket, ok := t.findTicket(ctx, user)
+	if !ok {
+		return
+	}
+
+	if ticket.UserID != user.ID {
+		response.SendForbidden(ctx, ginI18n.MustGetMessage("Forbidden"))
+		return
+	}
+
+	if ticket.IsFinished() {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("The ticket is no longer open."))
+		return
+	}
+
+	ticket.Status = entity.TicketClosedStatus
+
+	updatedTicket, err := t.TicketApp.UpdateTicketByID(ticket.ID, ticket)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, updatedTicket.PublicData(), "")
+}
+
+// AssignTicketByID assigns a ticket to an admin, the authenticated admin by default
+func (t *Tickets) AssignTicketByID(ctx *gin.Context) {
+	var input struct {
+		AssigneeID *uint64 `json:"assignee_id"`
+	}
+
+	// The body is optional
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+			response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+			return
+		}
+	}
+
+	user, ok := requireAdmin(ctx, t.TokenService, t.AuthService, t.UserApp)
+	if !ok {
+		return
+	}
+
+	ticket, ok := t.findTicket(ctx, user)
+	if !ok {
+		return
+	}
+
+	if ticket.IsFinished() {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("The ticket is no longer open."))
+		return
+	}
+
+	assigneeID := user.ID
+	if input.AssigneeID != nil {
+		assignee, err := t.UserApp.GetUserByID(*input.AssigneeID)
+		if err != nil || !assignee.IsAdmin() {
+			response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Tickets can only be assigned to admins."))
+			return
+		}
+		assigneeID = assignee.ID
+	}
+
+	ticket.AssigneeID = &assigneeID
+	ticket.Status = entity.TicketInProgressStatus
+
+	updatedTicket, err := t.TicketApp.UpdateTicketByID(ticket.ID, ticket)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, updatedTicket.PublicData(), "")
+}
+
+// ResolveTicketByID resolves a ticket. A refund is credited to the user who opened it and an
+// adjustment, positive or negative, is added to the balance of the driver of the disputed order.
+func (t *Tickets) ResolveTicketByID(ctx *gin.Context) {
+	var input struct {
+		Resolution       string   `json:"resolution" validate:"required,max=5000"`
+		RefundAmount     *float64 `json:"refund_amount" validate:"omitempty,gt=0"`
+		DriverAdjustment *float64 `json:"driver_adjustment" validate:"omitempty,ne=0"`
+	}
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	user, ok := requireAdmin(ctx, t.TokenService, t.AuthService, t.UserApp)
+	if !ok {
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &input)
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	ticket, ok := t.findTicket(ctx, user)
+	if !ok {
+		return
+	}
+
+	if ticket.IsFinished() {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("The ticket is no longer open."))
+		return
+	}
+
+	var credit *entity.Credit
+	var balance *entity.Balance
+
+	if input.RefundAmount != nil || input.DriverAdjustment != nil {
+		if ticket.OrderID == nil {
+			response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Refunds and adjustments require a disputed order."))
+			return
+		}
+
+		order, err := t.OrderApp.GetOrderByID(*ticket.OrderID)
+		if err != nil {
+			response.SendNotFound(ctx, ginI18n.MustGetMessage("Order not found."))
+			return
+		}
+
+		if input.RefundAmount != nil {
+			if order.UserID != ticket.UserID {
+				response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Refunds can only be granted to the sender of the order."))
+				return
+			}
+
+			if order.Amount == nil || *input.RefundAmount > order.RefundableAmount(0) {
+				response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("The refund can't exceed the amount of the order."))
+				return
+			}
+
+			credit = &entity.Credit{
+				UserID:  order.UserID,
+				OrderID: order.ID,
+				Amount:  *input.RefundAmount,
+				Reason:  entity.RefundCreditReason,
+			}
+		}
+
+		if input.DriverAdjustment != nil {
+			if order.DriverID == 0 {
+				response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("The order has no driver to adjust the balance of."))
+				return
+			}
+
+			balance = &entity.Balance{
+				DriverID: order.DriverID,
+				OrderID:  order.ID,
+				Balance:  *input.DriverAdjustment,
+			}
+		}
+	}
+
+	resolvedAt := time.Now()
+
+	if ticket.AssigneeID == nil {
+		ticket.AssigneeID = &user.ID
+	}
+	ticket.Resolution = &input.Resolution
+	ticket.RefundAmount = input.RefundAmount
+	ticket.DriverAdjustment = input.DriverAdjustment
+	ticket.ResolvedAt = &resolvedAt
+
+	resolvedTicket, err := t.TicketApp.ResolveTicket(ticket, credit, balance)
+	if errors.Is(err, repository.ErrRefundExceedsAmount) {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("The refund can't exceed what's left to refund on the order."))
+		return
+	}
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, resolvedTicket.PublicData(), "")
+}
+
+// getUser returns the authenticated user, sending an error response otherwise
+func (t *Tickets) getUser(ctx *gin.Context) (*entity.User, bool) {
+	// Extract the token metadata from the request
+	metadata, err := t.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := t.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Get the user from the user application service
+	user, err := t.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	return user, true
+}
+
+// findTicket returns the ticket of the URL parameter if the user opened it or is an admin,
+// sending an error response otherwise
+func (t *Tickets) findTicket(ctx *gin.Context, user *entity.User) (*entity.Ticket, bool) {
+	// Parse the ticket ID from the URL parameter.
+	ticketID, err := strconv.ParseUint(ctx.Param("ticket_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid ticket ID."))
+		return nil, false
+	}
+
+	ticket, err := t.TicketApp.GetTicketByID(ticketID)
+	if err != nil || (ticket.UserID != user.ID && !user.IsAdmin()) {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Ticket not found."))
+		return nil, false
+	}
+
+	return ticket, true
+}
+
+// attach stores the optional attachment of a ticket message, sending an error response if it
+// can't be accepted
+func (t *Tickets) attach(ctx *gin.Context, message *entity.TicketMessage) bool {
+	file, err := ctx.FormFile("attachment")
+	if err != nil {
+		// The attachment is optional
+		return true
+	}
+
+	if !isValidAttachment(file) {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Attachments must be images or PDF files of up to 10 MB."))
+		return false
+	}
+
+	fileInfo, err := upload.UploadFile(file, "uploads")
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return false
+	}
+
+	attachment := fileInfo.Name()
+	message.Attachment = &attachment
+
+	return true
+}
+
+func isValidAttachment(file *multipart.FileHeader) bool {
+	return file.Size <= maxAttachmentSize && attachmentExtensions[strings.ToLower(filepath.Ext(file.Filename))]
+}
@@ -0,0 +1,127 @@
+package interfaces
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/pkg/language"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// TrackOrder shows the order shared through a public tracking link, to anyone holding its token.
+// The recipients who aren't registered follow their orders this way.
+func (o *Orders) TrackOrder(ctx *gin.Context) {
+	order, err := o.OrderApp.GetOrderByShareToken(ctx.Param("token"))
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Tracking link not found."))
+		return
+	}
+
+	if order.ShareTokenExpired(time.Now()) {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("This tracking link has expired."))
+		return
+	}
+
+	orderTimelines, err := o.OrderTimelineApp.GetAllOrderTimelinesByOrderID(order.ID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	// The estimated time of arrival is only given while the driver heads to the recipient
+	var eta *float64
+	if order.IsOutForDelivery() {
+		if route, err := o.getDriverRoute(order); err == nil && route != nil {
+			eta = &route.Duration
+		}
+	}
+
+	response.SendOK(ctx, order.TrackingData(orderTimelines, eta, language.GetLanguage(ctx)), "")
+}
+
+// GetOrderShareTokenByID sends the tracking number and the token of the public tracking link of an
+// order to its sender. A new token is issued when the previous one was revoked.
+func (o *Orders) GetOrderShareTokenByID(ctx *gin.Context) {
+	order, ok := o.getSenderOrder(ctx)
+	if !ok {
+		return
+	}
+
+	if order.ShareTokenExpired(time.Now()) {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("Tracking links aren't available for finished orders."))
+		return
+	}
+
+	if order.ShareToken == nil {
+		shareToken, err := entity.NewShareToken()
+		if err != nil {
+			response.SendInternalServerError(ctx, err.Error())
+			return
+		}
+
+		if err := o.OrderApp.UpdateOrderShareTokenByID(order.ID, &shareToken); err != nil {
+			response.SendInternalServerError(ctx, err.Error())
+			return
+		}
+
+		order.ShareToken = &shareToken
+	}
+
+	data := make(map[string]interface{})
+	data["tracking_number"] = order.TrackingNumber
+	data["share_token"] = *order.ShareToken
+
+	response.SendOK(ctx, data, "")
+}
+
+// RevokeOrderShareTokenByID stops the public tracking link of an order from working. The sender
+// gets a new link, with a different token, the next time they ask for it.
+func (o *Orders) RevokeOrderShareTokenByID(ctx *gin.Context) {
+	order, ok := o.getSenderOrder(ctx)
+	if !ok {
+		return
+	}
+
+	if err := o.OrderApp.UpdateOrderShareTokenByID(order.ID, nil); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, nil, ginI18n.MustGetMessage("The tracking link was revoked."))
+}
+
+// getSenderOrder retrieves the order given in the URL if the authenticated user sent it, sending
+// an error response otherwise
+func (o *Orders) getSenderOrder(ctx *gin.Context) (*entity.Order, bool) {
+	// Extract the token metadata from the request
+	metadata, err := o.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := o.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Parse the order ID from the URL parameter.
+	orderID, err := strconv.ParseUint(ctx.Param("order_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid order ID."))
+		return nil, false
+	}
+
+	order, err := o.OrderApp.GetOrderByIDAndUserID(orderID, userID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Order not found."))
+		return nil, false
+	}
+
+	return order, true
+}
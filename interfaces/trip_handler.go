@@ -0,0 +1,232 @@
+package interfaces
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/domain/repository"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// Trips holds the trip-related application interfaces
+type Trips struct {
+	AuthService   auth.AuthServiceInterface
+	TokenService  auth.TokenInterface
+	UnitOfWorkApp application.UnitOfWorkApplicationInterface
+	TripApp       application.TripApplicationInterface
+	UserApp       application.UserApplicationInterface
+	DriverApp     application.DriverApplicationInterface
+}
+
+// NewTrips returns a new instance of Trips
+func NewTrips(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, unitOfWorkApp application.UnitOfWorkApplicationInterface, tripApp application.TripApplicationInterface, userApp application.UserApplicationInterface, driverApp application.DriverApplicationInterface) *Trips {
+	return &Trips{
+		AuthService:   authService,
+		TokenService:  tokenService,
+		UnitOfWorkApp: unitOfWorkApp,
+		TripApp:       tripApp,
+		UserApp:       userApp,
+		DriverApp:     driverApp,
+	}
+}
+
+// GetCurrentTrip retrieves the active trip of the authenticated driver
+func (t *Trips) GetCurrentTrip(ctx *gin.Context) {
+	driver, ok := t.getDriver(ctx)
+	if !ok {
+		return
+	}
+
+	trip, err := t.TripApp.GetActiveTripByDriverID(driver.ID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Trip not found."))
+		return
+	}
+
+	response.SendOK(ctx, trip.PublicData(), "")
+}
+
+// GetSuggestedSequence computes a suggested visiting order for the pending stops of the driver's active trip
+func (t *Trips) GetSuggestedSequence(ctx *gin.Context) {
+	driver, ok := t.getDriver(ctx)
+	if !ok {
+		return
+	}
+
+	trip, err := t.TripApp.GetActiveTripByDriverID(driver.ID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Trip not found."))
+		return
+	}
+
+	sequence := trip.SuggestedSequence(driver.Latitude, driver.Longitude)
+
+	var stopPublicData []interface{}
+	for _, stop := range sequence {
+		stopPublicData = append(stopPublicData, stop.PublicData())
+	}
+
+	response.SendOK(ctx, stopPublicData, "")
+}
+
+// CompleteTripStopByID marks a stop of the driver's active trip as done and moves its order along
+func (t *Trips) CompleteTripStopByID(ctx *gin.Context) {
+	driver, ok := t.getDriver(ctx)
+	if !ok {
+		return
+	}
+
+	// Parse the stop ID from the URL parameter.
+	stopID, err := strconv.ParseUint(ctx.Param("stop_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid stop ID."))
+		return
+	}
+
+	trip, err := t.TripApp.GetActiveTripByDriverID(driver.ID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Trip not found."))
+		return
+	}
+
+	stop, err := t.TripApp.GetTripStopByIDAndTripID(stopID, trip.ID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Stop not found."))
+		return
+	}
+
+	if stop.Status == entity.TripStopDoneStatus {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Stop already done."))
+		return
+	}
+
+	// A drop-off can't be done before the shipment has been picked up
+	if stop.Type == entity.DropOffStopType {
+		for _, tripStop := range trip.Stops {
+			if tripStop.OrderID == stop.OrderID && tripStop.Type == entity.PickupStopType && tripStop.Status != entity.TripStopDoneStatus {
+				response.SendBadRequest(ctx, ginI18n.MustGetMessage("The pickup of this order is not done yet."))
+				return
+			}
+		}
+	}
+
+	now := time.Now()
+	stop.Status = entity.TripStopDoneStatus
+	stop.CompletedAt = &now
+
+	for i := range trip.Stops {
+		if trip.Stops[i].ID == stop.ID {
+			trip.Stops[i] = *stop
+		}
+	}
+
+	// The stop, the order and the trip are updated together. The order only moves from the status
+	// it was read in, so a concurrent change of the order rolls the stop back.
+	err = t.UnitOfWorkApp.Do(func(tx repository.Transaction) error {
+		if _, err := tx.Trip().UpdateTripStopByID(stop.ID, stop); err != nil {
+			return err
+		}
+
+		if err := advanceTripStopOrder(tx, trip, stop, driver); err != nil {
+			return err
+		}
+
+		// The trip is over once every stop has been visited
+		if trip.IsCompleted() {
+			trip.Status = entity.TripCompletedStatus
+
+			if _, err := tx.Trip().UpdateTripByID(trip.ID, trip); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		response.SendConflict(ctx, ginI18n.MustGetMessage("The order was changed meanwhile."))
+		return
+	}
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, trip.PublicData(), "")
+}
+
+// advanceTripStopOrder moves the order of a stop that was just done to its next status. The
+// delivered orders get the driver's balance and the event of the delivery, like the ones delivered
+// through the order endpoints.
+func advanceTripStopOrder(tx repository.Transaction, trip *entity.Trip, stop *entity.TripStop, driver *entity.Driver) error {
+	order, err := tx.Order().GetOrderByID(stop.OrderID)
+	if err != nil {
+		return err
+	}
+
+	status, ok := trip.OrderStatusAfterStop(order, stop)
+	if !ok {
+		return nil
+	}
+
+	update := entity.Order{Status: status}
+	if status == entity.ShipmentDeliveredStatus {
+		update.DeliveredAt = stop.CompletedAt
+	}
+
+	if _, err := tx.Order().UpdateOrderByIDAndStatus(order.ID, order.Status, &update); err != nil {
+		return err
+	}
+
+	if status != entity.ShipmentDeliveredStatus {
+		return nil
+	}
+
+	order.Status = update.Status
+	order.DeliveredAt = update.DeliveredAt
+
+	balance := entity.Balance{OrderID: order.ID, DriverID: driver.ID}
+	if _, err := tx.Balance().CreateBalance(&balance); err != nil {
+		return err
+	}
+
+	return emitOrderEvent(tx, entity.OrderDeliveredEvent, order, driver.UserID)
+}
+
+// getDriver returns the driver profile of the authenticated user
+func (t *Trips) getDriver(ctx *gin.Context) (*entity.Driver, bool) {
+	// Extract the token metadata from the request
+	metadata, err := t.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := t.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Get the user from the user application service
+	user, err := t.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Get the driver from the driver application service
+	driver, err := t.DriverApp.GetDriverByUserID(user.ID)
+	if err != nil {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Driver not found."))
+		return nil, false
+	}
+
+	return driver, true
+}
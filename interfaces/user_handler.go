@@ -5,6 +5,7 @@ import (
 
 	"github.com/OmarBader7/web-service-jayeek/application"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/stats"
 	"github.com/OmarBader7/web-service-jayeek/pkg/language"
 	"github.com/OmarBader7/web-service-jayeek/pkg/pagination"
 	"github.com/OmarBader7/web-service-jayeek/pkg/response"
@@ -18,15 +19,17 @@ type Users struct {
 	TokenService auth.TokenInterface
 	UserApp      application.UserApplicationInterface
 	LocationApp  application.LocationApplicationInterface
+	StatsService stats.StatsServiceInterface
 }
 
 // NewUsers returns a new instance of Users
-func NewUsers(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, userApp application.UserApplicationInterface, locationApp application.LocationApplicationInterface) *Users {
+func NewUsers(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, userApp application.UserApplicationInterface, locationApp application.LocationApplicationInterface, statsService stats.StatsServiceInterface) *Users {
 	return &Users{
 		AuthService:  authService,
 		TokenService: tokenService,
 		UserApp:      userApp,
 		LocationApp:  locationApp,
+		StatsService: statsService,
 	}
 }
 
@@ -112,6 +115,14 @@ func (u *Users) GetUserByID(ctx *gin.Context) {
 		}
 	}
 
+	// Statistics are only loaded for the details of a single user
+	stats, err := u.StatsService.GetUserStats(user.ID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+	user.SetStats(stats)
+
 	userPublicData := user.PublicData(language.GetLanguage(ctx), currentUserID)
 
 	// Send the user as a response.
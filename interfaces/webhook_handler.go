@@ -0,0 +1,402 @@
+package interfaces
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/OmarBader7/web-service-jayeek/application"
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/webhook"
+	"github.com/OmarBader7/web-service-jayeek/pkg/pagination"
+	"github.com/OmarBader7/web-service-jayeek/pkg/response"
+	"github.com/OmarBader7/web-service-jayeek/pkg/validator"
+	ginI18n "github.com/gin-contrib/i18n"
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type Webhooks struct {
+	AuthService  auth.AuthServiceInterface
+	TokenService auth.TokenInterface
+	UserApp      application.UserApplicationInterface
+	WebhookApp   application.WebhookApplicationInterface
+}
+
+// NewWebhooks creates and returns a new instance of Webhooks.
+func NewWebhooks(authService auth.AuthServiceInterface, tokenService auth.TokenInterface, userApp application.UserApplicationInterface, webhookApp application.WebhookApplicationInterface) *Webhooks {
+	return &Webhooks{
+		AuthService:  authService,
+		TokenService: tokenService,
+		UserApp:      userApp,
+		WebhookApp:   webhookApp,
+	}
+}
+
+// webhookInput is the body of the requests creating and updating a webhook subscription
+type webhookInput struct {
+	URL        string             `json:"url" validate:"required,url,max=2048"`
+	EventTypes []entity.EventType `json:"event_types" validate:"required,min=1"`
+	IsActive   *bool              `json:"is_active"`
+}
+
+// GetAllWebhooks retrieves the webhook subscriptions of the authenticated user
+func (w *Webhooks) GetAllWebhooks(ctx *gin.Context) {
+	user, ok := w.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	webhookSubscriptions, err := w.WebhookApp.GetAllWebhookSubscriptionsByUserID(user.ID)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if len(webhookSubscriptions) <= 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No webhooks found."))
+		return
+	}
+
+	var webhookPublicData []interface{}
+	for _, webhookSubscription := range webhookSubscriptions {
+		webhookPublicData = append(webhookPublicData, webhookSubscription.PublicData())
+	}
+
+	response.SendOK(ctx, webhookPublicData, "")
+}
+
+// CreateWebhook subscribes an endpoint to events of the authenticated user's orders. The secret
+// signing the deliveries is only returned here.
+func (w *Webhooks) CreateWebhook(ctx *gin.Context) {
+	var input webhookInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	user, ok := w.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &input)
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	// Deliveries are only posted to public https endpoints
+	if err := webhook.ValidateURL(input.URL); err != nil {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("The webhook URL must use https and point to a public address."))
+		return
+	}
+
+	eventTypes, ok := webhookEventTypes(ctx, input.EventTypes)
+	if !ok {
+		return
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	webhookSubscription := entity.WebhookSubscription{
+		UserID:     user.ID,
+		URL:        input.URL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		IsActive:   input.IsActive == nil || *input.IsActive,
+	}
+
+	if _, err := w.WebhookApp.CreateWebhookSubscription(&webhookSubscription); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	// Prepare the response data.
+	data := make(map[string]interface{})
+	data["webhook"] = webhookSubscription.PublicData()
+	data["secret"] = secret
+
+	response.SendCreated(ctx, data, "")
+}
+
+// UpdateWebhookByID updates the endpoint, the events or the state of a webhook subscription
+func (w *Webhooks) UpdateWebhookByID(ctx *gin.Context) {
+	var input webhookInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid request body"))
+		return
+	}
+
+	user, ok := w.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	validationErrors, _ := validator.ValidateExcept(ctx, &input)
+	if validationErrors != nil {
+		response.SendUnprocessableEntity(ctx, validationErrors, "")
+		return
+	}
+
+	// Deliveries are only posted to public https endpoints
+	if err := webhook.ValidateURL(input.URL); err != nil {
+		response.SendUnprocessableEntity(ctx, nil, ginI18n.MustGetMessage("The webhook URL must use https and point to a public address."))
+		return
+	}
+
+	webhookSubscription, ok := w.getWebhook(ctx, user)
+	if !ok {
+		return
+	}
+
+	eventTypes, ok := webhookEventTypes(ctx, input.EventTypes)
+	if !ok {
+		return
+	}
+
+	webhookSubscription.URL = input.URL
+	webhookSubscription.EventTypes = eventTypes
+	if input.IsActive != nil {
+		webhookSubscription.IsActive = *input.IsActive
+	}
+	webhookSubscription.UpdatedAt = time.Now()
+
+	if _, err := w.WebhookApp.UpdateWebhookSubscriptionByID(webhookSubscription.ID, webhookSubscription); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, webhookSubscription.PublicData(), "")
+}
+
+// DeleteWebhookByID deletes a webhook subscription of the authenticated user and its deliveries
+func (w *Webhooks) DeleteWebhookByID(ctx *gin.Context) {
+	user, ok := w.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	webhookSubscription, ok := w.getWebhook(ctx, user)
+	if !ok {
+		return
+	}
+
+	if err := w.WebhookApp.DeleteWebhookSubscriptionByID(webhookSubscription.ID); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, nil, ginI18n.MustGetMessage("Webhook deleted successfully."))
+}
+
+// GetAllWebhookDeliveriesByWebhookID retrieves a paginated log of the deliveries of a webhook
+// subscription of the authenticated user, optionally filtered by status
+func (w *Webhooks) GetAllWebhookDeliveriesByWebhookID(ctx *gin.Context) {
+	user, ok := w.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	webhookSubscription, ok := w.getWebhook(ctx, user)
+	if !ok {
+		return
+	}
+
+	w.sendWebhookDeliveries(ctx, webhookSubscription.ID, ctx.Query("status"))
+}
+
+// GetAllWebhookDeliveries retrieves a paginated list of the webhook deliveries of every customer,
+// the failed ones unless another status is asked for. Only admins can see them.
+func (w *Webhooks) GetAllWebhookDeliveries(ctx *gin.Context) {
+	if _, ok := requireAdmin(ctx, w.TokenService, w.AuthService, w.UserApp); !ok {
+		return
+	}
+
+	w.sendWebhookDeliveries(ctx, 0, ctx.DefaultQuery("status", string(entity.WebhookDeliveryFailedStatus)))
+}
+
+// ReplayWebhookDeliveryByID queues a delivery for another round of attempts. Customers replay the
+// deliveries of their webhooks, admins any delivery.
+func (w *Webhooks) ReplayWebhookDeliveryByID(ctx *gin.Context) {
+	user, ok := w.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	// Parse the delivery ID from the URL parameter.
+	deliveryID, err := strconv.ParseUint(ctx.Param("delivery_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid delivery ID."))
+		return
+	}
+
+	webhookDelivery, err := w.WebhookApp.GetWebhookDeliveryByID(deliveryID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+	if err != nil || (!user.IsAdmin() && webhookDelivery.Subscription.UserID != user.ID) {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Delivery not found."))
+		return
+	}
+
+	if webhookDelivery.Status == entity.WebhookDeliveryPendingStatus {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("The delivery is already pending."))
+		return
+	}
+
+	webhookDelivery.Status = entity.WebhookDeliveryPendingStatus
+	webhookDelivery.Attempts = 0
+	webhookDelivery.NextAttemptAt = time.Now()
+	webhookDelivery.DeliveredAt = nil
+
+	if _, err := w.WebhookApp.UpdateWebhookDeliveryByID(webhookDelivery.ID, webhookDelivery); err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	response.SendOK(ctx, webhookDelivery.PublicData(), ginI18n.MustGetMessage("Delivery queued for replay."))
+}
+
+// sendWebhookDeliveries sends a page of the webhook deliveries
+func (w *Webhooks) sendWebhookDeliveries(ctx *gin.Context, subscriptionID uint64, status string) {
+	// Get the desired page number from the query parameters.
+	page := pagination.GetPage(ctx)
+
+	// Set the number of items per page.
+	perPage := 30
+
+	count, err := w.WebhookApp.CountWebhookDeliveries(subscriptionID, status)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	webhookDeliveries, err := w.WebhookApp.GetAllWebhookDeliveries(subscriptionID, status, page, perPage)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return
+	}
+
+	if page == 1 && len(webhookDeliveries) <= 0 {
+		response.SendOK(ctx, nil, ginI18n.MustGetMessage("No deliveries found."))
+		return
+	}
+
+	// Check if the page is valid
+	if page <= 0 || (len(webhookDeliveries) <= 0 && page*perPage > int(count)) {
+		response.SendNotFound(ctx, ginI18n.MustGetMessage("Page not found."))
+		return
+	}
+
+	var webhookDeliveryPublicData []interface{}
+	for _, webhookDelivery := range webhookDeliveries {
+		webhookDeliveryPublicData = append(webhookDeliveryPublicData, webhookDelivery.PublicData())
+	}
+
+	// Prepare the response data.
+	data := make(map[string]interface{})
+	data["data"] = webhookDeliveryPublicData
+	data["current_page"] = page
+	if page*perPage < int(count) {
+		data["next_page"] = page + 1
+	}
+	data["total"] = count
+
+	response.SendOK(ctx, data, "")
+}
+
+// getWebhook returns the webhook subscription of the URL parameter, if it belongs to the user
+func (w *Webhooks) getWebhook(ctx *gin.Context, user *entity.User) (*entity.WebhookSubscription, bool) {
+	// Parse the webhook ID from the URL parameter.
+	webhookID, err := strconv.ParseUint(ctx.Param("webhook_id"), 10, 64)
+	if err != nil {
+		response.SendBadRequest(ctx, ginI18n.MustGetMessage("Invalid webhook ID."))
+		return nil, false
+	}
+
+	webhookSubscription, err := w.WebhookApp.GetWebhookSubscriptionByIDAndUserID(webhookID, user.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.SendNotFound(ctx, ginI18n.MustGetMessage("Webhook not found."))
+			return nil, false
+		}
+		response.SendInternalServerError(ctx, err.Error())
+		return nil, false
+	}
+
+	return webhookSubscription, true
+}
+
+func (w *Webhooks) authenticate(ctx *gin.Context) (*entity.User, bool) {
+	// Extract the token metadata from the request
+	metadata, err := w.TokenService.ExtractTokenMetadata(ctx.Request)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Fetch the authenticated user's ID from the auth service
+	userID, err := w.AuthService.FetchAuth(metadata.AccessTokenUUID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	// Get the user from the user application service
+	user, err := w.UserApp.GetUserByID(userID)
+	if err != nil {
+		response.SendUnauthorized(ctx, ginI18n.MustGetMessage("Unauthorized"))
+		return nil, false
+	}
+
+	return user, true
+}
+
+// webhookEventTypes checks the selected event types against the ones webhooks are notified of
+func webhookEventTypes(ctx *gin.Context, selected []entity.EventType) (datatypes.JSON, bool) {
+	for _, eventType := range selected {
+		supported := false
+		for _, webhookEventType := range entity.WebhookEventTypes {
+			if eventType == webhookEventType {
+				supported = true
+				break
+			}
+		}
+
+		if !supported {
+			response.SendBadRequest(ctx, ginI18n.MustGetMessage("Unsupported webhook event type."))
+			return nil, false
+		}
+	}
+
+	eventTypes, err := json.Marshal(selected)
+	if err != nil {
+		response.SendInternalServerError(ctx, err.Error())
+		return nil, false
+	}
+
+	return datatypes.JSON(eventTypes), true
+}
+
+// generateWebhookSecret returns a random secret signing the deliveries of a webhook
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
@@ -5,14 +5,33 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/OmarBader7/web-service-jayeek/domain/entity"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/auth"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/calling"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/chat"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/chatsync"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/config"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/events"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/firebase"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/geo"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/geocoding"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/invoice"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/label"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/moderation"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/notification"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/persistence"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/profile"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/routing"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/scheduler"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/stats"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/surge"
 	"github.com/OmarBader7/web-service-jayeek/infrastructure/user_setting"
+	"github.com/OmarBader7/web-service-jayeek/infrastructure/webhook"
 	"github.com/OmarBader7/web-service-jayeek/interfaces"
 	ginI18n "github.com/gin-contrib/i18n"
 	"github.com/gin-gonic/gin"
@@ -43,6 +62,7 @@ func main() {
 	RedisHost := conf.RedisHost
 	RedisPassword := conf.RedisPassword
 	RedisPort := conf.RedisPort
+	chatProvider := conf.ChatProvider
 	streamApiKey := conf.StreamApiKey
 	streamApiSecret := conf.StreamApiSecret
 
@@ -54,6 +74,9 @@ func main() {
 	repositories.AutoMigrate()
 
 	var seed = flag.Bool("seed", false, "a bool")
+	var reconcileChat = flag.Bool("reconcile-chat", false, "queue the chat membership changes matching the existing orders and exit")
+	var importGeo = flag.String("import-geo", "", "import the regions, cities and districts of a GeoJSON file and exit")
+	var importLocations = flag.String("import-locations", "", "import the regions, cities, districts and locations of a JSON or CSV file and exit")
 
 	flag.Parse()
 	if *seed {
@@ -72,25 +95,103 @@ func main() {
 		repositories.SeedSettings()
 	}
 
+	// Create new geocoding service, backed by the regions, cities and districts imported in the database
+	geocodingService := geocoding.NewLocalGeocodingService(repositories.Geocoding, repositories.City, repositories.District)
+
+	if *importGeo != "" {
+		file, err := os.Open(*importGeo)
+		if err != nil {
+			log.Fatal("Error opening the geocoding dataset: ", err)
+		}
+		defer file.Close()
+
+		if err := geocodingService.ImportDataset(file); err != nil {
+			log.Fatal("Error importing the geocoding dataset: ", err)
+		}
+		log.Println("Geocoding dataset imported")
+		return
+	}
+
+	if *importLocations != "" {
+		file, err := os.Open(*importLocations)
+		if err != nil {
+			log.Fatal("Error opening the locations file: ", err)
+		}
+		defer file.Close()
+
+		format := geocoding.JSONHierarchyFormat
+		if strings.EqualFold(filepath.Ext(*importLocations), ".csv") {
+			format = geocoding.CSVHierarchyFormat
+		}
+
+		if err := geocodingService.ImportHierarchy(file, format); err != nil {
+			log.Fatal("Error importing the locations: ", err)
+		}
+		log.Println("Locations imported")
+		return
+	}
+
 	// Create new Redis service
 	redisService, err := auth.NewRedisService(RedisHost, RedisPort, RedisPassword)
 	if err != nil {
 		log.Fatal("Error creating Redis service: ", err)
 	}
 
-	streamService, err := chat.NewStreamService(streamApiKey, streamApiSecret)
-	if err != nil {
-		log.Fatal("Error creating Stream service: ", err)
+	// Existing deployments configured with a Stream API key keep using Stream
+	if chatProvider == "" {
+		chatProvider = chat.LocalProvider
+		if streamApiKey != "" {
+			chatProvider = chat.StreamProvider
+		}
+	}
+
+	// Create new chat service, the messaging service is only set when the conversations are hosted locally
+	var chatProviderService chat.ChatServiceInterface
+	var messagingService chat.MessagingServiceInterface
+	var webhookVerifier chat.WebhookVerifierInterface
+
+	switch chatProvider {
+	case chat.StreamProvider:
+		streamService, err := chat.NewStreamService(streamApiKey, streamApiSecret)
+		if err != nil {
+			log.Fatal("Error creating Stream service: ", err)
+		}
+		chatProviderService = streamService.ChatService
+		webhookVerifier = streamService.ChatService
+	case chat.LocalProvider:
+		localChatService := chat.NewLocalChatService(repositories.Chat, redisService.RedisClient)
+		chatProviderService = localChatService
+		messagingService = localChatService
+	default:
+		log.Fatal("Unknown chat provider: ", chatProvider)
+	}
+
+	// Create new chat sync service, applying the chat membership changes queued by the handlers
+	chatSyncService := chatsync.NewChatSyncService(chatProviderService, repositories.ChatOutbox, repositories.Order, repositories.Rating)
+
+	if *reconcileChat {
+		if err := chatSyncService.Reconcile(); err != nil {
+			log.Fatal("Error reconciling chat channels: ", err)
+		}
+		log.Println("Chat membership changes queued, the running API instances apply them")
+		return
 	}
 
 	// Create new token generator
 	tokenGenerator := auth.NewToken()
 
+	// Create new token generator authenticating the requests made with an organization's API key too,
+	// for the routes using APIKeyAuthMiddleware
+	apiKeyTokenGenerator := auth.NewAPIKeyToken(tokenGenerator, redisService.RedisClient, repositories.APIKey)
+
+	// Create new stats service, caching the user statistics in Redis
+	statsService := stats.NewStatsService(redisService.RedisClient, repositories.UserStats)
+
 	// Create new authentication service
-	authService := interfaces.NewAuth(redisService.AuthService, tokenGenerator, streamService.ChatService, repositories.User, repositories.Location, repositories.Order, repositories.PasswordReset, repositories.PhoneVerification)
+	authService := interfaces.NewAuth(redisService.AuthService, tokenGenerator, repositories.UnitOfWork, repositories.User, repositories.Location, repositories.Order, repositories.PasswordReset, repositories.PhoneVerification, statsService)
 
 	// Create new user service
-	userService := interfaces.NewUsers(redisService.AuthService, tokenGenerator, repositories.User, repositories.Location)
+	userService := interfaces.NewUsers(redisService.AuthService, tokenGenerator, repositories.User, repositories.Location, statsService)
 
 	// Create new device service
 	deviceService := interfaces.NewDevices(redisService.AuthService, tokenGenerator, repositories.Device, repositories.User)
@@ -105,6 +206,12 @@ func main() {
 
 	cityService := interfaces.NewCities(redisService.AuthService, tokenGenerator, repositories.City)
 
+	// Create new region service
+	regionService := interfaces.NewRegions(redisService.AuthService, tokenGenerator, repositories.Region)
+
+	// Create new district service
+	districtService := interfaces.NewDistricts(redisService.AuthService, tokenGenerator, repositories.District)
+
 	// Create new location service
 	locationService := interfaces.NewLocations(redisService.AuthService, tokenGenerator, repositories.Location)
 
@@ -127,13 +234,34 @@ func main() {
 	truckModelService := interfaces.NewTruckModels(redisService.AuthService, tokenGenerator, repositories.TruckModel)
 
 	// Create new driver service
-	driverService := interfaces.NewDrivers(redisService.AuthService, tokenGenerator, repositories.Driver, repositories.User, repositories.TransportationMode, repositories.IdentityDocument)
+	driverService := interfaces.NewDrivers(redisService.AuthService, tokenGenerator, repositories.Driver, repositories.User, repositories.TransportationMode, repositories.IdentityDocument, repositories.Rating, repositories.City, repositories.Region, repositories.DriverCoverage, statsService)
 
 	// Create new order service
-	orderService := interfaces.NewOrders(redisService.AuthService, tokenGenerator, streamService.ChatService, repositories.Order, repositories.User, repositories.Category, repositories.Location, repositories.Driver, repositories.Size, repositories.TruckType, repositories.TruckModel, repositories.DeliveryTime, repositories.ShipmentContent, repositories.ExtraService, repositories.Balance, repositories.Setting, repositories.Offer, repositories.Rating)
+	orderService := interfaces.NewOrders(redisService.AuthService, apiKeyTokenGenerator, repositories.UnitOfWork, routing.NewRoutingService(conf.OsrmURL), invoice.NewInvoiceService("./resources/fonts/DejaVuSans.ttf"), label.NewLabelService("./resources/fonts/DejaVuSans.ttf"), geocodingService, repositories.Order, repositories.User, repositories.Category, repositories.Location, repositories.Driver, repositories.Size, repositories.TruckType, repositories.TruckModel, repositories.DeliveryTime, repositories.ShipmentContent, repositories.ExtraService, repositories.Balance, repositories.Setting, repositories.Offer, repositories.Rating, repositories.OrderTimeline, repositories.RecurringOrder, repositories.Trip, repositories.ServiceArea, repositories.Promotion, repositories.Credit, repositories.Invoice, repositories.Organization, repositories.Address, repositories.Hub, repositories.OrderLeg)
 
 	// Create new offer service
-	offerService := interfaces.NewOffers(redisService.AuthService, tokenGenerator, streamService.ChatService, repositories.User, repositories.Offer, repositories.Order, repositories.Driver)
+	offerService := interfaces.NewOffers(redisService.AuthService, tokenGenerator, repositories.UnitOfWork, repositories.User, repositories.Offer, repositories.Order, repositories.Driver, repositories.Trip, repositories.Promotion, repositories.OrderLeg)
+
+	// Create new trip service
+	tripService := interfaces.NewTrips(redisService.AuthService, tokenGenerator, repositories.UnitOfWork, repositories.Trip, repositories.User, repositories.Driver)
+
+	// Create new hub service
+	hubService := interfaces.NewHubs(redisService.AuthService, tokenGenerator, repositories.Hub, repositories.City, repositories.User)
+
+	// Create new scan service
+	scanService := interfaces.NewScans(redisService.AuthService, tokenGenerator, repositories.Scan, repositories.Order, repositories.Hub, repositories.Driver, repositories.User)
+
+	// Create new service area service
+	serviceAreaService := interfaces.NewServiceAreas(redisService.AuthService, tokenGenerator, repositories.ServiceArea, repositories.User)
+
+	// Create new promotion service
+	promotionService := interfaces.NewPromotions(redisService.AuthService, tokenGenerator, repositories.Promotion, repositories.User)
+
+	// Create new referral service
+	referralService := interfaces.NewReferrals(redisService.AuthService, tokenGenerator, repositories.User, repositories.Credit)
+
+	// Create new support ticket service
+	ticketService := interfaces.NewTickets(redisService.AuthService, tokenGenerator, repositories.Ticket, repositories.User, repositories.Order, repositories.Driver, repositories.Credit)
 
 	// Create new page service
 	pageService := interfaces.NewPages(repositories.Page)
@@ -141,8 +269,20 @@ func main() {
 	// Create new FAQ service
 	faqService := interfaces.NewFAQs(repositories.FAQ)
 
+	// Create new call service, the masked numbers are drawn from the masked_calling_numbers setting
+	callService := interfaces.NewCalls(redisService.AuthService, tokenGenerator, calling.NewPoolCallingService(redisService.RedisClient, repositories.Setting), repositories.User, repositories.Order, repositories.Driver, conf.CallingWebhookSecret)
+
+	// Create new organization service
+	organizationService := interfaces.NewOrganizations(redisService.AuthService, apiKeyTokenGenerator, repositories.User, repositories.Organization, repositories.APIKey, repositories.Order)
+
+	// Create new webhook service
+	webhookService := interfaces.NewWebhooks(redisService.AuthService, tokenGenerator, repositories.User, repositories.Webhook)
+
+	// Create new address service
+	addressService := interfaces.NewAddresses(redisService.AuthService, tokenGenerator, repositories.User, repositories.Location, repositories.Address)
+
 	// Create new chat service
-	chatService := interfaces.NewChat(redisService.AuthService, tokenGenerator, streamService.ChatService)
+	chatService := interfaces.NewChat(redisService.AuthService, tokenGenerator, chatProviderService, messagingService, webhookVerifier, moderation.NewModerationService(repositories.Setting), repositories.User, repositories.FlaggedMessage)
 
 	// Create new profile service
 	profileService := interfaces.NewProfile(redisService.AuthService, tokenGenerator, repositories.User, repositories.Driver, repositories.Location, profile.NewProfileService(userService.UserApp))
@@ -153,9 +293,49 @@ func main() {
 	// Create new geo service
 	geoService := interfaces.NewGeo(geo.NewGeoService())
 
+	// Create new geocoding handler
+	geocodingHandler := interfaces.NewGeocoding(geocodingService)
+
 	// Create new setting service
 	settingService := interfaces.NewSettings(redisService.AuthService, tokenGenerator, repositories.Setting)
 
+	// Start the scheduler that releases scheduled and preferred orders, generates recurring orders and completes delivered orders
+	schedulerService := scheduler.NewSchedulerService(repositories.UnitOfWork, repositories.Order, repositories.RecurringOrder, orderService, repositories.Setting)
+	go schedulerService.Start(time.Minute)
+
+	// Start the surge calculator that compares demand and supply per service area
+	surgeService := surge.NewSurgeService(repositories.ServiceArea, repositories.Setting)
+	go surgeService.Start(5 * time.Minute)
+
+	// Start the chat sync worker that applies and retries the chat membership changes
+	go chatSyncService.Start(10 * time.Second)
+
+	// Start the webhook worker that delivers and retries the events sent to the business customers
+	webhookDeliveryService := webhook.NewWebhookService(repositories.UnitOfWork, repositories.Webhook)
+	go webhookDeliveryService.Start(10 * time.Second)
+
+	// Start the dispatcher that delivers the domain events of the outbox to their subscribers
+	dispatcher := events.NewDispatcher(repositories.UnitOfWork)
+	for _, eventType := range []entity.EventType{entity.OrderAcceptedEvent, entity.OrderCanceledEvent, entity.OrderDeliveredEvent, entity.OrderDriverRatedEvent, entity.OrderRecipientLinkedEvent} {
+		dispatcher.Subscribe(eventType, "chat", chatSyncService.HandleOrderEvent)
+		dispatcher.Subscribe(eventType, "stats", statsService.HandleOrderEvent)
+	}
+	for _, eventType := range entity.WebhookEventTypes {
+		dispatcher.Subscribe(eventType, "webhook", webhookDeliveryService.HandleEvent)
+	}
+	if conf.FirebaseCredentialsFile != "" {
+		firebaseService, err := firebase.NewFirebaseService(conf.FirebaseCredentialsFile)
+		if err != nil {
+			log.Fatal("Error creating Firebase service: ", err)
+		}
+
+		notificationService := notification.NewNotificationService(firebaseService, repositories.Device)
+		for _, eventType := range []entity.EventType{entity.OrderAcceptedEvent, entity.OrderCanceledEvent, entity.OrderDeliveredEvent, entity.OrderReturnedEvent} {
+			dispatcher.Subscribe(eventType, "notification", notificationService.HandleOrderEvent)
+		}
+	}
+	go dispatcher.Start(5 * time.Second)
+
 	// Create new router
 	router := gin.Default()
 
@@ -225,6 +405,18 @@ func main() {
 			cityGroup.GET("/:city_id", interfaces.AuthMiddleware(), cityService.GetCityByID)
 		}
 
+		regionGroup := taxonomyGroup.Group("/regions")
+		{
+			regionGroup.GET("/", regionService.GetAllRegions)
+			regionGroup.GET("/:region_id", interfaces.AuthMiddleware(), regionService.GetRegionByID)
+		}
+
+		districtGroup := taxonomyGroup.Group("/districts")
+		{
+			districtGroup.GET("/", districtService.GetAllDistricts)
+			districtGroup.GET("/:district_id", interfaces.AuthMiddleware(), districtService.GetDistrictByID)
+		}
+
 		shipmentContentGroup := taxonomyGroup.Group("/shipment-contents")
 		{
 			shipmentContentGroup.GET("/", interfaces.AuthMiddleware(), shipmentContentService.GetAllShipmentContents)
@@ -267,9 +459,15 @@ func main() {
 		driverGroup.GET("/", interfaces.AuthMiddleware(), driverService.GetAllDrivers)
 		driverGroup.POST("/", interfaces.AuthMiddleware(), driverService.CreateDriver)
 		driverGroup.GET("/:driver_id", interfaces.AuthMiddleware(), driverService.GetDriverByID)
+		driverGroup.GET("/:driver_id/reviews", interfaces.AuthMiddleware(), driverService.GetDriverReviewsByID)
 		driverGroup.GET("/by-location/:location_id", interfaces.AuthMiddleware(), driverService.GetDriversByUserLocationID)
+		driverGroup.GET("/coverage", interfaces.AuthMiddleware(), driverService.GetDriverCoverage)
+		driverGroup.PUT("/coverage", interfaces.AuthMiddleware(), driverService.UpdateDriverCoverage)
 	}
 
+	// The organizations' systems create and follow their orders with an API key
+	apiKeyAuthMiddleware := interfaces.APIKeyAuthMiddleware(repositories.APIKey)
+
 	orderGroup := router.Group("/orders")
 	{
 		driverPoolGroup := orderGroup.Group("/driver-pools")
@@ -279,15 +477,100 @@ func main() {
 			driverPoolGroup.PUT("/by-order/:order_id/decline", interfaces.AuthMiddleware(), orderService.DeclineDriverPoolByID)
 		}
 
-		orderGroup.GET("/", interfaces.AuthMiddleware(), orderService.GetAllOrders)
+		recurringOrderGroup := orderGroup.Group("/recurring")
+		{
+			recurringOrderGroup.GET("/", interfaces.AuthMiddleware(), orderService.GetAllRecurringOrders)
+			recurringOrderGroup.POST("/", interfaces.AuthMiddleware(), orderService.CreateRecurringOrder)
+			recurringOrderGroup.PUT("/:recurring_order_id/cancel", interfaces.AuthMiddleware(), orderService.CancelRecurringOrderByID)
+		}
+
+		orderGroup.GET("/", apiKeyAuthMiddleware, orderService.GetAllOrders)
 		orderGroup.GET("/purchases", interfaces.AuthMiddleware(), orderService.GetAllPurchases)
-		orderGroup.POST("/", interfaces.AuthMiddleware(), orderService.CreateOrder)
-		orderGroup.GET("/:order_id", interfaces.AuthMiddleware(), orderService.GetOrderByID)
+		orderGroup.POST("/", apiKeyAuthMiddleware, orderService.CreateOrder)
+		orderGroup.POST("/quote", apiKeyAuthMiddleware, orderService.QuoteOrder)
+		orderGroup.POST("/bulk", apiKeyAuthMiddleware, orderService.BulkCreateOrders)
+		orderGroup.GET("/:order_id", apiKeyAuthMiddleware, orderService.GetOrderByID)
 		orderGroup.PUT("/:order_id/cancel", interfaces.AuthMiddleware(), orderService.CancelOrderByID)
 		orderGroup.PUT("/:order_id/deliver", interfaces.AuthMiddleware(), orderService.DeliverOrderByID)
 		orderGroup.PUT("/:order_id/pickup", interfaces.AuthMiddleware(), orderService.PickupOrderByID)
 		orderGroup.POST("/:order_id/rate", interfaces.AuthMiddleware(), orderService.RateOrderByID)
+		orderGroup.POST("/:order_id/rate-customer", interfaces.AuthMiddleware(), orderService.RateCustomerByID)
 		orderGroup.POST("/:order_id/return", interfaces.AuthMiddleware(), orderService.ReturnOrderByID)
+		orderGroup.PUT("/:order_id/recipient-address", interfaces.AuthMiddleware(), orderService.UpdateOrderRecipientAddressByID)
+		orderGroup.GET("/:order_id/timeline", apiKeyAuthMiddleware, orderService.GetOrderTimelineByID)
+		orderGroup.GET("/:order_id/legs", interfaces.AuthMiddleware(), orderService.GetOrderLegsByID)
+		orderGroup.GET("/:order_id/invoice", interfaces.AuthMiddleware(), orderService.GetOrderInvoiceByID)
+		orderGroup.GET("/:order_id/label", interfaces.AuthMiddleware(), orderService.GetOrderLabelByID)
+		orderGroup.GET("/:order_id/call", interfaces.AuthMiddleware(), callService.GetOrderCallNumber)
+		orderGroup.GET("/:order_id/share", interfaces.AuthMiddleware(), orderService.GetOrderShareTokenByID)
+		orderGroup.DELETE("/:order_id/share", interfaces.AuthMiddleware(), orderService.RevokeOrderShareTokenByID)
+	}
+
+	// The tracking links are opened by recipients who may not be registered
+	router.GET("/track/:token", orderService.TrackOrder)
+
+	tripGroup := router.Group("/trips")
+	{
+		tripGroup.GET("/current", interfaces.AuthMiddleware(), tripService.GetCurrentTrip)
+		tripGroup.GET("/current/sequence", interfaces.AuthMiddleware(), tripService.GetSuggestedSequence)
+		tripGroup.PUT("/current/stops/:stop_id/done", interfaces.AuthMiddleware(), tripService.CompleteTripStopByID)
+	}
+
+	legGroup := router.Group("/legs")
+	{
+		legGroup.GET("/", interfaces.AuthMiddleware(), orderService.GetAllOrderLegs)
+		legGroup.GET("/available", interfaces.AuthMiddleware(), orderService.GetAllAvailableOrderLegs)
+		legGroup.POST("/:leg_id/offers", interfaces.AuthMiddleware(), orderService.CreateOrderLegOffer)
+		legGroup.PUT("/:leg_id/pickup", interfaces.AuthMiddleware(), orderService.PickupOrderLegByID)
+		legGroup.PUT("/:leg_id/complete", interfaces.AuthMiddleware(), orderService.CompleteOrderLegByID)
+	}
+
+	hubGroup := router.Group("/hubs")
+	{
+		hubGroup.GET("/", interfaces.AuthMiddleware(), hubService.GetAllHubs)
+		hubGroup.POST("/", interfaces.AuthMiddleware(), hubService.CreateHub)
+		hubGroup.GET("/:hub_id", interfaces.AuthMiddleware(), hubService.GetHubByID)
+		hubGroup.PUT("/:hub_id", interfaces.AuthMiddleware(), hubService.UpdateHubByID)
+		hubGroup.GET("/:hub_id/members", interfaces.AuthMiddleware(), hubService.GetAllHubMembers)
+		hubGroup.POST("/:hub_id/members", interfaces.AuthMiddleware(), hubService.AddHubMember)
+		hubGroup.DELETE("/:hub_id/members/:user_id", interfaces.AuthMiddleware(), hubService.DeleteHubMemberByUserID)
+	}
+
+	scanGroup := router.Group("/scans")
+	{
+		scanGroup.POST("/", interfaces.AuthMiddleware(), scanService.CreateScan)
+	}
+
+	serviceAreaGroup := router.Group("/service-areas")
+	{
+		serviceAreaGroup.GET("/", interfaces.AuthMiddleware(), serviceAreaService.GetAllServiceAreas)
+		serviceAreaGroup.POST("/", interfaces.AuthMiddleware(), serviceAreaService.CreateServiceArea)
+		serviceAreaGroup.GET("/:service_area_id", interfaces.AuthMiddleware(), serviceAreaService.GetServiceAreaByID)
+		serviceAreaGroup.PUT("/:service_area_id", interfaces.AuthMiddleware(), serviceAreaService.UpdateServiceAreaByID)
+		serviceAreaGroup.DELETE("/:service_area_id", interfaces.AuthMiddleware(), serviceAreaService.DeleteServiceAreaByID)
+	}
+
+	promotionGroup := router.Group("/promotions")
+	{
+		promotionGroup.GET("/", interfaces.AuthMiddleware(), promotionService.GetAllPromotions)
+		promotionGroup.POST("/", interfaces.AuthMiddleware(), promotionService.CreatePromotion)
+		promotionGroup.PUT("/:promotion_id/deactivate", interfaces.AuthMiddleware(), promotionService.DeactivatePromotionByID)
+	}
+
+	referralGroup := router.Group("/referrals")
+	{
+		referralGroup.GET("/", interfaces.AuthMiddleware(), referralService.GetReferral)
+	}
+
+	ticketGroup := router.Group("/tickets")
+	{
+		ticketGroup.GET("/", interfaces.AuthMiddleware(), ticketService.GetAllTickets)
+		ticketGroup.POST("/", interfaces.AuthMiddleware(), ticketService.CreateTicket)
+		ticketGroup.GET("/:ticket_id", interfaces.AuthMiddleware(), ticketService.GetTicketByID)
+		ticketGroup.POST("/:ticket_id/messages", interfaces.AuthMiddleware(), ticketService.CreateTicketMessage)
+		ticketGroup.PUT("/:ticket_id/close", interfaces.AuthMiddleware(), ticketService.CloseTicketByID)
+		ticketGroup.PUT("/:ticket_id/assign", interfaces.AuthMiddleware(), ticketService.AssignTicketByID)
+		ticketGroup.PUT("/:ticket_id/resolve", interfaces.AuthMiddleware(), ticketService.ResolveTicketByID)
 	}
 
 	offerGroup := router.Group("/offers")
@@ -326,10 +609,65 @@ func main() {
 	{
 		chatGroup.GET("/token/client", interfaces.AuthMiddleware(), chatService.GetClientStreamToken)
 		chatGroup.GET("/token/driver", interfaces.AuthMiddleware(), chatService.GetDriverStreamToken)
+		chatGroup.GET("/channels", interfaces.AuthMiddleware(), chatService.GetChatChannels)
+		chatGroup.GET("/channels/:channel_id/messages", interfaces.AuthMiddleware(), chatService.GetChatMessages)
+		chatGroup.POST("/channels/:channel_id/messages", interfaces.AuthMiddleware(), chatService.SendChatMessage)
+		chatGroup.PUT("/channels/:channel_id/read", interfaces.AuthMiddleware(), chatService.MarkChatChannelRead)
+		chatGroup.GET("/socket", chatService.ConnectChatSocket)
+		chatGroup.POST("/webhooks/stream", chatService.ModerateStreamMessage)
+		chatGroup.GET("/flagged-messages", interfaces.AuthMiddleware(), chatService.GetAllFlaggedMessages)
+		chatGroup.PUT("/flagged-messages/:flagged_message_id/review", interfaces.AuthMiddleware(), chatService.ReviewFlaggedMessageByID)
+	}
+
+	callGroup := router.Group("/calls")
+	{
+		callGroup.POST("/forward", callService.ForwardCall)
+	}
+
+	organizationGroup := router.Group("/organizations")
+	{
+		organizationGroup.GET("", interfaces.AuthMiddleware(), organizationService.GetAllOrganizations)
+		organizationGroup.POST("", interfaces.AuthMiddleware(), organizationService.CreateOrganization)
+		organizationGroup.GET("/:organization_id", interfaces.AuthMiddleware(), organizationService.GetOrganizationByID)
+		organizationGroup.PUT("/:organization_id", interfaces.AuthMiddleware(), organizationService.UpdateOrganizationByID)
+		organizationGroup.GET("/:organization_id/members", interfaces.AuthMiddleware(), organizationService.GetAllOrganizationMembers)
+		organizationGroup.POST("/:organization_id/members", interfaces.AuthMiddleware(), organizationService.AddOrganizationMember)
+		organizationGroup.PUT("/:organization_id/members/:user_id", interfaces.AuthMiddleware(), organizationService.UpdateOrganizationMemberByID)
+		organizationGroup.DELETE("/:organization_id/members/:user_id", interfaces.AuthMiddleware(), organizationService.RemoveOrganizationMemberByID)
+		organizationGroup.GET("/:organization_id/api-keys", interfaces.AuthMiddleware(), organizationService.GetAllAPIKeys)
+		organizationGroup.POST("/:organization_id/api-keys", interfaces.AuthMiddleware(), organizationService.CreateAPIKey)
+		organizationGroup.DELETE("/:organization_id/api-keys/:api_key_id", interfaces.AuthMiddleware(), organizationService.RevokeAPIKeyByID)
+		organizationGroup.GET("/:organization_id/orders", apiKeyAuthMiddleware, organizationService.GetAllOrganizationOrders)
+	}
+
+	webhookGroup := router.Group("/webhooks")
+	{
+		webhookGroup.GET("", interfaces.AuthMiddleware(), webhookService.GetAllWebhooks)
+		webhookGroup.POST("", interfaces.AuthMiddleware(), webhookService.CreateWebhook)
+		webhookGroup.PUT("/:webhook_id", interfaces.AuthMiddleware(), webhookService.UpdateWebhookByID)
+		webhookGroup.DELETE("/:webhook_id", interfaces.AuthMiddleware(), webhookService.DeleteWebhookByID)
+		webhookGroup.GET("/:webhook_id/deliveries", interfaces.AuthMiddleware(), webhookService.GetAllWebhookDeliveriesByWebhookID)
+		webhookGroup.GET("/deliveries", interfaces.AuthMiddleware(), webhookService.GetAllWebhookDeliveries)
+		webhookGroup.PUT("/deliveries/:delivery_id/replay", interfaces.AuthMiddleware(), webhookService.ReplayWebhookDeliveryByID)
+	}
+
+	addressGroup := router.Group("/addresses")
+	{
+		addressGroup.GET("", interfaces.AuthMiddleware(), addressService.GetAllAddresses)
+		addressGroup.POST("", interfaces.AuthMiddleware(), addressService.CreateAddress)
+		addressGroup.GET("/:address_id", interfaces.AuthMiddleware(), addressService.GetAddressByID)
+		addressGroup.PUT("/:address_id", interfaces.AuthMiddleware(), addressService.UpdateAddressByID)
+		addressGroup.DELETE("/:address_id", interfaces.AuthMiddleware(), addressService.DeleteAddressByID)
 	}
 
 	router.GET("/geo/iso2", geoService.Iso2)
 
+	geocodingGroup := router.Group("/geocoding")
+	{
+		geocodingGroup.GET("/search", interfaces.AuthMiddleware(), geocodingHandler.Search)
+		geocodingGroup.GET("/reverse/:latitude/:longitude", interfaces.AuthMiddleware(), geocodingHandler.Reverse)
+	}
+
 	settingGroup := router.Group("/settings")
 	{
 		settingGroup.GET("/", settingService.GetAllSettings)
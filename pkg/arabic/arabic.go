@@ -0,0 +1,225 @@
+package arabic
+
+import "unicode"
+
+// forms holds the isolated, final, initial and medial presentation forms of an Arabic letter.
+// Letters that only join to the previous letter have no initial and medial forms.
+type forms [4]rune
+
+const (
+	isolated = iota
+	final
+	initial
+	medial
+)
+
+var letters = map[rune]forms{
+	'ء': {'ﺀ', 0, 0, 0},
+	'آ': {'ﺁ', 'ﺂ', 0, 0},
+	'أ': {'ﺃ', 'ﺄ', 0, 0},
+	'ؤ': {'ﺅ', 'ﺆ', 0, 0},
+	'إ': {'ﺇ', 'ﺈ', 0, 0},
+	'ئ': {'ﺉ', 'ﺊ', 'ﺋ', 'ﺌ'},
+	'ا': {'ﺍ', 'ﺎ', 0, 0},
+	'ب': {'ﺏ', 'ﺐ', 'ﺑ', 'ﺒ'},
+	'ة': {'ﺓ', 'ﺔ', 0, 0},
+	'ت': {'ﺕ', 'ﺖ', 'ﺗ', 'ﺘ'},
+	'ث': {'ﺙ', 'ﺚ', 'ﺛ', 'ﺜ'},
+	'ج': {'ﺝ', 'ﺞ', 'ﺟ', 'ﺠ'},
+	'ح': {'ﺡ', 'ﺢ', 'ﺣ', 'ﺤ'},
+	'خ': {'ﺥ', 'ﺦ', 'ﺧ', 'ﺨ'},
+	'د': {'ﺩ', 'ﺪ', 0, 0},
+	'ذ': {'ﺫ', 'ﺬ', 0, 0},
+	'ر': {'ﺭ', 'ﺮ', 0, 0},
+	'ز': {'ﺯ', 'ﺰ', 0, 0},
+	'س': {'ﺱ', 'ﺲ', 'ﺳ', 'ﺴ'},
+	'ش': {'ﺵ', 'ﺶ', 'ﺷ', 'ﺸ'},
+	'ص': {'ﺹ', 'ﺺ', 'ﺻ', 'ﺼ'},
+	'ض': {'ﺽ', 'ﺾ', 'ﺿ', 'ﻀ'},
+	'ط': {'ﻁ', 'ﻂ', 'ﻃ', 'ﻄ'},
+	'ظ': {'ﻅ', 'ﻆ', 'ﻇ', 'ﻈ'},
+	'ع': {'ﻉ', 'ﻊ', 'ﻋ', 'ﻌ'},
+	'غ': {'ﻍ', 'ﻎ', 'ﻏ', 'ﻐ'},
+	'ـ': {'ـ', 'ـ', 'ـ', 'ـ'},
+	'ف': {'ﻑ', 'ﻒ', 'ﻓ', 'ﻔ'},
+	'ق': {'ﻕ', 'ﻖ', 'ﻗ', 'ﻘ'},
+	'ك': {'ﻙ', 'ﻚ', 'ﻛ', 'ﻜ'},
+	'ل': {'ﻝ', 'ﻞ', 'ﻟ', 'ﻠ'},
+	'م': {'ﻡ', 'ﻢ', 'ﻣ', 'ﻤ'},
+	'ن': {'ﻥ', 'ﻦ', 'ﻧ', 'ﻨ'},
+	'ه': {'ﻩ', 'ﻪ', 'ﻫ', 'ﻬ'},
+	'و': {'ﻭ', 'ﻮ', 0, 0},
+	'ى': {'ﻯ', 'ﻰ', 0, 0},
+	'ي': {'ﻱ', 'ﻲ', 'ﻳ', 'ﻴ'},
+}
+
+// lamAlef holds the isolated and final forms of the ligatures of lam followed by an alef.
+var lamAlef = map[rune][2]rune{
+	'آ': {'ﻵ', 'ﻶ'},
+	'أ': {'ﻷ', 'ﻸ'},
+	'إ': {'ﻹ', 'ﻺ'},
+	'ا': {'ﻻ', 'ﻼ'},
+}
+
+var mirrored = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+}
+
+// Shape converts the Arabic letters of a text to their contextual presentation forms and
+// reorders the text for renderers that only lay out text from left to right, such as PDF
+// writers. Texts without Arabic letters are returned unchanged.
+func Shape(text string) string {
+	runes := []rune(text)
+
+	hasArabic := false
+	for _, r := range runes {
+		if _, ok := letters[r]; ok {
+			hasArabic = true
+			break
+		}
+	}
+	if !hasArabic {
+		return text
+	}
+
+	return string(reorder(join(runes)))
+}
+
+// join replaces every Arabic letter with the form matching its neighbours.
+func join(runes []rune) []rune {
+	shaped := make([]rune, 0, len(runes))
+
+	for i := 0; i < len(runes); i++ {
+		f, ok := letters[runes[i]]
+		if !ok {
+			shaped = append(shaped, runes[i])
+			continue
+		}
+
+		prev := previousLetter(runes, i)
+		joinsPrevious := prev != 0 && letters[prev][initial] != 0
+
+		// Lam followed by an alef is written as a single ligature
+		if runes[i] == 'ل' {
+			if next := nextLetter(runes, i); next != 0 {
+				if ligature, ok := lamAlef[next]; ok {
+					if joinsPrevious {
+						shaped = append(shaped, ligature[1])
+					} else {
+						shaped = append(shaped, ligature[0])
+					}
+					i = nextLetterIndex(runes, i)
+					continue
+				}
+			}
+		}
+
+		joinsNext := f[initial] != 0 && nextLetter(runes, i) != 0
+
+		switch {
+		case joinsPrevious && joinsNext:
+			shaped = append(shaped, f[medial])
+		case joinsPrevious && f[final] != 0:
+			shaped = append(shaped, f[final])
+		case joinsNext:
+			shaped = append(shaped, f[initial])
+		default:
+			shaped = append(shaped, f[isolated])
+		}
+	}
+
+	return shaped
+}
+
+// isMark reports whether the rune is an Arabic diacritic, which doesn't break the joining of letters.
+func isMark(r rune) bool {
+	return r >= '\u064B' && r <= '\u065F' || r == '\u0670'
+}
+
+func previousLetter(runes []rune, i int) rune {
+	for j := i - 1; j >= 0; j-- {
+		if isMark(runes[j]) {
+			continue
+		}
+		if _, ok := letters[runes[j]]; ok {
+			return runes[j]
+		}
+		return 0
+	}
+	return 0
+}
+
+func nextLetterIndex(runes []rune, i int) int {
+	for j := i + 1; j < len(runes); j++ {
+		if isMark(runes[j]) {
+			continue
+		}
+		if _, ok := letters[runes[j]]; ok {
+			return j
+		}
+		return -1
+	}
+	return -1
+}
+
+func nextLetter(runes []rune, i int) rune {
+	if j := nextLetterIndex(runes, i); j >= 0 {
+		return runes[j]
+	}
+	return 0
+}
+
+// isLeftToRight reports whether the rune is written from left to right, such as Latin letters and digits.
+func isLeftToRight(r rune) bool {
+	return r < '\u0590' && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// reorder lays out a right-to-left text visually. Runs of left-to-right text, such as numbers
+// and Latin words, keep their own order.
+func reorder(runes []rune) []rune {
+	var runs [][]rune
+
+	for i := 0; i < len(runes); {
+		j := i
+		if isLeftToRight(runes[i]) {
+			// A left-to-right run spans the neutral characters between its letters and digits
+			last := i
+			for j < len(runes) && (isLeftToRight(runes[j]) || !isRightToLeft(runes[j])) {
+				if isLeftToRight(runes[j]) {
+					last = j
+				}
+				j++
+			}
+			j = last + 1
+			runs = append(runs, runes[i:j])
+		} else {
+			for j < len(runes) && !isLeftToRight(runes[j]) {
+				j++
+			}
+			run := make([]rune, 0, j-i)
+			for k := j - 1; k >= i; k-- {
+				if m, ok := mirrored[runes[k]]; ok {
+					run = append(run, m)
+				} else {
+					run = append(run, runes[k])
+				}
+			}
+			runs = append(runs, run)
+		}
+		i = j
+	}
+
+	visual := make([]rune, 0, len(runes))
+	for k := len(runs) - 1; k >= 0; k-- {
+		visual = append(visual, runs[k]...)
+	}
+	return visual
+}
+
+// isRightToLeft reports whether the rune belongs to the Arabic script.
+func isRightToLeft(r rune) bool {
+	return r >= '\u0600' && r <= '\u06FF' || r >= '\uFB50' && r <= '\uFDFF' || r >= '\uFE70' && r <= '\uFEFF'
+}
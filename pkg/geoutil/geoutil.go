@@ -25,3 +25,37 @@ func CalculateDistance(lat1, lon1, lat2, lon2 float64) float64 {
 
 	return EarthRadiusKm * c
 }
+
+// EncodePolyline encodes a list of [latitude, longitude] points using the
+// Google encoded polyline algorithm format with a precision of 5 decimals.
+func EncodePolyline(points [][2]float64) string {
+	var encoded []byte
+	var prevLat, prevLon int64
+
+	for _, point := range points {
+		lat := int64(math.Round(point[0] * 1e5))
+		lon := int64(math.Round(point[1] * 1e5))
+
+		encoded = appendPolylineValue(encoded, lat-prevLat)
+		encoded = appendPolylineValue(encoded, lon-prevLon)
+
+		prevLat, prevLon = lat, lon
+	}
+
+	return string(encoded)
+}
+
+// appendPolylineValue appends a single signed value to an encoded polyline.
+func appendPolylineValue(encoded []byte, value int64) []byte {
+	shifted := value << 1
+	if value < 0 {
+		shifted = ^shifted
+	}
+
+	for shifted >= 0x20 {
+		encoded = append(encoded, byte((0x20|(shifted&0x1f))+63))
+		shifted >>= 5
+	}
+
+	return append(encoded, byte(shifted+63))
+}
@@ -113,3 +113,15 @@ func SendForbidden(c *gin.Context, message string) {
 
 	c.JSON(code, response)
 }
+
+// SendConflict sends a 409 Conflict response with the provided message
+func SendConflict(c *gin.Context, message string) {
+	code := http.StatusConflict
+	response := Response{
+		Success: false,
+		Status:  code,
+		Message: message,
+	}
+
+	c.JSON(code, response)
+}
@@ -3,10 +3,12 @@ package upload
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // UploadFile saves the uploaded file to the specified directory with a generated filename
@@ -41,8 +43,9 @@ func UploadFile(file *multipart.FileHeader, directory string) (os.FileInfo, erro
 }
 
 func generateFilename(originalFilename string) string {
-	// Generate a unique identifier using a cryptographic hash function
-	hash := generateHash(originalFilename)
+	// Generate a unique identifier using a cryptographic hash function, including the current
+	// timestamp so files uploaded with the same name don't overwrite each other
+	hash := generateHash(fmt.Sprintf("%s-%d", originalFilename, time.Now().UnixNano()))
 
 	// Get the file extension from the original filename
 	fileExtension := filepath.Ext(originalFilename)
@@ -69,3 +72,18 @@ func generateHash(input string) string {
 
 	return hashString
 }
+
+// SaveFile writes generated content to the specified directory under the given filename,
+// creating the directory when needed, and returns file information.
+func SaveFile(data []byte, directory string, filename string) (os.FileInfo, error) {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(directory, filename)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, err
+	}
+
+	return os.Stat(path)
+}
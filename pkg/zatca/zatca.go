@@ -0,0 +1,43 @@
+package zatca
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// The tags of the fields encoded in the QR code of a simplified tax invoice.
+const (
+	sellerNameTag byte = iota + 1
+	vatNumberTag
+	timestampTag
+	totalTag
+	vatTotalTag
+)
+
+// EncodeQR returns the base64 encoded TLV (tag, length, value) payload that the QR code of a
+// simplified tax invoice must carry: the seller name, the seller VAT registration number, the
+// invoice timestamp, the invoice total including VAT and the VAT total.
+func EncodeQR(sellerName string, vatNumber string, issuedAt time.Time, total float64, vatTotal float64) string {
+	var payload []byte
+
+	payload = appendTLV(payload, sellerNameTag, sellerName)
+	payload = appendTLV(payload, vatNumberTag, vatNumber)
+	payload = appendTLV(payload, timestampTag, issuedAt.UTC().Format(time.RFC3339))
+	payload = appendTLV(payload, totalTag, fmt.Sprintf("%.2f", total))
+	payload = appendTLV(payload, vatTotalTag, fmt.Sprintf("%.2f", vatTotal))
+
+	return base64.StdEncoding.EncodeToString(payload)
+}
+
+func appendTLV(payload []byte, tag byte, value string) []byte {
+	// The length is a single byte, so longer values are truncated on a character boundary
+	for len(value) > 255 {
+		_, size := utf8.DecodeLastRuneInString(value)
+		value = value[:len(value)-size]
+	}
+
+	payload = append(payload, tag, byte(len(value)))
+	return append(payload, value...)
+}
@@ -0,0 +1,85 @@
+package zatca
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// decodeTLV splits a base64 encoded TLV payload into its values by tag
+func decodeTLV(t *testing.T, encoded string) map[byte]string {
+	payload, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := make(map[byte]string)
+	for len(payload) > 0 {
+		if len(payload) < 2 || len(payload) < 2+int(payload[1]) {
+			t.Fatalf("truncated TLV payload %v", payload)
+		}
+		tag, length := payload[0], int(payload[1])
+		fields[tag] = string(payload[2 : 2+length])
+		payload = payload[2+length:]
+	}
+	return fields
+}
+
+func TestEncodeQR(t *testing.T) {
+	riyadh := time.FixedZone("AST", 3*60*60)
+	issuedAt := time.Date(2024, 3, 1, 15, 4, 5, 0, riyadh)
+
+	tests := []struct {
+		name       string
+		sellerName string
+		vatNumber  string
+		total      float64
+		vatTotal   float64
+		want       map[byte]string
+	}{
+		{
+			name:       "latin seller",
+			sellerName: "Jayeek",
+			vatNumber:  "310122393500003",
+			total:      115,
+			vatTotal:   15,
+			want:       map[byte]string{1: "Jayeek", 2: "310122393500003", 3: "2024-03-01T12:04:05Z", 4: "115.00", 5: "15.00"},
+		},
+		{
+			name:       "arabic seller",
+			sellerName: "شركة جايك",
+			vatNumber:  "310122393500003",
+			total:      38.333,
+			vatTotal:   5,
+			want:       map[byte]string{1: "شركة جايك", 2: "310122393500003", 3: "2024-03-01T12:04:05Z", 4: "38.33", 5: "5.00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeTLV(t, EncodeQR(tt.sellerName, tt.vatNumber, issuedAt, tt.total, tt.vatTotal))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EncodeQR() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeQRTruncatesLongValues(t *testing.T) {
+	// 200 two-byte characters don't fit the single byte length
+	sellerName := strings.Repeat("ج", 200)
+
+	got := decodeTLV(t, EncodeQR(sellerName, "310122393500003", time.Now(), 1, 0))[sellerNameTag]
+	if len(got) > 255 {
+		t.Errorf("seller name is %d bytes, want at most 255", len(got))
+	}
+	if !utf8.ValidString(got) || !strings.HasPrefix(sellerName, got) {
+		t.Errorf("seller name %q isn't cut on a character boundary", got)
+	}
+	if len(got) != 254 {
+		t.Errorf("seller name is %d bytes, want 254", len(got))
+	}
+}